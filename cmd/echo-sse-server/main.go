@@ -81,9 +81,9 @@ func main() {
 // Echo tool handler
 func handleEcho(ctx context.Context, request server.ToolCallRequest) (interface{}, error) {
 	// Extract the message parameter
-	message, ok := request.Parameters["message"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid 'message' parameter")
+	message, err := request.GetString("message")
+	if err != nil {
+		return nil, err
 	}
 
 	// Return the echo response in the format expected by the MCP protocol
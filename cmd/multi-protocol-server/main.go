@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -20,14 +21,9 @@ const (
 	defaultAddr     = ":8080"
 	shutdownTimeout = 10 * time.Second
 
-	serverInstructions = `
-This is a multi-protocol MCP server example that can run in HTTP, SSE, or StdIO mode.
+	serverInstructions = `This is a multi-protocol MCP server example that can run in HTTP, SSE, or StdIO mode.
 It demonstrates how to use the SDK to create different server types
-with a shared configuration.
-
-Available tool:
-- echo_multi_protocol: Echoes back the input message
-`
+with a shared configuration.`
 )
 
 func main() {
@@ -40,10 +36,6 @@ func main() {
 	logger := log.New(os.Stdout, "[MCP-SERVER] ", log.LstdFlags|log.Lshortfile)
 	logger.Printf("Starting %s v%s in %s mode...", serverName, serverVersion, *mode)
 
-	// Create a server
-	mcpServer := server.NewMCPServer(serverName, serverVersion)
-	mcpServer.SetAddress(*addr)
-
 	// Create echo tool using the fluent API
 	echoTool := tools.NewTool("echo_multi_protocol",
 		tools.WithDescription("Echoes back the input message"),
@@ -52,12 +44,21 @@ func main() {
 			tools.Required(),
 		),
 	)
+	serverTools := []server.ToolWithHandler{{Tool: echoTool, Handler: handleEcho}}
+
+	// Create a server whose instructions list the registered tools
+	mcpServer := server.NewMCPServer(serverName, serverVersion,
+		server.WithInstructionsFunc(func(ctx context.Context) string {
+			return instructions(serverTools)
+		}),
+	)
+	mcpServer.SetAddress(*addr)
 
-	// Register the tool with its handler
+	// Register the tools with their handlers
 	ctx := context.Background()
-	err := mcpServer.AddTool(ctx, echoTool, handleEcho)
+	err := mcpServer.AddTools(ctx, serverTools...)
 	if err != nil {
-		logger.Fatalf("Failed to add tool: %v", err)
+		logger.Fatalf("Failed to add tools: %v", err)
 	}
 
 	// Start the appropriate server based on mode
@@ -91,12 +92,24 @@ func main() {
 	}
 }
 
+// instructions describes the server and lists its tools, so the list cannot
+// drift from the tools actually registered
+func instructions(serverTools []server.ToolWithHandler) string {
+	var b strings.Builder
+	b.WriteString(serverInstructions)
+	b.WriteString("\n\nAvailable tools:\n")
+	for _, t := range serverTools {
+		fmt.Fprintf(&b, "- %s: %s\n", t.Tool.Name, t.Tool.Description)
+	}
+	return b.String()
+}
+
 // handleEcho handles echo tool calls
 func handleEcho(ctx context.Context, request server.ToolCallRequest) (interface{}, error) {
 	// Extract message parameter
-	message, ok := request.Parameters["message"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid message parameter")
+	message, err := request.GetString("message")
+	if err != nil {
+		return nil, err
 	}
 
 	// Get current timestamp
@@ -1,5 +1,5 @@
 // This example demonstrates how to use the MCP Server SDK to create
-// a server that can run in either HTTP or stdio mode with a calculator tool.
+// a server that can run in HTTP mode, stdio mode or both with a calculator tool.
 package main
 
 import (
@@ -9,7 +9,6 @@ import (
 	"log"
 	"os"
 	"os/signal"
-	"strconv"
 	"syscall"
 	"time"
 
@@ -25,7 +24,7 @@ const (
 
 func main() {
 	// Parse command-line flags
-	mode := flag.String("mode", "http", "Server mode: http or stdio")
+	mode := flag.String("mode", "http", "Server mode: http, stdio or both")
 	addr := flag.String("addr", ":8080", "HTTP server address (for HTTP mode)")
 	flag.Parse()
 
@@ -73,8 +72,17 @@ func main() {
 			log.Fatalf("Stdio server error: %v", err)
 		}
 
+	case "both":
+		// Serve HTTP and stdio from one service until stdin closes or a signal arrives
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		log.Printf("HTTP server starting at http://localhost%s, stdio server reading stdin", mcpServer.GetAddress())
+		if err := mcpServer.Serve(ctx, server.HTTPTransport(), server.StdioTransport()); err != nil {
+			log.Fatalf("Server error: %v", err)
+		}
+
 	default:
-		log.Fatalf("Unknown mode: %s. Valid modes are 'http' or 'stdio'", *mode)
+		log.Fatalf("Unknown mode: %s. Valid modes are 'http', 'stdio' or 'both'", *mode)
 	}
 }
 
@@ -113,49 +121,18 @@ func startHTTPServer(mcpServer *server.MCPServer) {
 
 // handleCalculator handles calculator tool calls
 func handleCalculator(ctx context.Context, request server.ToolCallRequest) (interface{}, error) {
-	// Extract parameters
-	operation, ok := request.Parameters["operation"].(string)
-	if !ok {
-		return nil, fmt.Errorf("missing or invalid operation parameter")
-	}
-
-	// Extract and convert number parameters
-	aParam, aOk := request.Parameters["a"]
-	bParam, bOk := request.Parameters["b"]
-	if !aOk || !bOk {
-		return nil, fmt.Errorf("missing operands")
+	// Extract parameters; numbers may also arrive as strings
+	operation, err := request.GetString("operation")
+	if err != nil {
+		return nil, err
 	}
-
-	// Convert parameters to float64
-	var a, b float64
-	var err error
-
-	switch v := aParam.(type) {
-	case float64:
-		a = v
-	case int:
-		a = float64(v)
-	case string:
-		a, err = strconv.ParseFloat(v, 64)
-		if err != nil {
-			return nil, fmt.Errorf("invalid first operand: %v", err)
-		}
-	default:
-		return nil, fmt.Errorf("first operand has unsupported type")
+	a, err := request.GetFloat("a")
+	if err != nil {
+		return nil, err
 	}
-
-	switch v := bParam.(type) {
-	case float64:
-		b = v
-	case int:
-		b = float64(v)
-	case string:
-		b, err = strconv.ParseFloat(v, 64)
-		if err != nil {
-			return nil, fmt.Errorf("invalid second operand: %v", err)
-		}
-	default:
-		return nil, fmt.Errorf("second operand has unsupported type")
+	b, err := request.GetFloat("b")
+	if err != nil {
+		return nil, err
 	}
 
 	// Perform the calculation
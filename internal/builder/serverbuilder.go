@@ -2,6 +2,8 @@ package builder
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
@@ -9,6 +11,7 @@ import (
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/interfaces/rest"
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/interfaces/stdio"
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/usecases"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ServerBuilder implements the Builder pattern for creating MCP servers
@@ -16,25 +19,72 @@ type ServerBuilder struct {
 	name               string
 	version            string
 	instructions       string
+	instructionsFunc   func(ctx context.Context) string
 	address            string
 	resourceRepo       domain.ResourceRepository
+	templateRepo       domain.ResourceTemplateRepository
 	toolRepo           domain.ToolRepository
 	promptRepo         domain.PromptRepository
 	sessionRepo        domain.SessionRepository
 	notificationSender domain.NotificationSender
+	toolHandlers       map[string]usecases.ToolHandlerFunc
+	requestTimeout     time.Duration
+	metrics            domain.MetricsCollector
+	healthPath         string
+	basePath           string
+	requestLogging     bool
+	redactParams       []string
+	maxRequestBytes    int64
+	methodHandlers     map[string]rest.MethodHandler
+	interceptors       []rest.RequestInterceptor
+	tracer             trace.Tracer
+	completionProvs    []domain.CompletionProvider
+	promptCompleters   []promptCompleter
+	maxConcurrentCalls int
+	queueCalls         bool
+	callRate           float64
+	callBurst          int
+	callRatePerSession bool
+	capabilities       []string
+	shutdownGrace      time.Duration
+	idleTimeout        time.Duration
+	corsOrigins        []string
+	corsCredentials    bool
+	prettyJSON         bool
+	sseDelivery        server.SSEDelivery
+	batchMax           int
+	batchWindow        time.Duration
+	pingDiagnostics    bool
+
+	// service is the most recently built service, through which items
+	// removed at runtime are deleted so clients are notified
+	service *usecases.ServerService
+
+	// err is the first error registering a tool, see Err
+	err error
+}
+
+// promptCompleter is a completer registered for one argument of a prompt
+type promptCompleter struct {
+	prompt    string
+	argument  string
+	completer domain.ArgumentCompleter
 }
 
 // NewServerBuilder creates a new server builder with default values
 func NewServerBuilder() *ServerBuilder {
 	return &ServerBuilder{
-		name:         "MCP Server",
-		version:      "1.0.0",
-		instructions: "MCP Server for AI tools and resources",
-		address:      ":8080",
-		resourceRepo: server.NewInMemoryResourceRepository(),
-		toolRepo:     server.NewInMemoryToolRepository(),
-		promptRepo:   server.NewInMemoryPromptRepository(),
-		sessionRepo:  server.NewInMemorySessionRepository(),
+		name:            "MCP Server",
+		version:         "1.0.0",
+		instructions:    "MCP Server for AI tools and resources",
+		address:         ":8080",
+		resourceRepo:    server.NewInMemoryResourceRepository(),
+		templateRepo:    server.NewInMemoryResourceTemplateRepository(),
+		toolRepo:        server.NewInMemoryToolRepository(),
+		promptRepo:      server.NewInMemoryPromptRepository(),
+		sessionRepo:     server.NewInMemorySessionRepository(),
+		toolHandlers:    make(map[string]usecases.ToolHandlerFunc),
+		maxRequestBytes: server.DefaultMaxRequestBytes,
 	}
 }
 
@@ -56,18 +106,175 @@ func (b *ServerBuilder) WithInstructions(instructions string) *ServerBuilder {
 	return b
 }
 
+// WithInstructionsFunc sets a function that generates the server instructions
+// for each initialize request, taking precedence over WithInstructions
+func (b *ServerBuilder) WithInstructionsFunc(fn func(ctx context.Context) string) *ServerBuilder {
+	b.instructionsFunc = fn
+	return b
+}
+
 // WithAddress sets the server address
 func (b *ServerBuilder) WithAddress(address string) *ServerBuilder {
 	b.address = address
 	return b
 }
 
+// WithRequestTimeout sets the timeout for processing a single request
+func (b *ServerBuilder) WithRequestTimeout(timeout time.Duration) *ServerBuilder {
+	b.requestTimeout = timeout
+	return b
+}
+
+// WithShutdownGrace sets how long the server lets clients react to the
+// shutdown notification before closing their sessions
+func (b *ServerBuilder) WithShutdownGrace(grace time.Duration) *ServerBuilder {
+	b.shutdownGrace = grace
+	return b
+}
+
+// WithSessionIdleTimeout sets how long an SSE session may be inactive before
+// the server closes it
+func (b *ServerBuilder) WithSessionIdleTimeout(timeout time.Duration) *ServerBuilder {
+	b.idleTimeout = timeout
+	return b
+}
+
+// WithSSEDelivery sets the channel on which responses to SSE clients' messages
+// are delivered
+func (b *ServerBuilder) WithSSEDelivery(delivery server.SSEDelivery) *ServerBuilder {
+	b.sseDelivery = delivery
+	return b
+}
+
+// WithEventBatching sets the size and window for coalescing queued SSE events
+func (b *ServerBuilder) WithEventBatching(maxBatch int, window time.Duration) *ServerBuilder {
+	b.batchMax = maxBatch
+	b.batchWindow = window
+	return b
+}
+
+// WithPrettyJSON sets whether HTTP JSON-RPC responses are indented
+func (b *ServerBuilder) WithPrettyJSON(pretty bool) *ServerBuilder {
+	b.prettyJSON = pretty
+	return b
+}
+
+// WithPingDiagnostics adds server diagnostics to ping results
+func (b *ServerBuilder) WithPingDiagnostics() *ServerBuilder {
+	b.pingDiagnostics = true
+	return b
+}
+
+// WithTracer sets the OpenTelemetry tracer that traces every message
+func (b *ServerBuilder) WithTracer(tracer trace.Tracer) *ServerBuilder {
+	b.tracer = tracer
+	return b
+}
+
+// WithMetrics sets the collector that records request metrics
+func (b *ServerBuilder) WithMetrics(collector domain.MetricsCollector) *ServerBuilder {
+	b.metrics = collector
+	return b
+}
+
+// WithRequestLogging logs every JSON-RPC request with the named params redacted
+func (b *ServerBuilder) WithRequestLogging(redactParams ...string) *ServerBuilder {
+	b.requestLogging = true
+	b.redactParams = redactParams
+	return b
+}
+
+// WithBasePath mounts all HTTP endpoints under the given path prefix
+func (b *ServerBuilder) WithBasePath(path string) *ServerBuilder {
+	b.basePath = path
+	return b
+}
+
+// WithMaxRequestBytes limits the size of HTTP request bodies; zero or less removes the limit
+func (b *ServerBuilder) WithMaxRequestBytes(n int64) *ServerBuilder {
+	b.maxRequestBytes = n
+	return b
+}
+
+// WithMaxConcurrentCalls limits how many tool calls run at once; calls beyond
+// the limit wait if queue is true and are rejected otherwise
+func (b *ServerBuilder) WithMaxConcurrentCalls(n int, queue bool) *ServerBuilder {
+	b.maxConcurrentCalls = n
+	b.queueCalls = queue
+	return b
+}
+
+// WithCallRateLimit limits tool calls to rps per second up to burst, per
+// session or shared by all sessions
+func (b *ServerBuilder) WithCallRateLimit(rps float64, burst int, perSession bool) *ServerBuilder {
+	b.callRate = rps
+	b.callBurst = burst
+	b.callRatePerSession = perSession
+	return b
+}
+
+// WithCapabilities sets the capabilities advertised in the initialize
+// response instead of deriving them from what is registered
+func (b *ServerBuilder) WithCapabilities(names ...string) *ServerBuilder {
+	b.capabilities = append([]string{}, names...)
+	return b
+}
+
+// WithCORS restricts the browser origins allowed to call the HTTP server
+func (b *ServerBuilder) WithCORS(allowedOrigins []string, allowCredentials bool) *ServerBuilder {
+	b.corsOrigins = append([]string{}, allowedOrigins...)
+	b.corsCredentials = allowCredentials
+	return b
+}
+
+// WithHealthPath sets the path of the health check endpoint
+func (b *ServerBuilder) WithHealthPath(path string) *ServerBuilder {
+	b.healthPath = path
+	return b
+}
+
+// WithMethodHandler registers a handler for a custom JSON-RPC method. Handlers
+// for methods reserved by the MCP protocol are ignored.
+func (b *ServerBuilder) WithMethodHandler(method string, handler rest.MethodHandler) *ServerBuilder {
+	if b.methodHandlers == nil {
+		b.methodHandlers = make(map[string]rest.MethodHandler)
+	}
+	b.methodHandlers[method] = handler
+	return b
+}
+
+// WithRequestInterceptor adds an interceptor that runs on every incoming
+// message before method dispatch
+func (b *ServerBuilder) WithRequestInterceptor(interceptor rest.RequestInterceptor) *ServerBuilder {
+	b.interceptors = append(b.interceptors, interceptor)
+	return b
+}
+
+// WithCompletionProvider adds a provider that completes prompt and resource
+// template arguments without their own completer
+func (b *ServerBuilder) WithCompletionProvider(provider domain.CompletionProvider) *ServerBuilder {
+	b.completionProvs = append(b.completionProvs, provider)
+	return b
+}
+
+// WithPromptArgumentCompleter sets the completer for one argument of a prompt
+func (b *ServerBuilder) WithPromptArgumentCompleter(prompt, argument string, completer domain.ArgumentCompleter) *ServerBuilder {
+	b.promptCompleters = append(b.promptCompleters, promptCompleter{prompt: prompt, argument: argument, completer: completer})
+	return b
+}
+
 // WithResourceRepository sets the resource repository
 func (b *ServerBuilder) WithResourceRepository(repo domain.ResourceRepository) *ServerBuilder {
 	b.resourceRepo = repo
 	return b
 }
 
+// WithResourceTemplateRepository sets the resource template repository
+func (b *ServerBuilder) WithResourceTemplateRepository(repo domain.ResourceTemplateRepository) *ServerBuilder {
+	b.templateRepo = repo
+	return b
+}
+
 // WithToolRepository sets the tool repository
 func (b *ServerBuilder) WithToolRepository(repo domain.ToolRepository) *ServerBuilder {
 	b.toolRepo = repo
@@ -92,11 +299,106 @@ func (b *ServerBuilder) WithNotificationSender(sender domain.NotificationSender)
 	return b
 }
 
-// AddTool adds a tool to the server's tool repository
+// AddTool adds a tool to the server's tool repository. Invalid tools and
+// tools whose name is already registered are skipped; the first such error
+// is reported by Err.
 func (b *ServerBuilder) AddTool(ctx context.Context, tool *domain.Tool) *ServerBuilder {
-	if b.toolRepo != nil {
-		_ = b.toolRepo.AddTool(ctx, tool)
+	b.addTool(ctx, tool)
+	return b
+}
+
+// AddToolWithHandler adds a tool to the server's tool repository and registers
+// the handler that executes it
+func (b *ServerBuilder) AddToolWithHandler(ctx context.Context, tool *domain.Tool, handler usecases.ToolHandlerFunc) *ServerBuilder {
+	if b.addTool(ctx, tool) {
+		b.toolHandlers[tool.Name] = handler
+	}
+	return b
+}
+
+// addTool validates a tool and adds it to the tool repository, recording
+// the first error. It reports whether the tool was added.
+func (b *ServerBuilder) addTool(ctx context.Context, tool *domain.Tool) bool {
+	if b.toolRepo == nil {
+		return false
 	}
+
+	err := tool.Validate()
+	if err == nil {
+		if _, getErr := b.toolRepo.GetTool(ctx, tool.Name); getErr == nil {
+			err = domain.ErrDuplicateTool
+		}
+	}
+	if err == nil {
+		err = b.toolRepo.AddTool(ctx, tool)
+	}
+	if err != nil {
+		if b.err == nil {
+			b.err = fmt.Errorf("tool %s: %w", tool.Name, err)
+		}
+		return false
+	}
+	return true
+}
+
+// Err returns the first error registering a tool or prompt, or nil.
+func (b *ServerBuilder) Err() error {
+	return b.err
+}
+
+// AddToolsWithHandlers adds several tools to the server's tool repository and
+// registers the handlers that execute them. handlers[i] executes tools[i].
+// If a tool is invalid or its name is already registered, none are added and
+// the error names that tool. The tools are added under a single repository
+// lock if the repository implements domain.ToolBatchAdder.
+func (b *ServerBuilder) AddToolsWithHandlers(ctx context.Context, tools []*domain.Tool, handlers []usecases.ToolHandlerFunc) error {
+	if b.toolRepo == nil {
+		return nil
+	}
+
+	names := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		if err := tool.Validate(); err != nil {
+			return fmt.Errorf("tool %s: %w", tool.Name, err)
+		}
+		if names[tool.Name] {
+			return fmt.Errorf("tool %s: %w", tool.Name, domain.ErrDuplicateTool)
+		}
+		names[tool.Name] = true
+	}
+
+	if err := b.addToolsToRepo(ctx, tools); err != nil {
+		return err
+	}
+	for i, tool := range tools {
+		b.toolHandlers[tool.Name] = handlers[i]
+	}
+	return nil
+}
+
+// addToolsToRepo adds validated tools with distinct names to the tool
+// repository, atomically if it implements domain.ToolBatchAdder.
+func (b *ServerBuilder) addToolsToRepo(ctx context.Context, tools []*domain.Tool) error {
+	if adder, ok := b.toolRepo.(domain.ToolBatchAdder); ok {
+		return adder.AddTools(ctx, tools)
+	}
+
+	for _, tool := range tools {
+		if _, err := b.toolRepo.GetTool(ctx, tool.Name); err == nil {
+			return fmt.Errorf("tool %s: %w", tool.Name, domain.ErrDuplicateTool)
+		}
+	}
+	for _, tool := range tools {
+		if err := b.toolRepo.AddTool(ctx, tool); err != nil {
+			return fmt.Errorf("tool %s: %w", tool.Name, err)
+		}
+	}
+	return nil
+}
+
+// WithToolHandler registers the handler that executes the named tool
+func (b *ServerBuilder) WithToolHandler(name string, handler usecases.ToolHandlerFunc) *ServerBuilder {
+	b.toolHandlers[name] = handler
 	return b
 }
 
@@ -108,40 +410,214 @@ func (b *ServerBuilder) AddResource(ctx context.Context, resource *domain.Resour
 	return b
 }
 
-// AddPrompt adds a prompt to the server's prompt repository
+// DeleteResource removes a resource from the server's resource repository
+func (b *ServerBuilder) DeleteResource(ctx context.Context, uri string) *ServerBuilder {
+	if b.resourceRepo != nil {
+		_ = b.resourceRepo.DeleteResource(ctx, uri)
+	}
+	return b
+}
+
+// UpdateTool replaces the definition and handler of an existing tool. Once
+// the server has been built, connected clients get a single tool list change
+// notification. It returns a domain.ToolNotFoundError if the tool
+// does not exist.
+func (b *ServerBuilder) UpdateTool(ctx context.Context, tool *domain.Tool, handler usecases.ToolHandlerFunc) error {
+	if b.service != nil {
+		if err := b.service.UpdateTool(ctx, tool, handler); err != nil {
+			return err
+		}
+	} else {
+		if b.toolRepo == nil {
+			return domain.NewToolNotFoundError(tool.Name)
+		}
+		if _, err := b.toolRepo.GetTool(ctx, tool.Name); err != nil {
+			return err
+		}
+		if err := b.toolRepo.AddTool(ctx, tool); err != nil {
+			return err
+		}
+	}
+	b.toolHandlers[tool.Name] = handler
+	return nil
+}
+
+// RemoveTool removes a tool and its handler. Connected clients are notified
+// that the tool list changed once the server has been built. It returns a
+// domain.ToolNotFoundError if the tool does not exist.
+func (b *ServerBuilder) RemoveTool(ctx context.Context, name string) error {
+	delete(b.toolHandlers, name)
+	if b.service != nil {
+		return b.service.DeleteTool(ctx, name)
+	}
+	if b.toolRepo == nil {
+		return domain.NewToolNotFoundError(name)
+	}
+	return b.toolRepo.DeleteTool(ctx, name)
+}
+
+// RemoveResource removes a resource. Connected clients are notified that the
+// resource list changed once the server has been built. It returns a
+// domain.ResourceNotFoundError if the resource does not exist.
+func (b *ServerBuilder) RemoveResource(ctx context.Context, uri string) error {
+	if b.service != nil {
+		return b.service.DeleteResource(ctx, uri)
+	}
+	if b.resourceRepo == nil {
+		return domain.NewResourceNotFoundError(uri)
+	}
+	return b.resourceRepo.DeleteResource(ctx, uri)
+}
+
+// RemovePrompt removes a prompt. Connected clients are notified that the
+// prompt list changed once the server has been built. It returns a
+// domain.PromptNotFoundError if the prompt does not exist.
+func (b *ServerBuilder) RemovePrompt(ctx context.Context, name string) error {
+	if b.service != nil {
+		return b.service.DeletePrompt(ctx, name)
+	}
+	if b.promptRepo == nil {
+		return domain.NewPromptNotFoundError(name)
+	}
+	return b.promptRepo.DeletePrompt(ctx, name)
+}
+
+// NotifyResourceUpdated notifies the sessions subscribed to the resource at
+// uri that it changed. It does nothing before the server has been built.
+func (b *ServerBuilder) NotifyResourceUpdated(ctx context.Context, uri string) error {
+	if b.service == nil {
+		return nil
+	}
+	return b.service.NotifyResourceUpdated(ctx, uri)
+}
+
+// AddResourceTemplate adds a resource template to the server's resource template repository
+func (b *ServerBuilder) AddResourceTemplate(ctx context.Context, template *domain.ResourceTemplate) *ServerBuilder {
+	if b.templateRepo != nil {
+		if _, err := domain.ParseURITemplate(template.URITemplate); err == nil {
+			_ = b.templateRepo.AddResourceTemplate(ctx, template)
+		}
+	}
+	return b
+}
+
+// AddPrompt adds a prompt to the server's prompt repository. Invalid prompts
+// are not added; the first error is reported by Err.
 func (b *ServerBuilder) AddPrompt(ctx context.Context, prompt *domain.Prompt) *ServerBuilder {
-	if b.promptRepo != nil {
-		_ = b.promptRepo.AddPrompt(ctx, prompt)
+	if b.promptRepo == nil {
+		return b
+	}
+	err := prompt.Validate()
+	if err == nil {
+		err = b.promptRepo.AddPrompt(ctx, prompt)
+	}
+	if err != nil && b.err == nil {
+		b.err = fmt.Errorf("prompt %s: %w", prompt.Name, err)
 	}
 	return b
 }
 
-// BuildService builds and returns the server service
-func (b *ServerBuilder) BuildService() *usecases.ServerService {
-	// Create notification sender if not provided
+// NotificationSender returns the notification sender shared by the services
+// the builder builds, creating the default one if none was provided
+func (b *ServerBuilder) NotificationSender() domain.NotificationSender {
 	if b.notificationSender == nil {
 		b.notificationSender = server.NewNotificationSender("2.0")
 	}
+	return b.notificationSender
+}
 
+// BuildService builds and returns the server service
+func (b *ServerBuilder) BuildService() *usecases.ServerService {
 	// Create the server service config
 	config := usecases.ServerConfig{
-		Name:               b.name,
-		Version:            b.version,
-		Instructions:       b.instructions,
-		ResourceRepo:       b.resourceRepo,
-		ToolRepo:           b.toolRepo,
-		PromptRepo:         b.promptRepo,
-		SessionRepo:        b.sessionRepo,
-		NotificationSender: b.notificationSender,
+		Name:                 b.name,
+		Version:              b.version,
+		Instructions:         b.instructions,
+		InstructionsFunc:     b.instructionsFunc,
+		ResourceRepo:         b.resourceRepo,
+		ResourceTemplateRepo: b.templateRepo,
+		ToolRepo:             b.toolRepo,
+		PromptRepo:           b.promptRepo,
+		SessionRepo:          b.sessionRepo,
+		NotificationSender:   b.NotificationSender(),
+		ToolHandlers:         b.toolHandlers,
 	}
 
-	return usecases.NewServerService(config)
+	service := usecases.NewServerService(config)
+	if b.maxConcurrentCalls > 0 {
+		service.SetMaxConcurrentCalls(b.maxConcurrentCalls, b.queueCalls)
+	}
+	if b.callRate > 0 {
+		service.SetCallRateLimit(b.callRate, b.callBurst, b.callRatePerSession)
+	}
+	for _, provider := range b.completionProvs {
+		service.AddCompletionProvider(provider)
+	}
+	for _, c := range b.promptCompleters {
+		ref := domain.CompletionReference{Type: domain.CompletionRefPrompt, Name: c.prompt}
+		service.SetArgumentCompleter(ref, c.argument, c.completer)
+	}
+	b.service = service
+	return service
 }
 
-// BuildMCPServer builds and returns an MCP server
-func (b *ServerBuilder) BuildMCPServer() *rest.MCPServer {
+// BuildMCPServer builds and returns an MCP server. Options are applied after
+// the ones derived from the builder's configuration.
+func (b *ServerBuilder) BuildMCPServer(extra ...rest.MCPServerOption) *rest.MCPServer {
 	service := b.BuildService()
-	return rest.NewMCPServer(service, b.address)
+
+	opts := []rest.MCPServerOption{rest.WithMaxRequestBytes(b.maxRequestBytes)}
+	if b.requestTimeout > 0 {
+		opts = append(opts, rest.WithRequestTimeout(b.requestTimeout))
+	}
+	if b.shutdownGrace > 0 {
+		opts = append(opts, rest.WithShutdownGrace(b.shutdownGrace))
+	}
+	if b.idleTimeout > 0 {
+		opts = append(opts, rest.WithSessionIdleTimeout(b.idleTimeout))
+	}
+	if b.prettyJSON {
+		opts = append(opts, rest.WithPrettyJSON(true))
+	}
+	if b.sseDelivery != server.SSEDeliveryBoth {
+		opts = append(opts, rest.WithSSEDelivery(b.sseDelivery))
+	}
+	if b.batchMax > 1 && b.batchWindow > 0 {
+		opts = append(opts, rest.WithEventBatching(b.batchMax, b.batchWindow))
+	}
+	if b.pingDiagnostics {
+		opts = append(opts, rest.WithPingDiagnostics())
+	}
+	if b.tracer != nil {
+		opts = append(opts, rest.WithTracer(b.tracer))
+	}
+	if b.metrics != nil {
+		opts = append(opts, rest.WithMetrics(b.metrics))
+	}
+	if b.requestLogging {
+		opts = append(opts, rest.WithRequestLogging(b.redactParams...))
+	}
+	if b.basePath != "" {
+		opts = append(opts, rest.WithPathPrefix(b.basePath))
+	}
+	if b.healthPath != "" {
+		opts = append(opts, rest.WithHealthPath(b.healthPath))
+	}
+	if b.capabilities != nil {
+		opts = append(opts, rest.WithCapabilities(b.capabilities...))
+	}
+	if b.corsOrigins != nil {
+		opts = append(opts, rest.WithCORS(b.corsOrigins, b.corsCredentials))
+	}
+	for _, interceptor := range b.interceptors {
+		opts = append(opts, rest.WithRequestInterceptor(interceptor))
+	}
+	opts = append(opts, extra...)
+	mcpServer := rest.NewMCPServer(service, b.address, opts...)
+	for method, handler := range b.methodHandlers {
+		_ = mcpServer.AddMethodHandler(method, handler)
+	}
+	return mcpServer
 }
 
 // BuildStdioServer builds a stdio server that uses the MCP server
@@ -152,6 +628,10 @@ func (b *ServerBuilder) BuildStdioServer(opts ...stdio.StdioOption) *stdio.Stdio
 
 // ServeStdio builds and starts serving a stdio server
 func (b *ServerBuilder) ServeStdio(opts ...stdio.StdioOption) error {
+	if b.err != nil {
+		return b.err
+	}
+
 	// Create a default logger for stdio
 	logger, err := logging.New(logging.Config{
 		Level:       logging.InfoLevel,
@@ -6,8 +6,10 @@ import (
 
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/usecases"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockResourceRepository is a mock for the ResourceRepository interface
@@ -231,6 +233,7 @@ func TestServerBuilder_AddTool(t *testing.T) {
 	mockRepo := new(MockToolRepository)
 	builder.toolRepo = mockRepo
 
+	mockRepo.On("GetTool", ctx, tool.Name).Return((*domain.Tool)(nil), domain.NewToolNotFoundError(tool.Name))
 	mockRepo.On("AddTool", ctx, tool).Return(nil)
 
 	result = builder.AddTool(ctx, tool)
@@ -238,6 +241,51 @@ func TestServerBuilder_AddTool(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestServerBuilder_AddToolsWithHandlers(t *testing.T) {
+	builder := NewServerBuilder()
+	mockRepo := new(MockToolRepository)
+	builder.toolRepo = mockRepo
+	ctx := context.Background()
+
+	first := &domain.Tool{Name: "first"}
+	second := &domain.Tool{Name: "second"}
+	mockRepo.On("GetTool", ctx, "first").Return((*domain.Tool)(nil), domain.NewToolNotFoundError("first"))
+	mockRepo.On("GetTool", ctx, "second").Return((*domain.Tool)(nil), domain.NewToolNotFoundError("second"))
+	mockRepo.On("AddTool", ctx, first).Return(nil)
+	mockRepo.On("AddTool", ctx, second).Return(nil)
+
+	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) { return nil, nil }
+	err := builder.AddToolsWithHandlers(ctx, []*domain.Tool{first, second}, []usecases.ToolHandlerFunc{handler, handler})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	assert.Contains(t, builder.toolHandlers, "first")
+	assert.Contains(t, builder.toolHandlers, "second")
+}
+
+func TestServerBuilder_AddToolsWithHandlersIsAllOrNothing(t *testing.T) {
+	builder := NewServerBuilder()
+	ctx := context.Background()
+	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) { return nil, nil }
+	require.NoError(t, builder.AddToolsWithHandlers(ctx, []*domain.Tool{{Name: "taken"}}, []usecases.ToolHandlerFunc{handler}))
+
+	// A name already in the repository rejects the whole batch
+	err := builder.AddToolsWithHandlers(ctx,
+		[]*domain.Tool{{Name: "fresh"}, {Name: "taken"}},
+		[]usecases.ToolHandlerFunc{handler, handler})
+	assert.ErrorIs(t, err, domain.ErrDuplicateTool)
+	assert.ErrorContains(t, err, "taken")
+	_, err = builder.toolRepo.GetTool(ctx, "fresh")
+	assert.Error(t, err, "no tool of a rejected batch should be added")
+	assert.NotContains(t, builder.toolHandlers, "fresh")
+
+	// So does a name repeated within the batch
+	err = builder.AddToolsWithHandlers(ctx,
+		[]*domain.Tool{{Name: "twice"}, {Name: "twice"}},
+		[]usecases.ToolHandlerFunc{handler, handler})
+	assert.ErrorIs(t, err, domain.ErrDuplicateTool)
+	assert.NotContains(t, builder.toolHandlers, "twice")
+}
+
 func TestServerBuilder_AddResource(t *testing.T) {
 	// Test with nil resourceRepo
 	builderWithNilRepo := &ServerBuilder{resourceRepo: nil}
@@ -280,6 +328,76 @@ func TestServerBuilder_AddPrompt(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestServerBuilder_AddToolRejectsInvalidTools(t *testing.T) {
+	ctx := context.Background()
+	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) { return nil, nil }
+
+	builder := NewServerBuilder().AddToolWithHandler(ctx, &domain.Tool{Name: "echo"}, handler)
+	assert.NoError(t, builder.Err())
+
+	// Duplicate names are rejected and keep the first handler
+	builder.AddToolWithHandler(ctx, &domain.Tool{Name: "echo", Description: "second"}, handler)
+	assert.ErrorIs(t, builder.Err(), domain.ErrDuplicateTool)
+	assert.EqualError(t, builder.Err(), "tool echo: tool already registered")
+	tool, err := builder.toolRepo.GetTool(ctx, "echo")
+	if assert.NoError(t, err) {
+		assert.Empty(t, tool.Description)
+	}
+
+	// Unknown parameter types are rejected; only the first error is kept
+	builder = NewServerBuilder().AddTool(ctx, &domain.Tool{
+		Name:       "weather",
+		Parameters: []domain.ToolParameter{{Name: "days", Type: "int"}},
+	})
+	var validationErr *domain.ValidationError
+	if assert.ErrorAs(t, builder.Err(), &validationErr) {
+		assert.Equal(t, "days", validationErr.Field)
+	}
+	builder.AddTool(ctx, &domain.Tool{Name: "search", Parameters: []domain.ToolParameter{{Type: "string"}}})
+	assert.Contains(t, builder.Err().Error(), "tool weather:")
+	_, err = builder.toolRepo.GetTool(ctx, "weather")
+	assert.Error(t, err)
+	assert.Equal(t, builder.Err(), builder.ServeStdio())
+}
+
+func TestServerBuilder_RemoveTool(t *testing.T) {
+	ctx := context.Background()
+	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) { return nil, nil }
+	builder := NewServerBuilder().AddToolWithHandler(ctx, &domain.Tool{Name: "test-tool"}, handler)
+
+	// Removal after the service is built notifies clients
+	mockSender := new(MockNotificationSender)
+	mockSender.On("BroadcastNotification", ctx, mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.Method == domain.ToolListChangedMethod
+	})).Return(nil).Once()
+	builder.WithNotificationSender(mockSender)
+	builder.BuildService()
+
+	err := builder.RemoveTool(ctx, "test-tool")
+	assert.NoError(t, err)
+	assert.NotContains(t, builder.toolHandlers, "test-tool")
+	mockSender.AssertExpectations(t)
+
+	// Removing a missing tool fails without notifying
+	err = builder.RemoveTool(ctx, "test-tool")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	mockSender.AssertNumberOfCalls(t, "BroadcastNotification", 1)
+}
+
+func TestServerBuilder_RemoveResourceAndPrompt(t *testing.T) {
+	ctx := context.Background()
+	builder := NewServerBuilder().
+		AddResource(ctx, &domain.Resource{URI: "test://resource"}).
+		AddPrompt(ctx, &domain.Prompt{Name: "test-prompt"})
+
+	// Removal before the service is built goes to the repositories
+	assert.NoError(t, builder.RemoveResource(ctx, "test://resource"))
+	assert.NoError(t, builder.RemovePrompt(ctx, "test-prompt"))
+
+	assert.ErrorIs(t, builder.RemoveResource(ctx, "test://resource"), domain.ErrNotFound)
+	assert.ErrorIs(t, builder.RemovePrompt(ctx, "test-prompt"), domain.ErrNotFound)
+}
+
 func TestServerBuilder_BuildService(t *testing.T) {
 	// Test with nil notificationSender
 	builder := NewServerBuilder().
@@ -305,6 +423,20 @@ func TestServerBuilder_BuildService(t *testing.T) {
 	assert.NotNil(t, service)
 }
 
+func TestServerBuilder_WithPromptArgumentCompleter(t *testing.T) {
+	builder := NewServerBuilder().WithPromptArgumentCompleter("review", "language",
+		func(ctx context.Context, value string) ([]string, error) {
+			return []string{"go", "python"}, nil
+		})
+
+	service := builder.BuildService()
+	ref := domain.CompletionReference{Type: domain.CompletionRefPrompt, Name: "review"}
+	result, err := service.Complete(context.Background(), ref, domain.CompletionArgument{Name: "language"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "python"}, result.Values)
+}
+
 func TestServerBuilder_BuildMCPServer(t *testing.T) {
 	builder := NewServerBuilder().
 		WithName("Test Server").
@@ -0,0 +1,39 @@
+package domain
+
+import "context"
+
+// ClientLogFunc sends a log message at the given MCP level, such as
+// "warning", to the client that made the request. logger names the source of
+// the message and may be empty.
+type ClientLogFunc func(ctx context.Context, level, logger string, data interface{}) error
+
+type clientLogKey struct{}
+
+// WithClientLog returns a context carrying the function that forwards log
+// messages to the client that made the request.
+func WithClientLog(ctx context.Context, log ClientLogFunc) context.Context {
+	return context.WithValue(ctx, clientLogKey{}, log)
+}
+
+// ClientLogFromContext returns the function that forwards log messages to
+// the client, if the request can send them.
+func ClientLogFromContext(ctx context.Context) (ClientLogFunc, bool) {
+	log, ok := ctx.Value(clientLogKey{}).(ClientLogFunc)
+	return log, ok && log != nil
+}
+
+// NewLogMessageNotification creates a notifications/message notification.
+// An empty logger name is omitted.
+func NewLogMessageNotification(level, logger string, data interface{}) *Notification {
+	params := map[string]interface{}{
+		"level": level,
+		"data":  data,
+	}
+	if logger != "" {
+		params["logger"] = logger
+	}
+	return &Notification{
+		Method: "notifications/message",
+		Params: params,
+	}
+}
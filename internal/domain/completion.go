@@ -0,0 +1,66 @@
+package domain
+
+import "context"
+
+// MaxCompletionValues is the most completion values returned in one response.
+const MaxCompletionValues = 100
+
+// Completion reference types.
+const (
+	CompletionRefPrompt   = "ref/prompt"
+	CompletionRefResource = "ref/resource"
+)
+
+// CompletionReference identifies what an argument is being completed for:
+// a prompt by Name or a resource template by URI.
+type CompletionReference struct {
+	Type string
+	Name string
+	URI  string
+}
+
+// Key returns the name or URI that identifies the referenced prompt or
+// resource template.
+func (r CompletionReference) Key() string {
+	if r.Type == CompletionRefResource {
+		return r.URI
+	}
+	return r.Name
+}
+
+// CompletionArgument is the argument being completed and its partial value.
+type CompletionArgument struct {
+	Name  string
+	Value string
+}
+
+// CompletionProvider suggests values for an argument of a prompt or resource
+// template. It returns nil values for references it does not handle.
+type CompletionProvider interface {
+	Complete(ctx context.Context, ref CompletionReference, arg CompletionArgument) ([]string, error)
+}
+
+// ArgumentCompleter suggests values for a single argument given its partial
+// value.
+type ArgumentCompleter func(ctx context.Context, value string) ([]string, error)
+
+// CompletionResult is the response to a completion request.
+type CompletionResult struct {
+	Values  []string
+	Total   int
+	HasMore bool
+}
+
+// NewCompletionResult builds a result from all candidate values, keeping at
+// most MaxCompletionValues of them.
+func NewCompletionResult(values []string) CompletionResult {
+	result := CompletionResult{Values: values, Total: len(values)}
+	if result.Values == nil {
+		result.Values = []string{}
+	}
+	if len(values) > MaxCompletionValues {
+		result.Values = values[:MaxCompletionValues]
+		result.HasMore = true
+	}
+	return result
+}
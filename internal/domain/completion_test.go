@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNewCompletionResult(t *testing.T) {
+	if result := NewCompletionResult(nil); result.Values == nil || result.Total != 0 || result.HasMore {
+		t.Errorf("NewCompletionResult(nil) = %+v, want empty values", result)
+	}
+
+	values := make([]string, MaxCompletionValues+5)
+	for i := range values {
+		values[i] = fmt.Sprintf("v%d", i)
+	}
+	result := NewCompletionResult(values)
+	if len(result.Values) != MaxCompletionValues || result.Total != len(values) || !result.HasMore {
+		t.Errorf("NewCompletionResult() = %d values, total %d, hasMore %v; want %d, %d, true",
+			len(result.Values), result.Total, result.HasMore, MaxCompletionValues, len(values))
+	}
+}
+
+func TestCompletionReference_Key(t *testing.T) {
+	if key := (CompletionReference{Type: CompletionRefPrompt, Name: "greet"}).Key(); key != "greet" {
+		t.Errorf("prompt Key() = %q, want greet", key)
+	}
+	if key := (CompletionReference{Type: CompletionRefResource, URI: "file:///{path}"}).Key(); key != "file:///{path}" {
+		t.Errorf("resource Key() = %q, want file:///{path}", key)
+	}
+}
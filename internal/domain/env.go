@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+type envKey struct{}
+
+// WithEnv returns a context carrying environment variables captured for handlers.
+func WithEnv(ctx context.Context, env map[string]string) context.Context {
+	return context.WithValue(ctx, envKey{}, env)
+}
+
+// EnvFromContext returns the value of a captured environment variable. It
+// reports false if the variable was not captured or not set.
+func EnvFromContext(ctx context.Context, key string) (string, bool) {
+	env, _ := ctx.Value(envKey{}).(map[string]string)
+	value, ok := env[key]
+	return value, ok
+}
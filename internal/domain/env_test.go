@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEnvFromContext(t *testing.T) {
+	if _, ok := EnvFromContext(context.Background(), "API_KEY"); ok {
+		t.Error("EnvFromContext() should report no value on a bare context")
+	}
+
+	ctx := WithEnv(context.Background(), map[string]string{"API_KEY": "secret", "EMPTY": ""})
+	if value, ok := EnvFromContext(ctx, "API_KEY"); !ok || value != "secret" {
+		t.Errorf("EnvFromContext(API_KEY) = %q, %v, want secret, true", value, ok)
+	}
+	if value, ok := EnvFromContext(ctx, "EMPTY"); !ok || value != "" {
+		t.Errorf("EnvFromContext(EMPTY) = %q, %v, want empty, true", value, ok)
+	}
+	if _, ok := EnvFromContext(ctx, "MISSING"); ok {
+		t.Error("EnvFromContext(MISSING) should report the variable as missing")
+	}
+}
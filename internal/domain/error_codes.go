@@ -0,0 +1,23 @@
+package domain
+
+// JSON-RPC error codes sent to clients. JSON-RPC 2.0 reserves -32768 to
+// -32000; codes from -32099 to -32000 are left for server-defined errors.
+const (
+	ParseErrorCode     = -32700
+	InvalidRequestCode = -32600
+	MethodNotFoundCode = -32601
+	InvalidParamsCode  = -32602
+	InternalErrorCode  = -32603
+
+	// ServerErrorCode is sent when the server cannot take the request right
+	// now, e.g. because it is busy, rate limited or draining.
+	ServerErrorCode = -32000
+	// MaintenanceCode is sent for requests rejected while the server is in
+	// maintenance mode.
+	MaintenanceCode = -32001
+	// RateLimitedCode is sent for tool calls over the tool's own rate limit.
+	RateLimitedCode = -32029
+	// ResourceNotFoundCode is the MCP code for a resource URI that does not
+	// exist.
+	ResourceNotFoundCode = -32002
+)
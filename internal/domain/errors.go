@@ -1,6 +1,9 @@
 package domain
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Common domain errors
 var (
@@ -9,6 +12,18 @@ var (
 	ErrInvalidInput   = NewError("invalid input", 400)
 	ErrInternal       = NewError("internal server error", 500)
 	ErrNotImplemented = NewError("not implemented", 501)
+
+	// ErrNoContentProvider is returned when reading a resource that has no content provider.
+	ErrNoContentProvider = NewError("no content provider", 501)
+
+	// ErrNoPromptTemplate is returned when rendering a prompt that has no template.
+	ErrNoPromptTemplate = NewError("no prompt template", 501)
+
+	// ErrInvalidToolOutput is returned when a tool result does not match its output schema.
+	ErrInvalidToolOutput = NewError("tool output does not match output schema", 500)
+
+	// ErrDuplicateTool is returned when registering a tool whose name is already taken.
+	ErrDuplicateTool = NewError("tool already registered", 409)
 )
 
 // Error represents a domain error with an associated code.
@@ -41,6 +56,11 @@ func (e *ResourceNotFoundError) Error() string {
 	return e.Err.Error()
 }
 
+// Is reports whether target is ErrNotFound.
+func (e *ResourceNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
 // NewResourceNotFoundError creates a new ResourceNotFoundError.
 func NewResourceNotFoundError(uri string) *ResourceNotFoundError {
 	return &ResourceNotFoundError{
@@ -63,6 +83,11 @@ func (e *ToolNotFoundError) Error() string {
 	return e.Err.Error()
 }
 
+// Is reports whether target is ErrNotFound.
+func (e *ToolNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
 // NewToolNotFoundError creates a new ToolNotFoundError.
 func NewToolNotFoundError(name string) *ToolNotFoundError {
 	return &ToolNotFoundError{
@@ -85,6 +110,11 @@ func (e *PromptNotFoundError) Error() string {
 	return e.Err.Error()
 }
 
+// Is reports whether target is ErrNotFound.
+func (e *PromptNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
 // NewPromptNotFoundError creates a new PromptNotFoundError.
 func NewPromptNotFoundError(name string) *PromptNotFoundError {
 	return &PromptNotFoundError{
@@ -141,3 +171,116 @@ func NewValidationError(field, message string) *ValidationError {
 		),
 	}
 }
+
+// Scopes of the tool call rate limit, see RateLimitError.
+const (
+	RateLimitScopeSession = "session"
+	RateLimitScopeServer  = "server"
+)
+
+// RateLimitError indicates that a rate limit was exceeded.
+type RateLimitError struct {
+	Scope string
+	// RetryAfter is how long until a call would be allowed, if known.
+	RetryAfter time.Duration
+	Err        *Error
+}
+
+// Error returns the error message.
+func (e *RateLimitError) Error() string {
+	return e.Err.Error()
+}
+
+// NewRateLimitError creates a new RateLimitError for the given scope.
+func NewRateLimitError(scope string) *RateLimitError {
+	return &RateLimitError{
+		Scope: scope,
+		Err: NewError(
+			fmt.Sprintf("rate limit exceeded for %s", scope),
+			429,
+		),
+	}
+}
+
+// ServerBusyError indicates that a call was rejected because the server was
+// already running as many calls as it allows.
+type ServerBusyError struct {
+	Limit int
+	Err   *Error
+}
+
+// Error returns the error message.
+func (e *ServerBusyError) Error() string {
+	return e.Err.Error()
+}
+
+// NewServerBusyError creates a new ServerBusyError for the given concurrency limit.
+func NewServerBusyError(limit int) *ServerBusyError {
+	return &ServerBusyError{
+		Limit: limit,
+		Err: NewError(
+			fmt.Sprintf("server busy: %d calls already in progress", limit),
+			503,
+		),
+	}
+}
+
+// TimeoutError indicates that a request did not complete within its timeout.
+type TimeoutError struct {
+	Timeout time.Duration
+	Err     *Error
+}
+
+// Error returns the error message.
+func (e *TimeoutError) Error() string {
+	return e.Err.Error()
+}
+
+// NewTimeoutError creates a new TimeoutError for the given timeout.
+func NewTimeoutError(timeout time.Duration) *TimeoutError {
+	return &TimeoutError{
+		Timeout: timeout,
+		Err: NewError(
+			fmt.Sprintf("request timed out after %s", timeout),
+			504,
+		),
+	}
+}
+
+// ToolError is returned by a tool handler to control the JSON-RPC error sent
+// to the client. A zero Code is sent as -32603 (internal error).
+type ToolError struct {
+	Code    int
+	Message string
+	Data    interface{}
+}
+
+// Error returns the error message.
+func (e *ToolError) Error() string {
+	return e.Message
+}
+
+// JSONRPCCode returns the JSON-RPC error code to send for the error.
+func (e *ToolError) JSONRPCCode() int {
+	if e.Code == 0 {
+		return InternalErrorCode
+	}
+	return e.Code
+}
+
+// PanicError indicates that a handler panicked. Stack holds the stack trace
+// of the panicking goroutine.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Error returns the error message.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("handler panicked: %v", e.Value)
+}
+
+// NewPanicError creates a new PanicError for a recovered panic value.
+func NewPanicError(value interface{}, stack []byte) *PanicError {
+	return &PanicError{Value: value, Stack: stack}
+}
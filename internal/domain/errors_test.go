@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -60,6 +61,9 @@ func TestResourceNotFoundError(t *testing.T) {
 	if err.Error() == "" {
 		t.Error("NewResourceNotFoundError().Error() should not return empty string")
 	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("NewResourceNotFoundError() should match ErrNotFound")
+	}
 }
 
 func TestToolNotFoundError(t *testing.T) {
@@ -78,6 +82,9 @@ func TestToolNotFoundError(t *testing.T) {
 	if err.Error() == "" {
 		t.Error("NewToolNotFoundError().Error() should not return empty string")
 	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("NewToolNotFoundError() should match ErrNotFound")
+	}
 }
 
 func TestPromptNotFoundError(t *testing.T) {
@@ -96,6 +103,9 @@ func TestPromptNotFoundError(t *testing.T) {
 	if err.Error() == "" {
 		t.Error("NewPromptNotFoundError().Error() should not return empty string")
 	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("NewPromptNotFoundError() should match ErrNotFound")
+	}
 }
 
 func TestSessionNotFoundError(t *testing.T) {
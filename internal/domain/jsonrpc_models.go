@@ -1,6 +1,15 @@
 package domain
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
 // JSONRPCRequest represents a JSON-RPC request in the domain layer.
+// Decoded string and numeric IDs are RequestIDs, so they are echoed back in
+// the form the client sent them.
 type JSONRPCRequest struct {
 	JSONRPC string      `json:"jsonrpc"`
 	ID      interface{} `json:"id"`
@@ -8,6 +17,29 @@ type JSONRPCRequest struct {
 	Params  interface{} `json:"params,omitempty"`
 }
 
+// UnmarshalJSON decodes a request, keeping a string or numeric ID as a
+// RequestID. A missing or null ID is left nil.
+func (r *JSONRPCRequest) UnmarshalJSON(data []byte) error {
+	type plainRequest JSONRPCRequest
+	var decoded struct {
+		plainRequest
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*r = JSONRPCRequest(decoded.plainRequest)
+
+	if len(decoded.ID) == 0 || string(decoded.ID) == "null" {
+		r.ID = nil
+	} else if id, ok := ParseRequestID(decoded.ID); ok {
+		r.ID = id
+	} else if err := json.Unmarshal(decoded.ID, &r.ID); err != nil {
+		return err
+	}
+	return nil
+}
+
 // JSONRPCResponse represents a JSON-RPC response in the domain layer.
 type JSONRPCResponse struct {
 	JSONRPC string        `json:"jsonrpc"`
@@ -43,3 +75,80 @@ func CreateErrorResponse(jsonrpcVersion string, id interface{}, code int, messag
 		},
 	}
 }
+
+// CreateErrorResponseWithData creates a new JSONRPCResponse with an error
+// that carries additional structured data.
+func CreateErrorResponseWithData(jsonrpcVersion string, id interface{}, code int, message string, data interface{}) JSONRPCResponse {
+	response := CreateErrorResponse(jsonrpcVersion, id, code, message)
+	response.Error.Data = data
+	return response
+}
+
+// ValidateJSONRPCRequest checks that a raw message structurally conforms to a
+// JSON-RPC 2.0 request or notification. It verifies that jsonrpc is "2.0",
+// method is a non-empty string, id (when present) is a string, number or null,
+// and params (when present) is an object or array. Messages whose method does
+// not start with "notifications/" must carry an id.
+func ValidateJSONRPCRequest(rawMessage []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(rawMessage, &fields); err != nil {
+		return fmt.Errorf("request must be a JSON object")
+	}
+
+	var version string
+	if raw, ok := fields["jsonrpc"]; !ok {
+		return fmt.Errorf("missing 'jsonrpc' member")
+	} else if err := json.Unmarshal(raw, &version); err != nil || version != "2.0" {
+		return fmt.Errorf("'jsonrpc' must be exactly \"2.0\"")
+	}
+
+	var method string
+	if raw, ok := fields["method"]; !ok {
+		return fmt.Errorf("missing 'method' member")
+	} else if err := json.Unmarshal(raw, &method); err != nil {
+		return fmt.Errorf("'method' must be a string")
+	} else if method == "" {
+		return fmt.Errorf("'method' must not be empty")
+	}
+
+	if raw, ok := fields["id"]; ok {
+		switch jsonKind(raw) {
+		case '"', 'n', '0':
+		default:
+			return fmt.Errorf("'id' must be a string, number or null")
+		}
+	} else if !strings.HasPrefix(method, "notifications/") {
+		return fmt.Errorf("missing 'id' member for request method '%s'", method)
+	}
+
+	if raw, ok := fields["params"]; ok {
+		switch jsonKind(raw) {
+		case '{', '[':
+		default:
+			return fmt.Errorf("'params' must be an object or array")
+		}
+	}
+
+	return nil
+}
+
+// jsonKind returns a byte identifying the kind of a raw JSON value: '{' for
+// objects, '[' for arrays, '"' for strings, '0' for numbers, 'n' for null,
+// 'b' for booleans and 0 for anything else.
+func jsonKind(raw json.RawMessage) byte {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return 0
+	}
+	switch c := trimmed[0]; {
+	case c == '{' || c == '[' || c == '"':
+		return c
+	case c == 'n':
+		return 'n'
+	case c == 't' || c == 'f':
+		return 'b'
+	case c == '-' || (c >= '0' && c <= '9'):
+		return '0'
+	}
+	return 0
+}
@@ -112,3 +112,34 @@ func TestJSONRPCRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateJSONRPCRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		wantErr bool
+	}{
+		{"Valid request", `{"jsonrpc":"2.0","id":1,"method":"ping"}`, false},
+		{"Valid string id with params", `{"jsonrpc":"2.0","id":"a","method":"tools/call","params":{"name":"x"}}`, false},
+		{"Valid array params", `{"jsonrpc":"2.0","id":1,"method":"m","params":[1,2]}`, false},
+		{"Valid notification", `{"jsonrpc":"2.0","method":"notifications/initialized"}`, false},
+		{"Not an object", `[1,2]`, true},
+		{"Missing jsonrpc", `{"id":1,"method":"ping"}`, true},
+		{"Wrong jsonrpc version", `{"jsonrpc":"1.0","id":1,"method":"ping"}`, true},
+		{"Numeric method", `{"jsonrpc":"2.0","id":1,"method":5}`, true},
+		{"Empty method", `{"jsonrpc":"2.0","id":1,"method":""}`, true},
+		{"Object id", `{"jsonrpc":"2.0","id":{},"method":"ping"}`, true},
+		{"Boolean id", `{"jsonrpc":"2.0","id":true,"method":"ping"}`, true},
+		{"Missing id for request", `{"jsonrpc":"2.0","method":"ping"}`, true},
+		{"String params", `{"jsonrpc":"2.0","id":1,"method":"ping","params":"x"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJSONRPCRequest([]byte(tt.message))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateJSONRPCRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "strings"
+
+// DescriptionFor returns the tool description for the given locale. It tries
+// the exact locale, then its base language (e.g. "fr" for "fr-CA"), and
+// falls back to the default description.
+func (t *Tool) DescriptionFor(locale string) string {
+	return localizedText(t.Description, t.LocalizedDescriptions, locale)
+}
+
+// LocaleFromInitializeParams extracts the locale a client requested in the
+// initializationOptions of an initialize request, if any.
+func LocaleFromInitializeParams(params interface{}) string {
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	options, ok := paramsMap["initializationOptions"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	locale, _ := options["locale"].(string)
+	return locale
+}
+
+// localizedText picks the translation matching the locale, falling back to
+// the base language and then the default text.
+func localizedText(defaultText string, translations map[string]string, locale string) string {
+	if locale == "" || len(translations) == 0 {
+		return defaultText
+	}
+	if text, ok := translations[locale]; ok {
+		return text
+	}
+	if base, _, found := strings.Cut(locale, "-"); found {
+		if text, ok := translations[base]; ok {
+			return text
+		}
+	}
+	return defaultText
+}
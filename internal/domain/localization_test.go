@@ -0,0 +1,44 @@
+package domain
+
+import "testing"
+
+func TestTool_DescriptionFor(t *testing.T) {
+	tool := &Tool{
+		Description: "Search documents",
+		LocalizedDescriptions: map[string]string{
+			"fr":    "Rechercher des documents",
+			"pt-BR": "Pesquisar documentos",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		locale string
+		want   string
+	}{
+		{"No locale", "", "Search documents"},
+		{"Exact match", "pt-BR", "Pesquisar documentos"},
+		{"Base language", "fr-CA", "Rechercher des documents"},
+		{"Unknown locale", "de", "Search documents"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tool.DescriptionFor(tt.locale); got != tt.want {
+				t.Errorf("DescriptionFor(%q) = %v, want %v", tt.locale, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocaleFromInitializeParams(t *testing.T) {
+	params := map[string]interface{}{
+		"initializationOptions": map[string]interface{}{"locale": "ja"},
+	}
+	if got := LocaleFromInitializeParams(params); got != "ja" {
+		t.Errorf("LocaleFromInitializeParams() = %v, want ja", got)
+	}
+	if got := LocaleFromInitializeParams(map[string]interface{}{}); got != "" {
+		t.Errorf("LocaleFromInitializeParams() = %v, want empty", got)
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// MetricsCollector records request metrics. Implement it to send metrics to
+// a custom backend. A collector that also implements http.Handler is served
+// at the /metrics endpoint.
+type MetricsCollector interface {
+	// ObserveRequest records one processed JSON-RPC message with its method,
+	// processing time and whether it produced an error response.
+	ObserveRequest(method string, duration time.Duration, failed bool)
+}
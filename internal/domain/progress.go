@@ -0,0 +1,58 @@
+package domain
+
+import "context"
+
+// ProgressReporter sends a progress update for the request in flight.
+type ProgressReporter func(ctx context.Context, progress, total float64) error
+
+type sessionIDKey struct{}
+
+type progressKey struct{}
+
+type progressContext struct {
+	token    interface{}
+	reporter ProgressReporter
+}
+
+// WithSessionID returns a context carrying the ID of the session the request came from.
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDKey{}, sessionID)
+}
+
+// SessionIDFromContext returns the ID of the session the request came from.
+func SessionIDFromContext(ctx context.Context) (string, bool) {
+	sessionID, ok := ctx.Value(sessionIDKey{}).(string)
+	return sessionID, ok && sessionID != ""
+}
+
+// WithProgress returns a context carrying the request's progress token and
+// the reporter used to send progress notifications for it.
+func WithProgress(ctx context.Context, token interface{}, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressKey{}, progressContext{token: token, reporter: reporter})
+}
+
+// ProgressFromContext returns the progress token and reporter for the request,
+// if the client asked for progress updates.
+func ProgressFromContext(ctx context.Context) (interface{}, ProgressReporter, bool) {
+	p, ok := ctx.Value(progressKey{}).(progressContext)
+	if !ok || p.token == nil || p.reporter == nil {
+		return nil, nil, false
+	}
+	return p.token, p.reporter, true
+}
+
+// NewProgressNotification creates a notifications/progress notification.
+// A total of zero or less is omitted, meaning the total is unknown.
+func NewProgressNotification(token interface{}, progress, total float64) *Notification {
+	params := map[string]interface{}{
+		"progressToken": token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	return &Notification{
+		Method: "notifications/progress",
+		Params: params,
+	}
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"testing"
+)
+
+func TestProgressFromContext(t *testing.T) {
+	ctx := context.Background()
+	if _, _, ok := ProgressFromContext(ctx); ok {
+		t.Error("ProgressFromContext() should report no progress for a bare context")
+	}
+
+	var got []float64
+	reporter := func(ctx context.Context, progress, total float64) error {
+		got = append(got, progress, total)
+		return nil
+	}
+
+	if _, _, ok := ProgressFromContext(WithProgress(ctx, nil, reporter)); ok {
+		t.Error("ProgressFromContext() should report no progress without a token")
+	}
+
+	token, report, ok := ProgressFromContext(WithProgress(ctx, "tok-1", reporter))
+	if !ok {
+		t.Fatal("ProgressFromContext() should return the reporter")
+	}
+	if token != "tok-1" {
+		t.Errorf("ProgressFromContext() token = %v, want %v", token, "tok-1")
+	}
+	if err := report(ctx, 1, 2); err != nil {
+		t.Errorf("report() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("report() got %v, want [1 2]", got)
+	}
+}
+
+func TestNewProgressNotification(t *testing.T) {
+	n := NewProgressNotification(7, 3, 10)
+	if n.Method != "notifications/progress" {
+		t.Errorf("Method = %v, want notifications/progress", n.Method)
+	}
+	if n.Params["progressToken"] != 7 || n.Params["progress"] != 3.0 || n.Params["total"] != 10.0 {
+		t.Errorf("Params = %v", n.Params)
+	}
+
+	if _, ok := NewProgressNotification(7, 3, 0).Params["total"]; ok {
+		t.Error("Params should omit an unknown total")
+	}
+}
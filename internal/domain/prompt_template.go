@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// promptPlaceholder matches {{param}} placeholders in prompt templates.
+var promptPlaceholder = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*\}\}`)
+
+// Validate checks the prompt's definition when it is registered. The prompt
+// must have a name, and every parameter must have a unique name and either
+// no type or one of the tool parameter types.
+func (p *Prompt) Validate() error {
+	if p.Name == "" {
+		return NewValidationError("name", "prompt name is required")
+	}
+	seen := make(map[string]bool, len(p.Parameters))
+	for _, param := range p.Parameters {
+		if param.Name == "" {
+			return NewValidationError("parameters", "parameter name is required")
+		}
+		if seen[param.Name] {
+			return NewValidationError(param.Name, "duplicate parameter")
+		}
+		seen[param.Name] = true
+		if param.Type != "" && !parameterTypes[param.Type] {
+			return NewValidationError(param.Name, fmt.Sprintf("unsupported type %q, want string, number, integer, boolean, object or array", param.Type))
+		}
+	}
+	return nil
+}
+
+// ValidateArguments checks the arguments of a prompts/get request against
+// the prompt's parameters. Required parameters must be present and typed
+// parameters must match their type. Since clients usually send prompt
+// arguments as strings, a string supplied for a non-string parameter is
+// decoded as JSON first, so "42" satisfies an integer parameter. It returns
+// a ValidationError naming the offending argument.
+func (p *Prompt) ValidateArguments(args map[string]interface{}) error {
+	for _, param := range p.Parameters {
+		value, ok := args[param.Name]
+		if !ok || value == nil {
+			if param.Required {
+				return NewValidationError(param.Name, "required parameter is missing")
+			}
+			continue
+		}
+		if param.Type == "" {
+			continue
+		}
+		if s, ok := value.(string); ok && param.Type != "string" {
+			var decoded interface{}
+			if err := json.Unmarshal([]byte(s), &decoded); err == nil {
+				value = decoded
+			}
+		}
+		if err := validateValue(param.Name, ToolParameter{Type: param.Type}, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render substitutes the {{param}} placeholders in the prompt template with the
+// given arguments. Arguments are checked with ValidateArguments, and
+// placeholders that are neither declared parameters nor supplied arguments are
+// reported as an error instead of being passed through.
+func (p *Prompt) Render(args map[string]interface{}) (string, error) {
+	if err := p.ValidateArguments(args); err != nil {
+		return "", err
+	}
+	declared := make(map[string]bool, len(p.Parameters))
+	for _, param := range p.Parameters {
+		declared[param.Name] = true
+	}
+
+	unresolved := map[string]bool{}
+	rendered := promptPlaceholder.ReplaceAllStringFunc(p.Template, func(match string) string {
+		name := promptPlaceholder.FindStringSubmatch(match)[1]
+		if value, ok := args[name]; ok && value != nil {
+			return promptArgumentString(value)
+		}
+		if declared[name] {
+			// Optional parameter that was not supplied
+			return ""
+		}
+		unresolved[name] = true
+		return match
+	})
+
+	if len(unresolved) > 0 {
+		names := make([]string, 0, len(unresolved))
+		for name := range unresolved {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", NewError(
+			fmt.Sprintf("prompt %s has unresolved placeholders: %s", p.Name, strings.Join(names, ", ")),
+			500,
+		)
+	}
+
+	return rendered, nil
+}
+
+// promptArgumentString converts a prompt argument value to its textual form.
+func promptArgumentString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrompt_Render(t *testing.T) {
+	prompt := &Prompt{
+		Name:     "greeting",
+		Template: "Hello, {{name}}! Welcome to {{ place }}.{{suffix}}",
+		Parameters: []PromptParameter{
+			{Name: "name", Required: true},
+			{Name: "place", Required: true},
+			{Name: "suffix"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		args           map[string]interface{}
+		want           string
+		wantValidation bool
+		wantErr        bool
+	}{
+		{
+			name: "All parameters supplied",
+			args: map[string]interface{}{"name": "Ada", "place": "Paris", "suffix": " Enjoy."},
+			want: "Hello, Ada! Welcome to Paris. Enjoy.",
+		},
+		{
+			name: "Optional parameter omitted",
+			args: map[string]interface{}{"name": "Ada", "place": 42},
+			want: "Hello, Ada! Welcome to 42.",
+		},
+		{
+			name:           "Missing required parameter",
+			args:           map[string]interface{}{"name": "Ada"},
+			wantValidation: true,
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prompt.Render(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) != tt.wantValidation {
+				t.Errorf("Render() error = %v, want validation error %v", err, tt.wantValidation)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrompt_RenderUnknownPlaceholder(t *testing.T) {
+	prompt := &Prompt{
+		Name:     "broken",
+		Template: "Hello, {{name}} from {{city}}",
+	}
+
+	_, err := prompt.Render(map[string]interface{}{"name": "Ada"})
+	if err == nil {
+		t.Fatal("Render() should fail for unresolved placeholders")
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		t.Errorf("Render() error should not be a validation error: %v", err)
+	}
+}
+
+func TestPrompt_ValidateArguments(t *testing.T) {
+	prompt := &Prompt{
+		Name: "report",
+		Parameters: []PromptParameter{
+			{Name: "title", Type: "string", Required: true},
+			{Name: "limit", Type: "integer"},
+			{Name: "draft", Type: "boolean"},
+			{Name: "tags", Type: "array"},
+			{Name: "note"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantField string
+	}{
+		{name: "Typed values", args: map[string]interface{}{"title": "Q3", "limit": 10, "draft": true, "tags": []interface{}{"a"}}},
+		{name: "String-encoded values", args: map[string]interface{}{"title": "Q3", "limit": "10", "draft": "false", "tags": `["a"]`}},
+		{name: "Untyped parameter accepts anything", args: map[string]interface{}{"title": "Q3", "note": 42}},
+		{name: "Missing required parameter", args: map[string]interface{}{"limit": 10}, wantField: "title"},
+		{name: "Non-string for string parameter", args: map[string]interface{}{"title": 3}, wantField: "title"},
+		{name: "Fractional integer", args: map[string]interface{}{"title": "Q3", "limit": "2.5"}, wantField: "limit"},
+		{name: "Invalid boolean", args: map[string]interface{}{"title": "Q3", "draft": "yes"}, wantField: "draft"},
+		{name: "Quoted JSON string for integer", args: map[string]interface{}{"title": "Q3", "limit": `"10"`}, wantField: "limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := prompt.ValidateArguments(tt.args)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("ValidateArguments() error = %v, want nil", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+				t.Errorf("ValidateArguments() error = %v, want validation error for %s", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestPrompt_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		prompt  *Prompt
+		wantErr bool
+	}{
+		{name: "Valid prompt", prompt: &Prompt{Name: "p", Parameters: []PromptParameter{{Name: "a"}, {Name: "b", Type: "number"}}}},
+		{name: "Missing name", prompt: &Prompt{}, wantErr: true},
+		{name: "Unnamed parameter", prompt: &Prompt{Name: "p", Parameters: []PromptParameter{{Type: "string"}}}, wantErr: true},
+		{name: "Duplicate parameter", prompt: &Prompt{Name: "p", Parameters: []PromptParameter{{Name: "a"}, {Name: "a"}}}, wantErr: true},
+		{name: "Unsupported type", prompt: &Prompt{Name: "p", Parameters: []PromptParameter{{Name: "a", Type: "date"}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.prompt.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
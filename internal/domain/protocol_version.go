@@ -0,0 +1,35 @@
+package domain
+
+// SupportedProtocolVersions are the MCP protocol versions the server speaks,
+// newest first.
+var SupportedProtocolVersions = []string{
+	"2025-03-26",
+	"2024-11-05",
+}
+
+// LatestProtocolVersion is the newest supported MCP protocol version.
+var LatestProtocolVersion = SupportedProtocolVersions[0]
+
+// NegotiateProtocolVersion returns the protocol version to answer an
+// initialize request with: the requested version if it is supported, and the
+// latest supported version otherwise. ok is false if the requested version
+// is not supported.
+func NegotiateProtocolVersion(requested string) (version string, ok bool) {
+	for _, supported := range SupportedProtocolVersions {
+		if supported == requested {
+			return requested, true
+		}
+	}
+	return LatestProtocolVersion, false
+}
+
+// ProtocolVersionFromInitializeParams extracts the protocol version a client
+// requested in an initialize request, if any.
+func ProtocolVersionFromInitializeParams(params interface{}) string {
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	version, _ := paramsMap["protocolVersion"].(string)
+	return version
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "testing"
+
+func TestNegotiateProtocolVersion(t *testing.T) {
+	tests := []struct {
+		requested string
+		want      string
+		wantOK    bool
+	}{
+		{requested: "2024-11-05", want: "2024-11-05", wantOK: true},
+		{requested: "2025-03-26", want: "2025-03-26", wantOK: true},
+		{requested: "2099-01-01", want: LatestProtocolVersion, wantOK: false},
+		{requested: "", want: LatestProtocolVersion, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := NegotiateProtocolVersion(tt.requested)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("NegotiateProtocolVersion(%q) = %q, %v, want %q, %v", tt.requested, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestProtocolVersionFromInitializeParams(t *testing.T) {
+	params := map[string]interface{}{"protocolVersion": "2024-11-05"}
+	if got := ProtocolVersionFromInitializeParams(params); got != "2024-11-05" {
+		t.Errorf("ProtocolVersionFromInitializeParams() = %q, want 2024-11-05", got)
+	}
+	if got := ProtocolVersionFromInitializeParams(nil); got != "" {
+		t.Errorf("ProtocolVersionFromInitializeParams(nil) = %q, want empty", got)
+	}
+}
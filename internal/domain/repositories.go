@@ -2,6 +2,47 @@ package domain
 
 import "context"
 
+// ResourceContentProvider supplies the contents of a resource.
+type ResourceContentProvider interface {
+	// ReadContent returns the contents of the resource with the given URI.
+	ReadContent(ctx context.Context, uri string) ([]ResourceContents, error)
+}
+
+// ResourceContentProviderFunc adapts a function to a ResourceContentProvider.
+type ResourceContentProviderFunc func(ctx context.Context, uri string) ([]ResourceContents, error)
+
+// ReadContent calls f(ctx, uri).
+func (f ResourceContentProviderFunc) ReadContent(ctx context.Context, uri string) ([]ResourceContents, error) {
+	return f(ctx, uri)
+}
+
+// ResourceTemplateContentProvider supplies the contents of resources matching a resource template.
+type ResourceTemplateContentProvider interface {
+	// ReadTemplateContent returns the contents of the resource with the given
+	// URI. vars holds the values of the template variables extracted from it.
+	ReadTemplateContent(ctx context.Context, uri string, vars map[string]string) ([]ResourceContents, error)
+}
+
+// ResourceTemplateContentProviderFunc adapts a function to a ResourceTemplateContentProvider.
+type ResourceTemplateContentProviderFunc func(ctx context.Context, uri string, vars map[string]string) ([]ResourceContents, error)
+
+// ReadTemplateContent calls f(ctx, uri, vars).
+func (f ResourceTemplateContentProviderFunc) ReadTemplateContent(ctx context.Context, uri string, vars map[string]string) ([]ResourceContents, error) {
+	return f(ctx, uri, vars)
+}
+
+// ResourceTemplateRepository defines the interface for managing resource templates.
+type ResourceTemplateRepository interface {
+	// ListResourceTemplates returns all registered resource templates.
+	ListResourceTemplates(ctx context.Context) ([]*ResourceTemplate, error)
+
+	// AddResourceTemplate adds a resource template to the repository.
+	AddResourceTemplate(ctx context.Context, template *ResourceTemplate) error
+
+	// DeleteResourceTemplate removes the resource template with the given URI template.
+	DeleteResourceTemplate(ctx context.Context, uriTemplate string) error
+}
+
 // ResourceRepository defines the interface for managing resources.
 type ResourceRepository interface {
 	// GetResource retrieves a resource by its URI.
@@ -17,6 +58,13 @@ type ResourceRepository interface {
 	DeleteResource(ctx context.Context, uri string) error
 }
 
+// ResourceReplacer is implemented by resource repositories that can replace
+// all of their resources in one atomic step.
+type ResourceReplacer interface {
+	// ReplaceResources replaces the repository contents with the given resources.
+	ReplaceResources(ctx context.Context, resources []*Resource) error
+}
+
 // ToolRepository defines the interface for managing tools.
 type ToolRepository interface {
 	// GetTool retrieves a tool by its name.
@@ -32,6 +80,29 @@ type ToolRepository interface {
 	DeleteTool(ctx context.Context, name string) error
 }
 
+// ToolReplacer is implemented by tool repositories that can replace all of
+// their tools in one atomic step.
+type ToolReplacer interface {
+	// ReplaceTools replaces the repository contents with the given tools.
+	ReplaceTools(ctx context.Context, tools []*Tool) error
+}
+
+// ToolBatchAdder is implemented by tool repositories that can add several
+// tools in one atomic step.
+type ToolBatchAdder interface {
+	// AddTools adds all of the tools, or none of them if one's name is
+	// already taken, returning an error matching ErrDuplicateTool.
+	AddTools(ctx context.Context, tools []*Tool) error
+}
+
+// ToolUpdater is implemented by tool repositories that can replace an
+// existing tool in one atomic step.
+type ToolUpdater interface {
+	// UpdateTool replaces the tool with the same name. It returns a
+	// ToolNotFoundError if there is none.
+	UpdateTool(ctx context.Context, tool *Tool) error
+}
+
 // PromptRepository defines the interface for managing prompts.
 type PromptRepository interface {
 	// GetPrompt retrieves a prompt by its name.
@@ -47,6 +118,13 @@ type PromptRepository interface {
 	DeletePrompt(ctx context.Context, name string) error
 }
 
+// PromptReplacer is implemented by prompt repositories that can replace all
+// of their prompts in one atomic step.
+type PromptReplacer interface {
+	// ReplacePrompts replaces the repository contents with the given prompts.
+	ReplacePrompts(ctx context.Context, prompts []*Prompt) error
+}
+
 // SessionRepository defines the interface for managing client sessions.
 type SessionRepository interface {
 	// GetSession retrieves a session by its ID.
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// RequestID is a JSON-RPC request ID. It keeps the exact JSON form the client
+// sent, a string or a number, so responses echo it back unchanged: numeric
+// IDs stay numbers and large integers keep their precision. RequestIDs are
+// comparable and can be used as map keys; the string "1" and the number 1 are
+// different IDs.
+type RequestID struct {
+	raw string
+}
+
+// ParseRequestID parses the JSON encoding of a request ID. It reports false
+// if data is not a JSON string or number.
+func ParseRequestID(data []byte) (RequestID, bool) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return RequestID{}, false
+	}
+	switch {
+	case data[0] == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return RequestID{}, false
+		}
+	case data[0] == '-' || (data[0] >= '0' && data[0] <= '9'):
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return RequestID{}, false
+		}
+	default:
+		return RequestID{}, false
+	}
+	return RequestID{raw: string(data)}, true
+}
+
+// NewRequestID normalizes a request ID decoded from JSON, such as a float64
+// from a generic map, to a RequestID. It reports false for values that are
+// not valid JSON-RPC IDs.
+func NewRequestID(id interface{}) (RequestID, bool) {
+	switch v := id.(type) {
+	case RequestID:
+		return v, v.raw != ""
+	case string:
+		data, _ := json.Marshal(v)
+		return RequestID{raw: string(data)}, true
+	case float64:
+		return RequestID{raw: strconv.FormatFloat(v, 'f', -1, 64)}, true
+	case json.Number:
+		return ParseRequestID([]byte(v))
+	case int:
+		return RequestID{raw: strconv.Itoa(v)}, true
+	case int64:
+		return RequestID{raw: strconv.FormatInt(v, 10)}, true
+	default:
+		return RequestID{}, false
+	}
+}
+
+// IsString reports whether the client sent the ID as a string.
+func (id RequestID) IsString() bool {
+	return len(id.raw) > 0 && id.raw[0] == '"'
+}
+
+// String returns the ID for display: the string value or the number as sent.
+func (id RequestID) String() string {
+	if id.IsString() {
+		var s string
+		_ = json.Unmarshal([]byte(id.raw), &s)
+		return s
+	}
+	return id.raw
+}
+
+// MarshalJSON returns the ID in the form the client sent it.
+func (id RequestID) MarshalJSON() ([]byte, error) {
+	if id.raw == "" {
+		return []byte("null"), nil
+	}
+	return []byte(id.raw), nil
+}
+
+// UnmarshalJSON parses a string or numeric ID.
+func (id *RequestID) UnmarshalJSON(data []byte) error {
+	parsed, ok := ParseRequestID(data)
+	if !ok {
+		return fmt.Errorf("invalid JSON-RPC id: %s", data)
+	}
+	*id = parsed
+	return nil
+}
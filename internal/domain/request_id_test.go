@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantOK   bool
+		wantStr  bool
+		wantText string
+	}{
+		{"Number", `7`, true, false, "7"},
+		{"Large integer", `9007199254740993`, true, false, "9007199254740993"},
+		{"String", `"abc"`, true, true, "abc"},
+		{"Numeric string", `"1"`, true, true, "1"},
+		{"Null", `null`, false, false, ""},
+		{"Object", `{"a":1}`, false, false, ""},
+		{"Bool", `true`, false, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := ParseRequestID([]byte(tt.data))
+			if ok != tt.wantOK {
+				t.Fatalf("ParseRequestID(%s) ok = %v, want %v", tt.data, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if id.IsString() != tt.wantStr || id.String() != tt.wantText {
+				t.Errorf("ParseRequestID(%s) = %q (string %v), want %q (string %v)", tt.data, id.String(), id.IsString(), tt.wantText, tt.wantStr)
+			}
+			encoded, _ := json.Marshal(id)
+			if string(encoded) != tt.data {
+				t.Errorf("json.Marshal() = %s, want %s", encoded, tt.data)
+			}
+		})
+	}
+}
+
+func TestNewRequestID(t *testing.T) {
+	number, _ := ParseRequestID([]byte(`42`))
+	str, _ := ParseRequestID([]byte(`"42"`))
+
+	if id, ok := NewRequestID(float64(42)); !ok || id != number {
+		t.Errorf("NewRequestID(float64(42)) = %v, %v, want %v", id, ok, number)
+	}
+	if id, ok := NewRequestID("42"); !ok || id != str {
+		t.Errorf("NewRequestID(\"42\") = %v, %v, want %v", id, ok, str)
+	}
+	if number == str {
+		t.Error("string and numeric IDs should differ")
+	}
+	if _, ok := NewRequestID(map[string]interface{}{}); ok {
+		t.Error("NewRequestID() should reject objects")
+	}
+}
+
+func TestJSONRPCRequest_UnmarshalID(t *testing.T) {
+	var request JSONRPCRequest
+	if err := json.Unmarshal([]byte(`{"jsonrpc":"2.0","id":12345678901234567890,"method":"ping"}`), &request); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	response, _ := json.Marshal(CreateResponse("2.0", request.ID, "ok"))
+	if want := `{"jsonrpc":"2.0","id":12345678901234567890,"result":"ok"}`; string(response) != want {
+		t.Errorf("response = %s, want %s", response, want)
+	}
+
+	request = JSONRPCRequest{}
+	if err := json.Unmarshal([]byte(`{"jsonrpc":"2.0","id":null,"method":"notifications/initialized"}`), &request); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if request.ID != nil {
+		t.Errorf("null ID = %#v, want nil", request.ID)
+	}
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "encoding/base64"
+
+// ResourceTemplatesToMCP converts resource templates to the MCP
+// resources/templates/list format.
+func ResourceTemplatesToMCP(templates []*ResourceTemplate) []map[string]interface{} {
+	entries := make([]map[string]interface{}, len(templates))
+	for i, template := range templates {
+		entries[i] = map[string]interface{}{
+			"uriTemplate": template.URITemplate,
+			"name":        template.Name,
+			"description": template.Description,
+			"mimeType":    template.MIMEType,
+		}
+	}
+	return entries
+}
+
+// ToMCP converts the contents to an MCP resource contents entry. Text contents
+// are returned in the "text" field and binary contents base64-encoded in "blob".
+func (c ResourceContents) ToMCP() map[string]interface{} {
+	entry := map[string]interface{}{
+		"uri":      c.URI,
+		"mimeType": c.MIMEType,
+	}
+	if c.Text == "" && len(c.Content) > 0 {
+		entry["blob"] = base64.StdEncoding.EncodeToString(c.Content)
+	} else {
+		entry["text"] = c.Text
+	}
+	return entry
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"path"
+	"strings"
+)
+
+// ResourceFilter selects the resources returned by resources/list. The zero
+// value matches every resource.
+type ResourceFilter struct {
+	// URIPrefix matches resources whose URI starts with it.
+	URIPrefix string
+	// Pattern matches resource URIs with path.Match syntax, e.g.
+	// "file:///docs/*.md". A * does not match a /.
+	Pattern string
+}
+
+// Validate checks that the filter's pattern is well-formed. It returns a
+// ValidationError for the pattern if it is not.
+func (f ResourceFilter) Validate() error {
+	if f.Pattern == "" {
+		return nil
+	}
+	if _, err := path.Match(f.Pattern, ""); err != nil {
+		return NewValidationError("pattern", err.Error())
+	}
+	return nil
+}
+
+// Matches reports whether the resource URI satisfies both the prefix and the
+// pattern of the filter. Malformed patterns match nothing.
+func (f ResourceFilter) Matches(uri string) bool {
+	if !strings.HasPrefix(uri, f.URIPrefix) {
+		return false
+	}
+	if f.Pattern == "" {
+		return true
+	}
+	matched, err := path.Match(f.Pattern, uri)
+	return err == nil && matched
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResourceFilter_Matches(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ResourceFilter
+		uri    string
+		want   bool
+	}{
+		{name: "Zero filter", uri: "file:///notes.txt", want: true},
+		{name: "Prefix match", filter: ResourceFilter{URIPrefix: "file:///docs/"}, uri: "file:///docs/a.md", want: true},
+		{name: "Prefix mismatch", filter: ResourceFilter{URIPrefix: "file:///docs/"}, uri: "file:///src/a.go", want: false},
+		{name: "Pattern match", filter: ResourceFilter{Pattern: "file:///docs/*.md"}, uri: "file:///docs/a.md", want: true},
+		{name: "Pattern does not cross slashes", filter: ResourceFilter{Pattern: "file:///docs/*.md"}, uri: "file:///docs/sub/a.md", want: false},
+		{name: "Prefix and pattern", filter: ResourceFilter{URIPrefix: "db://", Pattern: "*.md"}, uri: "file:///a.md", want: false},
+		{name: "Malformed pattern", filter: ResourceFilter{Pattern: "file:///["}, uri: "file:///[", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.uri); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", tt.uri, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceFilter_Validate(t *testing.T) {
+	if err := (ResourceFilter{Pattern: "file:///*.md"}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+
+	var validationErr *ValidationError
+	err := ResourceFilter{Pattern: "file:///["}.Validate()
+	if !errors.As(err, &validationErr) || validationErr.Field != "pattern" {
+		t.Errorf("Validate() error = %v, want validation error for pattern", err)
+	}
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"sync"
+)
+
+// StdioSessionID is the ID of the single synthetic session of a stdio server.
+const StdioSessionID = "stdio"
+
+// SessionInfo describes the client session a request came from.
+type SessionInfo struct {
+	ID            string
+	ClientName    string
+	ClientVersion string
+	UserAgent     string
+	// Ready reports whether the client sent notifications/initialized.
+	Ready bool
+}
+
+// SessionInfoHolder holds the info of a single client session. Transports
+// create one per connection; the client name and version are filled in when
+// the client initializes.
+type SessionInfoHolder struct {
+	mu   sync.RWMutex
+	info SessionInfo
+}
+
+// NewSessionInfoHolder creates the info holder of a session.
+func NewSessionInfoHolder(id, userAgent string) *SessionInfoHolder {
+	return &SessionInfoHolder{info: SessionInfo{ID: id, UserAgent: userAgent}}
+}
+
+// Info returns a snapshot of the session info.
+func (h *SessionInfoHolder) Info() SessionInfo {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.info
+}
+
+// SetClientInfo records the client name and version from the params of an
+// initialize request. Params without clientInfo leave the info unchanged.
+func (h *SessionInfoHolder) SetClientInfo(params interface{}) {
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return
+	}
+	clientInfo, ok := paramsMap["clientInfo"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	name, _ := clientInfo["name"].(string)
+	version, _ := clientInfo["version"].(string)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.info.ClientName = name
+	h.info.ClientVersion = version
+}
+
+// MarkReady records that the client sent notifications/initialized and the
+// session is ready for normal operation.
+func (h *SessionInfoHolder) MarkReady() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.info.Ready = true
+}
+
+type sessionInfoKey struct{}
+
+// WithSessionInfo returns a context carrying the info holder of the session the request came from.
+func WithSessionInfo(ctx context.Context, holder *SessionInfoHolder) context.Context {
+	return context.WithValue(ctx, sessionInfoKey{}, holder)
+}
+
+// SessionInfoHolderFromContext returns the info holder of the session the request came from.
+func SessionInfoHolderFromContext(ctx context.Context) (*SessionInfoHolder, bool) {
+	holder, ok := ctx.Value(sessionInfoKey{}).(*SessionInfoHolder)
+	return holder, ok && holder != nil
+}
+
+// SessionInfoFromContext returns the info of the session the request came from.
+func SessionInfoFromContext(ctx context.Context) (SessionInfo, bool) {
+	holder, ok := SessionInfoHolderFromContext(ctx)
+	if !ok {
+		return SessionInfo{}, false
+	}
+	return holder.Info(), true
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSessionInfoHolder(t *testing.T) {
+	holder := NewSessionInfoHolder("session-1", "curl/8.0")
+
+	want := SessionInfo{ID: "session-1", UserAgent: "curl/8.0"}
+	if got := holder.Info(); got != want {
+		t.Errorf("Info() = %+v, want %+v", got, want)
+	}
+
+	// Params without clientInfo leave the info unchanged
+	holder.SetClientInfo(map[string]interface{}{"protocolVersion": "2024-11-05"})
+	holder.SetClientInfo("not a map")
+	if got := holder.Info(); got != want {
+		t.Errorf("Info() = %+v, want %+v", got, want)
+	}
+
+	holder.SetClientInfo(map[string]interface{}{
+		"clientInfo": map[string]interface{}{"name": "inspector", "version": "0.9.0"},
+	})
+	want.ClientName, want.ClientVersion = "inspector", "0.9.0"
+	if got := holder.Info(); got != want {
+		t.Errorf("Info() = %+v, want %+v", got, want)
+	}
+
+	holder.MarkReady()
+	want.Ready = true
+	if got := holder.Info(); got != want {
+		t.Errorf("Info() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSessionInfoFromContext(t *testing.T) {
+	if _, ok := SessionInfoFromContext(context.Background()); ok {
+		t.Error("SessionInfoFromContext() should report no info on a bare context")
+	}
+
+	ctx := WithSessionInfo(context.Background(), NewSessionInfoHolder(StdioSessionID, ""))
+	info, ok := SessionInfoFromContext(ctx)
+	if !ok || info.ID != StdioSessionID {
+		t.Errorf("SessionInfoFromContext() = %+v, %v, want the stdio session", info, ok)
+	}
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"sync"
+)
+
+// SessionStore is a thread-safe key-value store scoped to a single client
+// session. Transports create one per connection and clear it when the
+// client disconnects.
+type SessionStore struct {
+	mu     sync.RWMutex
+	values map[string]interface{}
+}
+
+// NewSessionStore creates an empty session store.
+func NewSessionStore() *SessionStore {
+	return &SessionStore{
+		values: make(map[string]interface{}),
+	}
+}
+
+// Get returns the value stored under key.
+func (s *SessionStore) Get(key string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Set stores value under key, replacing any previous value.
+func (s *SessionStore) Set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+// Delete removes the value stored under key.
+func (s *SessionStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// Clear removes all values. It is called when the session ends.
+func (s *SessionStore) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = make(map[string]interface{})
+}
+
+type sessionStoreKey struct{}
+
+// WithSessionStore returns a context carrying the store of the session the request came from.
+func WithSessionStore(ctx context.Context, store *SessionStore) context.Context {
+	return context.WithValue(ctx, sessionStoreKey{}, store)
+}
+
+// SessionStoreFromContext returns the store of the session the request came from.
+func SessionStoreFromContext(ctx context.Context) (*SessionStore, bool) {
+	store, ok := ctx.Value(sessionStoreKey{}).(*SessionStore)
+	return store, ok && store != nil
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSessionStore(t *testing.T) {
+	store := NewSessionStore()
+
+	if _, ok := store.Get("count"); ok {
+		t.Error("Get() on an empty store should report the key as missing")
+	}
+
+	store.Set("count", 1)
+	if value, ok := store.Get("count"); !ok || value != 1 {
+		t.Errorf("Get() = %v, %v, want 1, true", value, ok)
+	}
+
+	store.Delete("count")
+	if _, ok := store.Get("count"); ok {
+		t.Error("Get() after Delete() should report the key as missing")
+	}
+
+	store.Set("cart", []string{"apple"})
+	store.Clear()
+	if _, ok := store.Get("cart"); ok {
+		t.Error("Get() after Clear() should report the key as missing")
+	}
+}
+
+func TestSessionStore_Concurrent(t *testing.T) {
+	store := NewSessionStore()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("key-%d", i%5)
+			store.Set(key, i)
+			store.Get(key)
+			store.Delete(key)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestSessionStoreFromContext(t *testing.T) {
+	if _, ok := SessionStoreFromContext(context.Background()); ok {
+		t.Error("SessionStoreFromContext() should report no store on a bare context")
+	}
+
+	store := NewSessionStore()
+	got, ok := SessionStoreFromContext(WithSessionStore(context.Background(), store))
+	if !ok || got != store {
+		t.Errorf("SessionStoreFromContext() = %v, %v, want the stored store", got, ok)
+	}
+}
@@ -68,3 +68,16 @@ type ConnectionManager interface {
 	// Count returns the number of active sessions.
 	Count() int
 }
+
+// Transport defines a bidirectional transport that carries JSON-RPC messages
+// between the server and its clients.
+type Transport interface {
+	// Start begins accepting client connections. It blocks until the transport is closed.
+	Start() error
+
+	// Send sends a message to the client connected with the given session ID.
+	Send(sessionID string, message interface{}) error
+
+	// Close stops the transport and closes all client connections.
+	Close() error
+}
@@ -0,0 +1,45 @@
+package domain
+
+// ToolAnnotations are hints about a tool's behavior that help clients decide
+// whether to ask the user before calling it. Clients must not rely on them
+// for security. A nil hint is left out and takes its default from the MCP
+// specification.
+type ToolAnnotations struct {
+	// ReadOnlyHint reports that the tool does not modify its environment.
+	ReadOnlyHint *bool
+	// DestructiveHint reports that the tool may perform destructive updates.
+	DestructiveHint *bool
+	// IdempotentHint reports that repeated calls with the same arguments have
+	// no additional effect.
+	IdempotentHint *bool
+	// OpenWorldHint reports that the tool interacts with external entities.
+	OpenWorldHint *bool
+}
+
+// AnnotationsJSON returns the annotations object advertised in tools/list,
+// or nil if the tool sets no hints.
+func (t *Tool) AnnotationsJSON() map[string]interface{} {
+	if t.Annotations == nil {
+		return nil
+	}
+
+	annotations := make(map[string]interface{})
+	hints := []struct {
+		name  string
+		value *bool
+	}{
+		{"readOnlyHint", t.Annotations.ReadOnlyHint},
+		{"destructiveHint", t.Annotations.DestructiveHint},
+		{"idempotentHint", t.Annotations.IdempotentHint},
+		{"openWorldHint", t.Annotations.OpenWorldHint},
+	}
+	for _, hint := range hints {
+		if hint.value != nil {
+			annotations[hint.name] = *hint.value
+		}
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToolAnnotationsJSON(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name string
+		tool Tool
+		want map[string]interface{}
+	}{
+		{"no annotations", Tool{Name: "plain"}, nil},
+		{"no hints set", Tool{Name: "empty", Annotations: &ToolAnnotations{}}, nil},
+		{
+			"hints set",
+			Tool{Name: "search", Annotations: &ToolAnnotations{ReadOnlyHint: &yes, OpenWorldHint: &no}},
+			map[string]interface{}{"readOnlyHint": true, "openWorldHint": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tool.AnnotationsJSON(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AnnotationsJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
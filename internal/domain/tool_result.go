@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ValidateStructuredContent checks the structuredContent of a tool result
+// against the tool's output schema. Results flagged with isError report a
+// tool failure and are not checked. Errors wrap ErrInvalidToolOutput.
+func (t *Tool) ValidateStructuredContent(result interface{}) error {
+	if len(t.OutputSchema) == 0 {
+		return nil
+	}
+
+	// Normalize handler results such as Go structs to their JSON form
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToolOutput, err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("%w: result is not an object", ErrInvalidToolOutput)
+	}
+	if isError, _ := decoded["isError"].(bool); isError {
+		return nil
+	}
+	structured, ok := decoded["structuredContent"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%w: missing structuredContent", ErrInvalidToolOutput)
+	}
+
+	if err := validateProperties("structuredContent", t.OutputSchema, structured); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidToolOutput, err)
+	}
+	return nil
+}
+
+// WrapToolResult converts a plain tool handler return value into an MCP tool
+// result. A string becomes a text content block, a []byte holding an image
+// becomes an image content block and any other value is returned as
+// structuredContent with its JSON text as content. Results that already carry
+// a "content" field are returned unchanged.
+func WrapToolResult(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return map[string]interface{}{"content": []interface{}{}}, nil
+	case map[string]interface{}:
+		if _, ok := v["content"]; ok {
+			return v, nil
+		}
+	case string:
+		return map[string]interface{}{
+			"content": []interface{}{textContent(v)},
+		}, nil
+	case []byte:
+		if mimeType := http.DetectContentType(v); strings.HasPrefix(mimeType, "image/") {
+			return map[string]interface{}{
+				"content": []interface{}{
+					map[string]interface{}{
+						"type":     "image",
+						"data":     base64.StdEncoding.EncodeToString(v),
+						"mimeType": mimeType,
+					},
+				},
+			}, nil
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tool result: %w", err)
+	}
+	return map[string]interface{}{
+		"content":           []interface{}{textContent(string(data))},
+		"structuredContent": value,
+	}, nil
+}
+
+// StructuredResult is implemented by tool handler results that carry
+// machine-readable data. Its StructuredContent is sent as structuredContent
+// with a text fallback in content: the result's String method if it has one,
+// otherwise the JSON encoding of the structured content.
+type StructuredResult interface {
+	StructuredContent() interface{}
+}
+
+// WrapStructuredResult converts a StructuredResult into an MCP tool result.
+func WrapStructuredResult(result StructuredResult) (map[string]interface{}, error) {
+	structured := result.StructuredContent()
+
+	var text string
+	if stringer, ok := result.(fmt.Stringer); ok {
+		text = stringer.String()
+	} else {
+		data, err := json.Marshal(structured)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode structured tool result: %w", err)
+		}
+		text = string(data)
+	}
+
+	return map[string]interface{}{
+		"content":           []interface{}{textContent(text)},
+		"structuredContent": structured,
+	}, nil
+}
+
+// textContent builds a text content block.
+func textContent(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "text",
+		"text": text,
+	}
+}
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWrapToolResult(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	explicit := map[string]interface{}{"content": []interface{}{}, "isError": true}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  map[string]interface{}
+	}{
+		{
+			name:  "String",
+			value: "hello",
+			want:  map[string]interface{}{"content": []interface{}{map[string]interface{}{"type": "text", "text": "hello"}}},
+		},
+		{
+			name:  "Explicit content",
+			value: explicit,
+			want:  explicit,
+		},
+		{
+			name:  "Struct value",
+			value: map[string]interface{}{"sum": 3},
+			want: map[string]interface{}{
+				"content":           []interface{}{map[string]interface{}{"type": "text", "text": `{"sum":3}`}},
+				"structuredContent": map[string]interface{}{"sum": 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WrapToolResult(tt.value)
+			if err != nil {
+				t.Fatalf("WrapToolResult() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WrapToolResult() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Image bytes", func(t *testing.T) {
+		got, err := WrapToolResult(png)
+		if err != nil {
+			t.Fatalf("WrapToolResult() error = %v", err)
+		}
+		block := got.(map[string]interface{})["content"].([]interface{})[0].(map[string]interface{})
+		if block["type"] != "image" || block["mimeType"] != "image/png" {
+			t.Errorf("WrapToolResult() block = %v, want image/png image content", block)
+		}
+	})
+}
+
+func TestTool_ValidateStructuredContent(t *testing.T) {
+	tool := &Tool{
+		Name: "weather",
+		OutputSchema: []ToolParameter{
+			{Name: "temperature", Type: "number", Required: true},
+			{Name: "conditions", Type: "string"},
+		},
+	}
+
+	schema := tool.OutputJSONSchema()
+	if schema["type"] != "object" || !reflect.DeepEqual(schema["required"], []string{"temperature"}) {
+		t.Errorf("OutputJSONSchema() = %v, want an object requiring temperature", schema)
+	}
+	if (&Tool{Name: "plain"}).OutputJSONSchema() != nil {
+		t.Error("OutputJSONSchema() should be nil without an output schema")
+	}
+
+	type forecast struct {
+		StructuredContent map[string]interface{} `json:"structuredContent"`
+	}
+
+	tests := []struct {
+		name    string
+		result  interface{}
+		wantErr bool
+	}{
+		{"Conforming map", map[string]interface{}{"structuredContent": map[string]interface{}{"temperature": 21.5}}, false},
+		{"Conforming struct", forecast{StructuredContent: map[string]interface{}{"temperature": 3, "conditions": "snow"}}, false},
+		{"Missing structuredContent", map[string]interface{}{"content": []interface{}{}}, true},
+		{"Missing required field", map[string]interface{}{"structuredContent": map[string]interface{}{"conditions": "rain"}}, true},
+		{"Wrong field type", map[string]interface{}{"structuredContent": map[string]interface{}{"temperature": "warm"}}, true},
+		{"Not an object", "sunny", true},
+		{"Error result", map[string]interface{}{"content": []interface{}{}, "isError": true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tool.ValidateStructuredContent(tt.result)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("ValidateStructuredContent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidToolOutput) {
+				t.Errorf("ValidateStructuredContent() error = %v, want it to wrap ErrInvalidToolOutput", err)
+			}
+		})
+	}
+}
+
+type forecast struct {
+	City  string `json:"city"`
+	TempC int    `json:"tempC"`
+}
+
+func (f forecast) StructuredContent() interface{} { return f }
+
+type describedForecast struct{ forecast }
+
+func (f describedForecast) String() string { return f.City + ": sunny" }
+
+func TestWrapStructuredResult(t *testing.T) {
+	got, err := WrapStructuredResult(forecast{City: "Oslo", TempC: 4})
+	if err != nil {
+		t.Fatalf("WrapStructuredResult() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"content":           []interface{}{map[string]interface{}{"type": "text", "text": `{"city":"Oslo","tempC":4}`}},
+		"structuredContent": forecast{City: "Oslo", TempC: 4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WrapStructuredResult() = %v, want %v", got, want)
+	}
+
+	// A String method provides the text fallback
+	got, err = WrapStructuredResult(describedForecast{forecast{City: "Oslo"}})
+	if err != nil {
+		t.Fatalf("WrapStructuredResult() error = %v", err)
+	}
+	text := got["content"].([]interface{})[0].(map[string]interface{})["text"]
+	if text != "Oslo: sunny" {
+		t.Errorf("text fallback = %v, want Oslo: sunny", text)
+	}
+}
@@ -0,0 +1,335 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"unicode/utf8"
+)
+
+// InputSchema returns the JSON Schema describing the tool's arguments.
+func (t *Tool) InputSchema() map[string]interface{} {
+	return objectSchema(t.Parameters)
+}
+
+// OutputJSONSchema returns the JSON Schema describing the tool's
+// structuredContent result, or nil if the tool declares no output schema.
+func (t *Tool) OutputJSONSchema() map[string]interface{} {
+	if len(t.OutputSchema) == 0 {
+		return nil
+	}
+	return objectSchema(t.OutputSchema)
+}
+
+// Schema returns the JSON Schema for the parameter, including nested array
+// items and object properties.
+func (p ToolParameter) Schema() map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":        p.Type,
+		"description": p.Description,
+	}
+	if p.Default != nil {
+		schema["default"] = p.Default
+	}
+	if p.Minimum != nil {
+		schema["minimum"] = *p.Minimum
+	}
+	if p.Maximum != nil {
+		schema["maximum"] = *p.Maximum
+	}
+	if p.ExclusiveMinimum != nil {
+		schema["exclusiveMinimum"] = *p.ExclusiveMinimum
+	}
+	if p.ExclusiveMaximum != nil {
+		schema["exclusiveMaximum"] = *p.ExclusiveMaximum
+	}
+	if p.MinLength != nil {
+		schema["minLength"] = *p.MinLength
+	}
+	if p.MaxLength != nil {
+		schema["maxLength"] = *p.MaxLength
+	}
+	if p.Pattern != "" {
+		schema["pattern"] = p.Pattern
+	}
+	if p.Items != nil {
+		schema["items"] = p.Items.Schema()
+	}
+	if len(p.Properties) > 0 {
+		nested := objectSchema(p.Properties)
+		schema["properties"] = nested["properties"]
+		if required, ok := nested["required"]; ok {
+			schema["required"] = required
+		}
+	}
+	return schema
+}
+
+// objectSchema builds an object schema from a list of parameters.
+func objectSchema(params []ToolParameter) map[string]interface{} {
+	properties := make(map[string]interface{}, len(params))
+	required := []string{}
+
+	for _, param := range params {
+		properties[param.Name] = param.Schema()
+		if param.Required {
+			required = append(required, param.Name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// parameterTypes are the types a tool parameter can declare.
+var parameterTypes = map[string]bool{
+	"string":  true,
+	"number":  true,
+	"integer": true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+}
+
+// Validate checks the tool's definition when it is registered. The tool must
+// have a name, and every parameter, including nested ones, must have a name
+// and one of the types string, number, integer, boolean, object or array.
+// Array items are unnamed. It returns a ValidationError naming the offending
+// parameter.
+func (t *Tool) Validate() error {
+	if t.Name == "" {
+		return NewValidationError("name", "tool name is required")
+	}
+	if err := validateParameters("", t.Parameters); err != nil {
+		return err
+	}
+	return validateParameters("structuredContent", t.OutputSchema)
+}
+
+// validateParameters checks the names and types of a list of parameters.
+func validateParameters(prefix string, params []ToolParameter) error {
+	for i, param := range params {
+		if param.Name == "" {
+			path := fmt.Sprintf("parameters[%d]", i)
+			if prefix != "" {
+				path = prefix + "." + path
+			}
+			return NewValidationError(path, "parameter name is required")
+		}
+		path := param.Name
+		if prefix != "" {
+			path = prefix + "." + param.Name
+		}
+		if err := validateParameter(path, param); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateParameter checks the type of a parameter and its nested items and properties.
+func validateParameter(path string, param ToolParameter) error {
+	if !parameterTypes[param.Type] {
+		return NewValidationError(path, fmt.Sprintf("unsupported type %q, want string, number, integer, boolean, object or array", param.Type))
+	}
+	if param.Items != nil {
+		if err := validateParameter(path+"[]", *param.Items); err != nil {
+			return err
+		}
+	}
+	return validateParameters(path, param.Properties)
+}
+
+// CompilePatterns compiles the regular expressions of all string parameters,
+// including nested ones, so they are not compiled on every call. It returns
+// a ValidationError naming the parameter with an invalid pattern.
+func (t *Tool) CompilePatterns() error {
+	if err := compilePatterns("", t.Parameters); err != nil {
+		return err
+	}
+	return compilePatterns("structuredContent", t.OutputSchema)
+}
+
+// compilePatterns compiles the patterns of a list of parameters in place.
+func compilePatterns(prefix string, params []ToolParameter) error {
+	for i := range params {
+		path := params[i].Name
+		if prefix != "" {
+			path = prefix + "." + params[i].Name
+		}
+		if err := compilePattern(path, &params[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compilePattern compiles the pattern of a parameter and its nested items and properties.
+func compilePattern(path string, param *ToolParameter) error {
+	if param.Pattern != "" {
+		re, err := regexp.Compile(param.Pattern)
+		if err != nil {
+			return NewValidationError(path, fmt.Sprintf("invalid pattern: %v", err))
+		}
+		param.pattern = re
+	}
+	if param.Items != nil {
+		if err := compilePattern(path+"[]", param.Items); err != nil {
+			return err
+		}
+	}
+	return compilePatterns(path, param.Properties)
+}
+
+// ValidateArguments checks the call arguments against the tool's parameters.
+// Required parameters must be present and values must match the declared
+// type, recursing into array items and object properties. It returns a
+// ValidationError naming the offending argument.
+func (t *Tool) ValidateArguments(args map[string]interface{}) error {
+	return validateProperties("", t.Parameters, args)
+}
+
+// validateProperties validates the fields of an object against its parameters.
+func validateProperties(prefix string, params []ToolParameter, values map[string]interface{}) error {
+	for _, param := range params {
+		path := param.Name
+		if prefix != "" {
+			path = prefix + "." + param.Name
+		}
+
+		value, ok := values[param.Name]
+		if !ok || value == nil {
+			if param.Required {
+				return NewValidationError(path, "required parameter is missing")
+			}
+			continue
+		}
+
+		if err := validateValue(path, param, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateValue checks a single value against its parameter definition.
+func validateValue(path string, param ToolParameter, value interface{}) error {
+	switch param.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return typeMismatch(path, param.Type, value)
+		}
+		return validateString(path, param, s)
+	case "number":
+		f, ok := toFloat(value)
+		if !ok {
+			return typeMismatch(path, param.Type, value)
+		}
+		return validateRange(path, param, f)
+	case "integer":
+		f, ok := toFloat(value)
+		if !ok || f != math.Trunc(f) {
+			return typeMismatch(path, param.Type, value)
+		}
+		return validateRange(path, param, f)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return typeMismatch(path, param.Type, value)
+		}
+	case "array":
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return typeMismatch(path, param.Type, value)
+		}
+		if param.Items == nil {
+			return nil
+		}
+		for i := 0; i < rv.Len(); i++ {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			item := rv.Index(i).Interface()
+			if item == nil {
+				return NewValidationError(itemPath, "must not be null")
+			}
+			if err := validateValue(itemPath, *param.Items, item); err != nil {
+				return err
+			}
+		}
+	case "object":
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return typeMismatch(path, param.Type, value)
+		}
+		return validateProperties(path, param.Properties, fields)
+	}
+	return nil
+}
+
+// validateRange checks a numeric value against the parameter's bounds.
+func validateRange(path string, param ToolParameter, f float64) error {
+	switch {
+	case param.Minimum != nil && f < *param.Minimum:
+		return NewValidationError(path, fmt.Sprintf("must be >= %v", *param.Minimum))
+	case param.Maximum != nil && f > *param.Maximum:
+		return NewValidationError(path, fmt.Sprintf("must be <= %v", *param.Maximum))
+	case param.ExclusiveMinimum != nil && f <= *param.ExclusiveMinimum:
+		return NewValidationError(path, fmt.Sprintf("must be > %v", *param.ExclusiveMinimum))
+	case param.ExclusiveMaximum != nil && f >= *param.ExclusiveMaximum:
+		return NewValidationError(path, fmt.Sprintf("must be < %v", *param.ExclusiveMaximum))
+	}
+	return nil
+}
+
+// validateString checks a string value against the parameter's length and
+// pattern constraints.
+func validateString(path string, param ToolParameter, s string) error {
+	length := utf8.RuneCountInString(s)
+	if param.MinLength != nil && length < *param.MinLength {
+		return NewValidationError(path, fmt.Sprintf("must be at least %d characters", *param.MinLength))
+	}
+	if param.MaxLength != nil && length > *param.MaxLength {
+		return NewValidationError(path, fmt.Sprintf("must be at most %d characters", *param.MaxLength))
+	}
+	if param.Pattern == "" {
+		return nil
+	}
+
+	// Tools validated without being registered have no compiled pattern yet
+	re := param.pattern
+	if re == nil {
+		var err error
+		if re, err = regexp.Compile(param.Pattern); err != nil {
+			return NewValidationError(path, fmt.Sprintf("invalid pattern: %v", err))
+		}
+	}
+	if !re.MatchString(s) {
+		return NewValidationError(path, fmt.Sprintf("must match pattern %s", param.Pattern))
+	}
+	return nil
+}
+
+// typeMismatch returns a ValidationError for a value of the wrong type.
+func typeMismatch(path, want string, value interface{}) error {
+	return NewValidationError(path, fmt.Sprintf("expected %s, got %T", want, value))
+}
+
+// toFloat converts any Go numeric value to a float64.
+func toFloat(value interface{}) (float64, bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,266 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func nestedTestTool() *Tool {
+	return &Tool{
+		Name: "configure",
+		Parameters: []ToolParameter{
+			{
+				Name:     "tags",
+				Type:     "array",
+				Required: true,
+				Items:    &ToolParameter{Type: "string"},
+			},
+			{
+				Name: "config",
+				Type: "object",
+				Properties: []ToolParameter{
+					{Name: "name", Type: "string", Required: true},
+					{Name: "retries", Type: "integer"},
+				},
+			},
+		},
+	}
+}
+
+func TestTool_InputSchema(t *testing.T) {
+	schema := nestedTestTool().InputSchema()
+
+	if !reflect.DeepEqual(schema["required"], []string{"tags"}) {
+		t.Errorf("InputSchema() required = %v, want [tags]", schema["required"])
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	tags := properties["tags"].(map[string]interface{})
+	if items := tags["items"].(map[string]interface{}); items["type"] != "string" {
+		t.Errorf("tags items type = %v, want string", items["type"])
+	}
+
+	config := properties["config"].(map[string]interface{})
+	configProps := config["properties"].(map[string]interface{})
+	if _, ok := configProps["retries"]; !ok {
+		t.Error("config properties should include retries")
+	}
+	if !reflect.DeepEqual(config["required"], []string{"name"}) {
+		t.Errorf("config required = %v, want [name]", config["required"])
+	}
+}
+
+func TestTool_ValidateArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantField string
+	}{
+		{"Valid", map[string]interface{}{"tags": []interface{}{"a", "b"}, "config": map[string]interface{}{"name": "x", "retries": float64(3)}}, ""},
+		{"Go slice", map[string]interface{}{"tags": []string{"a"}}, ""},
+		{"Missing required", map[string]interface{}{}, "tags"},
+		{"Wrong array type", map[string]interface{}{"tags": "a"}, "tags"},
+		{"Wrong item type", map[string]interface{}{"tags": []interface{}{"a", 1.0}}, "tags[1]"},
+		{"Missing nested required", map[string]interface{}{"tags": []interface{}{}, "config": map[string]interface{}{}}, "config.name"},
+		{"Non-integral integer", map[string]interface{}{"tags": []interface{}{}, "config": map[string]interface{}{"name": "x", "retries": 1.5}}, "config.retries"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := nestedTestTool().ValidateArguments(tt.args)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("ValidateArguments() error = %v, want nil", err)
+				}
+				return
+			}
+
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("ValidateArguments() error = %v, want *ValidationError", err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("ValidateArguments() field = %v, want %v", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestTool_BooleanParameter(t *testing.T) {
+	tool := &Tool{
+		Name: "deploy",
+		Parameters: []ToolParameter{
+			{Name: "dry_run", Type: "boolean", Description: "Only print the plan", Required: true},
+		},
+	}
+
+	property := tool.InputSchema()["properties"].(map[string]interface{})["dry_run"].(map[string]interface{})
+	if property["type"] != "boolean" {
+		t.Errorf("dry_run type = %v, want boolean", property["type"])
+	}
+	if property["description"] != "Only print the plan" {
+		t.Errorf("dry_run description = %v, want %v", property["description"], "Only print the plan")
+	}
+
+	if err := tool.ValidateArguments(map[string]interface{}{"dry_run": true}); err != nil {
+		t.Errorf("ValidateArguments() error = %v, want nil", err)
+	}
+	for _, value := range []interface{}{"true", float64(1)} {
+		if err := tool.ValidateArguments(map[string]interface{}{"dry_run": value}); err == nil {
+			t.Errorf("ValidateArguments(%v) should reject non-boolean values", value)
+		}
+	}
+	if err := tool.ValidateArguments(map[string]interface{}{}); err == nil {
+		t.Error("ValidateArguments() should reject a missing required boolean")
+	}
+}
+
+func TestTool_NumericRange(t *testing.T) {
+	zero, hundred := 0.0, 100.0
+	tool := &Tool{
+		Name: "scale",
+		Parameters: []ToolParameter{
+			{Name: "factor", Type: "number", ExclusiveMinimum: &zero, Maximum: &hundred},
+			{Name: "steps", Type: "integer", Minimum: &zero, ExclusiveMaximum: &hundred},
+		},
+	}
+
+	factor := tool.InputSchema()["properties"].(map[string]interface{})["factor"].(map[string]interface{})
+	if factor["exclusiveMinimum"] != 0.0 || factor["maximum"] != 100.0 {
+		t.Errorf("factor schema = %v, want exclusiveMinimum 0 and maximum 100", factor)
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantField string
+	}{
+		{"In range", map[string]interface{}{"factor": 0.5, "steps": 0}, ""},
+		{"Inclusive maximum", map[string]interface{}{"factor": float64(100)}, ""},
+		{"Exclusive minimum", map[string]interface{}{"factor": float64(0)}, "factor"},
+		{"Above maximum", map[string]interface{}{"factor": 100.5}, "factor"},
+		{"Below minimum integer", map[string]interface{}{"steps": -1}, "steps"},
+		{"Exclusive maximum integer", map[string]interface{}{"steps": float64(100)}, "steps"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tool.ValidateArguments(tt.args)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("ValidateArguments() error = %v, want nil", err)
+				}
+				return
+			}
+
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("ValidateArguments() error = %v, want *ValidationError", err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("ValidateArguments() field = %v, want %v", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestTool_StringConstraints(t *testing.T) {
+	two, five := 2, 5
+	tool := &Tool{
+		Name: "tag",
+		Parameters: []ToolParameter{
+			{Name: "code", Type: "string", MinLength: &two, MaxLength: &five, Pattern: `^[a-z]+$`},
+			{Name: "labels", Type: "array", Items: &ToolParameter{Type: "string", Pattern: `^#`}},
+		},
+	}
+	if err := tool.CompilePatterns(); err != nil {
+		t.Fatalf("CompilePatterns() error = %v", err)
+	}
+
+	code := tool.InputSchema()["properties"].(map[string]interface{})["code"].(map[string]interface{})
+	if code["minLength"] != 2 || code["maxLength"] != 5 || code["pattern"] != `^[a-z]+$` {
+		t.Errorf("code schema = %v, want minLength, maxLength and pattern", code)
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantField string
+	}{
+		{"Valid", map[string]interface{}{"code": "abc", "labels": []interface{}{"#go"}}, ""},
+		{"Multibyte length", map[string]interface{}{"code": "éé"}, "code"},
+		{"Too short", map[string]interface{}{"code": "a"}, "code"},
+		{"Too long", map[string]interface{}{"code": "abcdef"}, "code"},
+		{"Pattern mismatch", map[string]interface{}{"code": "ABC"}, "code"},
+		{"Item pattern mismatch", map[string]interface{}{"labels": []interface{}{"#go", "rust"}}, "labels[1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tool.ValidateArguments(tt.args)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("ValidateArguments() error = %v, want nil", err)
+				}
+				return
+			}
+
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("ValidateArguments() error = %v, want *ValidationError", err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("ValidateArguments() field = %v, want %v", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestTool_CompilePatternsInvalid(t *testing.T) {
+	tool := &Tool{
+		Name: "broken",
+		Parameters: []ToolParameter{
+			{Name: "config", Type: "object", Properties: []ToolParameter{{Name: "id", Type: "string", Pattern: `([a-z`}}},
+		},
+	}
+
+	validationErr, ok := tool.CompilePatterns().(*ValidationError)
+	if !ok {
+		t.Fatalf("CompilePatterns() should return a *ValidationError for an invalid pattern")
+	}
+	if validationErr.Field != "config.id" {
+		t.Errorf("CompilePatterns() field = %v, want config.id", validationErr.Field)
+	}
+}
+
+func TestTool_Validate(t *testing.T) {
+	if err := nestedTestTool().Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		tool      *Tool
+		wantField string
+	}{
+		{"missing tool name", &Tool{}, "name"},
+		{"missing parameter name", &Tool{Name: "t", Parameters: []ToolParameter{{Type: "string"}}}, "parameters[0]"},
+		{"unknown type", &Tool{Name: "t", Parameters: []ToolParameter{{Name: "count", Type: "int"}}}, "count"},
+		{"missing type", &Tool{Name: "t", Parameters: []ToolParameter{{Name: "count"}}}, "count"},
+		{"unknown item type", &Tool{Name: "t", Parameters: []ToolParameter{{Name: "tags", Type: "array", Items: &ToolParameter{Type: "text"}}}}, "tags[]"},
+		{"missing property name", &Tool{Name: "t", Parameters: []ToolParameter{{Name: "config", Type: "object", Properties: []ToolParameter{{Type: "string"}}}}}, "config.parameters[0]"},
+		{"unknown output type", &Tool{Name: "t", OutputSchema: []ToolParameter{{Name: "temp", Type: "float"}}}, "structuredContent.temp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validationErr, ok := tt.tool.Validate().(*ValidationError)
+			if !ok {
+				t.Fatalf("Validate() should return a *ValidationError")
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("Validate() field = %v, want %v", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
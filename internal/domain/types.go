@@ -2,6 +2,9 @@
 package domain
 
 import (
+	"regexp"
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -27,6 +30,19 @@ type Resource struct {
 	Name        string
 	Description string
 	MIMEType    string
+	// ContentProvider supplies the resource contents for resources/read.
+	ContentProvider ResourceContentProvider
+}
+
+// ResourceTemplate describes a family of resources addressed by an RFC 6570
+// level 1 URI template such as "file:///{path}".
+type ResourceTemplate struct {
+	URITemplate string
+	Name        string
+	Description string
+	MIMEType    string
+	// ContentProvider supplies the contents of resources matching the template.
+	ContentProvider ResourceTemplateContentProvider
 }
 
 // ResourceContents represents the contents of a resource.
@@ -42,6 +58,25 @@ type Tool struct {
 	Name        string
 	Description string
 	Parameters  []ToolParameter
+	RateLimit   *ToolRateLimit
+	// Timeout overrides the server's request timeout for calls to this tool.
+	Timeout time.Duration
+	// LocalizedDescriptions maps a locale such as "fr" or "pt-BR" to a translated description.
+	LocalizedDescriptions map[string]string
+	// AutoWrapResults wraps plain handler return values as MCP content.
+	AutoWrapResults bool
+	// OutputSchema describes the fields of the tool's structuredContent result.
+	OutputSchema []ToolParameter
+	// ValidateOutput rejects results whose structuredContent does not match OutputSchema.
+	ValidateOutput bool
+	// Annotations are behavior hints advertised to clients in tools/list.
+	Annotations *ToolAnnotations
+}
+
+// ToolRateLimit limits how often a tool can be invoked across all sessions.
+type ToolRateLimit struct {
+	RequestsPerSecond int
+	Burst             int
 }
 
 // ToolParameter defines a parameter for a tool.
@@ -50,6 +85,25 @@ type ToolParameter struct {
 	Description string
 	Type        string
 	Required    bool
+	// Default is injected into the call arguments when an optional parameter is omitted.
+	Default interface{}
+	// Items describes the elements of an array parameter.
+	Items *ToolParameter
+	// Properties describes the fields of an object parameter.
+	Properties []ToolParameter
+	// Minimum and Maximum bound number and integer values inclusively;
+	// ExclusiveMinimum and ExclusiveMaximum bound them exclusively. Nil means unbounded.
+	Minimum          *float64
+	Maximum          *float64
+	ExclusiveMinimum *float64
+	ExclusiveMaximum *float64
+	// MinLength and MaxLength bound the length of a string value in characters.
+	MinLength *int
+	MaxLength *int
+	// Pattern is a regular expression a string value must match.
+	Pattern string
+
+	pattern *regexp.Regexp // Compiled Pattern, set by Tool.CompilePatterns
 }
 
 // ToolCall represents a request to execute a tool.
@@ -73,12 +127,17 @@ type Prompt struct {
 	Parameters  []PromptParameter
 }
 
-// PromptParameter defines a parameter for a prompt template.
+// PromptParameter defines a parameter for a prompt template. Type is one of
+// the tool parameter types; an empty Type accepts any value.
 type PromptParameter struct {
 	Name        string
 	Description string
 	Type        string
 	Required    bool
+	// Completable marks the argument as answered by completion/complete.
+	// Boolean arguments complete to true and false unless a completer or
+	// completion provider suggests other values.
+	Completable bool
 }
 
 // PromptRequest represents a request to render a prompt.
@@ -90,10 +149,22 @@ type PromptRequest struct {
 
 // PromptResult represents the result of a prompt rendering.
 type PromptResult struct {
-	Text  string
-	Error error
+	Description string
+	Text        string
+	Error       error
 }
 
+// HeartbeatMethod is the method of the heartbeat notifications that keep
+// client connections alive.
+const HeartbeatMethod = "notifications/heartbeat"
+
+// Methods of the notifications that tell clients a list changed.
+const (
+	ToolListChangedMethod     = "notifications/tools/list_changed"
+	ResourceListChangedMethod = "notifications/resources/list_changed"
+	PromptListChangedMethod   = "notifications/prompts/list_changed"
+)
+
 // Notification represents a notification that can be sent to clients.
 type Notification struct {
 	Method string
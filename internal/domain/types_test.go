@@ -129,3 +129,21 @@ func TestNotification(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceContents_ToMCP(t *testing.T) {
+	text := ResourceContents{URI: "a.txt", MIMEType: "text/plain", Text: "hi"}.ToMCP()
+	if text["text"] != "hi" {
+		t.Errorf("ToMCP()[\"text\"] = %v, want hi", text["text"])
+	}
+	if _, ok := text["blob"]; ok {
+		t.Error("ToMCP() should not include a blob for text contents")
+	}
+
+	blob := ResourceContents{URI: "a.png", MIMEType: "image/png", Content: []byte{0x89, 0x50}}.ToMCP()
+	if blob["blob"] != "iVA=" {
+		t.Errorf("ToMCP()[\"blob\"] = %v, want iVA=", blob["blob"])
+	}
+	if blob["mimeType"] != "image/png" {
+		t.Errorf("ToMCP()[\"mimeType\"] = %v, want image/png", blob["mimeType"])
+	}
+}
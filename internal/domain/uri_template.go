@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// URITemplate is a parsed RFC 6570 level 1 URI template, in which each
+// {var} expression is replaced by a percent-encoded value.
+type URITemplate struct {
+	raw       string
+	variables []string
+	pattern   *regexp.Regexp
+}
+
+// uriTemplateExpression matches a {...} expression in a URI template.
+var uriTemplateExpression = regexp.MustCompile(`\{[^{}]*\}`)
+
+// uriTemplateVarName matches a level 1 variable name.
+var uriTemplateVarName = regexp.MustCompile(`^(?:[A-Za-z0-9_]|%[0-9A-Fa-f]{2})+(?:\.(?:[A-Za-z0-9_]|%[0-9A-Fa-f]{2})+)*$`)
+
+// ParseURITemplate parses a level 1 URI template such as "file:///{path}".
+// Expressions with operators or modifiers from higher levels are rejected.
+func ParseURITemplate(template string) (*URITemplate, error) {
+	t := &URITemplate{raw: template}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	rest := template
+	for rest != "" {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			pattern.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		if rest[open] == '}' {
+			return nil, fmt.Errorf("invalid URI template %q: unmatched '}'", template)
+		}
+		pattern.WriteString(regexp.QuoteMeta(rest[:open]))
+
+		end := strings.IndexAny(rest[open+1:], "{}")
+		if end < 0 || rest[open+1+end] != '}' {
+			return nil, fmt.Errorf("invalid URI template %q: unclosed '{'", template)
+		}
+		name := rest[open+1 : open+1+end]
+		if !uriTemplateVarName.MatchString(name) {
+			return nil, fmt.Errorf("invalid URI template %q: unsupported expression {%s}", template, name)
+		}
+
+		t.variables = append(t.variables, name)
+		pattern.WriteString("(.*?)")
+		rest = rest[open+1+end+1:]
+	}
+	pattern.WriteString("$")
+
+	t.pattern = regexp.MustCompile(pattern.String())
+	return t, nil
+}
+
+// String returns the template as it was parsed.
+func (t *URITemplate) String() string {
+	return t.raw
+}
+
+// Variables returns the names of the template's variables in order.
+func (t *URITemplate) Variables() []string {
+	return append([]string(nil), t.variables...)
+}
+
+// Expand substitutes the variables into the template, percent-encoding every
+// character outside the unreserved set. Missing variables expand to empty.
+func (t *URITemplate) Expand(vars map[string]string) string {
+	i := 0
+	return uriTemplateExpression.ReplaceAllStringFunc(t.raw, func(string) string {
+		value := vars[t.variables[i]]
+		i++
+		return escapeUnreserved(value)
+	})
+}
+
+// Match reports whether uri is an expansion of the template and returns the
+// decoded variable values. Values may contain '/' so that templates such as
+// "file:///{path}" match nested paths; with several variables the earlier
+// ones match as little as possible.
+func (t *URITemplate) Match(uri string) (map[string]string, bool) {
+	groups := t.pattern.FindStringSubmatch(uri)
+	if groups == nil {
+		return nil, false
+	}
+
+	vars := make(map[string]string, len(t.variables))
+	for i, name := range t.variables {
+		value, err := url.PathUnescape(groups[i+1])
+		if err != nil {
+			value = groups[i+1]
+		}
+		vars[name] = value
+	}
+	return vars, true
+}
+
+// Match reports whether uri matches the resource template and returns the
+// values of the template variables.
+func (t *ResourceTemplate) Match(uri string) (map[string]string, bool) {
+	parsed, err := ParseURITemplate(t.URITemplate)
+	if err != nil {
+		return nil, false
+	}
+	return parsed.Match(uri)
+}
+
+// escapeUnreserved percent-encodes all characters except the RFC 3986
+// unreserved set, as required for level 1 simple string expansion.
+func escapeUnreserved(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
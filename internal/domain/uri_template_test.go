@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseURITemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantVars []string
+		wantErr  bool
+	}{
+		{"Single variable", "file:///{path}", []string{"path"}, false},
+		{"Several variables", "repo://{owner}/{name}/issues/{id}", []string{"owner", "name", "id"}, false},
+		{"No variables", "config://app", nil, false},
+		{"Dotted name", "db://{table.column}", []string{"table.column"}, false},
+		{"Unclosed expression", "file:///{path", nil, true},
+		{"Unmatched close", "file:///path}", nil, true},
+		{"Nested expression", "file:///{a{b}}", nil, true},
+		{"Empty expression", "file:///{}", nil, true},
+		{"Level 2 operator", "file:///{+path}", nil, true},
+		{"Variable list", "search://{q,lang}", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseURITemplate(tt.template)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseURITemplate(%q) error = nil, want error", tt.template)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseURITemplate(%q) error = %v", tt.template, err)
+			}
+			if !reflect.DeepEqual(parsed.Variables(), tt.wantVars) {
+				t.Errorf("Variables() = %v, want %v", parsed.Variables(), tt.wantVars)
+			}
+		})
+	}
+}
+
+func TestURITemplate_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		uri      string
+		want     map[string]string
+		wantOK   bool
+	}{
+		{"Single segment", "file:///{path}", "file:///notes.txt", map[string]string{"path": "notes.txt"}, true},
+		{"Nested path", "file:///{path}", "file:///docs/readme.md", map[string]string{"path": "docs/readme.md"}, true},
+		{"Percent-encoded value", "file:///{path}", "file:///docs%2Fmy%20notes.md", map[string]string{"path": "docs/my notes.md"}, true},
+		{"Several variables", "repo://{owner}/{name}/issues/{id}", "repo://golang/go/issues/42", map[string]string{"owner": "golang", "name": "go", "id": "42"}, true},
+		{"Different scheme", "file:///{path}", "http:///notes.txt", nil, false},
+		{"Missing literal suffix", "repo://{owner}/issues", "repo://golang/pulls", nil, false},
+		{"Literal metacharacters", "calc://{a}+{b}", "calc://1+2", map[string]string{"a": "1", "b": "2"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseURITemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseURITemplate(%q) error = %v", tt.template, err)
+			}
+			got, ok := parsed.Match(tt.uri)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Match(%q) = %v, %v, want %v, %v", tt.uri, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestURITemplate_Expand(t *testing.T) {
+	parsed, err := ParseURITemplate("repo://{owner}/{name}?q={query}")
+	if err != nil {
+		t.Fatalf("ParseURITemplate() error = %v", err)
+	}
+
+	got := parsed.Expand(map[string]string{"owner": "golang", "name": "go/x", "query": "Hello World!"})
+	want := "repo://golang/go%2Fx?q=Hello%20World%21"
+	if got != want {
+		t.Errorf("Expand() = %v, want %v", got, want)
+	}
+
+	if vars, ok := parsed.Match(got); !ok || vars["name"] != "go/x" || vars["query"] != "Hello World!" {
+		t.Errorf("Match(Expand()) = %v, %v, want the original values", vars, ok)
+	}
+}
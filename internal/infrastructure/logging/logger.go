@@ -3,6 +3,7 @@ package logging
 
 import (
 	"context"
+	"fmt"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -12,6 +13,8 @@ import (
 type Logger struct {
 	logger *zap.Logger
 	sugar  *zap.SugaredLogger
+	// level is shared with loggers derived by With; nil if it cannot change
+	level *zap.AtomicLevel
 }
 
 // Fields is a type alias for key-value pairs
@@ -68,27 +71,15 @@ func ProductionConfig() Config {
 // New creates a new logger with the given configuration
 func New(config Config) (*Logger, error) {
 	// Convert log level to zapcore level
-	var level zapcore.Level
-	switch config.Level {
-	case DebugLevel:
-		level = zapcore.DebugLevel
-	case InfoLevel:
-		level = zapcore.InfoLevel
-	case WarnLevel:
-		level = zapcore.WarnLevel
-	case ErrorLevel:
-		level = zapcore.ErrorLevel
-	case FatalLevel:
-		level = zapcore.FatalLevel
-	case PanicLevel:
-		level = zapcore.PanicLevel
-	default:
+	level, ok := zapLevel(config.Level)
+	if !ok {
 		level = zapcore.InfoLevel
 	}
+	atomicLevel := zap.NewAtomicLevelAt(level)
 
 	// Create zap configuration
 	zapConfig := zap.Config{
-		Level:             zap.NewAtomicLevelAt(level),
+		Level:             atomicLevel,
 		Development:       config.Development,
 		DisableCaller:     !config.Development,
 		DisableStacktrace: !config.Development,
@@ -129,9 +120,75 @@ func New(config Config) (*Logger, error) {
 	return &Logger{
 		logger: zapLogger,
 		sugar:  zapLogger.Sugar(),
+		level:  &atomicLevel,
 	}, nil
 }
 
+// zapLevel converts a LogLevel to the zap level.
+func zapLevel(level LogLevel) (zapcore.Level, bool) {
+	switch level {
+	case DebugLevel:
+		return zapcore.DebugLevel, true
+	case InfoLevel:
+		return zapcore.InfoLevel, true
+	case WarnLevel:
+		return zapcore.WarnLevel, true
+	case ErrorLevel:
+		return zapcore.ErrorLevel, true
+	case FatalLevel:
+		return zapcore.FatalLevel, true
+	case PanicLevel:
+		return zapcore.PanicLevel, true
+	default:
+		return zapcore.InfoLevel, false
+	}
+}
+
+// SetLevel changes the minimum level logged by the logger and the loggers
+// derived from it with With. It is safe to call while logging.
+func (l *Logger) SetLevel(level LogLevel) error {
+	zl, ok := zapLevel(level)
+	if !ok {
+		return fmt.Errorf("unknown log level: %s", level)
+	}
+	if l.level == nil {
+		return fmt.Errorf("logger level cannot be changed")
+	}
+	l.level.SetLevel(zl)
+	return nil
+}
+
+// Level returns the minimum level logged by the logger.
+func (l *Logger) Level() LogLevel {
+	if l.level == nil {
+		return LogLevel(zapcore.LevelOf(l.logger.Core()).String())
+	}
+	return LogLevel(l.level.Level().String())
+}
+
+// Enabled reports whether the logger logs messages at the given level.
+func (l *Logger) Enabled(level LogLevel) bool {
+	zl, ok := zapLevel(level)
+	return ok && l.logger.Core().Enabled(zl)
+}
+
+// LevelFromMCP maps a log level name of the MCP protocol, as sent in a
+// logging/setLevel request, to a LogLevel. It reports false for unknown names.
+func LevelFromMCP(level string) (LogLevel, bool) {
+	switch level {
+	case "debug":
+		return DebugLevel, true
+	case "info", "notice":
+		return InfoLevel, true
+	case "warning":
+		return WarnLevel, true
+	case "error", "critical", "alert", "emergency":
+		return ErrorLevel, true
+	default:
+		return "", false
+	}
+}
+
 // NewDevelopment creates a new development logger
 func NewDevelopment() (*Logger, error) {
 	return New(DevelopmentConfig())
@@ -157,6 +214,7 @@ func (l *Logger) With(fields Fields) *Logger {
 	return &Logger{
 		logger: newLogger,
 		sugar:  newLogger.Sugar(),
+		level:  l.level,
 	}
 }
 
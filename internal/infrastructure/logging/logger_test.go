@@ -524,3 +524,46 @@ func TestDefaultLogger(t *testing.T) {
 		t.Error("Expected SetDefault to set the default logger")
 	}
 }
+
+func TestLoggerSetLevel(t *testing.T) {
+	logger, err := New(Config{Level: InfoLevel, OutputPaths: []string{"stderr"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	derived := logger.With(Fields{"component": "test"})
+
+	if err := logger.SetLevel(WarnLevel); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	if logger.Level() != WarnLevel || derived.Level() != WarnLevel {
+		t.Errorf("Level() = %s, derived %s, want warn for both", logger.Level(), derived.Level())
+	}
+	if err := logger.SetLevel("verbose"); err == nil {
+		t.Error("SetLevel() with an unknown level succeeded, want error")
+	}
+
+	// Loggers built without an atomic level cannot change level
+	fixed, _ := newTestLogger(t)
+	if err := fixed.SetLevel(InfoLevel); err == nil {
+		t.Error("SetLevel() on a fixed logger succeeded, want error")
+	}
+}
+
+func TestLevelFromMCP(t *testing.T) {
+	tests := map[string]LogLevel{
+		"debug":     DebugLevel,
+		"info":      InfoLevel,
+		"notice":    InfoLevel,
+		"warning":   WarnLevel,
+		"error":     ErrorLevel,
+		"emergency": ErrorLevel,
+	}
+	for name, want := range tests {
+		if got, ok := LevelFromMCP(name); !ok || got != want {
+			t.Errorf("LevelFromMCP(%q) = %s, %v, want %s", name, got, ok, want)
+		}
+	}
+	if _, ok := LevelFromMCP("warn"); ok {
+		t.Error(`LevelFromMCP("warn") succeeded, want unknown`)
+	}
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CORSPolicy controls the CORS headers sent to browser clients. The zero
+// value allows any origin without credentials, sending
+// Access-Control-Allow-Origin: *.
+type CORSPolicy struct {
+	// AllowedOrigins lists the origins allowed to call the server, such as
+	// "https://app.example.com". Empty or "*" allows any origin.
+	AllowedOrigins []string
+	// AllowCredentials lets browsers send cookies and authorization headers.
+	// The request's Origin is echoed back instead of *, which browsers
+	// reject for credentialed requests.
+	AllowCredentials bool
+}
+
+// allowsAnyOrigin reports whether the policy allows every origin.
+func (p CORSPolicy) allowsAnyOrigin() bool {
+	if len(p.AllowedOrigins) == 0 {
+		return true
+	}
+	for _, origin := range p.AllowedOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// allows reports whether the policy allows origin.
+func (p CORSPolicy) allows(origin string) bool {
+	if p.allowsAnyOrigin() {
+		return true
+	}
+	for _, allowed := range p.AllowedOrigins {
+		if strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetHeaders sets the CORS headers for a response to r. It reports whether
+// the request's origin is allowed; no Access-Control-Allow-Origin header is
+// sent for origins that are not.
+func (p CORSPolicy) SetHeaders(w http.ResponseWriter, r *http.Request) bool {
+	if p.allowsAnyOrigin() && !p.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return true
+	}
+
+	// The response depends on the origin, so caches must not share it
+	if !headerContains(w.Header(), "Vary", "Origin") {
+		w.Header().Add("Vary", "Origin")
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" || !p.allows(origin) {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	if p.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+	return true
+}
+
+// HandlePreflight answers CORS preflight requests, which are OPTIONS
+// requests with an Access-Control-Request-Method header. It reports whether
+// r was a preflight request and has been answered: with 204 No Content if
+// the origin is allowed and 403 Forbidden otherwise.
+func (p CORSPolicy) HandlePreflight(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+		return false
+	}
+
+	if !p.SetHeaders(w, r) {
+		w.WriteHeader(http.StatusForbidden)
+		return true
+	}
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+		w.Header().Set("Access-Control-Allow-Headers", headers)
+	} else {
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	}
+	w.Header().Set("Access-Control-Max-Age", "600")
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// Handler wraps next so every response carries the policy's CORS headers
+// and preflight requests are answered before reaching next.
+func (p CORSPolicy) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p.HandlePreflight(w, r) {
+			return
+		}
+		p.SetHeaders(w, r)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// headerContains reports whether any value of the header key lists token.
+func headerContains(header http.Header, key, token string) bool {
+	for _, value := range header.Values(key) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
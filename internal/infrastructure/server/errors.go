@@ -13,6 +13,9 @@ var (
 	// ErrSessionClosed is returned when attempting to use a closed session
 	ErrSessionClosed = errors.New("session is closed")
 
+	// ErrDuplicateSession is returned when a session ID is already in use and duplicates are rejected
+	ErrDuplicateSession = errors.New("session ID already in use")
+
 	// ErrChannelFull is returned when a notification channel is full
 	ErrChannelFull = errors.New("notification channel is full")
 )
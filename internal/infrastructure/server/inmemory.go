@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
@@ -9,130 +10,264 @@ import (
 
 // InMemoryResourceRepository implements a ResourceRepository using in-memory storage.
 type InMemoryResourceRepository struct {
-	resources sync.Map
+	mu        sync.RWMutex
+	resources map[string]*domain.Resource
 }
 
 // NewInMemoryResourceRepository creates a new InMemoryResourceRepository.
 func NewInMemoryResourceRepository() *InMemoryResourceRepository {
-	return &InMemoryResourceRepository{}
+	return &InMemoryResourceRepository{resources: make(map[string]*domain.Resource)}
 }
 
 // GetResource retrieves a resource by its URI.
 func (r *InMemoryResourceRepository) GetResource(ctx context.Context, uri string) (*domain.Resource, error) {
-	if resource, ok := r.resources.Load(uri); ok {
-		return resource.(*domain.Resource), nil
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if resource, ok := r.resources[uri]; ok {
+		return resource, nil
 	}
 	return nil, domain.NewResourceNotFoundError(uri)
 }
 
 // ListResources returns all available resources.
 func (r *InMemoryResourceRepository) ListResources(ctx context.Context) ([]*domain.Resource, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var resources []*domain.Resource
-	r.resources.Range(func(_, value interface{}) bool {
-		resources = append(resources, value.(*domain.Resource))
-		return true
-	})
+	for _, resource := range r.resources {
+		resources = append(resources, resource)
+	}
 	return resources, nil
 }
 
 // AddResource adds a new resource to the repository.
 func (r *InMemoryResourceRepository) AddResource(ctx context.Context, resource *domain.Resource) error {
-	r.resources.Store(resource.URI, resource)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resources[resource.URI] = resource
 	return nil
 }
 
 // DeleteResource removes a resource from the repository.
 func (r *InMemoryResourceRepository) DeleteResource(ctx context.Context, uri string) error {
-	if _, ok := r.resources.Load(uri); !ok {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.resources[uri]; !ok {
 		return domain.NewResourceNotFoundError(uri)
 	}
-	r.resources.Delete(uri)
+	delete(r.resources, uri)
+	return nil
+}
+
+// ReplaceResources atomically replaces all resources in the repository.
+func (r *InMemoryResourceRepository) ReplaceResources(ctx context.Context, resources []*domain.Resource) error {
+	replacement := make(map[string]*domain.Resource, len(resources))
+	for _, resource := range resources {
+		replacement[resource.URI] = resource
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resources = replacement
+	return nil
+}
+
+// InMemoryResourceTemplateRepository implements a ResourceTemplateRepository using in-memory storage.
+type InMemoryResourceTemplateRepository struct {
+	mu        sync.RWMutex
+	templates []*domain.ResourceTemplate
+}
+
+// NewInMemoryResourceTemplateRepository creates a new InMemoryResourceTemplateRepository.
+func NewInMemoryResourceTemplateRepository() *InMemoryResourceTemplateRepository {
+	return &InMemoryResourceTemplateRepository{}
+}
+
+// ListResourceTemplates returns all resource templates in registration order.
+func (r *InMemoryResourceTemplateRepository) ListResourceTemplates(ctx context.Context) ([]*domain.ResourceTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]*domain.ResourceTemplate(nil), r.templates...), nil
+}
+
+// AddResourceTemplate adds a resource template, replacing any template with the same URI template.
+func (r *InMemoryResourceTemplateRepository) AddResourceTemplate(ctx context.Context, template *domain.ResourceTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.templates {
+		if existing.URITemplate == template.URITemplate {
+			r.templates[i] = template
+			return nil
+		}
+	}
+	r.templates = append(r.templates, template)
 	return nil
 }
 
+// DeleteResourceTemplate removes the resource template with the given URI template.
+func (r *InMemoryResourceTemplateRepository) DeleteResourceTemplate(ctx context.Context, uriTemplate string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.templates {
+		if existing.URITemplate == uriTemplate {
+			r.templates = append(r.templates[:i], r.templates[i+1:]...)
+			return nil
+		}
+	}
+	return domain.NewResourceNotFoundError(uriTemplate)
+}
+
 // InMemoryToolRepository implements a ToolRepository using in-memory storage.
 type InMemoryToolRepository struct {
-	tools sync.Map
+	mu    sync.RWMutex
+	tools map[string]*domain.Tool
 }
 
 // NewInMemoryToolRepository creates a new InMemoryToolRepository.
 func NewInMemoryToolRepository() *InMemoryToolRepository {
-	return &InMemoryToolRepository{}
+	return &InMemoryToolRepository{tools: make(map[string]*domain.Tool)}
 }
 
 // GetTool retrieves a tool by its name.
 func (r *InMemoryToolRepository) GetTool(ctx context.Context, name string) (*domain.Tool, error) {
-	if tool, ok := r.tools.Load(name); ok {
-		return tool.(*domain.Tool), nil
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if tool, ok := r.tools[name]; ok {
+		return tool, nil
 	}
 	return nil, domain.NewToolNotFoundError(name)
 }
 
 // ListTools returns all available tools.
 func (r *InMemoryToolRepository) ListTools(ctx context.Context) ([]*domain.Tool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var tools []*domain.Tool
-	r.tools.Range(func(_, value interface{}) bool {
-		tools = append(tools, value.(*domain.Tool))
-		return true
-	})
+	for _, tool := range r.tools {
+		tools = append(tools, tool)
+	}
 	return tools, nil
 }
 
 // AddTool adds a new tool to the repository.
 func (r *InMemoryToolRepository) AddTool(ctx context.Context, tool *domain.Tool) error {
-	r.tools.Store(tool.Name, tool)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tools[tool.Name] = tool
+	return nil
+}
+
+// AddTools atomically adds several tools. Nothing is added if a name is
+// already taken or repeated.
+func (r *InMemoryToolRepository) AddTools(ctx context.Context, tools []*domain.Tool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		if _, ok := r.tools[tool.Name]; ok || names[tool.Name] {
+			return fmt.Errorf("tool %s: %w", tool.Name, domain.ErrDuplicateTool)
+		}
+		names[tool.Name] = true
+	}
+	for _, tool := range tools {
+		r.tools[tool.Name] = tool
+	}
 	return nil
 }
 
 // DeleteTool removes a tool from the repository.
 func (r *InMemoryToolRepository) DeleteTool(ctx context.Context, name string) error {
-	if _, ok := r.tools.Load(name); !ok {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tools[name]; !ok {
 		return domain.NewToolNotFoundError(name)
 	}
-	r.tools.Delete(name)
+	delete(r.tools, name)
+	return nil
+}
+
+// UpdateTool atomically replaces an existing tool.
+func (r *InMemoryToolRepository) UpdateTool(ctx context.Context, tool *domain.Tool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tools[tool.Name]; !ok {
+		return domain.NewToolNotFoundError(tool.Name)
+	}
+	r.tools[tool.Name] = tool
+	return nil
+}
+
+// ReplaceTools atomically replaces all tools in the repository.
+func (r *InMemoryToolRepository) ReplaceTools(ctx context.Context, tools []*domain.Tool) error {
+	replacement := make(map[string]*domain.Tool, len(tools))
+	for _, tool := range tools {
+		replacement[tool.Name] = tool
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tools = replacement
 	return nil
 }
 
 // InMemoryPromptRepository implements a PromptRepository using in-memory storage.
 type InMemoryPromptRepository struct {
-	prompts sync.Map
+	mu      sync.RWMutex
+	prompts map[string]*domain.Prompt
 }
 
 // NewInMemoryPromptRepository creates a new InMemoryPromptRepository.
 func NewInMemoryPromptRepository() *InMemoryPromptRepository {
-	return &InMemoryPromptRepository{}
+	return &InMemoryPromptRepository{prompts: make(map[string]*domain.Prompt)}
 }
 
 // GetPrompt retrieves a prompt by its name.
 func (r *InMemoryPromptRepository) GetPrompt(ctx context.Context, name string) (*domain.Prompt, error) {
-	if prompt, ok := r.prompts.Load(name); ok {
-		return prompt.(*domain.Prompt), nil
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if prompt, ok := r.prompts[name]; ok {
+		return prompt, nil
 	}
 	return nil, domain.NewPromptNotFoundError(name)
 }
 
 // ListPrompts returns all available prompts.
 func (r *InMemoryPromptRepository) ListPrompts(ctx context.Context) ([]*domain.Prompt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var prompts []*domain.Prompt
-	r.prompts.Range(func(_, value interface{}) bool {
-		prompts = append(prompts, value.(*domain.Prompt))
-		return true
-	})
+	for _, prompt := range r.prompts {
+		prompts = append(prompts, prompt)
+	}
 	return prompts, nil
 }
 
 // AddPrompt adds a new prompt to the repository.
 func (r *InMemoryPromptRepository) AddPrompt(ctx context.Context, prompt *domain.Prompt) error {
-	r.prompts.Store(prompt.Name, prompt)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prompts[prompt.Name] = prompt
 	return nil
 }
 
 // DeletePrompt removes a prompt from the repository.
 func (r *InMemoryPromptRepository) DeletePrompt(ctx context.Context, name string) error {
-	if _, ok := r.prompts.Load(name); !ok {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.prompts[name]; !ok {
 		return domain.NewPromptNotFoundError(name)
 	}
-	r.prompts.Delete(name)
+	delete(r.prompts, name)
+	return nil
+}
+
+// ReplacePrompts atomically replaces all prompts in the repository.
+func (r *InMemoryPromptRepository) ReplacePrompts(ctx context.Context, prompts []*domain.Prompt) error {
+	replacement := make(map[string]*domain.Prompt, len(prompts))
+	for _, prompt := range prompts {
+		replacement[prompt.Name] = prompt
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prompts = replacement
 	return nil
 }
 
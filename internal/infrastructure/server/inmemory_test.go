@@ -91,6 +91,58 @@ func TestInMemoryResourceRepository_DeleteResource(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestInMemoryResourceTemplateRepository(t *testing.T) {
+	repo := NewInMemoryResourceTemplateRepository()
+	ctx := context.Background()
+
+	require.NoError(t, repo.AddResourceTemplate(ctx, &domain.ResourceTemplate{URITemplate: "file:///{path}", Name: "Files"}))
+	require.NoError(t, repo.AddResourceTemplate(ctx, &domain.ResourceTemplate{URITemplate: "repo://{name}", Name: "Repos"}))
+	require.NoError(t, repo.AddResourceTemplate(ctx, &domain.ResourceTemplate{URITemplate: "file:///{path}", Name: "Workspace files"}))
+
+	// Templates keep registration order and re-registering replaces in place
+	templates, err := repo.ListResourceTemplates(ctx)
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	assert.Equal(t, "Workspace files", templates[0].Name)
+	assert.Equal(t, "Repos", templates[1].Name)
+
+	require.NoError(t, repo.DeleteResourceTemplate(ctx, "file:///{path}"))
+	templates, err = repo.ListResourceTemplates(ctx)
+	require.NoError(t, err)
+	assert.Len(t, templates, 1)
+
+	_, ok := repo.DeleteResourceTemplate(ctx, "file:///{path}").(*domain.ResourceNotFoundError)
+	assert.True(t, ok)
+}
+
+func TestInMemoryRepositories_Replace(t *testing.T) {
+	ctx := context.Background()
+
+	tools := NewInMemoryToolRepository()
+	require.NoError(t, tools.AddTool(ctx, &domain.Tool{Name: "old"}))
+	require.NoError(t, tools.ReplaceTools(ctx, []*domain.Tool{{Name: "a"}, {Name: "b"}}))
+	list, err := tools.ListTools(ctx)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+	_, err = tools.GetTool(ctx, "old")
+	assert.Error(t, err)
+
+	resources := NewInMemoryResourceRepository()
+	require.NoError(t, resources.AddResource(ctx, &domain.Resource{URI: "old://x"}))
+	require.NoError(t, resources.ReplaceResources(ctx, []*domain.Resource{{URI: "new://x"}}))
+	_, err = resources.GetResource(ctx, "new://x")
+	assert.NoError(t, err)
+	_, err = resources.GetResource(ctx, "old://x")
+	assert.Error(t, err)
+
+	prompts := NewInMemoryPromptRepository()
+	require.NoError(t, prompts.AddPrompt(ctx, &domain.Prompt{Name: "old"}))
+	require.NoError(t, prompts.ReplacePrompts(ctx, nil))
+	promptList, err := prompts.ListPrompts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, promptList)
+}
+
 func TestNewInMemoryToolRepository(t *testing.T) {
 	repo := NewInMemoryToolRepository()
 	assert.NotNil(t, repo)
@@ -147,6 +199,37 @@ func TestInMemoryToolRepository_ListTools(t *testing.T) {
 	assert.Contains(t, names, "tool2")
 }
 
+func TestInMemoryToolRepository_AddTools(t *testing.T) {
+	repo := NewInMemoryToolRepository()
+	ctx := context.Background()
+	require.NoError(t, repo.AddTools(ctx, []*domain.Tool{{Name: "a"}, {Name: "b"}}))
+
+	// A taken name rejects the whole batch
+	err := repo.AddTools(ctx, []*domain.Tool{{Name: "c"}, {Name: "a"}})
+	assert.ErrorIs(t, err, domain.ErrDuplicateTool)
+	list, err := repo.ListTools(ctx)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+}
+
+func TestInMemoryToolRepository_UpdateTool(t *testing.T) {
+	repo := NewInMemoryToolRepository()
+	ctx := context.Background()
+
+	// Updating a missing tool does not create it
+	err := repo.UpdateTool(ctx, &domain.Tool{Name: "test-tool"})
+	var notFound *domain.ToolNotFoundError
+	assert.ErrorAs(t, err, &notFound)
+	_, err = repo.GetTool(ctx, "test-tool")
+	assert.Error(t, err)
+
+	require.NoError(t, repo.AddTool(ctx, &domain.Tool{Name: "test-tool", Description: "old"}))
+	require.NoError(t, repo.UpdateTool(ctx, &domain.Tool{Name: "test-tool", Description: "new"}))
+	tool, err := repo.GetTool(ctx, "test-tool")
+	require.NoError(t, err)
+	assert.Equal(t, "new", tool.Description)
+}
+
 func TestInMemoryToolRepository_DeleteTool(t *testing.T) {
 	repo := NewInMemoryToolRepository()
 	ctx := context.Background()
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/google/uuid"
+)
+
+// defaultInMemoryBufferSize is the number of messages buffered in each
+// direction of an in-memory transport pair.
+const defaultInMemoryBufferSize = 100
+
+// InMemoryOption defines a function type for configuring the server end of an
+// in-memory transport pair.
+type InMemoryOption func(*InMemoryTransport)
+
+// WithInMemoryMessageHandler sets the handler that processes messages sent by
+// the client end.
+func WithInMemoryMessageHandler(handler func(ctx context.Context, rawMessage json.RawMessage) interface{}) InMemoryOption {
+	return func(t *InMemoryTransport) {
+		t.mcpHandler = handler
+	}
+}
+
+// WithInMemoryNotifier registers the pair's session with the notification
+// sender so server notifications are delivered to the client end.
+func WithInMemoryNotifier(notifier *NotificationSender) InMemoryOption {
+	return func(t *InMemoryTransport) {
+		t.notifier = notifier
+	}
+}
+
+// WithInMemorySessionID sets the session ID shared by both ends of the pair.
+func WithInMemorySessionID(sessionID string) InMemoryOption {
+	return func(t *InMemoryTransport) {
+		t.sessionID = sessionID
+	}
+}
+
+// inMemoryPipe is the state shared by both ends of a pair.
+type inMemoryPipe struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// InMemoryTransport is one end of an in-process transport pair connected by
+// channels. It is meant for tests that drive the full server dispatch loop
+// without sockets or stdio pipes.
+//
+// The server end passes each message it receives to its message handler and
+// sends the response back. The client end has no handler; its messages are
+// read with Receive. Closing either end closes the pair.
+type InMemoryTransport struct {
+	sessionID  string
+	mcpHandler func(ctx context.Context, rawMessage json.RawMessage) interface{}
+	notifier   *NotificationSender
+	in         chan []byte
+	out        chan []byte
+	pipe       *inMemoryPipe
+	store      *domain.SessionStore
+	info       *domain.SessionInfoHolder
+}
+
+// Ensure InMemoryTransport implements the domain.Transport interface
+var _ domain.Transport = (*InMemoryTransport)(nil)
+
+// NewInMemoryTransportPair creates a connected client and server transport.
+// The options configure the server end.
+func NewInMemoryTransportPair(opts ...InMemoryOption) (client *InMemoryTransport, server *InMemoryTransport) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pipe := &inMemoryPipe{ctx: ctx, cancel: cancel}
+	toServer := make(chan []byte, defaultInMemoryBufferSize)
+	toClient := make(chan []byte, defaultInMemoryBufferSize)
+
+	server = &InMemoryTransport{
+		sessionID: uuid.New().String(),
+		in:        toServer,
+		out:       toClient,
+		pipe:      pipe,
+	}
+	for _, opt := range opts {
+		opt(server)
+	}
+	server.store = domain.NewSessionStore()
+	server.info = domain.NewSessionInfoHolder(server.sessionID, "in-memory")
+
+	client = &InMemoryTransport{
+		sessionID: server.sessionID,
+		in:        toClient,
+		out:       toServer,
+		pipe:      pipe,
+	}
+	return client, server
+}
+
+// SessionID returns the session ID shared by both ends of the pair.
+func (t *InMemoryTransport) SessionID() string {
+	return t.sessionID
+}
+
+// Start serves the transport until the pair is closed. On the server end it
+// dispatches incoming messages to the message handler one at a time, in the
+// order they were sent. On the client end it only waits for Close.
+func (t *InMemoryTransport) Start() error {
+	if t.mcpHandler == nil {
+		<-t.pipe.ctx.Done()
+		return nil
+	}
+	defer t.store.Clear()
+
+	if t.notifier != nil {
+		mcpSession := NewMCPSession(t.sessionID, "in-memory", 100)
+		t.notifier.RegisterSession(mcpSession)
+		defer t.notifier.unregisterSessionInstance(mcpSession)
+		go t.forwardNotifications(mcpSession)
+	}
+
+	for {
+		select {
+		case message := <-t.in:
+			ctx := domain.WithSessionStore(t.pipe.ctx, t.store)
+			ctx = domain.WithSessionInfo(ctx, t.info)
+			response := t.mcpHandler(ctx, json.RawMessage(message))
+			if response == nil {
+				continue
+			}
+			if err := t.Send(t.sessionID, response); err != nil {
+				return nil
+			}
+		case <-t.pipe.ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Send sends a message to the other end of the pair. It blocks while the
+// other end's buffer is full and fails once the pair is closed.
+func (t *InMemoryTransport) Send(sessionID string, message interface{}) error {
+	if sessionID != t.sessionID {
+		return ErrSessionNotFound
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	// Check first so a closed pair never accepts a message
+	if t.pipe.ctx.Err() != nil {
+		return ErrSessionClosed
+	}
+	select {
+	case t.out <- data:
+		return nil
+	case <-t.pipe.ctx.Done():
+		return ErrSessionClosed
+	}
+}
+
+// Receive returns the next message sent by the other end. It blocks until a
+// message arrives, ctx is done or the pair is closed. Messages sent before
+// the pair was closed can still be received.
+func (t *InMemoryTransport) Receive(ctx context.Context) (json.RawMessage, error) {
+	select {
+	case message := <-t.in:
+		return message, nil
+	default:
+	}
+
+	select {
+	case message := <-t.in:
+		return message, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-t.pipe.ctx.Done():
+		return nil, ErrSessionClosed
+	}
+}
+
+// Close closes both ends of the pair. Requests being handled see their
+// context canceled. Calling Close more than once is safe.
+func (t *InMemoryTransport) Close() error {
+	t.pipe.once.Do(t.pipe.cancel)
+	return nil
+}
+
+// forwardNotifications sends notifications for the session to the client end.
+func (t *InMemoryTransport) forwardNotifications(mcpSession *MCPSession) {
+	for {
+		select {
+		case notification, ok := <-mcpSession.NotificationChannel():
+			if !ok {
+				return
+			}
+			if err := t.Send(t.sessionID, notification); err != nil {
+				return
+			}
+		case <-t.pipe.ctx.Done():
+			return
+		}
+	}
+}
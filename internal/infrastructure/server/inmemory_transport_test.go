@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryTransportPair(t *testing.T) {
+	handler := func(ctx context.Context, rawMessage json.RawMessage) interface{} {
+		var request domain.JSONRPCRequest
+		_ = json.Unmarshal(rawMessage, &request)
+		if request.ID == nil {
+			return nil
+		}
+		info, _ := domain.SessionInfoFromContext(ctx)
+		return domain.CreateResponse("2.0", request.ID, map[string]interface{}{"method": request.Method, "session": info.ID})
+	}
+	notifier := NewNotificationSender("2.0")
+	client, srv := NewInMemoryTransportPair(
+		WithInMemoryMessageHandler(handler),
+		WithInMemoryNotifier(notifier),
+		WithInMemorySessionID("mem-1"),
+	)
+	assert.Equal(t, "mem-1", client.SessionID())
+
+	done := make(chan error, 1)
+	go func() { done <- srv.Start() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Notifications get no response; requests are answered in order
+	require.NoError(t, client.Send("mem-1", json.RawMessage(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)))
+	require.NoError(t, client.Send("mem-1", json.RawMessage(`{"jsonrpc":"2.0","id":7,"method":"ping"}`)))
+	message, err := client.Receive(ctx)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":7,"result":{"method":"ping","session":"mem-1"}}`, string(message))
+
+	// Server notifications reach the client end
+	require.Eventually(t, func() bool {
+		return notifier.SendNotification(ctx, "mem-1", &domain.Notification{Method: "notifications/tools/list_changed"}) == nil
+	}, time.Second, 10*time.Millisecond)
+	message, err = client.Receive(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, string(message), "notifications/tools/list_changed")
+
+	assert.ErrorIs(t, srv.Send("unknown", "x"), ErrSessionNotFound)
+
+	// Closing either end closes the pair
+	require.NoError(t, client.Close())
+	require.NoError(t, <-done)
+	assert.ErrorIs(t, client.Send("mem-1", "x"), ErrSessionClosed)
+	_, err = client.Receive(ctx)
+	assert.ErrorIs(t, err, ErrSessionClosed)
+}
@@ -83,6 +83,15 @@ func (n *NotificationSender) UnregisterSession(sessionID string) {
 	}
 }
 
+// unregisterSessionInstance unregisters the given session only if it is still
+// the session registered under its ID. A newer session that reused the same ID
+// is left untouched.
+func (n *NotificationSender) unregisterSessionInstance(session *MCPSession) {
+	if n.sessions.CompareAndDelete(session.ID(), session) {
+		session.Close()
+	}
+}
+
 // SendNotification sends a notification to a specific client.
 func (n *NotificationSender) SendNotification(ctx context.Context, sessionID string, notification *domain.Notification) error {
 	value, ok := n.sessions.Load(sessionID)
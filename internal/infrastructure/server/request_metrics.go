@@ -0,0 +1,125 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the default upper bounds, in seconds, of the
+// request latency histogram.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// RequestMetrics is an in-memory domain.MetricsCollector that keeps per-method
+// request counts, error counts and latency histograms and serves them in the
+// Prometheus text exposition format.
+type RequestMetrics struct {
+	mu      sync.Mutex
+	buckets []float64
+	methods map[string]*methodMetrics
+}
+
+// methodMetrics holds the metrics of a single method.
+type methodMetrics struct {
+	requests     uint64
+	errors       uint64
+	bucketCounts []uint64
+	sum          float64
+}
+
+// NewRequestMetrics creates a RequestMetrics with the given latency histogram
+// buckets in seconds, or DefaultLatencyBuckets if none are given.
+func NewRequestMetrics(buckets ...float64) *RequestMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &RequestMetrics{
+		buckets: sorted,
+		methods: make(map[string]*methodMetrics),
+	}
+}
+
+// ObserveRequest records one processed request.
+func (m *RequestMetrics) ObserveRequest(method string, duration time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	metrics, ok := m.methods[method]
+	if !ok {
+		metrics = &methodMetrics{bucketCounts: make([]uint64, len(m.buckets))}
+		m.methods[method] = metrics
+	}
+
+	seconds := duration.Seconds()
+	metrics.requests++
+	if failed {
+		metrics.errors++
+	}
+	metrics.sum += seconds
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			metrics.bucketCounts[i]++
+		}
+	}
+}
+
+// WritePrometheus writes all metrics in the Prometheus text exposition format.
+func (m *RequestMetrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	methods := make([]string, 0, len(m.methods))
+	for method := range m.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	var b strings.Builder
+	b.WriteString("# HELP mcp_requests_total Total number of JSON-RPC requests by method.\n")
+	b.WriteString("# TYPE mcp_requests_total counter\n")
+	for _, method := range methods {
+		fmt.Fprintf(&b, "mcp_requests_total{method=%s} %d\n", labelValue(method), m.methods[method].requests)
+	}
+
+	b.WriteString("# HELP mcp_request_errors_total Total number of JSON-RPC requests that returned an error by method.\n")
+	b.WriteString("# TYPE mcp_request_errors_total counter\n")
+	for _, method := range methods {
+		fmt.Fprintf(&b, "mcp_request_errors_total{method=%s} %d\n", labelValue(method), m.methods[method].errors)
+	}
+
+	b.WriteString("# HELP mcp_request_duration_seconds JSON-RPC request latency by method.\n")
+	b.WriteString("# TYPE mcp_request_duration_seconds histogram\n")
+	for _, method := range methods {
+		metrics := m.methods[method]
+		label := labelValue(method)
+		for i, bound := range m.buckets {
+			fmt.Fprintf(&b, "mcp_request_duration_seconds_bucket{method=%s,le=\"%s\"} %d\n",
+				label, strconv.FormatFloat(bound, 'g', -1, 64), metrics.bucketCounts[i])
+		}
+		fmt.Fprintf(&b, "mcp_request_duration_seconds_bucket{method=%s,le=\"+Inf\"} %d\n", label, metrics.requests)
+		fmt.Fprintf(&b, "mcp_request_duration_seconds_sum{method=%s} %s\n", label, strconv.FormatFloat(metrics.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "mcp_request_duration_seconds_count{method=%s} %d\n", label, metrics.requests)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (m *RequestMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.WritePrometheus(w)
+}
+
+// labelValue quotes a Prometheus label value.
+func labelValue(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`
+}
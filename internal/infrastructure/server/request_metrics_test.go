@@ -0,0 +1,37 @@
+package server
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestMetrics_WritePrometheus(t *testing.T) {
+	metrics := NewRequestMetrics(0.1, 0.01)
+	metrics.ObserveRequest("tools/call", 5*time.Millisecond, false)
+	metrics.ObserveRequest("tools/call", 50*time.Millisecond, true)
+	metrics.ObserveRequest("ping", time.Second, false)
+	metrics.ObserveRequest(`we"ird`, time.Millisecond, false)
+
+	var buf bytes.Buffer
+	require.NoError(t, metrics.WritePrometheus(&buf))
+	out := buf.String()
+
+	assert.Contains(t, out, "# TYPE mcp_requests_total counter\n")
+	assert.Contains(t, out, `mcp_requests_total{method="tools/call"} 2`)
+	assert.Contains(t, out, `mcp_request_errors_total{method="tools/call"} 1`)
+	assert.Contains(t, out, `mcp_request_errors_total{method="ping"} 0`)
+	assert.Contains(t, out, `mcp_request_duration_seconds_bucket{method="tools/call",le="0.01"} 1`)
+	assert.Contains(t, out, `mcp_request_duration_seconds_bucket{method="tools/call",le="0.1"} 2`)
+	assert.Contains(t, out, `mcp_request_duration_seconds_bucket{method="ping",le="0.1"} 0`)
+	assert.Contains(t, out, `mcp_request_duration_seconds_bucket{method="ping",le="+Inf"} 1`)
+	assert.Contains(t, out, `mcp_request_duration_seconds_sum{method="ping"} 1`)
+	assert.Contains(t, out, `mcp_request_duration_seconds_count{method="tools/call"} 2`)
+	assert.Contains(t, out, `mcp_requests_total{method="we\"ird"} 1`)
+
+	// Methods are written in a stable order
+	assert.Less(t, bytes.Index(buf.Bytes(), []byte(`{method="ping"}`)), bytes.Index(buf.Bytes(), []byte(`{method="tools/call"}`)))
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"strings"
+	"time"
+)
+
+// eventBatching holds the configuration for coalescing queued SSE events.
+type eventBatching struct {
+	maxBatch int
+	window   time.Duration
+}
+
+// WithEventBatching enables batching of queued SSE events. Events that accumulate
+// within the given window are sent as a single "batch" event whose data is a JSON
+// array of the individual messages, up to maxBatch messages per batch.
+// Clients must understand the batched format, so batching is opt-in and is
+// advertised in the initial "connected" event.
+func WithEventBatching(maxBatch int, window time.Duration) SSEOption {
+	return func(s *SSEServer) {
+		if maxBatch <= 1 || window <= 0 {
+			s.batching = nil
+			return
+		}
+		s.batching = &eventBatching{
+			maxBatch: maxBatch,
+			window:   window,
+		}
+	}
+}
+
+// collectBatch gathers events queued for the session within the batching window
+// and returns the SSE payload to write. A single event is returned unchanged.
+func (b *eventBatching) collectBatch(session *sseSession, first string) string {
+	events := []string{first}
+
+	timer := time.NewTimer(b.window)
+	defer timer.Stop()
+
+collect:
+	for len(events) < b.maxBatch {
+		select {
+		case event := <-session.eventQueue:
+			events = append(events, event)
+		case <-timer.C:
+			break collect
+		case <-session.ctx.Done():
+			break collect
+		}
+	}
+
+	if len(events) == 1 {
+		return first
+	}
+
+	payloads := make([]string, 0, len(events))
+	for _, event := range events {
+		data, ok := messageEventData(event)
+		if !ok {
+			// Unknown event format, send the events individually
+			return strings.Join(events, "")
+		}
+		payloads = append(payloads, data)
+	}
+
+	return "event: batch\ndata: [" + strings.Join(payloads, ",") + "]\n\n"
+}
+
+// messageEventData extracts the data payload from a formatted "message" SSE event.
+func messageEventData(event string) (string, bool) {
+	const prefix = "event: message\ndata: "
+	if !strings.HasPrefix(event, prefix) || !strings.HasSuffix(event, "\n\n") {
+		return "", false
+	}
+	data := strings.TrimSuffix(strings.TrimPrefix(event, prefix), "\n\n")
+	if strings.Contains(data, "\n") {
+		return "", false
+	}
+	return data, true
+}
@@ -246,7 +246,7 @@ func (s *sseHandler) handleSSE(w http.ResponseWriter, r *http.Request) {
 func (s *sseHandler) handleMessage(w http.ResponseWriter, r *http.Request) {
 	// Only accept POST requests for messages
 	if r.Method != http.MethodPost {
-		writeJSONRPCError(w, nil, -32600, "Method not allowed", s.jsonrpcVersion)
+		writeJSONRPCError(w, nil, domain.InvalidRequestCode, "Method not allowed", s.jsonrpcVersion)
 		return
 	}
 
@@ -264,21 +264,21 @@ func (s *sseHandler) handleMessage(w http.ResponseWriter, r *http.Request) {
 	// Extract sessionId from query parameters - this is critical
 	sessionID := r.URL.Query().Get("sessionId")
 	if sessionID == "" {
-		writeJSONRPCError(w, nil, -32602, "Missing sessionId parameter", s.jsonrpcVersion)
+		writeJSONRPCError(w, nil, domain.InvalidParamsCode, "Missing sessionId parameter", s.jsonrpcVersion)
 		return
 	}
 
 	// Verify session exists
 	session, ok := s.connectionMgr.GetSession(sessionID)
 	if !ok {
-		writeJSONRPCError(w, nil, -32602, "Invalid session ID", s.jsonrpcVersion)
+		writeJSONRPCError(w, nil, domain.InvalidParamsCode, "Invalid session ID", s.jsonrpcVersion)
 		return
 	}
 
 	// Read request body
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		writeJSONRPCError(w, nil, -32700, "Error reading request body", s.jsonrpcVersion)
+		writeJSONRPCError(w, nil, domain.ParseErrorCode, "Error reading request body", s.jsonrpcVersion)
 		return
 	}
 
@@ -301,7 +301,7 @@ func (s *sseHandler) handleMessage(w http.ResponseWriter, r *http.Request) {
 	if response != nil {
 		responseBytes, err := json.Marshal(response)
 		if err != nil {
-			writeJSONRPCError(w, nil, -32603, "Error marshalling response", s.jsonrpcVersion)
+			writeJSONRPCError(w, nil, domain.InternalErrorCode, "Error marshalling response", s.jsonrpcVersion)
 			return
 		}
 
@@ -0,0 +1,63 @@
+package server
+
+// SessionInfo describes an active SSE session and the state of its event queue.
+type SessionInfo struct {
+	ID            string `json:"id"`
+	QueueDepth    int    `json:"queueDepth"`
+	QueueCapacity int    `json:"queueCapacity"`
+	DroppedEvents int64  `json:"droppedEvents"`
+}
+
+// QueueMetrics summarizes event queue depth across all active sessions.
+// A session whose queue stays near capacity indicates a slow consumer.
+type QueueMetrics struct {
+	Sessions      []SessionInfo `json:"sessions"`
+	MaxQueueDepth int           `json:"maxQueueDepth"`
+}
+
+// Info returns a snapshot of the session's event queue depth.
+func (s *sseSession) Info() SessionInfo {
+	return SessionInfo{
+		ID:            s.id,
+		QueueDepth:    len(s.eventQueue),
+		QueueCapacity: cap(s.eventQueue),
+		DroppedEvents: s.dropped.Load(),
+	}
+}
+
+// Sessions returns a snapshot of all active sessions.
+func (p *ConnectionPool) Sessions() []SessionInfo {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	infos := make([]SessionInfo, 0, len(p.sessions))
+	for _, session := range p.sessions {
+		infos = append(infos, session.Info())
+	}
+	return infos
+}
+
+// QueueMetrics returns per-session event queue depths and the aggregate maximum.
+func (p *ConnectionPool) QueueMetrics() QueueMetrics {
+	metrics := QueueMetrics{Sessions: p.Sessions()}
+	for _, info := range metrics.Sessions {
+		if info.QueueDepth > metrics.MaxQueueDepth {
+			metrics.MaxQueueDepth = info.QueueDepth
+		}
+	}
+	return metrics
+}
+
+// SessionInfo returns the event queue state of the session with the given ID.
+func (s *SSEServer) SessionInfo(sessionID string) (SessionInfo, bool) {
+	session, ok := s.connectionPool.Get(sessionID)
+	if !ok {
+		return SessionInfo{}, false
+	}
+	return session.Info(), true
+}
+
+// QueueMetrics returns event queue depth metrics for all active SSE sessions.
+func (s *SSEServer) QueueMetrics() QueueMetrics {
+	return s.connectionPool.QueueMetrics()
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultReplayBufferSize is the default number of events kept per session
+// for replay to reconnecting clients.
+const defaultReplayBufferSize = 64
+
+// defaultReplayRetention is how long the events of a disconnected session are
+// kept for the client to reconnect.
+const defaultReplayRetention = 2 * time.Minute
+
+// WithReplayBufferSize sets how many recent events are kept per session. A
+// client reconnecting to the same session with a Last-Event-ID header is sent
+// the kept events that followed that ID. Zero or less disables replay; events
+// still carry IDs.
+func WithReplayBufferSize(n int) SSEOption {
+	return func(s *SSEServer) {
+		s.replaySize = n
+	}
+}
+
+// replayEvent is a sent event kept for replay.
+type replayEvent struct {
+	id      uint64
+	payload string
+}
+
+// eventLog assigns increasing IDs to the events of a session and keeps the
+// most recent ones for replay. It outlives a single connection so IDs keep
+// increasing when the client reconnects.
+type eventLog struct {
+	mu     sync.Mutex
+	size   int
+	lastID uint64
+	events []replayEvent // oldest first, at most size events
+
+	// Guarded by SSEServer.replayMu
+	conns  int
+	expiry *time.Timer
+}
+
+// record assigns IDs to the events in payload, keeps them for replay and
+// returns the payload with an id field added to each event.
+func (l *eventLog) record(payload string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var out strings.Builder
+	for _, event := range splitEvents(payload) {
+		l.lastID++
+		event = "id: " + strconv.FormatUint(l.lastID, 10) + "\n" + event
+		out.WriteString(event)
+
+		if l.size > 0 {
+			if len(l.events) == l.size {
+				l.events = append(l.events[:0], l.events[1:]...)
+			}
+			l.events = append(l.events, replayEvent{id: l.lastID, payload: event})
+		}
+	}
+	return out.String()
+}
+
+// since returns the kept events after lastID. complete is false if events
+// after lastID are no longer kept.
+func (l *eventLog) since(lastID uint64) (events []string, complete bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if lastID >= l.lastID {
+		return nil, true
+	}
+	complete = len(l.events) > 0 && l.events[0].id <= lastID+1
+	for _, event := range l.events {
+		if event.id > lastID {
+			events = append(events, event.payload)
+		}
+	}
+	return events, complete
+}
+
+// splitEvents splits an SSE payload into its events. Each event ends with a
+// blank line.
+func splitEvents(payload string) []string {
+	var events []string
+	for payload != "" {
+		end := strings.Index(payload, "\n\n")
+		if end < 0 {
+			events = append(events, payload)
+			break
+		}
+		events = append(events, payload[:end+2])
+		payload = payload[end+2:]
+	}
+	return events
+}
+
+// attachEventLog returns the event log of a session, creating it on first
+// connect, and keeps it while the connection is open.
+func (s *SSEServer) attachEventLog(sessionID string) *eventLog {
+	s.replayMu.Lock()
+	defer s.replayMu.Unlock()
+
+	log, ok := s.replayLogs[sessionID]
+	if !ok {
+		log = &eventLog{size: s.replaySize}
+		s.replayLogs[sessionID] = log
+	}
+	log.conns++
+	if log.expiry != nil {
+		log.expiry.Stop()
+		log.expiry = nil
+	}
+	return log
+}
+
+// detachEventLog releases a connection's hold on the event log. The log of a
+// session without connections is dropped after the retention period unless
+// the client reconnects.
+func (s *SSEServer) detachEventLog(sessionID string, log *eventLog) {
+	s.replayMu.Lock()
+	defer s.replayMu.Unlock()
+
+	log.conns--
+	if log.conns > 0 {
+		return
+	}
+	log.expiry = time.AfterFunc(s.replayRetention, func() {
+		s.replayMu.Lock()
+		defer s.replayMu.Unlock()
+		if s.replayLogs[sessionID] == log && log.conns == 0 {
+			delete(s.replayLogs, sessionID)
+		}
+	})
+}
+
+// recordWhileDisconnected keeps an event for a session whose client is
+// reconnecting, so it is replayed when the client returns. It reports false
+// if the session has no retained event log.
+func (s *SSEServer) recordWhileDisconnected(sessionID, payload string) bool {
+	s.replayMu.Lock()
+	log, ok := s.replayLogs[sessionID]
+	if ok && log.conns > 0 {
+		ok = false
+	}
+	s.replayMu.Unlock()
+
+	if ok {
+		log.record(payload)
+	}
+	return ok
+}
+
+// recordBroadcastWhileDisconnected keeps a broadcast event for every session
+// whose client is reconnecting.
+func (s *SSEServer) recordBroadcastWhileDisconnected(event interface{}) {
+	s.replayMu.Lock()
+	var logs []*eventLog
+	for _, log := range s.replayLogs {
+		if log.conns == 0 {
+			logs = append(logs, log)
+		}
+	}
+	s.replayMu.Unlock()
+
+	if len(logs) == 0 {
+		return
+	}
+	eventData, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	payload := fmt.Sprintf("event: message\ndata: %s\n\n", eventData)
+	for _, log := range logs {
+		log.record(payload)
+	}
+}
@@ -3,13 +3,20 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
 	"github.com/google/uuid"
 )
@@ -24,6 +31,14 @@ type sseSession struct {
 	notifChan  NotificationChannel
 	ctx        context.Context
 	cancel     context.CancelFunc
+	closeOnce  sync.Once
+	dropped    atomic.Int64 // Number of events dropped because the queue was full
+	store      *domain.SessionStore
+	info       *domain.SessionInfoHolder
+	// activity is signaled by touch, see WithSessionIdleTimeout
+	activity chan struct{}
+	// busy counts the messages from the client being processed
+	busy atomic.Int64
 }
 
 // SessionID returns the session ID.
@@ -36,36 +51,96 @@ func (s *sseSession) NotificationChannel() NotificationChannel {
 	return s.notifChan
 }
 
-// Close closes the notification channel and cancels the context.
+// Close cancels the session context and marks the session as done.
+// The notification channel is owned by the notification sender and is
+// closed when the session is unregistered from it.
 func (s *sseSession) Close() {
 	s.cancel()
-	close(s.notifChan)
-	close(s.done)
+	s.markDone()
 }
 
+// recordDrop counts an event dropped because the queue was full and returns
+// the total number of events dropped for the session.
+func (s *sseSession) recordDrop() int64 {
+	return s.dropped.Add(1)
+}
+
+// touch records activity on the session, resetting its idle timeout.
+func (s *sseSession) touch() {
+	select {
+	case s.activity <- struct{}{}:
+	default:
+	}
+}
+
+// markDone closes the done channel exactly once.
+func (s *sseSession) markDone() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// defaultEventQueueSize is the default capacity of a session's event queue.
+const defaultEventQueueSize = 100
+
+// DefaultMaxRequestBytes is the default limit on the size of a request body.
+const DefaultMaxRequestBytes int64 = 4 << 20
+
 // SSEContextFunc is a function that takes an existing context and the current
 // request and returns a potentially modified context based on the request
 // content. This can be used to inject context values from headers, for example.
 type SSEContextFunc func(ctx context.Context, r *http.Request) context.Context
 
+// SessionIDFunc derives the session ID of a new SSE connection from its
+// request. Returning an empty ID falls back to the default; returning an
+// error rejects the connection.
+type SessionIDFunc func(r *http.Request) (string, error)
+
+// DuplicateSessionPolicy determines how the connection pool reacts when a new
+// connection arrives with a session ID that is already in use.
+type DuplicateSessionPolicy int
+
+const (
+	// DuplicateSessionReplace closes the existing connection and replaces it
+	// with the new one. This allows clients to reconnect with the same session ID.
+	DuplicateSessionReplace DuplicateSessionPolicy = iota
+
+	// DuplicateSessionReject keeps the existing connection and rejects the new one.
+	DuplicateSessionReject
+)
+
 // ConnectionPool manages active SSE sessions.
 type ConnectionPool struct {
-	mu       sync.RWMutex
-	sessions map[string]*sseSession
+	mu              sync.RWMutex
+	sessions        map[string]*sseSession
+	duplicatePolicy DuplicateSessionPolicy
 }
 
 // NewConnectionPool creates a new connection pool.
 func NewConnectionPool() *ConnectionPool {
 	return &ConnectionPool{
-		sessions: make(map[string]*sseSession),
+		sessions:        make(map[string]*sseSession),
+		duplicatePolicy: DuplicateSessionReplace,
 	}
 }
 
-// Add adds a session to the pool.
-func (p *ConnectionPool) Add(session *sseSession) {
+// Add adds a session to the pool. If a session with the same ID already exists,
+// the pool's duplicate session policy decides whether the existing session is
+// closed and replaced or the new session is rejected with ErrDuplicateSession.
+func (p *ConnectionPool) Add(session *sseSession) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+
+	if existing, ok := p.sessions[session.id]; ok && existing != session {
+		if p.duplicatePolicy == DuplicateSessionReject {
+			return ErrDuplicateSession
+		}
+		// Cancel the existing session so its connection loop exits and cleans up
+		existing.cancel()
+	}
+
 	p.sessions[session.id] = session
+	return nil
 }
 
 // Remove removes a session from the pool.
@@ -75,6 +150,24 @@ func (p *ConnectionPool) Remove(sessionID string) {
 	delete(p.sessions, sessionID)
 }
 
+// removeSession removes the given session from the pool only if it is still the
+// session registered under its ID. This prevents a replaced connection from
+// removing the connection that replaced it. It reports whether the session
+// ended with this connection, that is no other connection took over its ID.
+func (p *ConnectionPool) removeSession(session *sseSession) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	current, ok := p.sessions[session.id]
+	if !ok {
+		return true
+	}
+	if current != session {
+		return false
+	}
+	delete(p.sessions, session.id)
+	return true
+}
+
 // Get returns a session by ID.
 func (p *ConnectionPool) Get(sessionID string) (*sseSession, bool) {
 	p.mu.RLock()
@@ -83,11 +176,12 @@ func (p *ConnectionPool) Get(sessionID string) (*sseSession, bool) {
 	return session, ok
 }
 
-// Broadcast sends an event to all active sessions.
-func (p *ConnectionPool) Broadcast(event interface{}) {
+// Broadcast sends an event to all active sessions and returns the number of
+// sessions whose queue was full and dropped the event.
+func (p *ConnectionPool) Broadcast(event interface{}) int {
 	eventData, err := json.Marshal(event)
 	if err != nil {
-		return
+		return 0
 	}
 
 	eventStr := fmt.Sprintf("event: message\ndata: %s\n\n", eventData)
@@ -95,16 +189,20 @@ func (p *ConnectionPool) Broadcast(event interface{}) {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
+	dropped := 0
 	for _, session := range p.sessions {
 		select {
 		case session.eventQueue <- eventStr:
-			// Event queued successfully
+			session.touch()
 		case <-session.done:
 			// Session is closed
 		default:
 			// Queue is full
+			session.recordDrop()
+			dropped++
 		}
 	}
+	return dropped
 }
 
 // CloseAll closes all active sessions.
@@ -127,6 +225,32 @@ func (p *ConnectionPool) Count() int {
 	return len(p.sessions)
 }
 
+// IDs returns the IDs of the active sessions in sorted order.
+func (p *ConnectionPool) IDs() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	ids := make([]string, 0, len(p.sessions))
+	for id := range p.sessions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Pending returns the number of notifications and events queued for the
+// active sessions but not yet written to their connections.
+func (p *ConnectionPool) Pending() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	pending := 0
+	for _, session := range p.sessions {
+		pending += len(session.notifChan) + len(session.eventQueue)
+	}
+	return pending
+}
+
 // SSEServer implements a Server-Sent Events (SSE) based server.
 // It provides real-time communication capabilities over HTTP using the SSE protocol.
 type SSEServer struct {
@@ -138,8 +262,24 @@ type SSEServer struct {
 	connectionPool  *ConnectionPool
 	srv             *http.Server
 	contextFunc     SSEContextFunc
+	sessionIDFunc   SessionIDFunc
+	onSessionClose  func(sessionID string)
 	mcpHandler      func(ctx context.Context, rawMessage json.RawMessage) interface{}
 	logger          *logging.Logger
+	batching        *eventBatching
+	eventQueueSize  int
+	sendTimeout     time.Duration
+	heartbeat       time.Duration
+	idleTimeout     time.Duration
+	prettyJSON      bool
+	delivery        SSEDelivery
+	maxBodyBytes    int64
+	cors            CORSPolicy
+	// Event replay for reconnecting clients, see WithReplayBufferSize
+	replayMu        sync.Mutex
+	replayLogs      map[string]*eventLog
+	replaySize      int
+	replayRetention time.Duration
 	ctx             context.Context
 	cancel          context.CancelFunc
 }
@@ -202,6 +342,113 @@ func WithSSEEndpoint(endpoint string) SSEOption {
 	}
 }
 
+// WithDuplicateSessionPolicy sets how the server handles a new SSE connection
+// that reuses the session ID of an active connection.
+func WithDuplicateSessionPolicy(policy DuplicateSessionPolicy) SSEOption {
+	return func(s *SSEServer) {
+		s.connectionPool.duplicatePolicy = policy
+	}
+}
+
+// WithEventQueueSize sets the capacity of each session's event queue
+func WithEventQueueSize(n int) SSEOption {
+	return func(s *SSEServer) {
+		if n > 0 {
+			s.eventQueueSize = n
+		}
+	}
+}
+
+// WithSendTimeout makes SendEventToSession wait up to the given duration for
+// room in a full event queue instead of failing immediately
+func WithSendTimeout(timeout time.Duration) SSEOption {
+	return func(s *SSEServer) {
+		s.sendTimeout = timeout
+	}
+}
+
+// WithHeartbeatInterval sends an SSE comment on idle connections at the given
+// interval so proxies do not reap them. The timer restarts whenever an event is
+// flushed. Zero disables the heartbeat
+func WithHeartbeatInterval(interval time.Duration) SSEOption {
+	return func(s *SSEServer) {
+		s.heartbeat = interval
+	}
+}
+
+// WithSessionIdleTimeout closes sessions that neither received a message nor
+// sent an event for the given duration, freeing their resources. Heartbeat
+// comments and notifications do not count as activity, and a session is not
+// closed while one of its messages is being processed. Zero disables the
+// timeout
+func WithSessionIdleTimeout(timeout time.Duration) SSEOption {
+	return func(s *SSEServer) {
+		s.idleTimeout = timeout
+	}
+}
+
+// SSEDelivery selects how responses to messages posted to the message
+// endpoint are delivered.
+type SSEDelivery int
+
+const (
+	// SSEDeliveryBoth sends responses both as an event on the SSE stream and
+	// in the HTTP response body. It is the default.
+	SSEDeliveryBoth SSEDelivery = iota
+	// SSEDeliveryHTTP sends responses only in the HTTP response body.
+	SSEDeliveryHTTP
+	// SSEDeliverySSE sends responses only on the SSE stream and answers the
+	// HTTP request with 202 Accepted, as the MCP HTTP with SSE transport
+	// specifies.
+	SSEDeliverySSE
+)
+
+// String returns the name of the delivery mode.
+func (d SSEDelivery) String() string {
+	switch d {
+	case SSEDeliveryHTTP:
+		return "http"
+	case SSEDeliverySSE:
+		return "sse"
+	default:
+		return "both"
+	}
+}
+
+// WithSSEDelivery sets how responses to posted messages are delivered, so
+// clients receive each response on a single channel. Notifications are
+// always sent on the SSE stream.
+func WithSSEDelivery(delivery SSEDelivery) SSEOption {
+	return func(s *SSEServer) {
+		s.delivery = delivery
+	}
+}
+
+// WithPrettyJSON indents the JSON-RPC responses written to message request
+// bodies. Events on the SSE stream are always compact, since each must fit on
+// a single data line.
+func WithPrettyJSON(pretty bool) SSEOption {
+	return func(s *SSEServer) {
+		s.prettyJSON = pretty
+	}
+}
+
+// WithMaxRequestBytes limits the size of message request bodies. Larger
+// requests are rejected with HTTP 413. Zero or less removes the limit.
+func WithMaxRequestBytes(n int64) SSEOption {
+	return func(s *SSEServer) {
+		s.maxBodyBytes = n
+	}
+}
+
+// WithCORS sets the CORS policy for the SSE and message endpoints. The
+// default allows any origin.
+func WithCORS(policy CORSPolicy) SSEOption {
+	return func(s *SSEServer) {
+		s.cors = policy
+	}
+}
+
 // WithHTTPServer sets the HTTP server instance
 func WithHTTPServer(srv *http.Server) SSEOption {
 	return func(s *SSEServer) {
@@ -217,6 +464,24 @@ func WithSSEContextFunc(fn SSEContextFunc) SSEOption {
 	}
 }
 
+// WithSessionIDFunc sets a function that derives session IDs from the
+// connection request, e.g. from an authenticated user or a correlation
+// header. When it returns an empty ID, the session query parameter or a new
+// UUID is used; when it returns an error, the connection is rejected with 400.
+func WithSessionIDFunc(fn SessionIDFunc) SSEOption {
+	return func(s *SSEServer) {
+		s.sessionIDFunc = fn
+	}
+}
+
+// WithSessionCloseFunc sets a function called with the session ID when an SSE
+// connection closes, to release state kept for the session.
+func WithSessionCloseFunc(fn func(sessionID string)) SSEOption {
+	return func(s *SSEServer) {
+		s.onSessionClose = fn
+	}
+}
+
 // NewSSEServer creates a new SSE server instance with the given notification sender and options.
 func NewSSEServer(notifier *NotificationSender, mcpHandler func(ctx context.Context, rawMessage json.RawMessage) interface{}, opts ...SSEOption) *SSEServer {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -242,6 +507,11 @@ func NewSSEServer(notifier *NotificationSender, mcpHandler func(ctx context.Cont
 		mcpHandler:      mcpHandler,
 		connectionPool:  NewConnectionPool(),
 		logger:          defaultLogger,
+		eventQueueSize:  defaultEventQueueSize,
+		maxBodyBytes:    DefaultMaxRequestBytes,
+		replayLogs:      make(map[string]*eventLog),
+		replaySize:      defaultReplayBufferSize,
+		replayRetention: defaultReplayRetention,
 		ctx:             ctx,
 		cancel:          cancel,
 	}
@@ -303,7 +573,7 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+	s.cors.SetHeaders(w, r)
 
 	flusher, ok := w.(http.Flusher)
 	if !ok {
@@ -311,9 +581,11 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionID := r.URL.Query().Get("session")
-	if sessionID == "" {
-		sessionID = uuid.New().String()
+	sessionID, err := s.newSessionID(r)
+	if err != nil {
+		s.logger.Warn("Session ID function failed, rejecting SSE connection", logging.Fields{"error": err})
+		http.Error(w, fmt.Sprintf("Invalid session: %v", err), http.StatusBadRequest)
+		return
 	}
 
 	// Create a context for this session that is a child of the server context
@@ -324,23 +596,53 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		writer:     w,
 		flusher:    flusher,
 		done:       make(chan struct{}),
-		eventQueue: make(chan string, 100), // Buffer for events
+		eventQueue: make(chan string, s.eventQueueSize), // Buffer for events
 		id:         sessionID,
 		notifChan:  make(NotificationChannel, 100),
 		ctx:        sessionCtx,
 		cancel:     sessionCancel,
+		store:      domain.NewSessionStore(),
+		info:       domain.NewSessionInfoHolder(sessionID, r.UserAgent()),
+		activity:   make(chan struct{}, 1),
 	}
 
 	// Add the session to the connection pool
-	s.connectionPool.Add(session)
-	defer s.connectionPool.Remove(sessionID)
+	if err := s.connectionPool.Add(session); err != nil {
+		sessionCancel()
+		s.logger.Warn("Rejected duplicate SSE connection", logging.Fields{"sessionId": sessionID})
+		http.Error(w, "Session already connected", http.StatusConflict)
+		return
+	}
+	defer func() {
+		// A replaced connection must not release the state of its replacement
+		if s.connectionPool.removeSession(session) && s.onSessionClose != nil {
+			s.onSessionClose(sessionID)
+		}
+	}()
+	defer session.store.Clear()
 
-	s.notifier.RegisterSession(&MCPSession{
+	// Number the session's events and keep recent ones for reconnects
+	events := s.attachEventLog(sessionID)
+	defer s.detachEventLog(sessionID, events)
+	defer func() {
+		// Keep events that were queued but not written for the reconnect
+		for {
+			select {
+			case event := <-session.eventQueue:
+				events.record(event)
+			default:
+				return
+			}
+		}
+	}()
+
+	mcpSession := &MCPSession{
 		id:        sessionID,
 		userAgent: r.UserAgent(),
 		notifChan: session.notifChan,
-	})
-	defer s.notifier.UnregisterSession(sessionID)
+	}
+	s.notifier.RegisterSession(mcpSession)
+	defer s.notifier.unregisterSessionInstance(mcpSession)
 
 	// Start notification handler for this session
 	go func() {
@@ -351,7 +653,10 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 				if err == nil {
 					select {
 					case session.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
-						// Event queued successfully
+						// Heartbeats must not keep an idle session open
+						if notification.Method != domain.HeartbeatMethod {
+							session.touch()
+						}
 					case <-session.done:
 						return
 					case <-session.ctx.Done():
@@ -370,92 +675,219 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 
 	messageEndpoint := fmt.Sprintf("%s?sessionId=%s", s.CompleteMessageEndpoint(), sessionID)
 
-	// Send the initial connected event
-	fmt.Fprintf(w, "event: connected\ndata: {\"sessionId\": \"%s\"}\n\n", sessionID)
+	// Send the initial connected event, advertising event batching if enabled
+	if s.batching != nil {
+		fmt.Fprintf(w, "event: connected\ndata: {\"sessionId\": \"%s\", \"eventBatching\": {\"maxBatch\": %d, \"windowMs\": %d}}\n\n",
+			sessionID, s.batching.maxBatch, s.batching.window.Milliseconds())
+	} else {
+		fmt.Fprintf(w, "event: connected\ndata: {\"sessionId\": \"%s\"}\n\n", sessionID)
+	}
 	flusher.Flush()
 
 	// Send the endpoint event
 	fmt.Fprintf(w, "event: endpoint\ndata: %s\n\n", messageEndpoint)
 	flusher.Flush()
 
+	// Replay the events a reconnecting client missed
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		if lastID, err := strconv.ParseUint(header, 10, 64); err == nil {
+			missed, complete := events.since(lastID)
+			if !complete {
+				s.logger.Warn("Events missed since Last-Event-ID are no longer buffered", logging.Fields{"sessionId": sessionID, "lastEventId": lastID})
+			}
+			for _, event := range missed {
+				fmt.Fprint(w, event)
+			}
+			flusher.Flush()
+		}
+	}
+
+	// Keep idle connections alive with periodic comments if enabled
+	var heartbeat <-chan time.Time
+	var heartbeatTimer *time.Timer
+	if s.heartbeat > 0 {
+		heartbeatTimer = time.NewTimer(s.heartbeat)
+		defer heartbeatTimer.Stop()
+		heartbeat = heartbeatTimer.C
+	}
+
+	// Close the session once it has been idle for too long if enabled
+	var idle <-chan time.Time
+	var idleTimer *time.Timer
+	if s.idleTimeout > 0 {
+		idleTimer = time.NewTimer(s.idleTimeout)
+		defer idleTimer.Stop()
+		idle = idleTimer.C
+	}
+
 	// Main event loop - this runs in the HTTP handler goroutine
 	for {
 		select {
 		case event := <-session.eventQueue:
-			// Write the event to the response
-			fmt.Fprint(w, event)
+			// Coalesce queued events into a single batch if batching is enabled
+			if s.batching != nil {
+				event = s.batching.collectBatch(session, event)
+			}
+			// Write the event to the response with its ID
+			fmt.Fprint(w, events.record(event))
 			flusher.Flush()
+			if heartbeatTimer != nil {
+				heartbeatTimer.Reset(s.heartbeat)
+			}
+		case <-heartbeat:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+			heartbeatTimer.Reset(s.heartbeat)
+		case <-session.activity:
+			if idleTimer != nil {
+				idleTimer.Reset(s.idleTimeout)
+			}
+		case <-idle:
+			if session.busy.Load() > 0 {
+				idleTimer.Reset(s.idleTimeout)
+				continue
+			}
+			s.logger.Info("Closing idle SSE session", logging.Fields{"sessionId": sessionID, "idleTimeout": s.idleTimeout.String()})
+			sessionCancel()
+			session.markDone()
+			return
 		case <-r.Context().Done():
 			sessionCancel()
-			close(session.done)
+			session.markDone()
 			return
 		case <-session.ctx.Done():
-			close(session.done)
+			session.markDone()
 			return
 		}
 	}
 }
 
+// newSessionID returns the ID of a new SSE connection: the one derived by
+// the session ID function if set, else the session query parameter, else a
+// new UUID.
+func (s *SSEServer) newSessionID(r *http.Request) (string, error) {
+	if s.sessionIDFunc != nil {
+		sessionID, err := s.sessionIDFunc(r)
+		if err != nil {
+			return "", err
+		}
+		if sessionID != "" {
+			return sessionID, nil
+		}
+	}
+	if sessionID := r.URL.Query().Get("session"); sessionID != "" {
+		return sessionID, nil
+	}
+	return uuid.New().String(), nil
+}
+
 // handleMessage processes incoming JSON-RPC messages from clients and sends responses
 // back through both the SSE connection and HTTP response.
 func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
+	s.cors.SetHeaders(w, r)
 	if r.Method != http.MethodPost {
-		s.writeJSONRPCError(w, nil, -32600, "Method not allowed")
+		s.writeJSONRPCError(w, nil, domain.InvalidRequestCode, "Method not allowed")
 		return
 	}
 
 	sessionID := r.URL.Query().Get("sessionId")
 	if sessionID == "" {
-		s.writeJSONRPCError(w, nil, -32602, "Missing sessionId")
+		s.writeJSONRPCError(w, nil, domain.InvalidParamsCode, "Missing sessionId")
 		return
 	}
 
 	session, ok := s.connectionPool.Get(sessionID)
 	if !ok {
-		s.writeJSONRPCError(w, nil, -32602, "Invalid session ID")
+		s.writeJSONRPCError(w, nil, domain.InvalidParamsCode, "Invalid session ID")
 		return
 	}
 
-	// Create context for the message handler
+	// The message and its response count as activity on the session
+	session.touch()
+	session.busy.Add(1)
+	defer func() {
+		session.busy.Add(-1)
+		session.touch()
+	}()
+
+	// Create context for the message handler, tagged with the originating session
 	ctx := r.Context()
 	if s.contextFunc != nil {
 		ctx = s.contextFunc(ctx, r)
 	}
+	ctx = domain.WithSessionID(ctx, sessionID)
+	ctx = domain.WithSessionStore(ctx, session.store)
+	ctx = domain.WithSessionInfo(ctx, session.info)
 
 	// Parse message as raw JSON
+	if s.maxBodyBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxBodyBytes)
+	}
 	var rawMessage json.RawMessage
 	if err := json.NewDecoder(r.Body).Decode(&rawMessage); err != nil {
-		s.writeJSONRPCError(w, nil, -32700, "Parse error")
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			s.logger.Warn("Request body too large", logging.Fields{"sessionId": sessionID, "limit": tooLarge.Limit})
+			s.writeJSONRPCErrorStatus(w, http.StatusRequestEntityTooLarge, nil, domain.InvalidRequestCode, fmt.Sprintf("Request body exceeds %d bytes", tooLarge.Limit))
+			return
+		}
+		s.writeJSONRPCError(w, nil, domain.ParseErrorCode, "Parse error")
 		return
 	}
 
 	// Process message through MCP handler
 	response := s.mcpHandler(ctx, rawMessage)
 
-	// Only send response if there is one (not for notifications)
-	if response != nil {
-		eventData, _ := json.Marshal(response)
-
-		// Queue the event for sending via SSE
-		select {
-		case session.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
-			// Event queued successfully
-		case <-session.done:
-			// Session is closed, don't try to queue
-		case <-session.ctx.Done():
-			// Session context was canceled
-		default:
-			// Queue is full, could log this
-		}
+	// Skip the response if the client disconnected while it was being built
+	if err := ctx.Err(); err != nil {
+		s.logger.Info("Client gone, dropping response", logging.Fields{"sessionId": sessionID, "error": err})
+		return
+	}
 
-		// Send HTTP response
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_ = json.NewEncoder(w).Encode(response)
-	} else {
+	// Only send response if there is one (not for notifications)
+	if response == nil {
 		// For notifications, just send 200 OK with no body
 		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Queue the response for sending via SSE
+	queued := false
+	if s.delivery != SSEDeliveryHTTP {
+		queued = s.queueResponse(session, sessionID, response)
+	}
+
+	// Send the HTTP response, unless it is delivered on the SSE stream only.
+	// A response that could not be queued is still sent so it is not lost.
+	if s.delivery == SSEDeliverySSE && queued {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	s.encodeJSON(w, response)
+}
+
+// queueResponse queues a response as a message event on the session's SSE
+// stream. It reports whether the event was queued.
+func (s *SSEServer) queueResponse(session *sseSession, sessionID string, response interface{}) bool {
+	eventData, _ := json.Marshal(response)
+
+	select {
+	case session.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
+		return true
+	case <-session.done:
+		// Session is closed, don't try to queue
+	case <-session.ctx.Done():
+		// Session context was canceled
+	default:
+		// Queue is full
+		s.logger.Warn("Dropped SSE event, queue full", logging.Fields{
+			"sessionId": sessionID,
+			"dropped":   session.recordDrop(),
+		})
 	}
+	return false
 }
 
 // writeJSONRPCError writes a JSON-RPC error response with the given error details.
@@ -464,6 +896,18 @@ func (s *SSEServer) writeJSONRPCError(
 	id interface{},
 	code int,
 	message string,
+) {
+	s.writeJSONRPCErrorStatus(w, http.StatusBadRequest, id, code, message)
+}
+
+// writeJSONRPCErrorStatus writes a JSON-RPC error response with the given
+// HTTP status.
+func (s *SSEServer) writeJSONRPCErrorStatus(
+	w http.ResponseWriter,
+	status int,
+	id interface{},
+	code int,
+	message string,
 ) {
 	response := map[string]interface{}{
 		"jsonrpc": "2.0",
@@ -474,8 +918,17 @@ func (s *SSEServer) writeJSONRPCError(
 		},
 	}
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusBadRequest)
-	_ = json.NewEncoder(w).Encode(response)
+	w.WriteHeader(status)
+	s.encodeJSON(w, response)
+}
+
+// encodeJSON writes v to w as JSON, indented if WithPrettyJSON is set.
+func (s *SSEServer) encodeJSON(w io.Writer, v interface{}) {
+	encoder := json.NewEncoder(w)
+	if s.prettyJSON {
+		encoder.SetIndent("", "  ")
+	}
+	_ = encoder.Encode(v)
 }
 
 // SendEventToSession sends an event to a specific SSE session identified by sessionID.
@@ -484,32 +937,91 @@ func (s *SSEServer) SendEventToSession(
 	sessionID string,
 	event interface{},
 ) error {
-	session, ok := s.connectionPool.Get(sessionID)
-	if !ok {
-		return fmt.Errorf("session not found: %s", sessionID)
-	}
-
 	eventData, err := json.Marshal(event)
 	if err != nil {
 		return err
 	}
 
+	eventStr := fmt.Sprintf("event: message\ndata: %s\n\n", eventData)
+
+	session, ok := s.connectionPool.Get(sessionID)
+	if !ok {
+		// Keep the event for a client that is reconnecting
+		if s.recordWhileDisconnected(sessionID, eventStr) {
+			return nil
+		}
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+
 	// Queue the event for sending via SSE
 	select {
-	case session.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
+	case session.eventQueue <- eventStr:
+		session.touch()
 		return nil
 	case <-session.done:
 		return fmt.Errorf("session closed")
 	case <-session.ctx.Done():
 		return fmt.Errorf("session context canceled")
 	default:
-		return fmt.Errorf("event queue full")
 	}
+
+	// The queue is full; wait for room if a send timeout is configured
+	if s.sendTimeout > 0 {
+		timer := time.NewTimer(s.sendTimeout)
+		defer timer.Stop()
+
+		select {
+		case session.eventQueue <- eventStr:
+			session.touch()
+			return nil
+		case <-session.done:
+			return fmt.Errorf("session closed")
+		case <-session.ctx.Done():
+			return fmt.Errorf("session context canceled")
+		case <-timer.C:
+		}
+	}
+
+	s.logger.Warn("Dropped SSE event, queue full", logging.Fields{
+		"sessionId": sessionID,
+		"dropped":   session.recordDrop(),
+	})
+	return fmt.Errorf("event queue full")
+}
+
+// SessionCount returns the number of active SSE sessions.
+func (s *SSEServer) SessionCount() int {
+	return s.connectionPool.Count()
+}
+
+// SessionIDs returns the IDs of the connected SSE sessions in sorted order.
+func (s *SSEServer) SessionIDs() []string {
+	return s.connectionPool.IDs()
+}
+
+// WaitForQueuedEvents waits until the events queued for the active sessions
+// have been written to their connections or ctx is done.
+func (s *SSEServer) WaitForQueuedEvents(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for s.connectionPool.Pending() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
 }
 
 // BroadcastEvent sends an event to all active SSE sessions.
 func (s *SSEServer) BroadcastEvent(event interface{}) {
-	s.connectionPool.Broadcast(event)
+	if dropped := s.connectionPool.Broadcast(event); dropped > 0 {
+		s.logger.Warn("Dropped broadcast event, queues full", logging.Fields{"sessions": dropped})
+	}
+	// Keep the event for clients that are reconnecting
+	s.recordBroadcastWhileDisconnected(event)
 }
 
 func (s *SSEServer) GetUrlPath(input string) (string, error) {
@@ -546,6 +1058,10 @@ func (s *SSEServer) CompleteMessagePath() string {
 
 // ServeHTTP implements the http.Handler interface.
 func (s *SSEServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.cors.HandlePreflight(w, r) {
+		return
+	}
+
 	path := r.URL.Path
 	// Use exact path matching rather than Contains
 	ssePath := s.CompleteSsePath()
@@ -0,0 +1,504 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func echoMCPHandler(ctx context.Context, rawMessage json.RawMessage) interface{} {
+	return map[string]interface{}{"jsonrpc": "2.0", "result": "ok"}
+}
+
+// openSSEStream opens an SSE connection and waits for the endpoint event.
+func openSSEStream(t *testing.T, url string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if strings.HasPrefix(line, "event: endpoint") {
+			break
+		}
+	}
+	return resp, reader
+}
+
+// waitForEOF drains the reader and reports whether the stream ended before the timeout.
+func waitForEOF(reader *bufio.Reader, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(io.Discard, reader)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func TestSSEServer_DuplicateSessionReplacesExisting(t *testing.T) {
+	notifier := NewNotificationSender("2.0")
+	sseServer := NewSSEServer(notifier, echoMCPHandler)
+	testServer := httptest.NewServer(sseServer)
+	defer testServer.Close()
+
+	firstResp, firstReader := openSSEStream(t, testServer.URL+"/sse?session=dup")
+	defer firstResp.Body.Close()
+
+	first, ok := sseServer.connectionPool.Get("dup")
+	require.True(t, ok)
+
+	secondResp, _ := openSSEStream(t, testServer.URL+"/sse?session=dup")
+	defer secondResp.Body.Close()
+
+	// The first connection should be closed by the server
+	assert.True(t, waitForEOF(firstReader, 2*time.Second), "first connection should be closed")
+
+	select {
+	case <-first.done:
+	case <-time.After(time.Second):
+		t.Fatal("first session was not cleaned up")
+	}
+
+	// The second connection should remain registered
+	second, ok := sseServer.connectionPool.Get("dup")
+	require.True(t, ok)
+	assert.NotSame(t, first, second)
+	assert.Equal(t, 1, sseServer.connectionPool.Count())
+
+	_, ok = notifier.sessions.Load("dup")
+	assert.True(t, ok, "replacement session should remain registered for notifications")
+}
+
+func TestSSEServer_SessionCloseFuncSkipsReplacedConnection(t *testing.T) {
+	var mu sync.Mutex
+	var closed []string
+	sseServer := NewSSEServer(NewNotificationSender("2.0"), echoMCPHandler, WithSessionCloseFunc(func(sessionID string) {
+		mu.Lock()
+		defer mu.Unlock()
+		closed = append(closed, sessionID)
+	}))
+	testServer := httptest.NewServer(sseServer)
+	defer testServer.Close()
+	closedSessions := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), closed...)
+	}
+
+	firstResp, firstReader := openSSEStream(t, testServer.URL+"/sse?session=dup")
+	defer firstResp.Body.Close()
+	secondResp, _ := openSSEStream(t, testServer.URL+"/sse?session=dup")
+
+	// The replaced connection exits without ending the session
+	require.True(t, waitForEOF(firstReader, 2*time.Second), "first connection should be closed")
+	assert.Never(t, func() bool { return len(closedSessions()) > 0 }, 200*time.Millisecond, 10*time.Millisecond,
+		"closing the replaced connection should not end the session")
+
+	// The session ends with the connection that owns it
+	secondResp.Body.Close()
+	require.Eventually(t, func() bool { return len(closedSessions()) == 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"dup"}, closedSessions())
+}
+
+func TestSSEServer_DuplicateSessionReject(t *testing.T) {
+	notifier := NewNotificationSender("2.0")
+	sseServer := NewSSEServer(notifier, echoMCPHandler, WithDuplicateSessionPolicy(DuplicateSessionReject))
+	testServer := httptest.NewServer(sseServer)
+	defer testServer.Close()
+
+	firstResp, _ := openSSEStream(t, testServer.URL+"/sse?session=dup")
+	defer firstResp.Body.Close()
+
+	first, ok := sseServer.connectionPool.Get("dup")
+	require.True(t, ok)
+
+	secondResp, err := http.Get(testServer.URL + "/sse?session=dup")
+	require.NoError(t, err)
+	defer secondResp.Body.Close()
+	assert.Equal(t, http.StatusConflict, secondResp.StatusCode)
+
+	current, ok := sseServer.connectionPool.Get("dup")
+	require.True(t, ok)
+	assert.Same(t, first, current)
+}
+
+func TestSSEServer_Heartbeat(t *testing.T) {
+	sseServer := NewSSEServer(NewNotificationSender("2.0"), echoMCPHandler, WithHeartbeatInterval(20*time.Millisecond))
+	testServer := httptest.NewServer(sseServer)
+	defer testServer.Close()
+	defer func() { _ = sseServer.Shutdown(context.Background()) }()
+
+	resp, reader := openSSEStream(t, testServer.URL+"/sse?session=idle")
+	defer resp.Body.Close()
+
+	lines := make(chan string, 1)
+	go func() {
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			if strings.HasPrefix(line, ":") {
+				lines <- line
+				return
+			}
+		}
+	}()
+
+	select {
+	case line := <-lines:
+		assert.Equal(t, ": heartbeat\n", line)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no heartbeat received on idle connection")
+	}
+}
+
+func TestSSEServer_SessionIdleTimeout(t *testing.T) {
+	notifier := NewNotificationSender("2.0")
+	sseServer := NewSSEServer(notifier, echoMCPHandler,
+		WithSessionIdleTimeout(100*time.Millisecond),
+		WithHeartbeatInterval(10*time.Millisecond),
+	)
+	testServer := httptest.NewServer(sseServer)
+	defer testServer.Close()
+	defer func() { _ = sseServer.Shutdown(context.Background()) }()
+
+	resp, reader := openSSEStream(t, testServer.URL+"/sse?session=active")
+	defer resp.Body.Close()
+
+	// Messages keep the session open past the idle timeout
+	for i := 0; i < 6; i++ {
+		msg, err := http.Post(testServer.URL+"/message?sessionId=active", "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		require.NoError(t, err)
+		msg.Body.Close()
+		time.Sleep(40 * time.Millisecond)
+	}
+	_, ok := sseServer.connectionPool.Get("active")
+	require.True(t, ok, "active session should stay open")
+
+	// Heartbeat comments and notifications do not count as activity
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(10 * time.Millisecond):
+				_ = notifier.BroadcastNotification(context.Background(), &domain.Notification{Method: domain.HeartbeatMethod})
+			}
+		}
+	}()
+	assert.True(t, waitForEOF(reader, 2*time.Second), "idle session should be closed")
+	_, ok = sseServer.connectionPool.Get("active")
+	assert.False(t, ok)
+}
+
+func TestEventBatching_CollectBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	session := &sseSession{
+		eventQueue: make(chan string, 10),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	session.eventQueue <- "event: message\ndata: {\"n\":2}\n\n"
+	session.eventQueue <- "event: message\ndata: {\"n\":3}\n\n"
+	session.eventQueue <- "event: message\ndata: {\"n\":4}\n\n"
+
+	batching := &eventBatching{maxBatch: 3, window: 50 * time.Millisecond}
+	event := batching.collectBatch(session, "event: message\ndata: {\"n\":1}\n\n")
+
+	assert.Equal(t, "event: batch\ndata: [{\"n\":1},{\"n\":2},{\"n\":3}]\n\n", event)
+	assert.Len(t, session.eventQueue, 1, "events beyond maxBatch should stay queued")
+
+	// A lone event within the window is sent unchanged
+	<-session.eventQueue
+	single := "event: message\ndata: {\"n\":5}\n\n"
+	assert.Equal(t, single, batching.collectBatch(session, single))
+}
+
+func TestConnectionPool_QueueMetrics(t *testing.T) {
+	pool := NewConnectionPool()
+
+	idle := &sseSession{id: "idle", eventQueue: make(chan string, 10), cancel: func() {}}
+	slow := &sseSession{id: "slow", eventQueue: make(chan string, 10), cancel: func() {}}
+	for i := 0; i < 7; i++ {
+		slow.eventQueue <- "event: message\ndata: {}\n\n"
+	}
+	require.NoError(t, pool.Add(idle))
+	require.NoError(t, pool.Add(slow))
+
+	metrics := pool.QueueMetrics()
+	assert.Len(t, metrics.Sessions, 2)
+	assert.Equal(t, 7, metrics.MaxQueueDepth)
+
+	info := slow.Info()
+	assert.Equal(t, SessionInfo{ID: "slow", QueueDepth: 7, QueueCapacity: 10}, info)
+}
+
+func TestConnectionPool_IDs(t *testing.T) {
+	pool := NewConnectionPool()
+	assert.Empty(t, pool.IDs())
+
+	for _, id := range []string{"b", "c", "a"} {
+		require.NoError(t, pool.Add(&sseSession{id: id, cancel: func() {}}))
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, pool.IDs())
+	assert.Equal(t, 3, pool.Count())
+
+	pool.Remove("b")
+	assert.Equal(t, []string{"a", "c"}, pool.IDs())
+}
+
+func TestConnectionPool_BroadcastCountsDrops(t *testing.T) {
+	pool := NewConnectionPool()
+	session := &sseSession{id: "full", eventQueue: make(chan string, 1), done: make(chan struct{}), cancel: func() {}}
+	require.NoError(t, pool.Add(session))
+
+	assert.Equal(t, 0, pool.Broadcast(map[string]string{"n": "1"}))
+	assert.Equal(t, 1, pool.Broadcast(map[string]string{"n": "2"}))
+	assert.Equal(t, int64(1), session.Info().DroppedEvents)
+}
+
+func TestSSEServer_SendEventToSessionWaitsForRoom(t *testing.T) {
+	s := NewSSEServer(NewNotificationSender("2.0"), echoMCPHandler,
+		WithLogger(logging.Default()), WithSendTimeout(time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	session := &sseSession{id: "slow", eventQueue: make(chan string, 1), done: make(chan struct{}), ctx: ctx, cancel: cancel}
+	session.eventQueue <- "event: message\ndata: {}\n\n"
+	require.NoError(t, s.connectionPool.Add(session))
+
+	// Drain the queue after a short delay so the blocked send can complete
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		<-session.eventQueue
+	}()
+
+	require.NoError(t, s.SendEventToSession("slow", map[string]string{"n": "1"}))
+	assert.Equal(t, int64(0), session.Info().DroppedEvents)
+}
+
+func TestSSEServer_SendEventToSessionDropsWithoutTimeout(t *testing.T) {
+	s := NewSSEServer(NewNotificationSender("2.0"), echoMCPHandler,
+		WithLogger(logging.Default()), WithEventQueueSize(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	session := &sseSession{id: "slow", eventQueue: make(chan string, s.eventQueueSize), done: make(chan struct{}), ctx: ctx, cancel: cancel}
+	require.NoError(t, s.connectionPool.Add(session))
+
+	require.NoError(t, s.SendEventToSession("slow", map[string]string{"n": "1"}))
+	assert.Error(t, s.SendEventToSession("slow", map[string]string{"n": "2"}))
+	assert.Equal(t, int64(1), session.Info().DroppedEvents)
+}
+
+func TestSSEServer_MaxRequestBytes(t *testing.T) {
+	sseServer := NewSSEServer(NewNotificationSender("2.0"), echoMCPHandler, WithMaxRequestBytes(64))
+	testServer := httptest.NewServer(sseServer)
+	defer testServer.Close()
+	defer func() { _ = sseServer.Shutdown(context.Background()) }()
+
+	resp, _ := openSSEStream(t, testServer.URL+"/sse?session=big")
+	defer resp.Body.Close()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"ping","params":{"pad":"` + strings.Repeat("x", 100) + `"}}`
+	msgResp, err := http.Post(testServer.URL+"/message?sessionId=big", "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer msgResp.Body.Close()
+	assert.Equal(t, http.StatusRequestEntityTooLarge, msgResp.StatusCode)
+
+	var response struct {
+		Error struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(msgResp.Body).Decode(&response))
+	assert.Equal(t, -32600, response.Error.Code)
+}
+
+func TestEventLog_RecordAndSince(t *testing.T) {
+	log := &eventLog{size: 2}
+
+	first := log.record("event: message\ndata: 1\n\n")
+	assert.Equal(t, "id: 1\nevent: message\ndata: 1\n\n", first)
+
+	// Events written together get their own IDs
+	assert.Equal(t, "id: 2\nevent: message\ndata: 2\n\nid: 3\nevent: message\ndata: 3\n\n",
+		log.record("event: message\ndata: 2\n\nevent: message\ndata: 3\n\n"))
+
+	missed, complete := log.since(1)
+	assert.True(t, complete)
+	assert.Equal(t, []string{"id: 2\nevent: message\ndata: 2\n\n", "id: 3\nevent: message\ndata: 3\n\n"}, missed)
+
+	// Event 1 fell out of the buffer
+	missed, complete = log.since(0)
+	assert.False(t, complete)
+	assert.Len(t, missed, 2)
+
+	missed, complete = log.since(3)
+	assert.True(t, complete)
+	assert.Empty(t, missed)
+}
+
+// readLineWithPrefix reads SSE lines until one starts with prefix.
+func readLineWithPrefix(t *testing.T, reader *bufio.Reader, prefix string) string {
+	t.Helper()
+
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if strings.HasPrefix(line, prefix) {
+			return line
+		}
+	}
+}
+
+func TestSSEServer_ReplaysMissedEventsOnReconnect(t *testing.T) {
+	sseServer := NewSSEServer(NewNotificationSender("2.0"), echoMCPHandler)
+	testServer := httptest.NewServer(sseServer)
+	defer testServer.Close()
+	defer func() { _ = sseServer.Shutdown(context.Background()) }()
+
+	resp, reader := openSSEStream(t, testServer.URL+"/sse?session=resume")
+	require.NoError(t, sseServer.SendEventToSession("resume", map[string]string{"n": "first"}))
+	assert.Equal(t, "id: 1\n", readLineWithPrefix(t, reader, "id: "))
+	resp.Body.Close()
+
+	// Events sent while the client is away are kept for its reconnect
+	require.Eventually(t, func() bool { return sseServer.SessionCount() == 0 }, 2*time.Second, 10*time.Millisecond)
+	require.NoError(t, sseServer.SendEventToSession("resume", map[string]string{"n": "second"}))
+
+	req, err := http.NewRequest(http.MethodGet, testServer.URL+"/sse?session=resume", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	reader = bufio.NewReader(resp.Body)
+	assert.Equal(t, "id: 2\n", readLineWithPrefix(t, reader, "id: "))
+	assert.Equal(t, "data: {\"n\":\"second\"}\n", readLineWithPrefix(t, reader, "data: {"))
+}
+
+func TestSSEServer_SessionIDFunc(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "sse.log")
+	logger, err := logging.New(logging.Config{Level: logging.WarnLevel, OutputPaths: []string{logPath}})
+	require.NoError(t, err)
+	sseServer := NewSSEServer(NewNotificationSender("2.0"), echoMCPHandler, WithLogger(logger),
+		WithSessionIDFunc(func(r *http.Request) (string, error) {
+			user := r.Header.Get("X-User")
+			if user == "blocked" {
+				return "", errors.New("user is blocked")
+			}
+			if user == "" {
+				return "", nil
+			}
+			return "user-" + user, nil
+		}))
+	testServer := httptest.NewServer(sseServer)
+	defer testServer.Close()
+	defer func() { _ = sseServer.Shutdown(context.Background()) }()
+
+	connect := func(user string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/sse?session=fallback", nil)
+		require.NoError(t, err)
+		if user != "" {
+			req.Header.Set("X-User", user)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := connect("alice")
+	defer resp.Body.Close()
+	assert.Equal(t, "event: connected\n", readLineWithPrefix(t, bufio.NewReader(resp.Body), "event: "))
+	_, ok := sseServer.connectionPool.Get("user-alice")
+	assert.True(t, ok)
+
+	// An empty ID falls back to the session query parameter
+	resp = connect("")
+	defer resp.Body.Close()
+	readLineWithPrefix(t, bufio.NewReader(resp.Body), "event: ")
+	_, ok = sseServer.connectionPool.Get("fallback")
+	assert.True(t, ok)
+
+	resp = connect("blocked")
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// The log names the failing function and its error
+	logged, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(logged), "Session ID function failed")
+	assert.Contains(t, string(logged), "user is blocked")
+}
+
+func TestSSEServer_Delivery(t *testing.T) {
+	tests := []struct {
+		delivery   SSEDelivery
+		wantStatus int
+		wantBody   bool
+		wantEvent  bool
+	}{
+		{SSEDeliveryBoth, http.StatusOK, true, true},
+		{SSEDeliveryHTTP, http.StatusOK, true, false},
+		{SSEDeliverySSE, http.StatusAccepted, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.delivery.String(), func(t *testing.T) {
+			sseServer := NewSSEServer(NewNotificationSender("2.0"), echoMCPHandler, WithSSEDelivery(tt.delivery))
+			testServer := httptest.NewServer(sseServer)
+			defer testServer.Close()
+			defer func() { _ = sseServer.Shutdown(context.Background()) }()
+
+			resp, reader := openSSEStream(t, testServer.URL+"/sse?session=delivery")
+			defer resp.Body.Close()
+
+			post, err := http.Post(testServer.URL+"/message?sessionId=delivery", "application/json",
+				strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+			require.NoError(t, err)
+			body, err := io.ReadAll(post.Body)
+			post.Body.Close()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, post.StatusCode)
+			assert.Equal(t, tt.wantBody, strings.Contains(string(body), `"result":"ok"`))
+
+			// The next event is the response if it was sent on the stream
+			require.NoError(t, sseServer.SendEventToSession("delivery", map[string]string{"n": "marker"}))
+			data := readLineWithPrefix(t, reader, "data: {")
+			assert.Equal(t, tt.wantEvent, strings.Contains(data, `"result":"ok"`))
+		})
+	}
+}
@@ -0,0 +1,524 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+	"github.com/google/uuid"
+)
+
+// websocketGUID is the GUID used to compute the Sec-WebSocket-Accept header (RFC 6455).
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket frame opcodes
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// defaultWebSocketMaxMessageSize is the default maximum size of an incoming message.
+const defaultWebSocketMaxMessageSize = 10 << 20
+
+// ErrWebSocketMessageTooLarge is returned when an incoming message exceeds the maximum size.
+var ErrWebSocketMessageTooLarge = errors.New("websocket message too large")
+
+// wsConn is a server-side WebSocket connection.
+type wsConn struct {
+	conn    net.Conn
+	rw      *bufio.ReadWriter
+	writeMu sync.Mutex
+	maxSize int64
+}
+
+// readMessage reads a complete (possibly fragmented) data message.
+// Control frames are handled transparently.
+func (c *wsConn) readMessage() (int, []byte, error) {
+	var (
+		message []byte
+		opcode  int
+	)
+
+	for {
+		fin, frameOp, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch frameOp {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			_ = c.writeFrame(wsOpClose, payload)
+			return 0, nil, io.EOF
+		case wsOpText, wsOpBinary:
+			opcode = frameOp
+			message = payload
+		case wsOpContinuation:
+			message = append(message, payload...)
+		default:
+			return 0, nil, fmt.Errorf("unsupported websocket opcode: %d", frameOp)
+		}
+
+		if int64(len(message)) > c.maxSize {
+			return 0, nil, ErrWebSocketMessageTooLarge
+		}
+		if fin {
+			return opcode, message, nil
+		}
+	}
+}
+
+// readFrame reads a single frame from the connection and unmasks its payload.
+func (c *wsConn) readFrame() (bool, int, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.rw, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	opcode := int(header[0] & 0x0F)
+	masked := header[1]&0x80 != 0
+	length := int64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+
+	if length < 0 || length > c.maxSize {
+		return false, 0, nil, ErrWebSocketMessageTooLarge
+	}
+
+	// Clients must mask all frames sent to the server
+	if !masked {
+		return false, 0, nil, fmt.Errorf("received unmasked websocket frame")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes a single unmasked frame with the FIN bit set.
+func (c *wsConn) writeFrame(opcode int, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	header := []byte{0x80 | byte(opcode)}
+	switch length := len(payload); {
+	case length <= 125:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// close closes the underlying network connection.
+func (c *wsConn) close() error {
+	return c.conn.Close()
+}
+
+// websocketSession represents an active WebSocket connection.
+type websocketSession struct {
+	id     string
+	conn   *wsConn
+	ctx    context.Context
+	cancel context.CancelFunc
+	store  *domain.SessionStore
+	info   *domain.SessionInfoHolder
+}
+
+// WebSocketOption defines a function type for configuring WebSocketTransport
+type WebSocketOption func(*WebSocketTransport)
+
+// WithWebSocketMessageHandler sets the handler that processes incoming JSON-RPC messages.
+func WithWebSocketMessageHandler(handler func(ctx context.Context, rawMessage json.RawMessage) interface{}) WebSocketOption {
+	return func(t *WebSocketTransport) {
+		t.mcpHandler = handler
+	}
+}
+
+// WithWebSocketNotifier registers WebSocket sessions with the notification sender
+// so server notifications are delivered over the socket.
+func WithWebSocketNotifier(notifier *NotificationSender) WebSocketOption {
+	return func(t *WebSocketTransport) {
+		t.notifier = notifier
+	}
+}
+
+// WithWebSocketEndpoint sets the path at which connections are upgraded.
+func WithWebSocketEndpoint(endpoint string) WebSocketOption {
+	return func(t *WebSocketTransport) {
+		t.endpoint = endpoint
+	}
+}
+
+// WithWebSocketLogger sets the logger for the WebSocket transport.
+func WithWebSocketLogger(logger *logging.Logger) WebSocketOption {
+	return func(t *WebSocketTransport) {
+		t.logger = logger
+	}
+}
+
+// WebSocketTransport implements a full-duplex transport over WebSocket.
+// Each JSON-RPC message is framed as a single text frame, and responses are
+// routed back to the socket the request arrived on.
+type WebSocketTransport struct {
+	addr       string
+	endpoint   string
+	srv        *http.Server
+	mcpHandler func(ctx context.Context, rawMessage json.RawMessage) interface{}
+	notifier   *NotificationSender
+	logger     *logging.Logger
+	mu         sync.RWMutex
+	sessions   map[string]*websocketSession
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+// Ensure WebSocketTransport implements the domain.Transport interface
+var _ domain.Transport = (*WebSocketTransport)(nil)
+
+// NewWebSocketTransport creates a new WebSocket transport listening on addr.
+func NewWebSocketTransport(addr string, opts ...WebSocketOption) *WebSocketTransport {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Create default logger
+	defaultLogger, err := logging.New(logging.Config{
+		Level:       logging.InfoLevel,
+		Development: true,
+		OutputPaths: []string{"stdout"},
+		InitialFields: logging.Fields{
+			"component": "websocket-transport",
+		},
+	})
+	if err != nil {
+		// Fallback to a simple default logger if we can't create the structured one
+		defaultLogger = logging.Default()
+	}
+
+	t := &WebSocketTransport{
+		addr:     addr,
+		endpoint: "/ws",
+		logger:   defaultLogger,
+		sessions: make(map[string]*websocketSession),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	// Apply all options
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// TransportFactory creates a transport listening on the given address.
+type TransportFactory func(addr string) domain.Transport
+
+// NewWebSocketTransportFactory returns a factory that creates WebSocket transports
+// configured with the given options.
+func NewWebSocketTransportFactory(opts ...WebSocketOption) TransportFactory {
+	return func(addr string) domain.Transport {
+		return NewWebSocketTransport(addr, opts...)
+	}
+}
+
+// Start begins accepting WebSocket connections on the configured address.
+func (t *WebSocketTransport) Start() error {
+	t.srv = &http.Server{
+		Addr:    t.addr,
+		Handler: t,
+	}
+
+	t.logger.Info("Starting WebSocket transport", logging.Fields{"address": t.addr, "endpoint": t.endpoint})
+	err := t.srv.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Send sends a message to the client connected with the given session ID.
+func (t *WebSocketTransport) Send(sessionID string, message interface{}) error {
+	t.mu.RLock()
+	session, ok := t.sessions[sessionID]
+	t.mu.RUnlock()
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	return session.conn.writeFrame(wsOpText, data)
+}
+
+// Close stops the transport and closes all active connections.
+func (t *WebSocketTransport) Close() error {
+	t.cancel()
+
+	t.mu.Lock()
+	for id, session := range t.sessions {
+		session.cancel()
+		_ = session.conn.writeFrame(wsOpClose, nil)
+		_ = session.conn.close()
+		delete(t.sessions, id)
+	}
+	t.mu.Unlock()
+
+	if t.srv != nil {
+		return t.srv.Shutdown(context.Background())
+	}
+	return nil
+}
+
+// Count returns the number of active WebSocket sessions.
+func (t *WebSocketTransport) Count() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.sessions)
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (t *WebSocketTransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != t.endpoint {
+		http.NotFound(w, r)
+		return
+	}
+	t.handleUpgrade(w, r)
+}
+
+// handleUpgrade upgrades the HTTP connection to a WebSocket and serves it.
+func (t *WebSocketTransport) handleUpgrade(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !headerContainsToken(r.Header, "Connection", "upgrade") || !headerContainsToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "Expected WebSocket upgrade", http.StatusBadRequest)
+		return
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported WebSocket version", http.StatusUpgradeRequired)
+		return
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "Missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket unsupported", http.StatusInternalServerError)
+		return
+	}
+	netConn, rw, err := hijacker.Hijack()
+	if err != nil {
+		t.logger.Error("Failed to hijack connection", logging.Fields{"error": err})
+		return
+	}
+
+	handshake := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + websocketAccept(key) + "\r\n\r\n"
+	if _, err := rw.WriteString(handshake); err != nil {
+		_ = netConn.Close()
+		return
+	}
+	if err := rw.Flush(); err != nil {
+		_ = netConn.Close()
+		return
+	}
+
+	sessionID := r.URL.Query().Get("session")
+	if sessionID == "" {
+		sessionID = uuid.New().String()
+	}
+
+	sessionCtx, sessionCancel := context.WithCancel(t.ctx)
+	session := &websocketSession{
+		id: sessionID,
+		conn: &wsConn{
+			conn:    netConn,
+			rw:      rw,
+			maxSize: defaultWebSocketMaxMessageSize,
+		},
+		ctx:    sessionCtx,
+		cancel: sessionCancel,
+		store:  domain.NewSessionStore(),
+		info:   domain.NewSessionInfoHolder(sessionID, r.UserAgent()),
+	}
+
+	t.addSession(session)
+	defer t.removeSession(session)
+	defer session.store.Clear()
+
+	if t.notifier != nil {
+		mcpSession := NewMCPSession(sessionID, r.UserAgent(), 100)
+		t.notifier.RegisterSession(mcpSession)
+		defer t.notifier.unregisterSessionInstance(mcpSession)
+		go t.forwardNotifications(session, mcpSession)
+	}
+
+	t.serveSession(session)
+}
+
+// serveSession reads messages from the session and writes back responses.
+func (t *WebSocketTransport) serveSession(session *websocketSession) {
+	defer session.cancel()
+	defer session.conn.close()
+
+	for {
+		opcode, message, err := session.conn.readMessage()
+		if err != nil {
+			if !errors.Is(err, io.EOF) && session.ctx.Err() == nil {
+				t.logger.Debug("WebSocket read ended", logging.Fields{"sessionId": session.id, "error": err})
+			}
+			return
+		}
+		if opcode != wsOpText || t.mcpHandler == nil {
+			continue
+		}
+
+		ctx := domain.WithSessionStore(session.ctx, session.store)
+		ctx = domain.WithSessionInfo(ctx, session.info)
+		response := t.mcpHandler(ctx, json.RawMessage(message))
+		if response == nil {
+			continue
+		}
+
+		data, err := json.Marshal(response)
+		if err != nil {
+			t.logger.Error("Failed to marshal response", logging.Fields{"sessionId": session.id, "error": err})
+			continue
+		}
+		if err := session.conn.writeFrame(wsOpText, data); err != nil {
+			return
+		}
+	}
+}
+
+// forwardNotifications writes notifications for the session to its socket.
+func (t *WebSocketTransport) forwardNotifications(session *websocketSession, mcpSession *MCPSession) {
+	for {
+		select {
+		case notification, ok := <-mcpSession.NotificationChannel():
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(notification)
+			if err != nil {
+				continue
+			}
+			if err := session.conn.writeFrame(wsOpText, data); err != nil {
+				return
+			}
+		case <-session.ctx.Done():
+			return
+		}
+	}
+}
+
+// addSession registers the session, closing any existing session with the same ID.
+func (t *WebSocketTransport) addSession(session *websocketSession) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if existing, ok := t.sessions[session.id]; ok {
+		existing.cancel()
+		_ = existing.conn.close()
+	}
+	t.sessions[session.id] = session
+}
+
+// removeSession removes the session if it is still the one registered under its ID.
+func (t *WebSocketTransport) removeSession(session *websocketSession) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if current, ok := t.sessions[session.id]; ok && current == session {
+		delete(t.sessions, session.id)
+	}
+}
+
+// websocketAccept computes the Sec-WebSocket-Accept value for the given key.
+func websocketAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// headerContainsToken reports whether the comma-separated header contains the token.
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dialWebSocket performs a WebSocket handshake against the test server.
+func dialWebSocket(t *testing.T, serverURL, path string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(serverURL, "http://"))
+	require.NoError(t, err)
+
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	request := "GET " + path + " HTTP/1.1\r\n" +
+		"Host: localhost\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"
+	_, err = conn.Write([]byte(request))
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	require.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get("Sec-WebSocket-Accept"))
+
+	return conn, reader
+}
+
+// writeClientFrame writes a masked text frame as a client would.
+func writeClientFrame(t *testing.T, conn net.Conn, payload []byte) {
+	t.Helper()
+
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | wsOpText}
+	if len(payload) <= 125 {
+		frame = append(frame, 0x80|byte(len(payload)))
+	} else {
+		frame = append(frame, 0x80|126, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(len(payload)))
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	_, err := conn.Write(frame)
+	require.NoError(t, err)
+}
+
+// readServerFrame reads an unmasked frame sent by the server.
+func readServerFrame(t *testing.T, conn net.Conn, reader *bufio.Reader) []byte {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	var header [2]byte
+	_, err := io.ReadFull(reader, header[:])
+	require.NoError(t, err)
+	require.Equal(t, byte(wsOpText), header[0]&0x0F)
+
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		_, err = io.ReadFull(reader, ext[:])
+		require.NoError(t, err)
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(reader, payload)
+	require.NoError(t, err)
+	return payload
+}
+
+func TestWebSocketTransport_RoundTrip(t *testing.T) {
+	handler := func(ctx context.Context, rawMessage json.RawMessage) interface{} {
+		var request domain.JSONRPCRequest
+		_ = json.Unmarshal(rawMessage, &request)
+		return domain.CreateResponse("2.0", request.ID, map[string]interface{}{"method": request.Method})
+	}
+
+	transport := NewWebSocketTransport(":0", WithWebSocketMessageHandler(handler))
+	testServer := httptest.NewServer(transport)
+	defer testServer.Close()
+
+	conn, reader := dialWebSocket(t, testServer.URL, "/ws?session=ws-1")
+	defer conn.Close()
+
+	writeClientFrame(t, conn, []byte(`{"jsonrpc":"2.0","id":7,"method":"ping"}`))
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(readServerFrame(t, conn, reader), &response))
+	assert.Equal(t, float64(7), response["id"])
+	assert.Equal(t, "ping", response["result"].(map[string]interface{})["method"])
+
+	// Messages sent by the server are routed to the session's socket
+	require.Eventually(t, func() bool { return transport.Count() == 1 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, transport.Send("ws-1", map[string]string{"hello": "world"}))
+	assert.JSONEq(t, `{"hello":"world"}`, string(readServerFrame(t, conn, reader)))
+
+	assert.ErrorIs(t, transport.Send("unknown", "x"), ErrSessionNotFound)
+}
+
+func TestWebSocketTransport_RejectsNonUpgrade(t *testing.T) {
+	transport := NewWebSocketTransport(":0")
+
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	w := httptest.NewRecorder()
+	transport.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/other", nil)
+	w = httptest.NewRecorder()
+	transport.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestNewWebSocketTransportFactory(t *testing.T) {
+	factory := NewWebSocketTransportFactory()
+	transport := factory(":0")
+
+	_, ok := transport.(*WebSocketTransport)
+	assert.True(t, ok)
+	assert.NoError(t, transport.Close())
+}
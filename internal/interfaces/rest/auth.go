@@ -0,0 +1,72 @@
+package rest
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+)
+
+// ErrUnauthorized is returned when a request does not carry valid credentials.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// AuthFunc authenticates an HTTP request, returning an error to reject it.
+type AuthFunc func(r *http.Request) error
+
+// WithAuthToken requires every request except /status to present the given
+// token in an "Authorization: Bearer" header. The token is compared in
+// constant time. An empty token, such as one read from an unset environment
+// variable, rejects every request instead of disabling authentication.
+func WithAuthToken(token string) MCPServerOption {
+	return WithAuthFunc(bearerTokenAuth(token))
+}
+
+// WithAuthFunc authenticates every request except /status with the given
+// function. Rejected requests receive HTTP 401.
+func WithAuthFunc(authFunc AuthFunc) MCPServerOption {
+	return func(s *MCPServer) {
+		s.authFunc = authFunc
+	}
+}
+
+// bearerTokenAuth returns an AuthFunc that checks for a static bearer token.
+// An empty token matches no request, not even one with an empty bearer token.
+func bearerTokenAuth(token string) AuthFunc {
+	expected := []byte(token)
+	return func(r *http.Request) error {
+		if len(expected) == 0 {
+			return ErrUnauthorized
+		}
+		header := r.Header.Get("Authorization")
+		const prefix = "Bearer "
+		if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+			return ErrUnauthorized
+		}
+		if subtle.ConstantTimeCompare([]byte(header[len(prefix):]), expected) != 1 {
+			return ErrUnauthorized
+		}
+		return nil
+	}
+}
+
+// authMiddleware rejects unauthenticated requests. The status and health
+// endpoints stay open so health checks work without credentials.
+func (s *MCPServer) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == s.path("/status") || r.URL.Path == s.path(s.healthPath) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := s.authFunc(r); err != nil {
+			s.logger.Warn("Rejected unauthenticated request", logging.Fields{"path": r.URL.Path, "remote": r.RemoteAddr})
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
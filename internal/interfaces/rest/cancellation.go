@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"context"
+	"errors"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+)
+
+// errRequestCancelled is the cause of the context of a request the client
+// cancelled with notifications/cancelled. Such requests get no response.
+var errRequestCancelled = errors.New("request cancelled by the client")
+
+// inflightKey identifies an in-flight request. Request IDs are only unique
+// within a session, so the originating session is part of the key.
+type inflightKey struct {
+	sessionID string
+	id        domain.RequestID
+}
+
+// newInflightKey returns the key of the request with the given ID in the
+// session of ctx. IDs are normalized so a cancellation whose requestId was
+// decoded as a float64 matches the request it refers to. It reports false for
+// requests without a session, such as plain POSTs to /jsonrpc: their IDs are
+// not scoped to one client, so one client could cancel another's request.
+func newInflightKey(ctx context.Context, id interface{}) (inflightKey, bool) {
+	sessionID, ok := domain.SessionIDFromContext(ctx)
+	if !ok {
+		return inflightKey{}, false
+	}
+	requestID, ok := domain.NewRequestID(id)
+	if !ok {
+		return inflightKey{}, false
+	}
+	return inflightKey{sessionID: sessionID, id: requestID}, true
+}
+
+// trackRequest records the cancel function of an in-flight request so it can
+// be cancelled by a notifications/cancelled message. The returned function
+// stops tracking the request and frees its ID for reuse. It reports false if
+// a request with the same ID is already in flight in the session. Requests
+// without a session are not tracked and cannot be cancelled.
+func (s *MCPServer) trackRequest(ctx context.Context, id interface{}, cancel context.CancelFunc) (func(), bool) {
+	key, ok := newInflightKey(ctx, id)
+	if !ok {
+		return func() {}, true
+	}
+
+	s.inflightMu.Lock()
+	if _, duplicate := s.inflight[key]; duplicate {
+		s.inflightMu.Unlock()
+		return nil, false
+	}
+	s.inflight[key] = cancel
+	s.inflightMu.Unlock()
+
+	return func() {
+		s.inflightMu.Lock()
+		delete(s.inflight, key)
+		s.inflightMu.Unlock()
+	}, true
+}
+
+// processCancelled cancels the in-flight request referenced by a
+// notifications/cancelled message. Notifications get no response.
+func (s *MCPServer) processCancelled(ctx context.Context, request domain.JSONRPCRequest) interface{} {
+	params, ok := request.Params.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	requestID, ok := params["requestId"]
+	if !ok || requestID == nil {
+		return nil
+	}
+
+	key, ok := newInflightKey(ctx, requestID)
+	if !ok {
+		return nil
+	}
+
+	s.inflightMu.Lock()
+	cancel, ok := s.inflight[key]
+	s.inflightMu.Unlock()
+
+	if !ok {
+		s.logger.Debug("Cancellation for unknown request", logging.Fields{"requestId": requestID})
+		return nil
+	}
+
+	s.logger.Info("Cancelling request", logging.Fields{"requestId": requestID, "reason": params["reason"]})
+	cancel()
+	return nil
+}
@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"context"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+)
+
+// Capabilities the server can advertise in the initialize response.
+const (
+	CapabilityResources   = "resources"
+	CapabilityTools       = "tools"
+	CapabilityPrompts     = "prompts"
+	CapabilityLogging     = "logging"
+	CapabilityCompletions = "completions"
+)
+
+// WithCapabilities sets the capabilities advertised in the initialize
+// response, instead of deriving them from what is registered. Unknown names
+// are ignored.
+func WithCapabilities(names ...string) MCPServerOption {
+	return func(s *MCPServer) {
+		s.capabilities = append([]string{}, names...)
+	}
+}
+
+// Capabilities returns the capabilities advertised in the initialize
+// response over both HTTP and stdio. Unless they are set with
+// WithCapabilities, tools, resources and prompts are advertised only if any
+// are registered, completions only if a completer is registered, and logging
+// always.
+func (s *MCPServer) Capabilities(ctx context.Context) map[string]interface{} {
+	names := s.capabilities
+	if names == nil {
+		names = s.detectCapabilities(ctx)
+	}
+
+	capabilities := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		switch name {
+		case CapabilityResources:
+			capabilities[name] = map[string]bool{"subscribe": true, "listChanged": true}
+		case CapabilityTools, CapabilityPrompts:
+			capabilities[name] = map[string]bool{"listChanged": true}
+		case CapabilityLogging, CapabilityCompletions:
+			capabilities[name] = struct{}{}
+		}
+	}
+	return capabilities
+}
+
+// detectCapabilities returns the capabilities backed by registered tools,
+// resources, prompts and completers.
+func (s *MCPServer) detectCapabilities(ctx context.Context) []string {
+	service := s.serviceFromContext(ctx)
+	names := []string{CapabilityLogging}
+
+	if tools, err := service.ListTools(ctx); err == nil && len(tools) > 0 {
+		names = append(names, CapabilityTools)
+	}
+	resources, err := service.ListResources(ctx)
+	hasResources := err == nil && len(resources) > 0
+	if !hasResources {
+		templates, err := service.ListResourceTemplates(ctx)
+		hasResources = err == nil && len(templates) > 0
+	}
+	if hasResources {
+		names = append(names, CapabilityResources)
+	}
+	prompts, err := service.ListPrompts(ctx)
+	if err == nil && len(prompts) > 0 {
+		names = append(names, CapabilityPrompts)
+	}
+	if service.HasCompletions() || hasCompletableArgument(prompts) {
+		names = append(names, CapabilityCompletions)
+	}
+	return names
+}
+
+// hasCompletableArgument reports whether any prompt has a completable
+// argument.
+func hasCompletableArgument(prompts []*domain.Prompt) bool {
+	for _, prompt := range prompts {
+		for _, param := range prompt.Parameters {
+			if param.Completable {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package rest
+
+import "github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/server"
+
+// WithCORS restricts which browser origins may call the server. Only the
+// listed origins, such as "https://app.example.com", get an
+// Access-Control-Allow-Origin header; "*" allows any origin. With
+// allowCredentials, the request's Origin is echoed back instead of * and
+// Access-Control-Allow-Credentials is set. The policy applies to every
+// endpoint, including /jsonrpc, /sse and /message, and preflight OPTIONS
+// requests are answered before authentication. By default any origin is
+// allowed without credentials.
+func WithCORS(allowedOrigins []string, allowCredentials bool) MCPServerOption {
+	return func(s *MCPServer) {
+		s.cors = server.CORSPolicy{
+			AllowedOrigins:   allowedOrigins,
+			AllowCredentials: allowCredentials,
+		}
+	}
+}
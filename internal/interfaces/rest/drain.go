@@ -0,0 +1,128 @@
+package rest
+
+import (
+	"context"
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+)
+
+// drainingErrorCode is returned for requests rejected while the server drains.
+const drainingErrorCode = domain.ServerErrorCode
+
+// shutdownFlushTimeout bounds how long Stop waits for the shutdown
+// notification to be written when no grace period is set.
+const shutdownFlushTimeout = time.Second
+
+// drainStopTimeout bounds how long Drain waits for cancelled requests to
+// return once its deadline has passed before closing their connections.
+const drainStopTimeout = time.Second
+
+// WithShutdownGrace sets how long Stop waits after notifying connected
+// clients with notifications/server/shutdown before closing their sessions,
+// so they can react before the connection drops. The default is zero: the
+// notification is still sent, but sessions close as soon as it is written.
+func WithShutdownGrace(grace time.Duration) MCPServerOption {
+	return func(s *MCPServer) {
+		s.shutdownGrace = grace
+	}
+}
+
+// Drain gracefully stops the server. New requests are rejected with -32000
+// "server draining" while requests already being processed run to completion,
+// then the server is stopped. If ctx expires first, the contexts of the
+// remaining requests are cancelled, connections whose handlers still have not
+// returned shortly after are closed, and ctx's error is returned.
+func (s *MCPServer) Drain(ctx context.Context) error {
+	s.drainMu.Lock()
+	s.draining = true
+	s.drainMu.Unlock()
+
+	s.logger.Info("Draining MCP server", logging.Fields{"address": s.httpServer.Addr})
+
+	done := make(chan struct{})
+	go func() {
+		s.requests.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return s.Stop(ctx)
+	case <-ctx.Done():
+		s.logger.Warn("Drain deadline reached, cancelling in-flight requests", logging.Fields{"error": ctx.Err()})
+		s.abortRequests()
+
+		// Stop with a fresh context, ctx is already done, and close the
+		// connections of handlers that ignore the cancellation
+		stopCtx, cancel := context.WithTimeout(context.Background(), drainStopTimeout)
+		defer cancel()
+		if err := s.Stop(stopCtx); err != nil {
+			s.logger.Warn("Requests still running after drain, closing connections", logging.Fields{"error": err})
+			if err := s.httpServer.Close(); err != nil {
+				s.logger.Warn("Failed to close connections", logging.Fields{"error": err})
+			}
+		}
+		return ctx.Err()
+	}
+}
+
+// Draining reports whether Drain has been called.
+func (s *MCPServer) Draining() bool {
+	s.drainMu.RLock()
+	defer s.drainMu.RUnlock()
+	return s.draining
+}
+
+// beginRequest registers an in-flight request so Drain waits for it. It
+// returns a function to call when the request is done, or an error response
+// if the server is draining. Notifications are still accepted so clients can
+// cancel requests that are being drained.
+func (s *MCPServer) beginRequest(request domain.JSONRPCRequest) (func(), interface{}) {
+	// Hold the lock while adding so Add never races with Drain's Wait
+	s.drainMu.RLock()
+	defer s.drainMu.RUnlock()
+
+	if s.draining && request.ID != nil {
+		return nil, domain.CreateErrorResponse(jsonRPCVersion, request.ID, drainingErrorCode, "server draining")
+	}
+	s.requests.Add(1)
+	s.inFlight.Add(1)
+	return func() {
+		s.inFlight.Add(-1)
+		s.requests.Done()
+	}, nil
+}
+
+// notifyShutdown sends notifications/server/shutdown to the connected SSE
+// clients, waits for it to be written and then for the shutdown grace period,
+// or until ctx is done. Stop closes the sessions once it returns.
+func (s *MCPServer) notifyShutdown(ctx context.Context) {
+	if s.sseServer.SessionCount() == 0 {
+		return
+	}
+
+	notification := &domain.Notification{
+		Method: "notifications/server/shutdown",
+		Params: map[string]interface{}{"gracePeriodMs": s.shutdownGrace.Milliseconds()},
+	}
+	if err := s.notifier.BroadcastNotification(ctx, notification); err != nil {
+		s.logger.Warn("Failed to notify clients of shutdown", logging.Fields{"error": err})
+	}
+
+	deadline := time.Now().Add(s.shutdownGrace)
+	flushCtx, cancel := context.WithTimeout(ctx, max(s.shutdownGrace, shutdownFlushTimeout))
+	defer cancel()
+	if err := s.sseServer.WaitForQueuedEvents(flushCtx); err != nil {
+		s.logger.Warn("Shutdown notification not delivered to all clients", logging.Fields{"error": err})
+	}
+
+	// Give clients the rest of the grace period to react
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
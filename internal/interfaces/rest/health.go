@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+)
+
+// defaultHealthPath is the path of the health check endpoint.
+const defaultHealthPath = "/healthz"
+
+// WithHealthPath changes the path of the health check endpoint from /healthz,
+// for example if it conflicts with another route. The path prefix still applies.
+func WithHealthPath(path string) MCPServerOption {
+	return func(s *MCPServer) {
+		s.healthPath = path
+	}
+}
+
+// listen opens the listener for the HTTP server and marks the server as ready
+// to accept connections. An empty address listens on defaultAddr.
+func (s *MCPServer) listen(defaultAddr string) (net.Listener, error) {
+	addr := s.httpServer.Addr
+	if addr == "" {
+		addr = defaultAddr
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s.ready.Store(true)
+	return listener, nil
+}
+
+// handleHealth reports whether the server can take requests. It answers 200
+// once the server is listening and 503 before that, while draining and after
+// it stopped. It does no other work so it can be used as a liveness or
+// readiness probe.
+func (s *MCPServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	status, reason := http.StatusOK, ""
+	switch {
+	case s.ctx.Err() != nil:
+		status, reason = http.StatusServiceUnavailable, "stopped"
+	case s.Draining():
+		status, reason = http.StatusServiceUnavailable, "draining"
+	case !s.ready.Load():
+		status, reason = http.StatusServiceUnavailable, "starting"
+	}
+
+	body := map[string]interface{}{"status": "ok"}
+	if status != http.StatusOK {
+		body = map[string]interface{}{"status": "unavailable", "reason": reason}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
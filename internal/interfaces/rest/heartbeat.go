@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+)
+
+// WithHeartbeat broadcasts a notifications/heartbeat with the server's uptime
+// and active session count to all sessions at the given interval, for clients
+// that use it to confirm the server is healthy. Unlike SSE keepalive comments
+// it is an application-level message. Disabled by default.
+func WithHeartbeat(interval time.Duration) MCPServerOption {
+	return func(s *MCPServer) {
+		s.heartbeat = interval
+	}
+}
+
+// startHeartbeat starts broadcasting heartbeat notifications if enabled. The
+// broadcasts stop when the server is stopped.
+func (s *MCPServer) startHeartbeat() {
+	if s.heartbeat <= 0 {
+		return
+	}
+	s.heartbeatOnce.Do(func() {
+		go s.runHeartbeat()
+	})
+}
+
+// runHeartbeat broadcasts a heartbeat on every tick until the server context is done.
+func (s *MCPServer) runHeartbeat() {
+	ticker := time.NewTicker(s.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.notifier.BroadcastNotification(s.ctx, s.heartbeatNotification()); err != nil && s.ctx.Err() == nil {
+				s.logger.Warn("Failed to broadcast heartbeat", logging.Fields{"error": err})
+			}
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// heartbeatNotification creates a notifications/heartbeat with the current server status.
+func (s *MCPServer) heartbeatNotification() *domain.Notification {
+	return &domain.Notification{
+		Method: domain.HeartbeatMethod,
+		Params: map[string]interface{}{
+			"status":        "ok",
+			"uptimeSeconds": int64(time.Since(s.startTime).Seconds()),
+			"sessions":      s.ActiveSessions(),
+			"timestamp":     time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+)
+
+// RequestInterceptor inspects every incoming JSON-RPC message, including
+// notifications, before it is dispatched. Returning an error rejects the
+// message: a *domain.ToolError controls the error code sent to the client,
+// and other errors are sent as -32600 (invalid request) with their message.
+// Rejected notifications are dropped without a response.
+type RequestInterceptor func(ctx context.Context, method string, params json.RawMessage) error
+
+// WithRequestInterceptor adds an interceptor that runs on every message
+// before method dispatch, over HTTP, SSE and stdio alike, for global
+// authorization, audit logging or version gating. Interceptors run in the
+// order they are added and the first error stops processing.
+func WithRequestInterceptor(interceptor RequestInterceptor) MCPServerOption {
+	return func(s *MCPServer) {
+		if interceptor != nil {
+			s.interceptors = append(s.interceptors, interceptor)
+		}
+	}
+}
+
+// InterceptRequest runs the request interceptors on a message. It returns
+// the error to send to the client if one of them rejects it.
+func (s *MCPServer) InterceptRequest(ctx context.Context, method string, params json.RawMessage) *domain.JSONRPCError {
+	for _, interceptor := range s.interceptors {
+		err := interceptor(ctx, method, params)
+		if err == nil {
+			continue
+		}
+
+		s.logger.Warn("Request rejected by interceptor", logging.Fields{"method": method, "error": err})
+		var toolErr *domain.ToolError
+		if errors.As(err, &toolErr) {
+			return &domain.JSONRPCError{Code: toolErr.JSONRPCCode(), Message: toolErr.Message, Data: toolErr.Data}
+		}
+		return &domain.JSONRPCError{Code: domain.InvalidRequestCode, Message: err.Error()}
+	}
+	return nil
+}
+
+// interceptRequest runs the request interceptors on a parsed message. It
+// returns the response to send if the message is rejected, which is nil for
+// rejected notifications.
+func (s *MCPServer) interceptRequest(ctx context.Context, request domain.JSONRPCRequest, rawMessage json.RawMessage) (response interface{}, rejected bool) {
+	if len(s.interceptors) == 0 {
+		return nil, false
+	}
+
+	var raw struct {
+		Params json.RawMessage `json:"params"`
+	}
+	_ = json.Unmarshal(rawMessage, &raw)
+
+	rpcErr := s.InterceptRequest(ctx, request.Method, raw.Params)
+	if rpcErr == nil {
+		return nil, false
+	}
+	if request.ID == nil {
+		return nil, true
+	}
+	return domain.CreateErrorResponseWithData(jsonRPCVersion, request.ID, rpcErr.Code, rpcErr.Message, rpcErr.Data), true
+}
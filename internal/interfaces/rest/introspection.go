@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"context"
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/usecases"
+)
+
+// introspectionToolName is the name of the built-in server info tool.
+const introspectionToolName = "__server_info__"
+
+// WithIntrospectionTool registers the read-only "__server_info__" tool, which
+// returns the same server details as the /status endpoint through a tool call.
+func WithIntrospectionTool() MCPServerOption {
+	return func(s *MCPServer) {
+		s.introspection = true
+	}
+}
+
+// serverStatus collects the server details reported by /status and the
+// introspection tool.
+func (s *MCPServer) serverStatus(ctx context.Context, service *usecases.ServerService) map[string]interface{} {
+	name, version, _ := service.ServerInfo()
+
+	status := map[string]interface{}{
+		"name":     name,
+		"version":  version,
+		"protocol": mcpProtocolVersion,
+		"uptime":   time.Since(s.startTime).Round(time.Second).String(),
+		"sessions": 0,
+	}
+	if s.sseServer != nil {
+		status["sessions"] = s.sseServer.SessionCount()
+	}
+
+	if tools, err := service.ListTools(ctx); err == nil {
+		status["tools"] = len(tools)
+	}
+	if resources, err := service.ListResources(ctx); err == nil {
+		status["resources"] = len(resources)
+	}
+	if prompts, err := service.ListPrompts(ctx); err == nil {
+		status["prompts"] = len(prompts)
+	}
+
+	calls := service.CallConcurrency()
+	status["toolCalls"] = map[string]interface{}{
+		"maxConcurrent": calls.Limit,
+		"queue":         calls.Queue,
+		"inFlight":      calls.InFlight,
+		"queued":        calls.Queued,
+		"rejected":      calls.Rejected,
+	}
+
+	return status
+}
+
+// registerIntrospectionTool adds the server info tool to the service.
+func (s *MCPServer) registerIntrospectionTool(service *usecases.ServerService) {
+	tool := &domain.Tool{
+		Name:        introspectionToolName,
+		Description: "Returns server name, version, uptime, active sessions and registered tool, resource and prompt counts",
+	}
+
+	err := service.AddToolWithHandler(s.ctx, tool, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return s.serverStatus(ctx, s.serviceFromContext(ctx)), nil
+	})
+	if err != nil {
+		s.logger.Warn("Failed to register introspection tool", logging.Fields{"error": err})
+	}
+}
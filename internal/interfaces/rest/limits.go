@@ -0,0 +1,17 @@
+package rest
+
+import "github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+
+// serverBusyErrorCode is returned for tool calls rejected because the
+// concurrency limit is reached.
+const serverBusyErrorCode = domain.ServerErrorCode
+
+// callRateLimitErrorCode is returned for tool calls over the session or
+// server call rate limit.
+const callRateLimitErrorCode = domain.ServerErrorCode
+
+// isCallRateLimit reports whether err is from the session or server call rate
+// limit rather than a tool's own rate limit.
+func isCallRateLimit(err *domain.RateLimitError) bool {
+	return err.Scope == domain.RateLimitScopeSession || err.Scope == domain.RateLimitScopeServer
+}
@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+)
+
+// SetLogLevel sets the minimum level of the server's logs from a log level
+// name of the MCP protocol, such as "warning". It returns a
+// domain.ValidationError for unknown names.
+func (s *MCPServer) SetLogLevel(level string) error {
+	logLevel, ok := logging.LevelFromMCP(level)
+	if !ok {
+		return domain.NewValidationError("level", fmt.Sprintf("unknown log level %q", level))
+	}
+	return s.logger.SetLevel(logLevel)
+}
+
+// processLoggingSetLevel handles logging/setLevel requests.
+func (s *MCPServer) processLoggingSetLevel(ctx context.Context, request domain.JSONRPCRequest) interface{} {
+	params, _ := request.Params.(map[string]interface{})
+	level, _ := params["level"].(string)
+
+	if err := s.SetLogLevel(level); err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidParamsCode, fmt.Sprintf("Invalid params: %v", err))
+		}
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InternalErrorCode, fmt.Sprintf("Internal error: %v", err))
+	}
+
+	s.logger.Info("Log level changed", logging.Fields{"level": level})
+	return domain.CreateResponse(jsonRPCVersion, request.ID, struct{}{})
+}
+
+// clientLog returns the function that forwards log messages from a tool
+// handler to the session that made the request, or to all sessions for
+// requests without one. Messages below the server's log level are dropped.
+func (s *MCPServer) clientLog(ctx context.Context) domain.ClientLogFunc {
+	sessionID, hasSession := domain.SessionIDFromContext(ctx)
+	return func(ctx context.Context, level, logger string, data interface{}) error {
+		if !clientLogEnabled(s.logger, level) {
+			return nil
+		}
+		notification := domain.NewLogMessageNotification(level, logger, data)
+		if hasSession {
+			return s.notifier.SendNotification(ctx, sessionID, notification)
+		}
+		return s.notifier.BroadcastNotification(ctx, notification)
+	}
+}
+
+// clientLogEnabled reports whether log messages at the MCP level pass the
+// logger's level.
+func clientLogEnabled(logger *logging.Logger, level string) bool {
+	logLevel, ok := logging.LevelFromMCP(level)
+	return ok && logger.Enabled(logLevel)
+}
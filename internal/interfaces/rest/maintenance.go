@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"strings"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+)
+
+// maintenanceErrorCode is returned for requests rejected during maintenance.
+const maintenanceErrorCode = domain.MaintenanceCode
+
+// defaultMaintenanceMessage is used when no maintenance message is given.
+const defaultMaintenanceMessage = "Server is in maintenance mode"
+
+// SetMaintenanceMode puts the server into or out of maintenance mode. While in
+// maintenance, existing sessions stay connected and initialize, ping and list
+// requests still work, but all other requests such as tools/call are rejected
+// with -32001 and the given message. Connected clients are notified of the change.
+func (s *MCPServer) SetMaintenanceMode(on bool, message string) {
+	if message == "" {
+		message = defaultMaintenanceMessage
+	}
+
+	s.maintenanceMu.Lock()
+	changed := s.maintenance != on
+	s.maintenance = on
+	s.maintenanceMessage = message
+	s.maintenanceMu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	s.logger.Info("Maintenance mode changed", logging.Fields{"maintenance": on, "message": message})
+
+	level := "info"
+	if on {
+		level = "warning"
+	}
+	if err := s.notifier.BroadcastNotification(s.ctx, &domain.Notification{
+		Method: "notifications/message",
+		Params: map[string]interface{}{
+			"level":  level,
+			"logger": "server",
+			"data": map[string]interface{}{
+				"maintenance": on,
+				"message":     message,
+			},
+		},
+	}); err != nil {
+		s.logger.Warn("Failed to broadcast maintenance mode", logging.Fields{"error": err})
+	}
+}
+
+// InMaintenance reports whether the server is in maintenance mode.
+func (s *MCPServer) InMaintenance() bool {
+	s.maintenanceMu.RLock()
+	defer s.maintenanceMu.RUnlock()
+	return s.maintenance
+}
+
+// MaintenanceRejection returns the error to reject a request for method with
+// because the server is in maintenance mode, or nil if the request is
+// allowed. Transports other than the built-in HTTP ones, such as stdio, use
+// it to enforce maintenance mode.
+func (s *MCPServer) MaintenanceRejection(method string) *domain.JSONRPCError {
+	s.maintenanceMu.RLock()
+	on, message := s.maintenance, s.maintenanceMessage
+	s.maintenanceMu.RUnlock()
+
+	if !on || allowedDuringMaintenance(method) {
+		return nil
+	}
+	return &domain.JSONRPCError{Code: maintenanceErrorCode, Message: message}
+}
+
+// maintenanceRejection returns an error response if the request must be
+// rejected because the server is in maintenance mode, or nil otherwise.
+func (s *MCPServer) maintenanceRejection(request domain.JSONRPCRequest) interface{} {
+	rpcErr := s.MaintenanceRejection(request.Method)
+	if rpcErr == nil {
+		return nil
+	}
+	return domain.CreateErrorResponse(jsonRPCVersion, request.ID, rpcErr.Code, rpcErr.Message)
+}
+
+// allowedDuringMaintenance reports whether a method keeps working in maintenance mode.
+func allowedDuringMaintenance(method string) bool {
+	switch method {
+	case "initialize", "ping", "tools/list", "resources/list", "resources/templates/list", "prompts/list":
+		return true
+	}
+	return strings.HasPrefix(method, "notifications/")
+}
@@ -0,0 +1,133 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+)
+
+// MethodHandler handles a custom JSON-RPC method. Returning a
+// *domain.ToolError controls the error code sent to the client; other errors
+// are sent as -32603 (internal error).
+type MethodHandler func(ctx context.Context, params json.RawMessage) (interface{}, error)
+
+// ErrReservedMethod is returned when registering a handler for a method the
+// MCP protocol defines.
+var ErrReservedMethod = errors.New("method is reserved by the MCP protocol")
+
+// reservedMethods are the MCP methods outside the reserved namespaces.
+var reservedMethods = map[string]bool{
+	"initialize": true,
+	"ping":       true,
+}
+
+// reservedPrefixes are the namespaces of MCP methods. Custom methods cannot
+// use them so they never shadow current or future protocol methods.
+var reservedPrefixes = []string{
+	"tools/",
+	"resources/",
+	"prompts/",
+	"notifications/",
+	"completion/",
+	"logging/",
+	"sampling/",
+	"roots/",
+	"elicitation/",
+}
+
+// IsReservedMethod reports whether method is defined by the MCP protocol.
+func IsReservedMethod(method string) bool {
+	if reservedMethods[method] {
+		return true
+	}
+	for _, prefix := range reservedPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddMethodHandler registers a handler for a custom method, such as an
+// experimental or vendor-specific extension. Requests for methods the server
+// does not implement itself are routed to it. A later registration for the
+// same method replaces the earlier one.
+func (s *MCPServer) AddMethodHandler(method string, handler MethodHandler) error {
+	if method == "" {
+		return fmt.Errorf("method name is required")
+	}
+	if handler == nil {
+		return fmt.Errorf("handler for method %s is nil", method)
+	}
+	if IsReservedMethod(method) {
+		return fmt.Errorf("%w: %s", ErrReservedMethod, method)
+	}
+
+	s.methodsMu.Lock()
+	defer s.methodsMu.Unlock()
+	if s.methods == nil {
+		s.methods = make(map[string]MethodHandler)
+	}
+	s.methods[method] = handler
+	return nil
+}
+
+// HasMethodHandler reports whether a custom handler is registered for method.
+func (s *MCPServer) HasMethodHandler(method string) bool {
+	s.methodsMu.RLock()
+	defer s.methodsMu.RUnlock()
+	_, ok := s.methods[method]
+	return ok
+}
+
+// CallMethodHandler runs the custom handler registered for method. ok is
+// false if there is none.
+func (s *MCPServer) CallMethodHandler(ctx context.Context, method string, params json.RawMessage) (result interface{}, rpcErr *domain.JSONRPCError, ok bool) {
+	s.methodsMu.RLock()
+	handler, ok := s.methods[method]
+	s.methodsMu.RUnlock()
+	if !ok {
+		return nil, nil, false
+	}
+
+	result, err := handler(ctx, params)
+	if err != nil {
+		var toolErr *domain.ToolError
+		if errors.As(err, &toolErr) {
+			return nil, &domain.JSONRPCError{Code: toolErr.JSONRPCCode(), Message: toolErr.Message, Data: toolErr.Data}, true
+		}
+		s.logger.Warn("Method handler failed", logging.Fields{"method": method, "error": err})
+		return nil, &domain.JSONRPCError{Code: domain.InternalErrorCode, Message: err.Error()}, true
+	}
+	if result == nil {
+		result = map[string]interface{}{}
+	}
+	return result, nil, true
+}
+
+// processCustomMethod routes a request for a method the server does not
+// implement to its custom handler, if one is registered.
+func (s *MCPServer) processCustomMethod(ctx context.Context, request domain.JSONRPCRequest) interface{} {
+	var params json.RawMessage
+	if request.Params != nil {
+		encoded, err := json.Marshal(request.Params)
+		if err != nil {
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidParamsCode, "Invalid params")
+		}
+		params = encoded
+	}
+
+	result, rpcErr, ok := s.CallMethodHandler(ctx, request.Method, params)
+	if !ok {
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.MethodNotFoundCode, fmt.Sprintf("Method '%s' not found", request.Method))
+	}
+	if rpcErr != nil {
+		return domain.CreateErrorResponseWithData(jsonRPCVersion, request.ID, rpcErr.Code, rpcErr.Message, rpcErr.Data)
+	}
+	return domain.CreateResponse(jsonRPCVersion, request.ID, result)
+}
@@ -0,0 +1,27 @@
+package rest
+
+import "time"
+
+// WithPingDiagnostics adds the server's uptime, active session count and
+// number of in-flight requests to ping results, so monitoring tools can use
+// ping as a health probe. By default ping returns an empty result, as the
+// specification describes.
+func WithPingDiagnostics() MCPServerOption {
+	return func(s *MCPServer) {
+		s.pingDiagnostics = true
+	}
+}
+
+// PingResult returns the result of a ping request, which is empty unless
+// WithPingDiagnostics is set. The in-flight count includes the ping itself.
+func (s *MCPServer) PingResult() interface{} {
+	if !s.pingDiagnostics {
+		return struct{}{}
+	}
+
+	return map[string]interface{}{
+		"uptimeSeconds":    int64(time.Since(s.startTime).Seconds()),
+		"sessions":         s.ActiveSessions(),
+		"inFlightRequests": s.inFlight.Load(),
+	}
+}
@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+)
+
+// redactedValue replaces the values of redacted parameters in logs.
+const redactedValue = "***"
+
+// WithRequestLogging logs a summary line with the method, ID, duration and
+// status of every JSON-RPC message at info level, and its params and response
+// at debug level. Values of the named params, e.g. "password" or "token", are
+// replaced by *** wherever they appear in the logged bodies and in the
+// server's other tool call logs; names are matched case-insensitively.
+// Disabled by default.
+func WithRequestLogging(redactParams ...string) MCPServerOption {
+	return func(s *MCPServer) {
+		s.requestLogging = true
+		s.redactParams = make(map[string]bool, len(redactParams))
+		for _, name := range redactParams {
+			s.redactParams[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// logRequest logs a processed message and its response.
+func (s *MCPServer) logRequest(rawMessage json.RawMessage, response interface{}, duration time.Duration) {
+	var request struct {
+		ID     interface{} `json:"id"`
+		Method string      `json:"method"`
+		Params interface{} `json:"params"`
+	}
+	_ = json.Unmarshal(rawMessage, &request)
+
+	fields := logging.Fields{
+		"method":     request.Method,
+		"id":         request.ID,
+		"durationMs": duration.Milliseconds(),
+		"status":     "ok",
+	}
+	if errResponse, ok := response.(domain.JSONRPCResponse); ok && errResponse.Error != nil {
+		fields["status"] = "error"
+		fields["errorCode"] = errResponse.Error.Code
+	}
+	s.logger.Info("JSON-RPC request", fields)
+
+	s.logger.Debug("JSON-RPC request body", logging.Fields{
+		"method":   request.Method,
+		"id":       request.ID,
+		"params":   s.redact(request.Params),
+		"response": s.redact(toJSONValue(response)),
+	})
+}
+
+// redact returns a copy of a decoded JSON value with the values of redacted
+// params replaced, or the value itself if no params are redacted.
+func (s *MCPServer) redact(value interface{}) interface{} {
+	if len(s.redactParams) == 0 {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if s.redactParams[strings.ToLower(key)] {
+				redacted[key] = redactedValue
+				continue
+			}
+			redacted[key] = s.redact(item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = s.redact(item)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// toJSONValue converts a response to its decoded JSON form so it can be
+// redacted like the request.
+func toJSONValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var decoded interface{}
+	_ = json.Unmarshal(data, &decoded)
+	return decoded
+}
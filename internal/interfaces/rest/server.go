@@ -2,37 +2,115 @@
 package rest
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/server"
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/usecases"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	// JSON-RPC version used by the MCP protocol
 	jsonRPCVersion = "2.0"
 
-	// MCP protocol version
-	mcpProtocolVersion = "2024-11-05"
+	// Default timeout for processing a single request
+	defaultRequestTimeout = 30 * time.Second
+
+	// Interval between tool lookups while waiting for a tool to be registered
+	toolLookupInterval = 10 * time.Millisecond
 )
 
+// mcpProtocolVersion is the MCP protocol version reported by /status
+var mcpProtocolVersion = domain.LatestProtocolVersion
+
 // MCPServer represents the HTTP server for the MCP protocol.
 type MCPServer struct {
+	serviceMu  sync.RWMutex
 	service    *usecases.ServerService
 	httpServer *http.Server
 	sseServer  *server.SSEServer
 	notifier   *server.NotificationSender
 	logger     *logging.Logger
-	ctx        context.Context
-	cancel     context.CancelFunc
+	strictRPC  bool
+	timeout    time.Duration
+	toolGrace  time.Duration
+	// introspection enables the built-in server info tool
+	introspection bool
+	startTime     time.Time
+	inflightMu    sync.Mutex
+	inflight      map[inflightKey]context.CancelFunc
+	authFunc      AuthFunc
+	maxDepth      int
+	maxBodyBytes  int64
+	pathPrefix    string
+	// idleTimeout closes inactive SSE sessions, see WithSessionIdleTimeout
+	idleTimeout time.Duration
+	errorData   bool
+	// prettyJSON indents JSON-RPC responses, see WithPrettyJSON
+	prettyJSON bool
+	// delivery selects the channel for SSE message responses, see WithSSEDelivery
+	delivery server.SSEDelivery
+	// batchMax and batchWindow coalesce queued SSE events, see WithEventBatching
+	batchMax    int
+	batchWindow time.Duration
+	// pingDiagnostics adds server details to ping results, see WithPingDiagnostics
+	pingDiagnostics bool
+	// interceptors inspect every message before dispatch, see WithRequestInterceptor
+	interceptors []RequestInterceptor
+	// tracer starts a span per message, see WithTracer
+	tracer trace.Tracer
+	// Application-level heartbeat, see WithHeartbeat
+	heartbeat     time.Duration
+	heartbeatOnce sync.Once
+	// sessionLocales holds the locale each session requested at initialization
+	sessionLocales sync.Map
+	// Maintenance mode state, see SetMaintenanceMode
+	maintenanceMu      sync.RWMutex
+	maintenance        bool
+	maintenanceMessage string
+	// Drain state, see Drain
+	drainMu  sync.RWMutex
+	draining bool
+	requests sync.WaitGroup
+	inFlight atomic.Int64
+	// abortCtx is cancelled when Drain's deadline passes, cancelling the
+	// requests still in flight
+	abortCtx      context.Context
+	abortRequests context.CancelFunc
+	// shutdownGrace is how long Stop lets clients react to the shutdown
+	// notification, see WithShutdownGrace
+	shutdownGrace time.Duration
+	// Request logging, see WithRequestLogging
+	requestLogging bool
+	redactParams   map[string]bool
+	// Health check state, see handleHealth
+	healthPath string
+	ready      atomic.Bool
+	// metrics records per-method request metrics, see WithMetrics
+	metrics domain.MetricsCollector
+	// capabilities overrides the advertised capabilities, see WithCapabilities
+	capabilities []string
+	// cors is the CORS policy for all endpoints, see WithCORS
+	cors server.CORSPolicy
+	// Custom method handlers, see AddMethodHandler
+	methodsMu sync.RWMutex
+	methods   map[string]MethodHandler
+	ctx       context.Context
+	cancel    context.CancelFunc
 }
 
 // MCPServerOption is a function option for MCPServer
@@ -45,6 +123,121 @@ func WithLogger(logger *logging.Logger) MCPServerOption {
 	}
 }
 
+// WithStrictJSONRPC enables strict structural validation of incoming JSON-RPC
+// requests. Non-conforming requests are rejected with -32600 and a precise reason.
+func WithStrictJSONRPC(strict bool) MCPServerOption {
+	return func(s *MCPServer) {
+		s.strictRPC = strict
+	}
+}
+
+// WithRequestTimeout sets how long a single request may take before it fails
+// with a timeout error. Tools can override it with their own timeout.
+func WithRequestTimeout(timeout time.Duration) MCPServerOption {
+	return func(s *MCPServer) {
+		if timeout > 0 {
+			s.timeout = timeout
+		}
+	}
+}
+
+// WithToolLookupGrace makes tools/call wait up to the given duration for a tool
+// that is not registered yet, instead of failing immediately with not found.
+// This smooths over registration races for servers that add tools asynchronously.
+// The default of zero disables waiting.
+func WithToolLookupGrace(grace time.Duration) MCPServerOption {
+	return func(s *MCPServer) {
+		s.toolGrace = grace
+	}
+}
+
+// WithMaxParamDepth rejects requests whose params are nested deeper than n
+// levels with -32600. Zero, the default, disables the check.
+func WithMaxParamDepth(n int) MCPServerOption {
+	return func(s *MCPServer) {
+		s.maxDepth = n
+	}
+}
+
+// WithMaxRequestBytes limits the size of request bodies on the JSON-RPC and
+// SSE message endpoints. Larger requests are rejected with HTTP 413 and
+// -32600. The default is 4 MiB; zero or less removes the limit.
+func WithMaxRequestBytes(n int64) MCPServerOption {
+	return func(s *MCPServer) {
+		s.maxBodyBytes = n
+	}
+}
+
+// WithSessionIdleTimeout closes SSE sessions that neither received a message
+// nor sent an event for the given duration. Heartbeats do not count as
+// activity. Zero, the default, keeps sessions open until the client
+// disconnects.
+func WithSessionIdleTimeout(timeout time.Duration) MCPServerOption {
+	return func(s *MCPServer) {
+		s.idleTimeout = timeout
+	}
+}
+
+// WithSSEDelivery sets whether responses to messages posted by SSE clients
+// are sent on the SSE stream, in the HTTP response body, or both, the
+// default. Clients that would otherwise receive each response twice can be
+// served on a single channel.
+func WithSSEDelivery(delivery server.SSEDelivery) MCPServerOption {
+	return func(s *MCPServer) {
+		s.delivery = delivery
+	}
+}
+
+// WithEventBatching sends SSE events that queue up within window as a single
+// "batch" event of up to maxBatch messages. Clients must understand the
+// batched format, so batching is off by default.
+func WithEventBatching(maxBatch int, window time.Duration) MCPServerOption {
+	return func(s *MCPServer) {
+		s.batchMax = maxBatch
+		s.batchWindow = window
+	}
+}
+
+// WithPrettyJSON indents JSON-RPC responses written to HTTP response bodies,
+// which is easier to read when debugging by hand. Responses are compact by
+// default; events on the SSE stream are always compact.
+func WithPrettyJSON(pretty bool) MCPServerOption {
+	return func(s *MCPServer) {
+		s.prettyJSON = pretty
+	}
+}
+
+// WithPathPrefix mounts all endpoints under the given path prefix, for servers
+// served behind a reverse proxy on a subpath, e.g. "/mcp" serves "/mcp/sse".
+func WithPathPrefix(prefix string) MCPServerOption {
+	return func(s *MCPServer) {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if prefix != "" && !strings.HasPrefix(prefix, "/") {
+			prefix = "/" + prefix
+		}
+		s.pathPrefix = prefix
+	}
+}
+
+// WithErrorData controls whether error responses include the structured
+// "data" member. When disabled, errors carry only code and message, for
+// clients that reject unknown fields. Data is included by default.
+func WithErrorData(include bool) MCPServerOption {
+	return func(s *MCPServer) {
+		s.errorData = include
+	}
+}
+
+// WithMetrics records the count, error count and latency of every processed
+// message per method with the given collector. If the collector implements
+// http.Handler it is served at /metrics; clients that accept only JSON still
+// get the SSE queue metrics there.
+func WithMetrics(collector domain.MetricsCollector) MCPServerOption {
+	return func(s *MCPServer) {
+		s.metrics = collector
+	}
+}
+
 // NewMCPServer creates a new MCP server.
 func NewMCPServer(service *usecases.ServerService, addr string, opts ...MCPServerOption) *MCPServer {
 	// Create root context for the server
@@ -64,15 +257,26 @@ func NewMCPServer(service *usecases.ServerService, addr string, opts ...MCPServe
 		defaultLogger = logging.Default()
 	}
 
-	notifier := server.NewNotificationSender(jsonRPCVersion)
+	// Share the service's sender so its notifications reach SSE sessions
+	notifier, ok := service.NotificationSender().(*server.NotificationSender)
+	if !ok {
+		notifier = server.NewNotificationSender(jsonRPCVersion)
+	}
 
 	s := &MCPServer{
-		service:  service,
-		notifier: notifier,
-		logger:   defaultLogger,
-		ctx:      ctx,
-		cancel:   cancel,
+		service:      service,
+		notifier:     notifier,
+		logger:       defaultLogger,
+		timeout:      defaultRequestTimeout,
+		errorData:    true,
+		healthPath:   defaultHealthPath,
+		maxBodyBytes: server.DefaultMaxRequestBytes,
+		startTime:    time.Now(),
+		inflight:     make(map[inflightKey]context.CancelFunc),
+		ctx:          ctx,
+		cancel:       cancel,
 	}
+	s.abortCtx, s.abortRequests = context.WithCancel(context.Background())
 
 	// Apply all options
 	for _, opt := range opts {
@@ -81,25 +285,31 @@ func NewMCPServer(service *usecases.ServerService, addr string, opts ...MCPServe
 
 	// Create message handler function for the SSE server
 	mcpHandler := func(ctx context.Context, rawMessage json.RawMessage) interface{} {
-		// Create a child context from the server context
-		handlerCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-		defer cancel() // Call cancel to prevent context leak
-		return s.processMessage(handlerCtx, rawMessage)
+		// The request timeout is applied by processMessage
+		return s.processMessage(ctx, rawMessage)
 	}
 
 	// Create a custom context function for the SSE server
 	contextFunc := func(parentCtx context.Context, r *http.Request) context.Context {
-		// Simply return the parent context instead of creating a new one with a discarded cancel
-		// This maintains the same behavior without leaking the context
-		return parentCtx
+		// Return the parent context instead of creating a new one with a
+		// discarded cancel, joining the caller's trace if tracing is enabled
+		return s.extractTraceContext(parentCtx, r.Header)
 	}
 
 	// Create the SSE Server with MCP message handler and enhanced context handling
 	sseOptions := []server.SSEOption{
 		server.WithMessageEndpoint("/message"),
 		server.WithSSEEndpoint("/sse"),
-		server.WithBasePath(""),
+		server.WithBasePath(s.pathPrefix),
 		server.WithSSEContextFunc(contextFunc),
+		server.WithMaxRequestBytes(s.maxBodyBytes),
+		server.WithCORS(s.cors),
+		server.WithSessionIdleTimeout(s.idleTimeout),
+		server.WithPrettyJSON(s.prettyJSON),
+		server.WithSSEDelivery(s.delivery),
+		server.WithEventBatching(s.batchMax, s.batchWindow),
+		// Release per-session state, such as rate limit budgets, on disconnect
+		server.WithSessionCloseFunc(func(sessionID string) { s.GetService().EndSession(sessionID) }),
 	}
 
 	// If we have a logger, pass it to the SSE server
@@ -115,29 +325,60 @@ func NewMCPServer(service *usecases.ServerService, addr string, opts ...MCPServe
 	mux := http.NewServeMux()
 
 	// Standard MCP endpoints
-	mux.HandleFunc("/", s.handleJSONRPC)        // Default endpoint for JSON-RPC
-	mux.HandleFunc("/jsonrpc", s.handleJSONRPC) // Alternative endpoint for JSON-RPC
-	mux.HandleFunc("/events", s.redirectToSSE)  // Redirect to SSE endpoint
+	mux.HandleFunc(s.path("/"), s.handleJSONRPC)        // Default endpoint for JSON-RPC
+	mux.HandleFunc(s.path("/jsonrpc"), s.handleJSONRPC) // Alternative endpoint for JSON-RPC
+	mux.HandleFunc(s.path("/events"), s.redirectToSSE)  // Redirect to SSE endpoint
 
 	// Add SSE server handler
-	mux.Handle("/sse", sseServer)
-	mux.Handle("/message", sseServer)
+	mux.Handle(s.path("/sse"), sseServer)
+	mux.Handle(s.path("/message"), sseServer)
 
 	// Add a simple status endpoint
-	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc(s.path("/status"), func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		name, version, _ := service.ServerInfo()
-		_ = json.NewEncoder(w).Encode(map[string]interface{}{
-			"status":   "ok",
-			"name":     name,
-			"version":  version,
-			"protocol": mcpProtocolVersion,
-		})
+		status := s.serverStatus(r.Context(), s.GetService())
+		status["status"] = "ok"
+		status["connections"] = s.ActiveSessions()
+		status["basePath"] = s.pathPrefix
+		status["endpoints"] = map[string]string{
+			"jsonrpc": s.path("/jsonrpc"),
+			"sse":     s.path("/sse"),
+			"message": s.path("/message"),
+			"health":  s.path(s.healthPath),
+			"metrics": s.path("/metrics"),
+		}
+		_ = json.NewEncoder(w).Encode(status)
+	})
+
+	// Cheap health check for load balancers and orchestrators
+	mux.HandleFunc(s.path(s.healthPath), s.handleHealth)
+
+	// Expose event queue depth per SSE session to detect slow consumers
+	mux.HandleFunc(s.path("/metrics"), func(w http.ResponseWriter, r *http.Request) {
+		if handler, ok := s.metrics.(http.Handler); ok && !strings.Contains(r.Header.Get("Accept"), "application/json") {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(sseServer.QueueMetrics())
 	})
 
+	if s.introspection {
+		s.registerIntrospectionTool(service)
+	}
+
+	// Require authentication if configured
+	var handler http.Handler = mux
+	if s.authFunc != nil {
+		handler = s.authMiddleware(mux)
+	}
+
+	// Answer CORS preflight requests, which carry no credentials, before authentication
+	handler = s.cors.Handler(handler)
+
 	s.httpServer = &http.Server{
 		Addr:    addr,
-		Handler: mux,
+		Handler: handler,
 	}
 
 	return s
@@ -145,20 +386,69 @@ func NewMCPServer(service *usecases.ServerService, addr string, opts ...MCPServe
 
 // redirectToSSE redirects clients to the SSE endpoint
 func (s *MCPServer) redirectToSSE(w http.ResponseWriter, r *http.Request) {
-	http.Redirect(w, r, "/sse", http.StatusFound)
+	http.Redirect(w, r, s.path("/sse"), http.StatusFound)
+}
+
+// path returns the endpoint path under the configured path prefix.
+func (s *MCPServer) path(endpoint string) string {
+	return s.pathPrefix + endpoint
 }
 
 // Start starts the MCP server.
 func (s *MCPServer) Start() error {
 	s.logger.Info("Starting MCP server", logging.Fields{"address": s.httpServer.Addr})
-	s.logger.Info("Available endpoints", logging.Fields{"endpoints": "/, /jsonrpc, /sse, /message, /events, /status"})
-	return s.httpServer.ListenAndServe()
+	endpoints := make([]string, 0, 8)
+	for _, endpoint := range []string{"/", "/jsonrpc", "/sse", "/message", "/events", "/status", s.healthPath, "/metrics"} {
+		endpoints = append(endpoints, s.path(endpoint))
+	}
+	s.logger.Info("Available endpoints", logging.Fields{"endpoints": strings.Join(endpoints, ", ")})
+	listener, err := s.listen(":http")
+	if err != nil {
+		return err
+	}
+	s.startHeartbeat()
+	return s.httpServer.Serve(listener)
+}
+
+// ActiveSessions returns the number of connected SSE clients.
+func (s *MCPServer) ActiveSessions() int {
+	return s.sseServer.SessionCount()
+}
+
+// SessionIDs returns the IDs of the connected SSE clients in sorted order.
+func (s *MCPServer) SessionIDs() []string {
+	return s.sseServer.SessionIDs()
+}
+
+// StartTLS starts the MCP server over HTTPS. The certificate and key files may
+// be empty if config already carries the certificates.
+func (s *MCPServer) StartTLS(certFile, keyFile string, config *tls.Config) error {
+	if config != nil {
+		s.httpServer.TLSConfig = config
+	}
+	s.logger.Info("Starting MCP server with TLS", logging.Fields{"address": s.httpServer.Addr})
+	listener, err := s.listen(":https")
+	if err != nil {
+		return err
+	}
+	s.startHeartbeat()
+	return s.httpServer.ServeTLS(listener, certFile, keyFile)
 }
 
 // Stop stops the MCP server.
 func (s *MCPServer) Stop(ctx context.Context) error {
-	// Cancel our internal context first to signal all ongoing operations to stop
+	// Warn connected clients before their sessions are closed
+	s.notifyShutdown(ctx)
+
+	// Close the SSE sessions, whose long-lived handlers would otherwise keep
+	// the HTTP server from shutting down until ctx expires
+	if err := s.sseServer.Shutdown(ctx); err != nil {
+		s.logger.Warn("Failed to close SSE sessions", logging.Fields{"error": err})
+	}
+
+	// Cancel our internal context to signal all ongoing operations to stop
 	s.cancel()
+	s.ready.Store(false)
 
 	// Shutdown the HTTP server
 	err := s.httpServer.Shutdown(ctx)
@@ -175,59 +465,153 @@ func (s *MCPServer) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Read request body
+	// Read request body, up to the configured limit
+	if s.maxBodyBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxBodyBytes)
+	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			s.logger.Warn("Request body too large", logging.Fields{"limit": tooLarge.Limit})
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			s.encodeJSON(w, domain.CreateErrorResponse(jsonRPCVersion, nil, domain.InvalidRequestCode,
+				fmt.Sprintf("Invalid Request: body exceeds %d bytes", tooLarge.Limit)))
+			return
+		}
 		http.Error(w, "Error reading request body", http.StatusBadRequest)
 		return
 	}
 
-	// Create context with timeout, derived from request context and server context
-	// This ensures the context is canceled if either the request ends or the server is stopped
-	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
-	defer cancel()
+	w.Header().Set("Content-Type", "application/json")
 
-	// Process the message
+	// Plain HTTP requests have no session, but handlers still see the user agent
+	ctx := domain.WithSessionInfo(r.Context(), domain.NewSessionInfoHolder("", r.UserAgent()))
+	ctx = s.extractTraceContext(ctx, r.Header)
+
+	// Batch requests are sent as a JSON array
+	if trimmed := bytes.TrimLeft(body, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+		responses := s.processBatch(ctx, trimmed)
+		if responses == nil {
+			// A batch of only notifications gets no response body
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		s.encodeJSON(w, responses)
+		return
+	}
+
+	// Process the message; the request timeout is applied by processMessage
 	response := s.processMessage(ctx, body)
+	if response == nil {
+		// Notifications get no response body
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
 
 	// Send response
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(response)
+	s.encodeJSON(w, response)
+}
+
+// encodeJSON writes v to w as JSON, indented if WithPrettyJSON is set.
+func (s *MCPServer) encodeJSON(w io.Writer, v interface{}) {
+	encoder := json.NewEncoder(w)
+	if s.prettyJSON {
+		encoder.SetIndent("", "  ")
+	}
+	_ = encoder.Encode(v)
+}
+
+// processBatch processes a JSON-RPC batch in order and returns the responses.
+// Notifications in the batch produce no response. It returns nil if there is
+// nothing to respond with.
+func (s *MCPServer) processBatch(ctx context.Context, body []byte) interface{} {
+	var messages []json.RawMessage
+	if err := json.Unmarshal(body, &messages); err != nil {
+		return domain.CreateErrorResponse(jsonRPCVersion, nil, domain.ParseErrorCode, "Parse error")
+	}
+	if len(messages) == 0 {
+		return domain.CreateErrorResponse(jsonRPCVersion, nil, domain.InvalidRequestCode, "Invalid Request: empty batch")
+	}
+
+	responses := make([]interface{}, 0, len(messages))
+	for _, message := range messages {
+		response := s.processMessage(ctx, message)
+		if isNotification(message) {
+			continue
+		}
+		responses = append(responses, response)
+	}
+
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
+
+// isNotification reports whether the message is a JSON-RPC notification,
+// that is a request object with a method but without an id member.
+func isNotification(rawMessage json.RawMessage) bool {
+	var message map[string]json.RawMessage
+	if err := json.Unmarshal(rawMessage, &message); err != nil {
+		return false
+	}
+	_, hasID := message["id"]
+	_, hasMethod := message["method"]
+	return hasMethod && !hasID
 }
 
 // Helper methods for processing specific JSON-RPC methods
 
+// processInitialized marks the session ready once the client confirms the
+// initialization. Like any notification it gets no response.
+func (s *MCPServer) processInitialized(ctx context.Context, request domain.JSONRPCRequest) interface{} {
+	if holder, ok := domain.SessionInfoHolderFromContext(ctx); ok {
+		holder.MarkReady()
+	}
+	s.logger.Info("Client initialized")
+	return nil
+}
+
 func (s *MCPServer) processInitialize(ctx context.Context, request domain.JSONRPCRequest) interface{} {
 	// Log initialization request
 	s.logger.Info("Processing initialize request")
 
-	// Parse initialization parameters if needed
-	// ...
+	// Record the client name and version for handlers
+	if holder, ok := domain.SessionInfoHolderFromContext(ctx); ok {
+		holder.SetClientInfo(request.Params)
+	}
+
+	// Remember the session's requested locale for localized metadata
+	if locale := domain.LocaleFromInitializeParams(request.Params); locale != "" {
+		if sessionID, ok := domain.SessionIDFromContext(ctx); ok {
+			s.sessionLocales.Store(sessionID, locale)
+		}
+	}
+
+	// Answer with the client's protocol version if we speak it
+	requested := domain.ProtocolVersionFromInitializeParams(request.Params)
+	protocolVersion, ok := domain.NegotiateProtocolVersion(requested)
+	if !ok {
+		s.logger.Warn("Unsupported protocol version requested", logging.Fields{"requested": requested, "protocolVersion": protocolVersion})
+	}
 
 	// Get server info
-	name, version, instructions := s.service.ServerInfo()
+	service := s.serviceFromContext(ctx)
+	name, version, _ := service.ServerInfo()
+	instructions := service.Instructions(ctx)
 
 	s.logger.Info("Server info", logging.Fields{"name": name, "version": version})
 
 	// Create response
 	result := map[string]interface{}{
-		"protocolVersion": mcpProtocolVersion,
+		"protocolVersion": protocolVersion,
 		"serverInfo": map[string]string{
 			"name":    name,
 			"version": version,
 		},
-		"capabilities": map[string]interface{}{
-			"resources": map[string]bool{
-				"listChanged": true,
-			},
-			"tools": map[string]bool{
-				"listChanged": true,
-			},
-			"prompts": map[string]bool{
-				"listChanged": true,
-			},
-			"logging": struct{}{},
-		},
+		"capabilities": s.Capabilities(ctx),
 	}
 
 	// Add instructions if provided
@@ -235,26 +619,37 @@ func (s *MCPServer) processInitialize(ctx context.Context, request domain.JSONRP
 		result["instructions"] = instructions
 	}
 
-	s.logger.Info("Processed initialize response", logging.Fields{"protocolVersion": mcpProtocolVersion})
+	s.logger.Info("Processed initialize response", logging.Fields{"protocolVersion": protocolVersion})
 	return domain.CreateResponse(jsonRPCVersion, request.ID, result)
 }
 
 func (s *MCPServer) processPing(ctx context.Context, request domain.JSONRPCRequest) interface{} {
-	// Simple ping response
 	s.logger.Debug("Processing ping request")
-	return domain.CreateResponse(jsonRPCVersion, request.ID, struct{}{})
+	return domain.CreateResponse(jsonRPCVersion, request.ID, s.PingResult())
 }
 
 func (s *MCPServer) processResourcesList(ctx context.Context, request domain.JSONRPCRequest) interface{} {
 	s.logger.Info("Processing resources/list request")
 
 	// Debug logging to verify service access
-	s.logger.Debug("Service access", logging.Fields{"servicePtr": fmt.Sprintf("%p", s.service)})
+	s.logger.Debug("Service access", logging.Fields{"servicePtr": fmt.Sprintf("%p", s.serviceFromContext(ctx))})
+
+	filter, ok := resourceFilterFromParams(request.Params)
+	if !ok {
+		s.logger.Warn("Invalid resources/list filter", logging.Fields{"params": request.Params})
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidParamsCode, "Invalid params: 'uriPrefix' and 'pattern' must be strings")
+	}
 
-	resources, err := s.service.ListResources(ctx)
+	resources, err := s.serviceFromContext(ctx).ListResourcesFiltered(ctx, filter)
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		s.logger.Warn("Invalid resources/list filter", logging.Fields{"error": err})
+		return domain.CreateErrorResponseWithData(jsonRPCVersion, request.ID, domain.InvalidParamsCode, fmt.Sprintf("Invalid params: %v", err),
+			map[string]interface{}{"field": validationErr.Field, "reason": validationErr.Message})
+	}
 	if err != nil {
 		s.logger.Error("Error listing resources", logging.Fields{"error": err})
-		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, -32603, fmt.Sprintf("Internal error: %v", err))
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InternalErrorCode, fmt.Sprintf("Internal error: %v", err))
 	}
 
 	s.logger.Info("Found resources", logging.Fields{"count": len(resources)})
@@ -278,6 +673,45 @@ func (s *MCPServer) processResourcesList(ctx context.Context, request domain.JSO
 	return domain.CreateResponse(jsonRPCVersion, request.ID, result)
 }
 
+// resourceFilterFromParams reads the optional uriPrefix and pattern
+// parameters of a resources/list request. It reports false if either is
+// present but not a string.
+func resourceFilterFromParams(params interface{}) (domain.ResourceFilter, bool) {
+	var filter domain.ResourceFilter
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return filter, true
+	}
+	if value, exists := paramsMap["uriPrefix"]; exists {
+		if filter.URIPrefix, ok = value.(string); !ok {
+			return filter, false
+		}
+	}
+	if value, exists := paramsMap["pattern"]; exists {
+		if filter.Pattern, ok = value.(string); !ok {
+			return filter, false
+		}
+	}
+	return filter, true
+}
+
+func (s *MCPServer) processResourceTemplatesList(ctx context.Context, request domain.JSONRPCRequest) interface{} {
+	s.logger.Info("Processing resources/templates/list request")
+
+	templates, err := s.serviceFromContext(ctx).ListResourceTemplates(ctx)
+	if err != nil {
+		s.logger.Error("Error listing resource templates", logging.Fields{"error": err})
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InternalErrorCode, fmt.Sprintf("Internal error: %v", err))
+	}
+
+	result := map[string]interface{}{
+		"resourceTemplates": domain.ResourceTemplatesToMCP(templates),
+	}
+
+	s.logger.Info("Processed resources/templates/list response", logging.Fields{"templateCount": len(templates)})
+	return domain.CreateResponse(jsonRPCVersion, request.ID, result)
+}
+
 func (s *MCPServer) processResourcesRead(ctx context.Context, request domain.JSONRPCRequest) interface{} {
 	s.logger.Info("Processing resources/read request")
 
@@ -285,38 +719,41 @@ func (s *MCPServer) processResourcesRead(ctx context.Context, request domain.JSO
 	params, ok := request.Params.(map[string]interface{})
 	if !ok {
 		s.logger.Warn("Invalid params, expected map", logging.Fields{"paramsType": fmt.Sprintf("%T", request.Params)})
-		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, -32602, "Invalid params")
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidParamsCode, "Invalid params")
 	}
 
 	uri, ok := params["uri"].(string)
 	if !ok || uri == "" {
 		s.logger.Warn("Missing or invalid 'uri' parameter")
-		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, -32602, "Missing or invalid 'uri' parameter")
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidParamsCode, "Missing or invalid 'uri' parameter")
 	}
 
 	s.logger.Info("Reading resource", logging.Fields{"uri": uri})
 
-	// Get resource
-	resource, err := s.service.GetResource(ctx, uri)
+	// Read the resource contents from its content provider
+	contents, err := s.serviceFromContext(ctx).ReadResource(ctx, uri)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
+		var notFoundErr *domain.ResourceNotFoundError
+		switch {
+		case errors.Is(err, domain.ErrNotFound) || errors.As(err, &notFoundErr):
 			s.logger.Warn("Resource not found", logging.Fields{"uri": uri})
-			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, 404, fmt.Sprintf("Resource not found: %s", uri))
-		} else {
-			s.logger.Error("Error getting resource", logging.Fields{"uri": uri, "error": err})
-			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, -32603, fmt.Sprintf("Internal error: %v", err))
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.ResourceNotFoundCode, fmt.Sprintf("Resource not found: %s", uri))
+		case errors.Is(err, domain.ErrNoContentProvider):
+			s.logger.Warn("Resource has no content provider", logging.Fields{"uri": uri})
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.MethodNotFoundCode, "resources/read not supported: no content provider configured")
+		default:
+			s.logger.Error("Error reading resource", logging.Fields{"uri": uri, "error": err})
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InternalErrorCode, fmt.Sprintf("Internal error: %v", err))
 		}
 	}
 
-	// Placeholder for resource contents - in a real implementation, this would get the actual content
-	contents := map[string]interface{}{
-		"uri":      resource.URI,
-		"mimeType": resource.MIMEType,
-		"text":     "Sample resource content", // This would normally come from the resource
+	entries := make([]interface{}, len(contents))
+	for i, c := range contents {
+		entries[i] = c.ToMCP()
 	}
 
 	result := map[string]interface{}{
-		"contents": []interface{}{contents},
+		"contents": entries,
 	}
 
 	s.logger.Info("Processed resources/read response", logging.Fields{"uri": uri})
@@ -327,16 +764,18 @@ func (s *MCPServer) processToolsList(ctx context.Context, request domain.JSONRPC
 	s.logger.Info("Processing tools/list request")
 
 	// Debug logging to verify service access
-	s.logger.Debug("Service access", logging.Fields{"servicePtr": fmt.Sprintf("%p", s.service)})
+	s.logger.Debug("Service access", logging.Fields{"servicePtr": fmt.Sprintf("%p", s.serviceFromContext(ctx))})
 
-	tools, err := s.service.ListTools(ctx)
+	tools, err := s.serviceFromContext(ctx).ListTools(ctx)
 	if err != nil {
 		s.logger.Error("Error listing tools", logging.Fields{"error": err})
-		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, -32603, fmt.Sprintf("Internal error: %v", err))
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InternalErrorCode, fmt.Sprintf("Internal error: %v", err))
 	}
 
 	s.logger.Info("Found tools", logging.Fields{"count": len(tools)})
 
+	locale := s.sessionLocale(ctx)
+
 	// Convert domain tools to response format
 	toolList := make([]map[string]interface{}, len(tools))
 	for i, tool := range tools {
@@ -346,35 +785,17 @@ func (s *MCPServer) processToolsList(ctx context.Context, request domain.JSONRPC
 			"desc":  tool.Description,
 		})
 
-		// Format parameters as an object with properties
-		parametersObj := make(map[string]interface{})
-		parametersObj["type"] = "object"
-
-		properties := make(map[string]interface{})
-		required := []string{}
-
-		for _, param := range tool.Parameters {
-			paramObj := map[string]interface{}{
-				"type":        param.Type,
-				"description": param.Description,
-			}
-			properties[param.Name] = paramObj
-
-			if param.Required {
-				required = append(required, param.Name)
-			}
-		}
-
-		parametersObj["properties"] = properties
-		if len(required) > 0 {
-			parametersObj["required"] = required
-		}
-
 		// Build tool object
 		toolList[i] = map[string]interface{}{
 			"name":        tool.Name,
-			"description": tool.Description,
-			"inputSchema": parametersObj,
+			"description": tool.DescriptionFor(locale),
+			"inputSchema": tool.InputSchema(),
+		}
+		if outputSchema := tool.OutputJSONSchema(); outputSchema != nil {
+			toolList[i]["outputSchema"] = outputSchema
+		}
+		if annotations := tool.AnnotationsJSON(); annotations != nil {
+			toolList[i]["annotations"] = annotations
 		}
 	}
 
@@ -387,20 +808,22 @@ func (s *MCPServer) processToolsList(ctx context.Context, request domain.JSONRPC
 }
 
 func (s *MCPServer) processToolsCall(ctx context.Context, request domain.JSONRPCRequest) interface{} {
-	s.logger.Info("Processing tools/call request", logging.Fields{"request": fmt.Sprintf("%+v", request)})
+	logged := request
+	logged.Params = s.redact(request.Params)
+	s.logger.Info("Processing tools/call request", logging.Fields{"request": fmt.Sprintf("%+v", logged)})
 
 	// Extract parameters
 	params, ok := request.Params.(map[string]interface{})
 	if !ok {
 		s.logger.Warn("Invalid params, expected map", logging.Fields{"paramsType": fmt.Sprintf("%T", request.Params)})
-		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, -32602, "Invalid params")
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidParamsCode, "Invalid params")
 	}
 
 	// Get tool name
 	toolName, ok := params["name"].(string)
 	if !ok || toolName == "" {
 		s.logger.Warn("Missing or invalid 'name' parameter")
-		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, -32602, "Missing or invalid 'name' parameter")
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidParamsCode, "Missing or invalid 'name' parameter")
 	}
 
 	// Get tool parameters - check for 'arguments' field instead of 'parameters'
@@ -412,62 +835,82 @@ func (s *MCPServer) processToolsCall(ctx context.Context, request domain.JSONRPC
 
 	s.logger.Info("Tool call request", logging.Fields{
 		"tool":   toolName,
-		"params": fmt.Sprintf("%+v", toolParams),
+		"params": fmt.Sprintf("%+v", s.redact(toolParams)),
 	})
 
-	// Get the tool
-	_, err := s.service.GetTool(ctx, toolName)
-	if err != nil {
-		s.logger.Error("Error getting tool", logging.Fields{"tool": toolName, "error": err})
-		if errors.Is(err, domain.ErrNotFound) {
-			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, 404, fmt.Sprintf("Tool not found: %s", toolName))
-		} else {
-			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, -32603, fmt.Sprintf("Internal error: %v", err))
+	// Stream progress to the originating session if the client asked for it
+	if token := progressToken(params); token != nil {
+		if sessionID, ok := domain.SessionIDFromContext(ctx); ok {
+			ctx = domain.WithProgress(ctx, token, s.progressReporter(sessionID, token))
 		}
 	}
 
-	// Handle specific tools
-	var result interface{}
+	// Forward the handler's log messages to the client
+	ctx = domain.WithClientLog(ctx, s.clientLog(ctx))
 
-	switch toolName {
-	case "echo":
-		// Handle echo tool
-		messageVal, ok := toolParams["message"]
-		if !ok || messageVal == nil {
-			s.logger.Warn("Missing or invalid 'message' parameter for echo tool")
-			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, -32602, "Missing or invalid 'message' parameter")
-		}
+	// Give a tool that is still being registered a chance to appear
+	if s.toolGrace > 0 {
+		s.awaitTool(ctx, toolName)
+	}
 
-		// Convert message to string based on its type
-		var message string
-		switch v := messageVal.(type) {
-		case string:
-			message = v
-		case float64, int, int64, float32:
-			message = fmt.Sprintf("%v", v)
+	// Dispatch the call to the registered tool handler
+	result, err := s.serviceFromContext(ctx).CallTool(ctx, toolName, toolParams)
+	if err != nil {
+		var notFoundErr *domain.ToolNotFoundError
+		var handlerErr *usecases.ToolHandlerNotFoundError
+		var rateLimitErr *domain.RateLimitError
+		var busyErr *domain.ServerBusyError
+		var validationErr *domain.ValidationError
+		var toolErr *domain.ToolError
+		var panicErr *domain.PanicError
+		switch {
+		case errors.As(err, &panicErr):
+			s.logger.Error("Tool handler panicked", logging.Fields{"tool": toolName, "panic": fmt.Sprint(panicErr.Value), "stack": string(panicErr.Stack)})
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InternalErrorCode, "Internal error")
+		case errors.As(err, &toolErr):
+			s.logger.Warn("Tool returned error", logging.Fields{"tool": toolName, "code": toolErr.JSONRPCCode(), "error": err})
+			return domain.CreateErrorResponseWithData(jsonRPCVersion, request.ID, toolErr.JSONRPCCode(), toolErr.Message, toolErr.Data)
+		case errors.As(err, &validationErr):
+			s.logger.Warn("Invalid tool arguments", logging.Fields{"tool": toolName, "error": err})
+			return domain.CreateErrorResponseWithData(jsonRPCVersion, request.ID, domain.InvalidParamsCode, fmt.Sprintf("Invalid params: %v", err),
+				map[string]interface{}{"field": validationErr.Field, "reason": validationErr.Message})
+		case errors.Is(err, context.Canceled):
+			s.logger.Info("Tool call cancelled", logging.Fields{"tool": toolName})
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InternalErrorCode, "Request cancelled")
+		case errors.Is(err, context.DeadlineExceeded):
+			timeout, _ := ctx.Value(requestTimeoutKey{}).(time.Duration)
+			s.logger.Warn("Tool call timed out", logging.Fields{"tool": toolName, "timeout": timeout.String()})
+			return domain.CreateErrorResponseWithData(jsonRPCVersion, request.ID, domain.InternalErrorCode, domain.NewTimeoutError(timeout).Error(),
+				map[string]interface{}{"timeoutMs": timeout.Milliseconds()})
+		case errors.As(err, &rateLimitErr) && isCallRateLimit(rateLimitErr):
+			s.logger.Warn("Call rate limit exceeded", logging.Fields{"tool": toolName, "scope": rateLimitErr.Scope})
+			return domain.CreateErrorResponseWithData(jsonRPCVersion, request.ID, callRateLimitErrorCode, "Rate limit exceeded",
+				map[string]interface{}{"scope": rateLimitErr.Scope, "retryAfterMs": rateLimitErr.RetryAfter.Milliseconds()})
+		case errors.As(err, &rateLimitErr):
+			s.logger.Warn("Tool rate limit exceeded", logging.Fields{"tool": toolName})
+			return domain.CreateErrorResponseWithData(jsonRPCVersion, request.ID, domain.RateLimitedCode, fmt.Sprintf("Rate limit exceeded for tool: %s", toolName),
+				map[string]interface{}{"scope": rateLimitErr.Scope})
+		case errors.As(err, &busyErr):
+			s.logger.Warn("Tool call rejected, server busy", logging.Fields{"tool": toolName, "limit": busyErr.Limit})
+			return domain.CreateErrorResponseWithData(jsonRPCVersion, request.ID, serverBusyErrorCode, "Server busy",
+				map[string]interface{}{"maxConcurrentCalls": busyErr.Limit})
+		case errors.As(err, &notFoundErr):
+			s.logger.Warn("Tool not found", logging.Fields{"tool": toolName})
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidParamsCode, fmt.Sprintf("Tool not found: %s", toolName))
+		case errors.As(err, &handlerErr):
+			s.logger.Warn("Tool handler not implemented", logging.Fields{"tool": toolName})
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InternalErrorCode, fmt.Sprintf("Tool handler not implemented for: %s", toolName))
 		default:
-			// Try JSON conversion for complex types
-			jsonBytes, err := json.Marshal(v)
-			if err != nil {
-				message = fmt.Sprintf("%v", v)
-			} else {
-				message = string(jsonBytes)
-			}
-		}
-
-		// Echo the message back with content as an array of objects
-		result = map[string]interface{}{
-			"content": []map[string]interface{}{
-				{
-					"type": "text",
-					"text": message,
-				},
-			},
+			s.logger.Error("Tool execution failed", logging.Fields{"tool": toolName, "error": err})
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InternalErrorCode, fmt.Sprintf("Tool execution error: %v", err))
 		}
+	}
 
-	default:
-		s.logger.Warn("Tool handler not implemented", logging.Fields{"tool": toolName})
-		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, -32603, fmt.Sprintf("Tool handler not implemented for: %s", toolName))
+	// Catch results the handler cannot serialize before writing the response
+	if _, err := json.Marshal(result); err != nil {
+		s.logger.Error("Tool returned unserializable result", logging.Fields{"tool": toolName, "type": fmt.Sprintf("%T", result), "error": err})
+		return domain.CreateErrorResponseWithData(jsonRPCVersion, request.ID, domain.InternalErrorCode, "Tool returned unserializable result",
+			map[string]interface{}{"tool": toolName})
 	}
 
 	s.logger.Info("Processed tools/call response", logging.Fields{"tool": toolName})
@@ -476,10 +919,10 @@ func (s *MCPServer) processToolsCall(ctx context.Context, request domain.JSONRPC
 
 func (s *MCPServer) processPromptsList(ctx context.Context, request domain.JSONRPCRequest) interface{} {
 	s.logger.Info("Processing prompts/list request")
-	prompts, err := s.service.ListPrompts(ctx)
+	prompts, err := s.serviceFromContext(ctx).ListPrompts(ctx)
 	if err != nil {
 		s.logger.Error("Error listing prompts", logging.Fields{"error": err})
-		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, -32603, fmt.Sprintf("Internal error: %v", err))
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InternalErrorCode, fmt.Sprintf("Internal error: %v", err))
 	}
 
 	// Convert domain prompts to response format
@@ -512,22 +955,227 @@ func (s *MCPServer) processPromptsList(ctx context.Context, request domain.JSONR
 
 func (s *MCPServer) processPromptsGet(ctx context.Context, request domain.JSONRPCRequest) interface{} {
 	s.logger.Info("Processing prompts/get request")
-	// TODO: Implement prompt get handler
-	return domain.CreateErrorResponse(jsonRPCVersion, request.ID, -32603, "Prompt get not implemented")
+
+	// Extract parameters
+	params, ok := request.Params.(map[string]interface{})
+	if !ok {
+		s.logger.Warn("Invalid params, expected map", logging.Fields{"paramsType": fmt.Sprintf("%T", request.Params)})
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidParamsCode, "Invalid params")
+	}
+
+	// Get prompt name
+	promptName, ok := params["name"].(string)
+	if !ok || promptName == "" {
+		s.logger.Warn("Missing or invalid 'name' parameter")
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidParamsCode, "Missing or invalid 'name' parameter")
+	}
+
+	// Get prompt arguments
+	promptArgs, ok := params["arguments"].(map[string]interface{})
+	if !ok {
+		promptArgs = map[string]interface{}{}
+	}
+
+	rendered, err := s.serviceFromContext(ctx).RenderPrompt(ctx, &domain.PromptRequest{
+		Name:       promptName,
+		Parameters: promptArgs,
+	})
+	if err != nil {
+		var notFoundErr *domain.PromptNotFoundError
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &notFoundErr):
+			s.logger.Warn("Prompt not found", logging.Fields{"prompt": promptName})
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidParamsCode, fmt.Sprintf("Prompt not found: %s", promptName))
+		case errors.As(err, &validationErr):
+			s.logger.Warn("Invalid prompt arguments", logging.Fields{"prompt": promptName, "error": err})
+			return domain.CreateErrorResponseWithData(jsonRPCVersion, request.ID, domain.InvalidParamsCode, fmt.Sprintf("Invalid params: %v", err),
+				map[string]interface{}{"field": validationErr.Field, "reason": validationErr.Message})
+		case errors.Is(err, domain.ErrNoPromptTemplate):
+			s.logger.Warn("Prompt has no template", logging.Fields{"prompt": promptName})
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.MethodNotFoundCode, "prompts/get not supported: no template configured")
+		default:
+			s.logger.Error("Error rendering prompt", logging.Fields{"prompt": promptName, "error": err})
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InternalErrorCode, fmt.Sprintf("Internal error: %v", err))
+		}
+	}
+
+	s.logger.Info("Processed prompts/get response", logging.Fields{"prompt": promptName})
+	return domain.CreateResponse(jsonRPCVersion, request.ID, promptResultToMCP(rendered))
+}
+
+// promptResultToMCP converts a rendered prompt to the MCP prompts/get result format.
+func promptResultToMCP(rendered *domain.PromptResult) map[string]interface{} {
+	return map[string]interface{}{
+		"description": rendered.Description,
+		"messages": []map[string]interface{}{
+			{
+				"role": "user",
+				"content": map[string]interface{}{
+					"type": "text",
+					"text": rendered.Text,
+				},
+			},
+		},
+	}
 }
 
 // GetServerInfo returns information about the server.
 // This is useful for external components that need access to the server information.
 func (s *MCPServer) GetServerInfo() (name string, version string, instructions string) {
-	return s.service.ServerInfo()
+	return s.GetService().ServerInfo()
 }
 
 // GetService returns the server service.
 // This is useful for external components that need access to the service.
 func (s *MCPServer) GetService() *usecases.ServerService {
+	s.serviceMu.RLock()
+	defer s.serviceMu.RUnlock()
 	return s.service
 }
 
+// RegisterNotificationSession registers a session that receives the server's
+// notifications outside of SSE, such as the stdio session, and returns the
+// channel they are delivered on. The returned function unregisters the
+// session and closes the channel.
+func (s *MCPServer) RegisterNotificationSession(sessionID string) (<-chan server.JSONRPCNotification, func()) {
+	session := server.NewMCPSession(sessionID, "", 100)
+	s.notifier.RegisterSession(session)
+	return session.NotificationChannel(), func() { s.notifier.UnregisterSession(sessionID) }
+}
+
+// Reload atomically replaces the server service, swapping the tool, resource and
+// prompt registries without dropping SSE connections or the HTTP listener.
+// Requests already in flight complete against the previous service, while new
+// requests use the new one. Connected clients are notified that the lists changed.
+func (s *MCPServer) Reload(newService *usecases.ServerService) error {
+	if newService == nil {
+		return fmt.Errorf("service cannot be nil")
+	}
+
+	if s.introspection {
+		s.registerIntrospectionTool(newService)
+	}
+
+	s.serviceMu.Lock()
+	s.service = newService
+	s.serviceMu.Unlock()
+
+	s.logger.Info("Reloaded server service")
+
+	for _, method := range []string{
+		domain.ToolListChangedMethod,
+		domain.ResourceListChangedMethod,
+		domain.PromptListChangedMethod,
+	} {
+		if err := s.notifier.BroadcastNotification(s.ctx, &domain.Notification{
+			Method: method,
+			Params: map[string]interface{}{},
+		}); err != nil {
+			s.logger.Warn("Failed to broadcast list change", logging.Fields{"method": method, "error": err})
+		}
+	}
+
+	return nil
+}
+
+// serviceContextKey is the context key for the service bound to a request.
+type serviceContextKey struct{}
+
+// serviceFromContext returns the service bound to the request context,
+// falling back to the current service.
+func (s *MCPServer) serviceFromContext(ctx context.Context) *usecases.ServerService {
+	if service, ok := ctx.Value(serviceContextKey{}).(*usecases.ServerService); ok {
+		return service
+	}
+	return s.GetService()
+}
+
+// awaitTool waits until the tool is registered, the lookup grace period
+// elapses or the context is done, whichever comes first.
+func (s *MCPServer) awaitTool(ctx context.Context, toolName string) {
+	service := s.serviceFromContext(ctx)
+	if _, err := service.GetTool(ctx, toolName); err == nil {
+		return
+	}
+
+	s.logger.Debug("Waiting for tool registration", logging.Fields{"tool": toolName, "grace": s.toolGrace.String()})
+
+	deadline := time.NewTimer(s.toolGrace)
+	defer deadline.Stop()
+	ticker := time.NewTicker(toolLookupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := service.GetTool(ctx, toolName); err == nil {
+				return
+			}
+		case <-deadline.C:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// progressToken returns the progress token from the request's _meta, if any.
+func progressToken(params map[string]interface{}) interface{} {
+	meta, ok := params["_meta"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return meta["progressToken"]
+}
+
+// progressReporter returns a reporter that sends progress notifications for
+// the token to the given session.
+func (s *MCPServer) progressReporter(sessionID string, token interface{}) domain.ProgressReporter {
+	return func(ctx context.Context, progress, total float64) error {
+		return s.notifier.SendNotification(ctx, sessionID, domain.NewProgressNotification(token, progress, total))
+	}
+}
+
+// sessionLocale returns the locale the request's session asked for at
+// initialization, or an empty string for the default.
+func (s *MCPServer) sessionLocale(ctx context.Context) string {
+	sessionID, ok := domain.SessionIDFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	locale, _ := s.sessionLocales.Load(sessionID)
+	str, _ := locale.(string)
+	return str
+}
+
+// requestTimeoutKey is the context key for the timeout applied to a request.
+type requestTimeoutKey struct{}
+
+// RequestTimeout returns the timeout for the request. Tool calls use the tool's
+// own timeout when one is configured. Transports other than the built-in HTTP
+// ones use it to apply the same timeouts.
+func (s *MCPServer) RequestTimeout(ctx context.Context, request domain.JSONRPCRequest) time.Duration {
+	if request.Method != "tools/call" {
+		return s.timeout
+	}
+
+	params, ok := request.Params.(map[string]interface{})
+	if !ok {
+		return s.timeout
+	}
+	toolName, ok := params["name"].(string)
+	if !ok || toolName == "" {
+		return s.timeout
+	}
+
+	tool, err := s.serviceFromContext(ctx).GetTool(ctx, toolName)
+	if err != nil || tool.Timeout <= 0 {
+		return s.timeout
+	}
+	return tool.Timeout
+}
+
 // GetAddress returns the server's address
 func (s *MCPServer) GetAddress() string {
 	if s.httpServer != nil {
@@ -536,27 +1184,197 @@ func (s *MCPServer) GetAddress() string {
 	return ""
 }
 
-// processMessage processes a JSON-RPC message and returns a response.
-func (s *MCPServer) processMessage(ctx context.Context, rawMessage json.RawMessage) interface{} {
+// HandleMessage processes a JSON-RPC message or batch received over a
+// transport other than the built-in HTTP ones, such as an in-memory transport
+// in tests. It returns the response to send, or nil if there is none.
+func (s *MCPServer) HandleMessage(ctx context.Context, rawMessage json.RawMessage) interface{} {
+	return s.processMessage(ctx, rawMessage)
+}
+
+// processMessage processes a JSON-RPC message and returns a response,
+// tracing it, recording request metrics and logging the request if enabled.
+func (s *MCPServer) processMessage(ctx context.Context, rawMessage json.RawMessage) (response interface{}) {
+	if s.tracer != nil {
+		var span trace.Span
+		ctx, span = s.startSpan(ctx, rawMessage)
+		defer func() { endSpan(span, response) }()
+	}
+
+	if s.metrics == nil && !s.requestLogging {
+		return s.handleMessage(ctx, rawMessage)
+	}
+
+	start := time.Now()
+	response = s.handleMessage(ctx, rawMessage)
+	duration := time.Since(start)
+
+	if s.metrics != nil {
+		s.observeRequest(rawMessage, response, duration)
+	}
+	if s.requestLogging {
+		s.logRequest(rawMessage, response, duration)
+	}
+	return response
+}
+
+// metricMethods are the methods recorded under their own name in request
+// metrics. Other methods are recorded as "unknown" so clients cannot create
+// arbitrary metric labels.
+var metricMethods = map[string]bool{
+	"initialize":                true,
+	"ping":                      true,
+	"resources/list":            true,
+	"resources/read":            true,
+	"resources/templates/list":  true,
+	"resources/subscribe":       true,
+	"resources/unsubscribe":     true,
+	"tools/list":                true,
+	"tools/call":                true,
+	"notifications/initialized": true,
+	"notifications/cancelled":   true,
+	"prompts/list":              true,
+	"prompts/get":               true,
+	"completion/complete":       true,
+	"logging/setLevel":          true,
+}
+
+// observeRequest records the metrics of a processed message.
+func (s *MCPServer) observeRequest(rawMessage json.RawMessage, response interface{}, duration time.Duration) {
+	var request struct {
+		Method string `json:"method"`
+	}
+	_ = json.Unmarshal(rawMessage, &request)
+
+	method := request.Method
+	if !metricMethods[method] {
+		method = "unknown"
+	}
+	errResponse, ok := response.(domain.JSONRPCResponse)
+	s.metrics.ObserveRequest(method, duration, ok && errResponse.Error != nil)
+}
+
+// handleMessage processes a JSON-RPC message and returns a response.
+func (s *MCPServer) handleMessage(ctx context.Context, rawMessage json.RawMessage) interface{} {
 	// Check if the passed context is done
 	select {
 	case <-ctx.Done():
-		return domain.CreateErrorResponse(jsonRPCVersion, nil, -32603, "Request context canceled")
+		return domain.CreateErrorResponse(jsonRPCVersion, nil, domain.InternalErrorCode, "Request context canceled")
 	default:
 		// Continue processing
 	}
 
+	// Validate the request structure in strict mode
+	if s.strictRPC && json.Valid(rawMessage) {
+		if err := domain.ValidateJSONRPCRequest(rawMessage); err != nil {
+			s.logger.Warn("Invalid JSON-RPC request", logging.Fields{"error": err})
+			return domain.CreateErrorResponse(jsonRPCVersion, extractResponseID(rawMessage), domain.InvalidRequestCode, fmt.Sprintf("Invalid Request: %v", err))
+		}
+	}
+
 	// Parse JSON-RPC request
 	var request domain.JSONRPCRequest
 	if err := json.Unmarshal(rawMessage, &request); err != nil {
-		return domain.CreateErrorResponse(jsonRPCVersion, nil, -32700, "Parse error")
+		return domain.CreateErrorResponse(jsonRPCVersion, nil, domain.ParseErrorCode, "Parse error")
 	}
 
 	// Validate JSON-RPC version
 	if request.JSONRPC != jsonRPCVersion {
-		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, -32600, "Invalid JSON-RPC version")
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidRequestCode, "Invalid JSON-RPC version")
+	}
+
+	// Reject new requests while draining, and track the others so Drain
+	// can wait for them
+	done, rejection := s.beginRequest(request)
+	if rejection != nil {
+		return rejection
+	}
+	defer done()
+
+	// Reject requests that are not allowed during maintenance
+	if response := s.maintenanceRejection(request); response != nil {
+		return response
+	}
+
+	// Reject deeply nested params before any further processing
+	if s.maxDepth > 0 && exceedsDepth(request.Params, s.maxDepth) {
+		s.logger.Warn("Request params nested too deeply", logging.Fields{"method": request.Method, "maxDepth": s.maxDepth})
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidRequestCode, fmt.Sprintf("Invalid Request: params exceed maximum nesting depth of %d", s.maxDepth))
+	}
+
+	// Let the interceptors reject the message before it is dispatched
+	if response, rejected := s.interceptRequest(ctx, request, rawMessage); rejected {
+		return response
+	}
+
+	// Keep the caller's context to detect a client that went away while the
+	// request was processed
+	connCtx := ctx
+
+	// Bind the current service to the request so a concurrent reload
+	// does not change it while the request is in flight
+	ctx = context.WithValue(ctx, serviceContextKey{}, s.GetService())
+
+	// Apply the request timeout, or the tool's own timeout for tool calls
+	timeout := s.RequestTimeout(ctx, request)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, requestTimeoutKey{}, timeout)
+
+	// Cancel the request if Drain stops waiting for it
+	ctx, abort := context.WithCancel(ctx)
+	defer abort()
+	defer context.AfterFunc(s.abortCtx, abort)()
+
+	// Track requests by ID so clients can cancel them. Reusing the ID of a
+	// request still in flight would make the two responses ambiguous.
+	if request.ID != nil {
+		var cancelRequest context.CancelCauseFunc
+		ctx, cancelRequest = context.WithCancelCause(ctx)
+		defer cancelRequest(nil)
+		untrack, ok := s.trackRequest(ctx, request.ID, func() { cancelRequest(errRequestCancelled) })
+		if !ok {
+			s.logger.Warn("Duplicate request ID", logging.Fields{"method": request.Method, "id": request.ID})
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidRequestCode, "Invalid Request: duplicate request id")
+		}
+		defer untrack()
+	}
+
+	response := s.dispatchRecovered(ctx, request)
+
+	// Skip the response if the client disconnected while it was being built
+	if err := connCtx.Err(); err != nil {
+		s.logger.Info("Client gone, dropping response", logging.Fields{"method": request.Method, "error": err})
+		return nil
+	}
+
+	// Requests the client cancelled are not answered
+	if errors.Is(context.Cause(ctx), errRequestCancelled) {
+		s.logger.Info("Request cancelled by the client, dropping response", logging.Fields{"method": request.Method, "id": request.ID})
+		return nil
+	}
+
+	// Strip structured error data if disabled
+	if errResponse, ok := response.(domain.JSONRPCResponse); ok && errResponse.Error != nil && !s.errorData {
+		errResponse.Error.Data = nil
+		return errResponse
 	}
+	return response
+}
+
+// dispatchRecovered dispatches a request, turning a panic in its handler
+// into an internal error so a buggy handler cannot take down the server.
+func (s *MCPServer) dispatchRecovered(ctx context.Context, request domain.JSONRPCRequest) (response interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("Method handler panicked", logging.Fields{"method": request.Method, "panic": fmt.Sprint(r), "stack": string(debug.Stack())})
+			response = domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InternalErrorCode, "Internal error")
+		}
+	}()
+	return s.dispatch(ctx, request)
+}
 
+// dispatch routes a request to the handler for its method.
+func (s *MCPServer) dispatch(ctx context.Context, request domain.JSONRPCRequest) interface{} {
 	// Handle request based on method
 	switch request.Method {
 	case "initialize":
@@ -567,15 +1385,134 @@ func (s *MCPServer) processMessage(ctx context.Context, rawMessage json.RawMessa
 		return s.processResourcesList(ctx, request)
 	case "resources/read":
 		return s.processResourcesRead(ctx, request)
+	case "resources/templates/list":
+		return s.processResourceTemplatesList(ctx, request)
+	case "resources/subscribe":
+		return s.processResourcesSubscribe(ctx, request)
+	case "resources/unsubscribe":
+		return s.processResourcesUnsubscribe(ctx, request)
 	case "tools/list":
 		return s.processToolsList(ctx, request)
 	case "tools/call":
 		return s.processToolsCall(ctx, request)
+	case "notifications/initialized":
+		return s.processInitialized(ctx, request)
+	case "notifications/cancelled":
+		return s.processCancelled(ctx, request)
 	case "prompts/list":
 		return s.processPromptsList(ctx, request)
 	case "prompts/get":
 		return s.processPromptsGet(ctx, request)
+	case "completion/complete":
+		return s.processCompletionComplete(ctx, request)
+	case "logging/setLevel":
+		return s.processLoggingSetLevel(ctx, request)
 	default:
-		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, -32601, fmt.Sprintf("Method '%s' not found", request.Method))
+		// Notifications expect no response, not even method not found
+		if isNotificationRequest(request) {
+			s.logger.Info("Received notification", logging.Fields{"method": request.Method})
+			return nil
+		}
+		return s.processCustomMethod(ctx, request)
+	}
+}
+
+// isNotificationRequest reports whether a request is a client notification,
+// which carries no ID and expects no response.
+func isNotificationRequest(request domain.JSONRPCRequest) bool {
+	return request.ID == nil && strings.HasPrefix(request.Method, "notifications/")
+}
+
+// processCompletionComplete suggests values for a prompt or resource
+// template argument.
+func (s *MCPServer) processCompletionComplete(ctx context.Context, request domain.JSONRPCRequest) interface{} {
+	params, ok := request.Params.(map[string]interface{})
+	if !ok {
+		s.logger.Warn("Invalid params, expected map", logging.Fields{"paramsType": fmt.Sprintf("%T", request.Params)})
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidParamsCode, "Invalid params")
+	}
+	ref, arg := completionParams(params)
+
+	result, err := s.serviceFromContext(ctx).Complete(ctx, ref, arg)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return domain.CreateErrorResponseWithData(jsonRPCVersion, request.ID, domain.InvalidParamsCode, fmt.Sprintf("Invalid params: %v", err),
+				map[string]interface{}{"field": validationErr.Field, "reason": validationErr.Message})
+		}
+		s.logger.Error("Error completing argument", logging.Fields{"ref": ref.Key(), "argument": arg.Name, "error": err})
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InternalErrorCode, fmt.Sprintf("Internal error: %v", err))
+	}
+
+	return domain.CreateResponse(jsonRPCVersion, request.ID, completionResultToMCP(result))
+}
+
+// completionParams extracts the reference and argument of a
+// completion/complete request.
+func completionParams(params map[string]interface{}) (domain.CompletionReference, domain.CompletionArgument) {
+	var ref domain.CompletionReference
+	if refParams, ok := params["ref"].(map[string]interface{}); ok {
+		ref.Type, _ = refParams["type"].(string)
+		ref.Name, _ = refParams["name"].(string)
+		ref.URI, _ = refParams["uri"].(string)
+	}
+	var arg domain.CompletionArgument
+	if argParams, ok := params["argument"].(map[string]interface{}); ok {
+		arg.Name, _ = argParams["name"].(string)
+		arg.Value, _ = argParams["value"].(string)
+	}
+	return ref, arg
+}
+
+// completionResultToMCP converts a completion result to the MCP response shape.
+func completionResultToMCP(result domain.CompletionResult) map[string]interface{} {
+	return map[string]interface{}{
+		"completion": map[string]interface{}{
+			"values":  result.Values,
+			"total":   result.Total,
+			"hasMore": result.HasMore,
+		},
+	}
+}
+
+// exceedsDepth reports whether a decoded JSON value has objects or arrays
+// nested more than maxDepth levels deep. The walk stops as soon as the limit
+// is exceeded.
+func exceedsDepth(value interface{}, maxDepth int) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if maxDepth < 1 {
+			return true
+		}
+		for _, child := range v {
+			if exceedsDepth(child, maxDepth-1) {
+				return true
+			}
+		}
+	case []interface{}:
+		if maxDepth < 1 {
+			return true
+		}
+		for _, child := range v {
+			if exceedsDepth(child, maxDepth-1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extractResponseID returns the request ID if it can be echoed in a response,
+// or nil if it is missing or not a valid JSON-RPC ID.
+func extractResponseID(rawMessage json.RawMessage) interface{} {
+	var message struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(rawMessage, &message); err != nil {
+		return nil
+	}
+	if id, ok := domain.ParseRequestID(message.ID); ok {
+		return id
 	}
+	return nil
 }
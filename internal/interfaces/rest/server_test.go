@@ -0,0 +1,1665 @@
+package rest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/server"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func newTestMCPServer(t *testing.T, opts ...MCPServerOption) *MCPServer {
+	t.Helper()
+
+	service := usecases.NewServerService(usecases.ServerConfig{
+		Name:                 "test-server",
+		Version:              "1.0.0",
+		ResourceRepo:         server.NewInMemoryResourceRepository(),
+		ResourceTemplateRepo: server.NewInMemoryResourceTemplateRepository(),
+		ToolRepo:             server.NewInMemoryToolRepository(),
+		PromptRepo:           server.NewInMemoryPromptRepository(),
+		SessionRepo:          server.NewInMemorySessionRepository(),
+		NotificationSender:   server.NewNotificationSender(jsonRPCVersion),
+	})
+
+	opts = append([]MCPServerOption{WithLogger(logging.Default())}, opts...)
+	return NewMCPServer(service, ":0", opts...)
+}
+
+func postJSONRPC(t *testing.T, s *MCPServer, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/jsonrpc", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.handleJSONRPC(rec, req)
+	return rec
+}
+
+func handleMessageJSON(t *testing.T, ctx context.Context, s *MCPServer, body string) string {
+	t.Helper()
+
+	data, err := json.Marshal(s.HandleMessage(ctx, json.RawMessage(body)))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestHandleJSONRPC_Batch(t *testing.T) {
+	s := newTestMCPServer(t)
+
+	rec := postJSONRPC(t, s, `[
+		{"jsonrpc":"2.0","id":1,"method":"ping"},
+		{"jsonrpc":"2.0","method":"notifications/initialized"},
+		{"jsonrpc":"2.0","id":"two","method":"unknown"}
+	]`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var responses []map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &responses))
+	require.Len(t, responses, 2, "notifications should not produce responses")
+
+	assert.Equal(t, float64(1), responses[0]["id"])
+	assert.NotNil(t, responses[0]["result"])
+	assert.Equal(t, "two", responses[1]["id"])
+	assert.Equal(t, float64(-32601), responses[1]["error"].(map[string]interface{})["code"])
+}
+
+func TestHandleJSONRPC_PrettyJSON(t *testing.T) {
+	body := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+
+	compact := postJSONRPC(t, newTestMCPServer(t), body).Body.String()
+	assert.Equal(t, 1, strings.Count(compact, "\n"), "compact responses should be a single line")
+
+	pretty := postJSONRPC(t, newTestMCPServer(t, WithPrettyJSON(true)), body).Body.String()
+	assert.Contains(t, pretty, "\n  \"jsonrpc\": \"2.0\"")
+	assert.JSONEq(t, compact, pretty)
+}
+
+func TestHandleJSONRPC_EmptyBatch(t *testing.T) {
+	s := newTestMCPServer(t)
+
+	rec := postJSONRPC(t, s, `[]`)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, float64(-32600), response["error"].(map[string]interface{})["code"])
+}
+
+func TestHandleJSONRPC_NotificationOnlyBatch(t *testing.T) {
+	s := newTestMCPServer(t)
+
+	rec := postJSONRPC(t, s, `[{"jsonrpc":"2.0","method":"notifications/initialized"}]`)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestHandleJSONRPC_SingleRequest(t *testing.T) {
+	s := newTestMCPServer(t)
+
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":7,"method":"ping"}`)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, float64(7), response["id"])
+	assert.NotNil(t, response["result"])
+}
+
+func TestPingDiagnostics(t *testing.T) {
+	ping := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(postJSONRPC(t, newTestMCPServer(t), ping).Body.Bytes(), &response))
+	assert.Empty(t, response["result"], "ping should return an empty result by default")
+
+	require.NoError(t, json.Unmarshal(postJSONRPC(t, newTestMCPServer(t, WithPingDiagnostics()), ping).Body.Bytes(), &response))
+	result := response["result"].(map[string]interface{})
+	assert.Contains(t, result, "uptimeSeconds")
+	assert.Equal(t, float64(0), result["sessions"])
+	assert.Equal(t, float64(1), result["inFlightRequests"], "the ping itself is in flight")
+}
+
+func TestRequestInterceptor(t *testing.T) {
+	var seen []string
+	s := newTestMCPServer(t,
+		WithRequestInterceptor(func(ctx context.Context, method string, params json.RawMessage) error {
+			seen = append(seen, method+" "+string(params))
+			return nil
+		}),
+		WithRequestInterceptor(func(ctx context.Context, method string, params json.RawMessage) error {
+			switch method {
+			case "resources/list":
+				return errors.New("resources are disabled")
+			case "tools/list":
+				return &domain.ToolError{Code: -32001, Message: "unauthorized", Data: map[string]interface{}{"scope": "tools"}}
+			}
+			return nil
+		}),
+	)
+	ctx := context.Background()
+
+	assert.Contains(t, handleMessageJSON(t, ctx, s, `{"jsonrpc":"2.0","id":1,"method":"ping"}`), `"result"`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":2,"error":{"code":-32600,"message":"resources are disabled"}}`,
+		handleMessageJSON(t, ctx, s, `{"jsonrpc":"2.0","id":2,"method":"resources/list","params":{"cursor":"x"}}`))
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":3,"error":{"code":-32001,"message":"unauthorized","data":{"scope":"tools"}}}`,
+		handleMessageJSON(t, ctx, s, `{"jsonrpc":"2.0","id":3,"method":"tools/list"}`))
+
+	assert.Equal(t, []string{"ping ", `resources/list {"cursor":"x"}`, "tools/list "}, seen)
+}
+
+// recordingTracer records the spans it starts.
+type recordingTracer struct {
+	embedded.Tracer
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+// recordingSpan is a span recorded by recordingTracer.
+type recordingSpan struct {
+	trace.Span
+	name   string
+	parent trace.SpanContext
+	config trace.SpanConfig
+	status codes.Code
+	ended  bool
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := &recordingSpan{
+		Span:   noop.Span{},
+		name:   name,
+		parent: trace.SpanContextFromContext(ctx),
+		config: trace.NewSpanStartConfig(opts...),
+	}
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, description string) { s.status = code }
+func (s *recordingSpan) End(options ...trace.SpanEndOption)            { s.ended = true }
+
+func TestTracing(t *testing.T) {
+	tracer := &recordingTracer{}
+	s := newTestMCPServer(t, WithTracer(tracer))
+	var handlerSpan trace.Span
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "work"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			handlerSpan = trace.SpanFromContext(ctx)
+			return "done", nil
+		}))
+
+	req := httptest.NewRequest(http.MethodPost, "/jsonrpc", strings.NewReader(`{"jsonrpc":"2.0","id":"call-1","method":"tools/call","params":{"name":"work"}}`))
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	s.handleJSONRPC(httptest.NewRecorder(), req)
+	postJSONRPC(t, s, `{"jsonrpc":"2.0","id":2,"method":"acme/unknown"}`)
+
+	require.Len(t, tracer.spans, 2)
+	call := tracer.spans[0]
+	assert.Equal(t, "tools/call", call.name)
+	assert.Equal(t, trace.SpanKindServer, call.config.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", call.parent.TraceID().String(), "span should join the caller's trace")
+	assert.True(t, call.parent.IsRemote())
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.method", "tools/call"),
+		attribute.String("rpc.jsonrpc.request_id", "call-1"),
+		attribute.String("mcp.tool.name", "work"),
+	}, call.config.Attributes())
+	assert.Same(t, call, handlerSpan, "tool handlers should run within the span")
+	assert.True(t, call.ended)
+	assert.Equal(t, codes.Unset, call.status)
+
+	unknown := tracer.spans[1]
+	assert.Equal(t, "acme/unknown", unknown.name)
+	assert.False(t, unknown.parent.IsValid())
+	assert.Equal(t, codes.Error, unknown.status)
+	assert.True(t, unknown.ended)
+}
+
+func TestHandleJSONRPC_EchoesIDForm(t *testing.T) {
+	s := newTestMCPServer(t)
+
+	for _, id := range []string{`1`, `"1"`, `12345678901234567890`, `"req-9"`} {
+		rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":`+id+`,"method":"ping"}`)
+
+		var response struct {
+			ID json.RawMessage `json:"id"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, id, string(response.ID))
+	}
+}
+
+func TestProcessToolsCall_ToolLookupGrace(t *testing.T) {
+	s := newTestMCPServer(t, WithToolLookupGrace(time.Second))
+
+	// Register the tool shortly after the call arrives
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "late"},
+			func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+				return "registered", nil
+			})
+	}()
+
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"late"}}`)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Nil(t, response["error"])
+	assert.Equal(t, "registered", response["result"])
+}
+
+func TestProcessToolsCall_NoToolLookupGrace(t *testing.T) {
+	s := newTestMCPServer(t)
+
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"missing"}}`)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, float64(domain.InvalidParamsCode), response["error"].(map[string]interface{})["code"])
+}
+
+func TestErrorCodesAreNegative(t *testing.T) {
+	s := newTestMCPServer(t)
+	ctx := domain.WithSessionID(context.Background(), "session-1")
+
+	tests := []struct {
+		body     string
+		wantCode int
+	}{
+		{`{"jsonrpc":"2.0","id":1,"method":"ping"`, domain.ParseErrorCode},
+		{`{"jsonrpc":"1.0","id":1,"method":"ping"}`, domain.InvalidRequestCode},
+		{`{"jsonrpc":"2.0","id":1,"method":"acme/unknown"}`, domain.MethodNotFoundCode},
+		{`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{}}`, domain.InvalidParamsCode},
+		{`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"file:///missing"}}`, domain.ResourceNotFoundCode},
+		{`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"file:///missing"}}`, domain.ResourceNotFoundCode},
+		{`{"jsonrpc":"2.0","id":1,"method":"resources/list","params":{"pattern":"["}}`, domain.InvalidParamsCode},
+		{`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"missing"}}`, domain.InvalidParamsCode},
+		{`{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"missing"}}`, domain.InvalidParamsCode},
+		{`{"jsonrpc":"2.0","id":1,"method":"logging/setLevel","params":{"level":"loud"}}`, domain.InvalidParamsCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(handleMessageJSON(t, ctx, s, tt.body)), &response))
+
+			errObj, ok := response["error"].(map[string]interface{})
+			require.True(t, ok, "expected an error response, got %v", response)
+			code := errObj["code"].(float64)
+			assert.Less(t, code, float64(0), "JSON-RPC error codes must be negative")
+			assert.Equal(t, float64(tt.wantCode), code)
+		})
+	}
+}
+
+func TestIntrospectionTool(t *testing.T) {
+	s := newTestMCPServer(t, WithIntrospectionTool())
+
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"__server_info__"}}`)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Nil(t, response["error"])
+
+	info := response["result"].(map[string]interface{})
+	assert.Equal(t, "test-server", info["name"])
+	assert.Equal(t, "1.0.0", info["version"])
+	assert.Equal(t, mcpProtocolVersion, info["protocol"])
+	assert.Equal(t, float64(1), info["tools"])
+	assert.Equal(t, float64(0), info["sessions"])
+	assert.Contains(t, info, "uptime")
+}
+
+func TestIntrospectionTool_DisabledByDefault(t *testing.T) {
+	s := newTestMCPServer(t)
+
+	_, err := s.GetService().GetTool(context.Background(), introspectionToolName)
+	assert.Error(t, err)
+}
+
+func TestHandleMessage_DuplicateRequestID(t *testing.T) {
+	s := newTestMCPServer(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "slow"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			close(started)
+			<-release
+			return "done", nil
+		}))
+
+	session := domain.WithSessionID(context.Background(), "session-1")
+	first := make(chan string, 1)
+	go func() {
+		first <- handleMessageJSON(t, session, s, `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"slow"}}`)
+	}()
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("tool handler did not start")
+	}
+
+	// The same ID in the same session is rejected while the first is in flight
+	response := handleMessageJSON(t, session, s, `{"jsonrpc":"2.0","id":7,"method":"ping"}`)
+	assert.Contains(t, response, `"code":-32600`)
+	assert.Contains(t, response, "duplicate request id")
+
+	// Other sessions may use the same ID
+	other := domain.WithSessionID(context.Background(), "session-2")
+	assert.NotContains(t, handleMessageJSON(t, other, s, `{"jsonrpc":"2.0","id":7,"method":"ping"}`), `"error"`)
+
+	close(release)
+	assert.Contains(t, <-first, "done")
+
+	// The ID is free again once the first request completed
+	assert.NotContains(t, handleMessageJSON(t, session, s, `{"jsonrpc":"2.0","id":7,"method":"ping"}`), `"error"`)
+}
+
+func TestProcessMessage_CancelInFlightRequest(t *testing.T) {
+	s := newTestMCPServer(t)
+
+	started := make(chan struct{})
+	handlerDone := make(chan error, 1)
+	err := s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "slow"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			handlerDone <- ctx.Err()
+			return nil, ctx.Err()
+		})
+	require.NoError(t, err)
+
+	session := domain.WithSessionID(context.Background(), "session-1")
+	responses := make(chan interface{}, 1)
+	go func() {
+		responses <- s.processMessage(session, json.RawMessage(`{"jsonrpc":"2.0","id":42,"method":"tools/call","params":{"name":"slow"}}`))
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("tool handler did not start")
+	}
+
+	// Other sessions and plain HTTP requests cannot cancel the request
+	cancelled := `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":42,"reason":"user abort"}}`
+	assert.Nil(t, s.processMessage(domain.WithSessionID(context.Background(), "session-2"), json.RawMessage(cancelled)))
+	assert.Equal(t, http.StatusNoContent, postJSONRPC(t, s, cancelled).Code)
+	select {
+	case err := <-handlerDone:
+		t.Fatalf("request cancelled from outside its session: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.Nil(t, s.processMessage(session, json.RawMessage(cancelled)))
+	select {
+	case err := <-handlerDone:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("handler context was not cancelled")
+	}
+
+	// The cancelled request is not answered
+	assert.Nil(t, <-responses)
+
+	s.inflightMu.Lock()
+	assert.Empty(t, s.inflight, "finished requests should no longer be tracked")
+	s.inflightMu.Unlock()
+}
+
+func TestProcessMessage_SessionlessRequestsAreNotTracked(t *testing.T) {
+	s := newTestMCPServer(t)
+
+	var started sync.WaitGroup
+	started.Add(2)
+	release := make(chan struct{})
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "slow"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			started.Done()
+			select {
+			case <-release:
+				return "done", nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}))
+
+	// Two HTTP clients may use the same ID at the same time
+	responses := make(chan string, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			responses <- postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow"}}`).Body.String()
+		}()
+	}
+	started.Wait()
+
+	s.inflightMu.Lock()
+	assert.Empty(t, s.inflight, "requests without a session should not be tracked")
+	s.inflightMu.Unlock()
+
+	close(release)
+	for i := 0; i < 2; i++ {
+		assert.Contains(t, <-responses, "done")
+	}
+}
+
+func TestCORS(t *testing.T) {
+	ping := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+	send := func(s *MCPServer, method, path, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(ping))
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			req.Header.Set("Access-Control-Request-Headers", "Content-Type, Authorization")
+		}
+		rec := httptest.NewRecorder()
+		s.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Any origin is allowed by default, on every endpoint
+	s := newTestMCPServer(t)
+	rec := send(s, http.MethodPost, "/jsonrpc", "https://app.example.com")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	rec = send(s, http.MethodOptions, "/message", "https://app.example.com")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+
+	// Restricted origins are echoed back with credentials, before authentication
+	s = newTestMCPServer(t, WithCORS([]string{"https://app.example.com"}, true), WithAuthToken("secret"))
+	for _, path := range []string{"/jsonrpc", "/sse", "/message"} {
+		rec = send(s, http.MethodOptions, path, "https://app.example.com")
+		assert.Equal(t, http.StatusNoContent, rec.Code, path)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"), path)
+		assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"), path)
+		assert.Equal(t, "Content-Type, Authorization", rec.Header().Get("Access-Control-Allow-Headers"), path)
+		assert.Equal(t, "Origin", rec.Header().Get("Vary"), path)
+
+		rec = send(s, http.MethodOptions, path, "https://evil.example.com")
+		assert.Equal(t, http.StatusForbidden, rec.Code, path)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"), path)
+	}
+
+	rec = send(s, http.MethodPost, "/jsonrpc", "https://evil.example.com")
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	rec = send(s, http.MethodPost, "/jsonrpc", "https://app.example.com")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestAuthToken(t *testing.T) {
+	s := newTestMCPServer(t, WithAuthToken("secret"))
+	handler := s.httpServer.Handler
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		wantStatus    int
+	}{
+		{"Missing token", "/jsonrpc", "", http.StatusUnauthorized},
+		{"Wrong token", "/jsonrpc", "Bearer wrong", http.StatusUnauthorized},
+		{"Wrong scheme", "/jsonrpc", "Basic secret", http.StatusUnauthorized},
+		{"Valid token", "/jsonrpc", "Bearer secret", http.StatusOK},
+		{"SSE without token", "/sse", "", http.StatusUnauthorized},
+		{"Status is open", "/status", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := http.MethodPost
+			if tt.path != "/jsonrpc" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tt.path, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthToken_Empty(t *testing.T) {
+	// An unset token must not let in any client that sends the Bearer prefix
+	handler := newTestMCPServer(t, WithAuthToken("")).httpServer.Handler
+
+	for _, authorization := range []string{"", "Bearer ", "Bearer", "bearer  "} {
+		req := httptest.NewRequest(http.MethodPost, "/jsonrpc", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, "Authorization: %q", authorization)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestExceedsDepth(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   string
+		maxDepth int
+		want     bool
+	}{
+		{"Flat object", `{"a":1}`, 1, false},
+		{"Nested within limit", `{"a":{"b":[1,2]}}`, 3, false},
+		{"Nested beyond limit", `{"a":{"b":[1,2]}}`, 2, true},
+		{"Empty nested object counts", `{"a":{}}`, 1, true},
+		{"Scalar params", `"x"`, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.params), &params))
+			assert.Equal(t, tt.want, exceedsDepth(params, tt.maxDepth))
+		})
+	}
+}
+
+func TestMaxParamDepth(t *testing.T) {
+	s := newTestMCPServer(t, WithMaxParamDepth(3))
+
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"x","arguments":{"a":{"b":{}}}}}`)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, float64(-32600), response["error"].(map[string]interface{})["code"])
+}
+
+func TestProcessToolsList_LocalizedDescription(t *testing.T) {
+	s := newTestMCPServer(t)
+	require.NoError(t, s.GetService().AddTool(context.Background(), &domain.Tool{
+		Name:                  "search",
+		Description:           "Search documents",
+		LocalizedDescriptions: map[string]string{"fr": "Rechercher des documents"},
+	}))
+
+	listDescription := func(sessionID string) string {
+		ctx := domain.WithSessionID(context.Background(), sessionID)
+		response := s.processMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`))
+		result := response.(domain.JSONRPCResponse).Result.(map[string]interface{})
+		return result["tools"].([]map[string]interface{})[0]["description"].(string)
+	}
+
+	ctx := domain.WithSessionID(context.Background(), "french")
+	s.processMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"initializationOptions":{"locale":"fr-FR"}}}`))
+
+	assert.Equal(t, "Rechercher des documents", listDescription("french"))
+	assert.Equal(t, "Search documents", listDescription("other"))
+}
+
+func TestProcessToolsList_Annotations(t *testing.T) {
+	s := newTestMCPServer(t)
+	readOnly, destructive := true, false
+	require.NoError(t, s.GetService().AddTool(context.Background(), &domain.Tool{
+		Name:        "search",
+		Annotations: &domain.ToolAnnotations{ReadOnlyHint: &readOnly, DestructiveHint: &destructive},
+	}))
+	require.NoError(t, s.GetService().AddTool(context.Background(), &domain.Tool{Name: "plain"}))
+
+	var response struct {
+		Result struct {
+			Tools []map[string]interface{} `json:"tools"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(handleMessageJSON(t, context.Background(), s, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)), &response))
+
+	annotations := make(map[string]interface{})
+	for _, tool := range response.Result.Tools {
+		annotations[tool["name"].(string)] = tool["annotations"]
+	}
+	assert.Equal(t, map[string]interface{}{"readOnlyHint": true, "destructiveHint": false}, annotations["search"])
+	assert.Nil(t, annotations["plain"], "tools without hints should not advertise annotations")
+}
+
+func TestMaintenanceMode(t *testing.T) {
+	s := newTestMCPServer(t)
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "work"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return "done", nil
+		}))
+
+	errorCode := func(body string) interface{} {
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(postJSONRPC(t, s, body).Body.Bytes(), &response))
+		if errObj, ok := response["error"].(map[string]interface{}); ok {
+			return errObj["code"]
+		}
+		return nil
+	}
+
+	toolCall := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"work"}}`
+
+	s.SetMaintenanceMode(true, "deploying, back soon")
+	assert.True(t, s.InMaintenance())
+	assert.Equal(t, float64(maintenanceErrorCode), errorCode(toolCall))
+	assert.Nil(t, errorCode(`{"jsonrpc":"2.0","id":2,"method":"ping"}`))
+	assert.Nil(t, errorCode(`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`))
+
+	s.SetMaintenanceMode(false, "")
+	assert.Nil(t, errorCode(toolCall))
+}
+
+func TestPathPrefix(t *testing.T) {
+	s := newTestMCPServer(t, WithPathPrefix("/mcp/"))
+	handler := s.httpServer.Handler
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/mcp/status", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var status map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, "/mcp", status["basePath"])
+	assert.Equal(t, "/mcp/sse", status["endpoints"].(map[string]interface{})["sse"])
+	assert.Equal(t, "/mcp/message", status["endpoints"].(map[string]interface{})["message"])
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/mcp/events", nil))
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "/mcp/sse", rec.Header().Get("Location"))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp/jsonrpc", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestProcessMessage_ClientGoneDropsResponse(t *testing.T) {
+	s := newTestMCPServer(t)
+
+	connCtx, disconnect := context.WithCancel(context.Background())
+	defer disconnect()
+
+	// The client disconnects while the tool is running
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "hangup"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			disconnect()
+			return "done", nil
+		}))
+
+	response := s.processMessage(connCtx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"hangup"}}`))
+	assert.Nil(t, response)
+}
+
+func TestErrorData(t *testing.T) {
+	call := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"strict","arguments":{}}}`
+	tool := &domain.Tool{Name: "strict", Parameters: []domain.ToolParameter{{Name: "q", Type: "string", Required: true}}}
+	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) { return nil, nil }
+
+	tests := []struct {
+		name     string
+		opts     []MCPServerOption
+		wantData bool
+	}{
+		{"Included by default", nil, true},
+		{"Disabled", []MCPServerOption{WithErrorData(false)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestMCPServer(t, tt.opts...)
+			require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), tool, handler))
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(postJSONRPC(t, s, call).Body.Bytes(), &response))
+
+			errObj := response["error"].(map[string]interface{})
+			assert.Equal(t, float64(-32602), errObj["code"])
+			_, hasData := errObj["data"]
+			assert.Equal(t, tt.wantData, hasData)
+			if tt.wantData {
+				assert.Equal(t, "q", errObj["data"].(map[string]interface{})["field"])
+			}
+		})
+	}
+}
+
+func TestStatusReportsConnections(t *testing.T) {
+	s := newTestMCPServer(t)
+
+	rec := httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var status map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, float64(0), status["connections"])
+	assert.Equal(t, 0, s.ActiveSessions())
+	assert.Empty(t, s.SessionIDs())
+}
+
+func TestToolsCall_UnserializableResult(t *testing.T) {
+	s := newTestMCPServer(t)
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "leaky"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return map[string]interface{}{"updates": make(chan int)}, nil
+		}))
+
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"leaky","arguments":{}}}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	errObj := response["error"].(map[string]interface{})
+	assert.Equal(t, float64(-32603), errObj["code"])
+	assert.Equal(t, "Tool returned unserializable result", errObj["message"])
+	assert.Equal(t, "leaky", errObj["data"].(map[string]interface{})["tool"])
+}
+
+func TestHandlerPanics(t *testing.T) {
+	s := newTestMCPServer(t)
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "buggy"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			panic("nil map")
+		}))
+	require.NoError(t, s.AddMethodHandler("vendor/buggy", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		panic("nil map")
+	}))
+
+	for _, body := range []string{
+		`{"jsonrpc":"2.0","id":"call-7","method":"tools/call","params":{"name":"buggy","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":"call-7","method":"vendor/buggy"}`,
+	} {
+		rec := postJSONRPC(t, s, body)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "call-7", response["id"])
+		errObj := response["error"].(map[string]interface{})
+		assert.Equal(t, float64(-32603), errObj["code"])
+		assert.Equal(t, "Internal error", errObj["message"])
+	}
+
+	// The server keeps serving requests
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	assert.NotContains(t, rec.Body.String(), "error")
+}
+
+func TestHeartbeat(t *testing.T) {
+	s := newTestMCPServer(t, WithHeartbeat(10*time.Millisecond))
+	defer func() { _ = s.Stop(context.Background()) }()
+
+	session := server.NewMCPSession("client", "test", 10)
+	s.notifier.RegisterSession(session)
+	s.startHeartbeat()
+
+	select {
+	case notification := <-session.NotificationChannel():
+		data, err := json.Marshal(notification)
+		require.NoError(t, err)
+
+		var message map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &message))
+		assert.Equal(t, "notifications/heartbeat", message["method"])
+		params := message["params"].(map[string]interface{})
+		assert.Equal(t, "ok", params["status"])
+		assert.Contains(t, params, "uptimeSeconds")
+		assert.Equal(t, float64(0), params["sessions"])
+	case <-time.After(2 * time.Second):
+		t.Fatal("no heartbeat broadcast")
+	}
+}
+
+func TestToolsCall_ToolError(t *testing.T) {
+	s := newTestMCPServer(t)
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "signup"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return nil, fmt.Errorf("signup: %w", &domain.ToolError{
+				Code:    -32602,
+				Message: "Email already registered",
+				Data:    map[string]interface{}{"field": "email"},
+			})
+		}))
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "plain"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return nil, &domain.ToolError{Message: "backend unavailable"}
+		}))
+
+	tests := []struct {
+		tool     string
+		wantCode float64
+		wantMsg  string
+		wantData interface{}
+	}{
+		{"signup", -32602, "Email already registered", map[string]interface{}{"field": "email"}},
+		{"plain", -32603, "backend unavailable", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			rec := postJSONRPC(t, s, fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":%q,"arguments":{}}}`, tt.tool))
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			errObj := response["error"].(map[string]interface{})
+			assert.Equal(t, tt.wantCode, errObj["code"])
+			assert.Equal(t, tt.wantMsg, errObj["message"])
+			assert.Equal(t, tt.wantData, errObj["data"])
+		})
+	}
+}
+
+func TestUnsupportedFeatures(t *testing.T) {
+	s := newTestMCPServer(t)
+	ctx := context.Background()
+	require.NoError(t, s.GetService().AddResource(ctx, &domain.Resource{URI: "file:///notes.txt", Name: "notes"}))
+	require.NoError(t, s.GetService().AddPrompt(ctx, &domain.Prompt{Name: "empty"}))
+
+	tests := []struct {
+		name    string
+		body    string
+		wantMsg string
+	}{
+		{"Resource without provider", `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"file:///notes.txt"}}`, "resources/read not supported: no content provider configured"},
+		{"Prompt without template", `{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"empty"}}`, "prompts/get not supported: no template configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(postJSONRPC(t, s, tt.body).Body.Bytes(), &response))
+
+			errObj := response["error"].(map[string]interface{})
+			assert.Equal(t, float64(-32601), errObj["code"])
+			assert.Equal(t, tt.wantMsg, errObj["message"])
+			assert.NotContains(t, response, "result")
+		})
+	}
+}
+
+func TestPromptsGetValidatesArgumentTypes(t *testing.T) {
+	s := newTestMCPServer(t)
+	require.NoError(t, s.GetService().AddPrompt(context.Background(), &domain.Prompt{
+		Name:     "summarize",
+		Template: "Summarize in {{sentences}} sentences.",
+		Parameters: []domain.PromptParameter{
+			{Name: "sentences", Type: "integer", Required: true},
+			{Name: "brief", Type: "boolean", Completable: true},
+		},
+	}))
+
+	tests := []struct {
+		name      string
+		arguments string
+		wantField interface{}
+	}{
+		{"Typed value", `{"sentences":3}`, nil},
+		{"String-encoded value", `{"sentences":"3","brief":"true"}`, nil},
+		{"Missing required argument", `{}`, "sentences"},
+		{"Wrong type", `{"sentences":"three"}`, "sentences"},
+		{"Wrong optional type", `{"sentences":3,"brief":"maybe"}`, "brief"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response map[string]interface{}
+			rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"summarize","arguments":`+tt.arguments+`}}`)
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			if tt.wantField == nil {
+				assert.Contains(t, rec.Body.String(), "Summarize in 3 sentences.")
+				return
+			}
+			errObj := response["error"].(map[string]interface{})
+			assert.Equal(t, float64(-32602), errObj["code"])
+			assert.Equal(t, tt.wantField, errObj["data"].(map[string]interface{})["field"])
+		})
+	}
+
+	// Completable arguments enable the completions capability
+	var response map[string]interface{}
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Contains(t, response["result"].(map[string]interface{})["capabilities"], CapabilityCompletions)
+}
+
+func TestResourcesListFilter(t *testing.T) {
+	s := newTestMCPServer(t)
+	ctx := context.Background()
+	for _, uri := range []string{"file:///docs/a.md", "file:///docs/b.txt", "file:///src/main.go"} {
+		require.NoError(t, s.GetService().AddResource(ctx, &domain.Resource{URI: uri, Name: uri}))
+	}
+
+	listURIs := func(t *testing.T, params string) []string {
+		t.Helper()
+		var response map[string]interface{}
+		rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"resources/list","params":`+params+`}`)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		var uris []string
+		for _, resource := range response["result"].(map[string]interface{})["resources"].([]interface{}) {
+			uris = append(uris, resource.(map[string]interface{})["uri"].(string))
+		}
+		return uris
+	}
+
+	assert.Len(t, listURIs(t, `{}`), 3)
+	assert.ElementsMatch(t, []string{"file:///docs/a.md", "file:///docs/b.txt"}, listURIs(t, `{"uriPrefix":"file:///docs/"}`))
+	assert.Equal(t, []string{"file:///docs/a.md"}, listURIs(t, `{"pattern":"file:///*/*.md"}`))
+
+	for _, params := range []string{`{"pattern":"file:///["}`, `{"uriPrefix":42}`} {
+		var response map[string]interface{}
+		rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":2,"method":"resources/list","params":`+params+`}`)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, float64(-32602), response["error"].(map[string]interface{})["code"], params)
+	}
+}
+
+func TestResourceTemplates(t *testing.T) {
+	s := newTestMCPServer(t)
+	provider := domain.ResourceTemplateContentProviderFunc(func(ctx context.Context, uri string, vars map[string]string) ([]domain.ResourceContents, error) {
+		return []domain.ResourceContents{{Text: "contents of " + vars["path"]}}, nil
+	})
+	require.NoError(t, s.GetService().AddResourceTemplate(context.Background(), &domain.ResourceTemplate{
+		URITemplate:     "file:///{path}",
+		Name:            "Project files",
+		MIMEType:        "text/plain",
+		ContentProvider: provider,
+	}))
+
+	var listResponse map[string]interface{}
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"resources/templates/list"}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listResponse))
+	templates := listResponse["result"].(map[string]interface{})["resourceTemplates"].([]interface{})
+	require.Len(t, templates, 1)
+	assert.Equal(t, "file:///{path}", templates[0].(map[string]interface{})["uriTemplate"])
+	assert.Equal(t, "Project files", templates[0].(map[string]interface{})["name"])
+
+	var readResponse map[string]interface{}
+	rec = postJSONRPC(t, s, `{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"file:///src/main.go"}}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &readResponse))
+	contents := readResponse["result"].(map[string]interface{})["contents"].([]interface{})
+	require.Len(t, contents, 1)
+	entry := contents[0].(map[string]interface{})
+	assert.Equal(t, "file:///src/main.go", entry["uri"])
+	assert.Equal(t, "text/plain", entry["mimeType"])
+	assert.Equal(t, "contents of src/main.go", entry["text"])
+}
+
+func TestDrain(t *testing.T) {
+	s := newTestMCPServer(t)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "slow"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			close(started)
+			<-release
+			return "finished", nil
+		}))
+
+	inFlight := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		inFlight <- postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow","arguments":{}}}`)
+	}()
+	<-started
+
+	drained := make(chan error, 1)
+	go func() {
+		drained <- s.Drain(context.Background())
+	}()
+	require.Eventually(t, s.Draining, time.Second, 5*time.Millisecond)
+
+	// New requests are rejected while the in-flight one keeps running
+	var rejected map[string]interface{}
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rejected))
+	errObj := rejected["error"].(map[string]interface{})
+	assert.Equal(t, float64(-32000), errObj["code"])
+	assert.Equal(t, "server draining", errObj["message"])
+
+	select {
+	case err := <-drained:
+		t.Fatalf("Drain returned before the in-flight request finished: %v", err)
+	default:
+	}
+
+	close(release)
+	assert.Contains(t, (<-inFlight).Body.String(), "finished")
+	require.NoError(t, <-drained)
+}
+
+func TestDrain_DeadlineExceeded(t *testing.T) {
+	s := newTestMCPServer(t)
+	baseURL := serveTestMCPServer(t, s)
+
+	// One handler returns once cancelled, the other ignores its context
+	started := make(chan string, 2)
+	unblock := make(chan struct{})
+	t.Cleanup(func() { close(unblock) })
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "cooperative"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			started <- "cooperative"
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}))
+	// Tool handlers are abandoned by the service once cancelled, custom
+	// method handlers are not
+	require.NoError(t, s.AddMethodHandler("acme/stubborn", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		started <- "stubborn"
+		<-unblock
+		return "too late", nil
+	}))
+
+	type reply struct {
+		body string
+		err  error
+	}
+	call := func(message string) <-chan reply {
+		replies := make(chan reply, 1)
+		go func() {
+			resp, err := http.Post(baseURL+"/jsonrpc", "application/json",
+				strings.NewReader(message))
+			if err != nil {
+				replies <- reply{err: err}
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			replies <- reply{body: string(body), err: err}
+		}()
+		return replies
+	}
+	cooperative := call(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"cooperative","arguments":{}}}`)
+	stubborn := call(`{"jsonrpc":"2.0","id":2,"method":"acme/stubborn"}`)
+	<-started
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.ErrorIs(t, s.Drain(ctx), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), drainStopTimeout+time.Second, "Drain must not wait for handlers that ignore cancellation")
+
+	// The cancelled request is answered, the stubborn one loses its connection
+	got := <-cooperative
+	require.NoError(t, got.err)
+	assert.Contains(t, got.body, "Request cancelled")
+	assert.Error(t, (<-stubborn).err)
+
+	_, err := http.Post(baseURL+"/jsonrpc", "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":3,"method":"ping"}`))
+	assert.Error(t, err, "the server should no longer listen")
+}
+
+// serveTestMCPServer serves s on a local listener, as Start does, and returns
+// its base URL.
+func serveTestMCPServer(t *testing.T, s *MCPServer) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = s.httpServer.Serve(listener) }()
+	return "http://" + listener.Addr().String()
+}
+
+// connectSSEClient opens an SSE stream to baseURL and waits until the server
+// registered the session.
+func connectSSEClient(t *testing.T, s *MCPServer, baseURL string) *bufio.Reader {
+	t.Helper()
+
+	resp, err := http.Get(baseURL + "/sse")
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Eventually(t, func() bool { return s.ActiveSessions() == 1 }, time.Second, 5*time.Millisecond)
+	return bufio.NewReader(resp.Body)
+}
+
+func TestWithEventBatching(t *testing.T) {
+	s := newTestMCPServer(t, WithEventBatching(8, 25*time.Millisecond))
+	events := connectSSEClient(t, s, serveTestMCPServer(t, s))
+
+	// The connected event advertises the batching settings
+	_, err := events.ReadString('\n')
+	require.NoError(t, err)
+	data, err := events.ReadString('\n')
+	require.NoError(t, err)
+	assert.Contains(t, data, `"eventBatching": {"maxBatch": 8, "windowMs": 25}`)
+}
+
+func TestStopClosesSSESessions(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(s *MCPServer, ctx context.Context) error
+	}{
+		{"Stop", (*MCPServer).Stop},
+		{"Drain", (*MCPServer).Drain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestMCPServer(t)
+			events := connectSSEClient(t, s, serveTestMCPServer(t, s))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			start := time.Now()
+			require.NoError(t, tt.stop(s, ctx))
+			assert.Less(t, time.Since(start), time.Second, "open SSE sessions should not hold up the shutdown")
+
+			// The client sees its stream end
+			_, err := io.ReadAll(events)
+			assert.NoError(t, err)
+			assert.Zero(t, s.ActiveSessions())
+		})
+	}
+}
+
+func TestStopNotifiesClients(t *testing.T) {
+	s := newTestMCPServer(t, WithShutdownGrace(100*time.Millisecond))
+	events := connectSSEClient(t, s, serveTestMCPServer(t, s))
+
+	stopped := make(chan error, 1)
+	start := time.Now()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		stopped <- s.Stop(ctx)
+	}()
+
+	// The client is told about the shutdown before its session closes
+	var received []string
+	for {
+		line, err := events.ReadString('\n')
+		if err != nil {
+			break
+		}
+		received = append(received, line)
+	}
+	assert.Contains(t, strings.Join(received, ""), `"method":"notifications/server/shutdown"`)
+	require.NoError(t, <-stopped)
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, 100*time.Millisecond, "Stop should wait for the grace period")
+	assert.Less(t, elapsed, time.Second, "Stop should close the session after the grace period")
+}
+
+func TestReload(t *testing.T) {
+	s := newTestMCPServer(t)
+	require.NoError(t, s.GetService().AddTool(context.Background(), &domain.Tool{Name: "before"}))
+	notifications, unregister := s.RegisterNotificationSession("observer")
+	defer unregister()
+
+	assert.Error(t, s.Reload(nil))
+
+	next := newTestMCPServer(t).GetService()
+	require.NoError(t, next.AddTool(context.Background(), &domain.Tool{Name: "after"}))
+	require.NoError(t, s.Reload(next))
+	assert.Same(t, next, s.GetService())
+
+	// New requests use the new service
+	var response struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`).Body.Bytes(), &response))
+	require.Len(t, response.Result.Tools, 1)
+	assert.Equal(t, "after", response.Result.Tools[0].Name)
+
+	// Connected clients are told every list changed, with the same methods
+	// the service uses
+	var methods []string
+	for len(methods) < 3 {
+		select {
+		case notification := <-notifications:
+			methods = append(methods, notification.Method)
+		case <-time.After(time.Second):
+			t.Fatalf("got notifications %v, want three list changes", methods)
+		}
+	}
+	assert.ElementsMatch(t, []string{domain.ToolListChangedMethod, domain.ResourceListChangedMethod, domain.PromptListChangedMethod}, methods)
+}
+
+func TestMetrics(t *testing.T) {
+	metrics := server.NewRequestMetrics()
+	s := newTestMCPServer(t, WithMetrics(metrics))
+
+	postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	postJSONRPC(t, s, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"missing","arguments":{}}}`)
+	postJSONRPC(t, s, `{"jsonrpc":"2.0","id":3,"method":"no/such/method"}`)
+	postJSONRPC(t, s, `{not json`)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	body := rec.Body.String()
+	assert.Contains(t, body, `mcp_requests_total{method="ping"} 1`)
+	assert.Contains(t, body, `mcp_request_errors_total{method="ping"} 0`)
+	assert.Contains(t, body, `mcp_request_errors_total{method="tools/call"} 1`)
+	assert.Contains(t, body, `mcp_requests_total{method="unknown"} 2`)
+	assert.NotContains(t, body, "no/such/method")
+
+	// JSON clients still get the SSE queue metrics
+	req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/json")
+	rec = httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(rec, req)
+	assert.Contains(t, rec.Body.String(), "maxQueueDepth")
+}
+
+func TestHealthz(t *testing.T) {
+	s := newTestMCPServer(t, WithAuthToken("secret"))
+	health := func(path string) int {
+		rec := httptest.NewRecorder()
+		s.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	// Not listening yet
+	assert.Equal(t, http.StatusServiceUnavailable, health("/healthz"))
+
+	// Ready once listening, without credentials
+	s.ready.Store(true)
+	assert.Equal(t, http.StatusOK, health("/healthz"))
+
+	// Unavailable while draining
+	require.NoError(t, s.Drain(context.Background()))
+	assert.Equal(t, http.StatusServiceUnavailable, health("/healthz"))
+}
+
+func TestHealthz_CustomPath(t *testing.T) {
+	s := newTestMCPServer(t, WithHealthPath("/livez"), WithPathPrefix("/mcp"))
+	s.ready.Store(true)
+
+	rec := httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/mcp/livez", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
+
+func TestRequestLogging(t *testing.T) {
+	logPath := t.TempDir() + "/requests.log"
+	logger, err := logging.New(logging.Config{Level: logging.DebugLevel, OutputPaths: []string{logPath}})
+	require.NoError(t, err)
+
+	s := newTestMCPServer(t, WithLogger(logger), WithRequestLogging("password", "Token"))
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "login"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return map[string]interface{}{"token": "session-secret", "user": args["user"]}, nil
+		}))
+
+	postJSONRPC(t, s, `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"login","arguments":{"user":"ada","password":"hunter2","nested":[{"TOKEN":"abc"}]}}}`)
+	postJSONRPC(t, s, `{"jsonrpc":"2.0","id":8,"method":"no/such/method"}`)
+	require.NoError(t, logger.Sync())
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	logs := string(data)
+
+	assert.Contains(t, logs, "JSON-RPC request")
+	assert.Contains(t, logs, "tools/call")
+	assert.Contains(t, logs, "ada")
+	assert.Contains(t, logs, "***")
+	assert.NotContains(t, logs, "hunter2")
+	assert.NotContains(t, logs, "abc")
+	assert.NotContains(t, logs, "session-secret")
+	assert.Contains(t, logs, "-32601")
+}
+
+func TestToolsCall_IsErrorResult(t *testing.T) {
+	s := newTestMCPServer(t)
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{
+		Name:           "lookup",
+		OutputSchema:   []domain.ToolParameter{{Name: "id", Type: "string", Required: true}},
+		ValidateOutput: true,
+	}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{
+			"content": []interface{}{map[string]interface{}{"type": "text", "text": "no such record"}},
+			"isError": true,
+		}, nil
+	}))
+
+	var response map[string]interface{}
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"lookup","arguments":{}}}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+	// Tool failures are a normal result, not a JSON-RPC error
+	assert.Nil(t, response["error"])
+	result := response["result"].(map[string]interface{})
+	assert.Equal(t, true, result["isError"])
+	assert.Equal(t, "no such record", result["content"].([]interface{})[0].(map[string]interface{})["text"])
+}
+
+func TestSessionInfo(t *testing.T) {
+	s := newTestMCPServer(t)
+	var got domain.SessionInfo
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "whoami"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			got, _ = domain.SessionInfoFromContext(ctx)
+			return "ok", nil
+		}))
+
+	ctx := domain.WithSessionInfo(context.Background(), domain.NewSessionInfoHolder("session-1", "test-agent"))
+	s.processMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"clientInfo":{"name":"inspector","version":"1.2.0"}}}`))
+	s.processMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"whoami","arguments":{}}}`))
+	assert.Equal(t, domain.SessionInfo{ID: "session-1", ClientName: "inspector", ClientVersion: "1.2.0", UserAgent: "test-agent"}, got)
+
+	// Plain HTTP requests carry the user agent
+	req := httptest.NewRequest(http.MethodPost, "/jsonrpc", strings.NewReader(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"whoami","arguments":{}}}`))
+	req.Header.Set("User-Agent", "http-client")
+	s.handleJSONRPC(httptest.NewRecorder(), req)
+	assert.Equal(t, "http-client", got.UserAgent)
+	assert.Empty(t, got.ID)
+}
+
+func TestInitializedNotification(t *testing.T) {
+	s := newTestMCPServer(t)
+	holder := domain.NewSessionInfoHolder("session-1", "")
+	ctx := domain.WithSessionInfo(context.Background(), holder)
+
+	s.processMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`))
+	assert.False(t, holder.Info().Ready, "session should not be ready before notifications/initialized")
+
+	response := s.processMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	assert.Nil(t, response, "notifications should not produce responses")
+	assert.True(t, holder.Info().Ready)
+
+	// Unknown notifications are ignored rather than answered with method not found
+	assert.Nil(t, s.processMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","method":"notifications/unknown"}`)))
+}
+
+func TestAddMethodHandler(t *testing.T) {
+	s := newTestMCPServer(t)
+	require.NoError(t, s.AddMethodHandler("vendor/echo", func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		var args map[string]interface{}
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, &domain.ToolError{Code: -32602, Message: "invalid params"}
+		}
+		return args, nil
+	}))
+
+	var response map[string]interface{}
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"vendor/echo","params":{"text":"hi"}}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, map[string]interface{}{"text": "hi"}, response["result"])
+
+	// Handler errors control the error code
+	response = nil
+	rec = postJSONRPC(t, s, `{"jsonrpc":"2.0","id":2,"method":"vendor/echo"}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, float64(-32602), response["error"].(map[string]interface{})["code"])
+
+	// Unregistered methods are still not found
+	response = nil
+	rec = postJSONRPC(t, s, `{"jsonrpc":"2.0","id":3,"method":"vendor/missing"}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, float64(-32601), response["error"].(map[string]interface{})["code"])
+
+	// MCP methods cannot be shadowed
+	noop := func(ctx context.Context, params json.RawMessage) (interface{}, error) { return nil, nil }
+	for _, method := range []string{"initialize", "ping", "tools/call", "resources/subscribe", "completion/complete"} {
+		assert.ErrorIs(t, s.AddMethodHandler(method, noop), ErrReservedMethod, method)
+	}
+}
+
+func TestCompletionComplete(t *testing.T) {
+	s := newTestMCPServer(t)
+	ref := domain.CompletionReference{Type: domain.CompletionRefPrompt, Name: "review"}
+	s.GetService().SetArgumentCompleter(ref, "file", func(ctx context.Context, value string) ([]string, error) {
+		values := make([]string, 150)
+		for i := range values {
+			values[i] = fmt.Sprintf("%s%d.go", value, i)
+		}
+		return values, nil
+	})
+
+	var response map[string]interface{}
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"completion/complete","params":{"ref":{"type":"ref/prompt","name":"review"},"argument":{"name":"file","value":"src"}}}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	completion := response["result"].(map[string]interface{})["completion"].(map[string]interface{})
+	assert.Len(t, completion["values"], domain.MaxCompletionValues)
+	assert.Equal(t, "src0.go", completion["values"].([]interface{})[0])
+	assert.Equal(t, float64(150), completion["total"])
+	assert.Equal(t, true, completion["hasMore"])
+
+	response = nil
+	rec = postJSONRPC(t, s, `{"jsonrpc":"2.0","id":2,"method":"completion/complete","params":{"ref":{"type":"ref/unknown"},"argument":{"name":"file"}}}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, float64(-32602), response["error"].(map[string]interface{})["code"])
+}
+
+func TestMaxRequestBytes(t *testing.T) {
+	s := newTestMCPServer(t, WithMaxRequestBytes(64))
+
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"ping","params":{"pad":"`+strings.Repeat("x", 100)+`"}}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, float64(-32600), response["error"].(map[string]interface{})["code"])
+
+	// The limit defaults to 4 MiB
+	s = newTestMCPServer(t)
+	assert.Equal(t, server.DefaultMaxRequestBytes, s.maxBodyBytes)
+}
+
+func TestHandleMessage_InMemoryTransport(t *testing.T) {
+	s := newTestMCPServer(t)
+	client, srv := server.NewInMemoryTransportPair(server.WithInMemoryMessageHandler(s.HandleMessage))
+	go func() { _ = srv.Start() }()
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, client.Send(client.SessionID(), json.RawMessage(`{"jsonrpc":"2.0","id":"init","method":"initialize","params":{"protocolVersion":"2024-11-05"}}`)))
+	message, err := client.Receive(ctx)
+	require.NoError(t, err)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(message, &response))
+	assert.Equal(t, "init", response["id"])
+	assert.Equal(t, "test-server", response["result"].(map[string]interface{})["serverInfo"].(map[string]interface{})["name"])
+
+	require.NoError(t, client.Send(client.SessionID(), json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)))
+	message, err = client.Receive(ctx)
+	require.NoError(t, err)
+	response = nil
+	require.NoError(t, json.Unmarshal(message, &response))
+	assert.Equal(t, float64(2), response["id"])
+	assert.Contains(t, response["result"], "tools")
+}
+
+func TestCallRateLimit(t *testing.T) {
+	s := newTestMCPServer(t)
+	service := s.GetService()
+	require.NoError(t, service.AddToolWithHandler(context.Background(), &domain.Tool{Name: "echo"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return "ok", nil
+	}))
+	service.SetCallRateLimit(0.001, 1, false)
+
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo"}}`)
+	assert.NotContains(t, rec.Body.String(), `"error"`)
+
+	var response map[string]interface{}
+	rec = postJSONRPC(t, s, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo"}}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	rpcErr := response["error"].(map[string]interface{})
+	assert.Equal(t, float64(-32000), rpcErr["code"])
+	assert.Equal(t, "Rate limit exceeded", rpcErr["message"])
+	data := rpcErr["data"].(map[string]interface{})
+	assert.Equal(t, domain.RateLimitScopeServer, data["scope"])
+	assert.Greater(t, data["retryAfterMs"], float64(0))
+}
+
+func TestInitializeCapabilities(t *testing.T) {
+	initialize := func(s *MCPServer) map[string]interface{} {
+		var response map[string]interface{}
+		rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response["result"].(map[string]interface{})["capabilities"].(map[string]interface{})
+	}
+
+	// A tools-only server advertises only tools and logging
+	s := newTestMCPServer(t)
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "echo"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return "ok", nil
+	}))
+	capabilities := initialize(s)
+	assert.Contains(t, capabilities, CapabilityTools)
+	assert.Contains(t, capabilities, CapabilityLogging)
+	assert.NotContains(t, capabilities, CapabilityResources)
+	assert.NotContains(t, capabilities, CapabilityPrompts)
+	assert.NotContains(t, capabilities, CapabilityCompletions)
+
+	// Explicit capabilities override detection
+	s = newTestMCPServer(t, WithCapabilities(CapabilityPrompts))
+	assert.Equal(t, map[string]interface{}{"prompts": map[string]interface{}{"listChanged": true}}, initialize(s))
+}
+
+func TestInitializeNegotiatesProtocolVersion(t *testing.T) {
+	s := newTestMCPServer(t)
+	negotiate := func(requested string) string {
+		var response map[string]interface{}
+		rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"`+requested+`"}}`)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response["result"].(map[string]interface{})["protocolVersion"].(string)
+	}
+
+	for _, version := range domain.SupportedProtocolVersions {
+		assert.Equal(t, version, negotiate(version))
+	}
+	assert.Equal(t, domain.LatestProtocolVersion, negotiate("1999-01-01"))
+}
+
+func TestLoggingSetLevel(t *testing.T) {
+	logger, err := logging.New(logging.Config{Level: logging.InfoLevel, OutputPaths: []string{"stderr"}})
+	require.NoError(t, err)
+	s := newTestMCPServer(t, WithLogger(logger))
+
+	var response map[string]interface{}
+	rec := postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"logging/setLevel","params":{"level":"warning"}}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, map[string]interface{}{}, response["result"])
+	assert.Equal(t, logging.WarnLevel, logger.Level())
+
+	response = nil
+	rec = postJSONRPC(t, s, `{"jsonrpc":"2.0","id":2,"method":"logging/setLevel","params":{"level":"loud"}}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, float64(-32602), response["error"].(map[string]interface{})["code"])
+	assert.Equal(t, logging.WarnLevel, logger.Level())
+}
+
+func TestToolLogsForwardedToClient(t *testing.T) {
+	logger, err := logging.New(logging.Config{Level: logging.InfoLevel, OutputPaths: []string{"stderr"}})
+	require.NoError(t, err)
+	s := newTestMCPServer(t, WithLogger(logger))
+	require.NoError(t, s.GetService().AddToolWithHandler(context.Background(), &domain.Tool{Name: "work"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		log, ok := domain.ClientLogFromContext(ctx)
+		require.True(t, ok)
+		require.NoError(t, log(ctx, "debug", "work", "hidden"))
+		require.NoError(t, log(ctx, "warning", "work", "disk almost full"))
+		return "ok", nil
+	}))
+
+	// Requests without a session are broadcast
+	session := server.NewMCPSession("listener", "test", 10)
+	s.notifier.RegisterSession(session)
+	postJSONRPC(t, s, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"work"}}`)
+
+	// Only messages at or above the server's log level are sent
+	select {
+	case notification := <-session.NotificationChannel():
+		assert.Equal(t, "notifications/message", notification.Method)
+		assert.Equal(t, map[string]interface{}{"level": "warning", "logger": "work", "data": "disk almost full"}, notification.Params)
+	case <-time.After(time.Second):
+		t.Fatal("no log notification received")
+	}
+	select {
+	case notification := <-session.NotificationChannel():
+		t.Fatalf("unexpected notification %+v", notification)
+	default:
+	}
+}
+
+func TestResourceSubscriptions(t *testing.T) {
+	s := newTestMCPServer(t)
+	ctx := context.Background()
+	require.NoError(t, s.GetService().AddResource(ctx, &domain.Resource{URI: "logs://app", Name: "App logs"}))
+
+	session := server.NewMCPSession("subscriber", "test", 10)
+	s.notifier.RegisterSession(session)
+	other := server.NewMCPSession("other", "test", 10)
+	s.notifier.RegisterSession(other)
+	sessionCtx := domain.WithSessionID(ctx, "subscriber")
+
+	// The resources capability advertises subscriptions
+	capabilities := s.Capabilities(ctx)
+	assert.Equal(t, map[string]bool{"subscribe": true, "listChanged": true}, capabilities[CapabilityResources])
+
+	// Unknown resources and requests without a session are rejected
+	response := handleMessageJSON(t, sessionCtx, s, `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"logs://missing"}}`)
+	assert.Contains(t, response, `"code":-32002`)
+	response = handleMessageJSON(t, ctx, s, `{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"logs://app"}}`)
+	assert.Contains(t, response, `"code":-32602`)
+
+	response = handleMessageJSON(t, sessionCtx, s, `{"jsonrpc":"2.0","id":3,"method":"resources/subscribe","params":{"uri":"logs://app"}}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":3,"result":{}}`, response)
+
+	// Only the subscribed session is notified
+	require.NoError(t, s.GetService().NotifyResourceUpdated(ctx, "logs://app"))
+	select {
+	case notification := <-session.NotificationChannel():
+		assert.Equal(t, "notifications/resources/updated", notification.Method)
+		assert.Equal(t, map[string]interface{}{"uri": "logs://app"}, notification.Params)
+	case <-time.After(time.Second):
+		t.Fatal("no update notification received")
+	}
+	select {
+	case notification := <-other.NotificationChannel():
+		t.Fatalf("unexpected notification %+v", notification)
+	default:
+	}
+
+	// Unsubscribed sessions are no longer notified
+	response = handleMessageJSON(t, sessionCtx, s, `{"jsonrpc":"2.0","id":4,"method":"resources/unsubscribe","params":{"uri":"logs://app"}}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":4,"result":{}}`, response)
+	require.NoError(t, s.GetService().NotifyResourceUpdated(ctx, "logs://app"))
+	select {
+	case notification := <-session.NotificationChannel():
+		t.Fatalf("unexpected notification %+v", notification)
+	default:
+	}
+}
@@ -0,0 +1,57 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+)
+
+// processResourcesSubscribe subscribes the requesting session to updates of
+// a resource.
+func (s *MCPServer) processResourcesSubscribe(ctx context.Context, request domain.JSONRPCRequest) interface{} {
+	return s.processSubscription(ctx, request, true)
+}
+
+// processResourcesUnsubscribe removes a subscription of the requesting
+// session.
+func (s *MCPServer) processResourcesUnsubscribe(ctx context.Context, request domain.JSONRPCRequest) interface{} {
+	return s.processSubscription(ctx, request, false)
+}
+
+// processSubscription handles resources/subscribe and resources/unsubscribe.
+func (s *MCPServer) processSubscription(ctx context.Context, request domain.JSONRPCRequest, subscribe bool) interface{} {
+	params, _ := request.Params.(map[string]interface{})
+	uri, ok := params["uri"].(string)
+	if !ok || uri == "" {
+		s.logger.Warn("Missing or invalid 'uri' parameter")
+		return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidParamsCode, "Missing or invalid 'uri' parameter")
+	}
+
+	service := s.serviceFromContext(ctx)
+	var err error
+	if subscribe {
+		err = service.SubscribeResource(ctx, uri)
+	} else {
+		err = service.UnsubscribeResource(ctx, uri)
+	}
+	if err != nil {
+		var notFoundErr *domain.ResourceNotFoundError
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &notFoundErr):
+			s.logger.Warn("Resource not found", logging.Fields{"uri": uri})
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.ResourceNotFoundCode, fmt.Sprintf("Resource not found: %s", uri))
+		case errors.As(err, &validationErr):
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InvalidParamsCode, fmt.Sprintf("Invalid params: %v", err))
+		default:
+			s.logger.Error("Error updating resource subscription", logging.Fields{"uri": uri, "error": err})
+			return domain.CreateErrorResponse(jsonRPCVersion, request.ID, domain.InternalErrorCode, fmt.Sprintf("Internal error: %v", err))
+		}
+	}
+
+	s.logger.Info("Resource subscription updated", logging.Fields{"uri": uri, "subscribed": subscribe})
+	return domain.CreateResponse(jsonRPCVersion, request.ID, struct{}{})
+}
@@ -0,0 +1,87 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceContext extracts W3C trace context (traceparent) from HTTP headers.
+var traceContext = propagation.TraceContext{}
+
+// WithTracer starts an OpenTelemetry span for every JSON-RPC message, named
+// after its method, with the method, request ID and, for tool calls, tool
+// name as attributes. Tool handlers run within the span's context so their
+// own spans nest under it, and a traceparent header on the HTTP request makes
+// the span join the caller's trace. Tracing is disabled by default.
+func WithTracer(tracer trace.Tracer) MCPServerOption {
+	return func(s *MCPServer) {
+		s.tracer = tracer
+	}
+}
+
+// extractTraceContext returns ctx carrying the remote span context from the
+// request headers, if tracing is enabled.
+func (s *MCPServer) extractTraceContext(ctx context.Context, header http.Header) context.Context {
+	if s.tracer == nil {
+		return ctx
+	}
+	return traceContext.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// startSpan starts the span of a JSON-RPC message.
+func (s *MCPServer) startSpan(ctx context.Context, rawMessage json.RawMessage) (context.Context, trace.Span) {
+	var request struct {
+		Method string          `json:"method"`
+		ID     json.RawMessage `json:"id"`
+		Params struct {
+			Name string `json:"name"`
+		} `json:"params"`
+	}
+	_ = json.Unmarshal(rawMessage, &request)
+
+	name := request.Method
+	if name == "" {
+		name = "unknown"
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.method", request.Method),
+	}
+	if id, ok := requestIDAttribute(request.ID); ok {
+		attrs = append(attrs, attribute.String("rpc.jsonrpc.request_id", id))
+	}
+	if request.Method == "tools/call" && request.Params.Name != "" {
+		attrs = append(attrs, attribute.String("mcp.tool.name", request.Params.Name))
+	}
+
+	return s.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+}
+
+// requestIDAttribute formats a raw request ID as a span attribute value.
+// String IDs are unquoted and numbers keep their original form.
+func requestIDAttribute(raw json.RawMessage) (string, bool) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", false
+	}
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil {
+		return id, true
+	}
+	return string(raw), true
+}
+
+// endSpan records the outcome of a message on its span and ends it.
+func endSpan(span trace.Span, response interface{}) {
+	if errResponse, ok := response.(domain.JSONRPCResponse); ok && errResponse.Error != nil {
+		span.SetAttributes(attribute.Int("rpc.jsonrpc.error_code", errResponse.Error.Code))
+		span.SetStatus(codes.Error, errResponse.Error.Message)
+	}
+	span.End()
+}
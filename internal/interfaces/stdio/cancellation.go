@@ -0,0 +1,71 @@
+package stdio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+)
+
+// errRequestCancelled is the cause of the context of a request the client
+// cancelled with notifications/cancelled. Such requests get no response.
+var errRequestCancelled = errors.New("request cancelled by the client")
+
+// isCancellation reports whether a message is a notifications/cancelled.
+func isCancellation(message string) bool {
+	var notification struct {
+		Method string `json:"method"`
+	}
+	return json.Unmarshal([]byte(message), &notification) == nil && notification.Method == "notifications/cancelled"
+}
+
+// trackRequest records the cancel function of an in-flight request so it can
+// be cancelled by a notifications/cancelled message. The returned function
+// stops tracking the request and frees its ID for reuse. It reports false if
+// a request with the same ID is already in flight.
+func (p *MessageProcessor) trackRequest(id interface{}, cancel context.CancelFunc) (func(), bool) {
+	requestID, ok := domain.NewRequestID(id)
+	if !ok {
+		return func() {}, true
+	}
+
+	p.inflightMu.Lock()
+	defer p.inflightMu.Unlock()
+	if _, duplicate := p.inflight[requestID]; duplicate {
+		return nil, false
+	}
+	p.inflight[requestID] = cancel
+
+	return func() {
+		p.inflightMu.Lock()
+		delete(p.inflight, requestID)
+		p.inflightMu.Unlock()
+	}, true
+}
+
+// cancelRequest cancels the in-flight request referenced by the params of a
+// notifications/cancelled message.
+func (p *MessageProcessor) cancelRequest(params interface{}) {
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return
+	}
+	requestID, ok := domain.NewRequestID(paramsMap["requestId"])
+	if !ok {
+		return
+	}
+
+	p.inflightMu.Lock()
+	cancel, ok := p.inflight[requestID]
+	p.inflightMu.Unlock()
+
+	if !ok {
+		p.logger.Debug("Cancellation for unknown request", logging.Fields{"requestId": requestID.String()})
+		return
+	}
+
+	p.logger.Info("Cancelling request", logging.Fields{"requestId": requestID.String(), "reason": paramsMap["reason"]})
+	cancel()
+}
@@ -0,0 +1,175 @@
+package stdio
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Framing selects how JSON-RPC messages are delimited on the stdio streams.
+type Framing int
+
+const (
+	// FramingAuto detects the framing from the first message: header framing
+	// if it starts with a Content-Length header, newline framing otherwise.
+	// Responses use the detected framing.
+	FramingAuto Framing = iota
+	// FramingNewline delimits each message with a newline.
+	FramingNewline
+	// FramingContentLength precedes each message with LSP-style headers,
+	// "Content-Length: <n>\r\n\r\n", and no delimiter after it.
+	FramingContentLength
+)
+
+// maxContentLength is the largest message accepted with header framing.
+const maxContentLength = 64 << 20
+
+// errMissingContentLength is returned when a header block has no
+// Content-Length header.
+var errMissingContentLength = errors.New("missing Content-Length header")
+
+// WithFraming sets how messages are delimited on stdin and stdout. The
+// default, FramingAuto, detects the framing the client uses.
+func WithFraming(framing Framing) StdioOption {
+	return func(s *StdioServer) {
+		s.framing = framing
+	}
+}
+
+// messageReader reads framed messages from the input stream.
+type messageReader struct {
+	reader  *bufio.Reader
+	framing Framing
+}
+
+// newMessageReader creates a reader for the given framing.
+func newMessageReader(r io.Reader, framing Framing) *messageReader {
+	return &messageReader{reader: bufio.NewReader(r), framing: framing}
+}
+
+// readMessage returns the next message. A final message that is not
+// terminated by a newline is returned before io.EOF.
+func (m *messageReader) readMessage() (string, error) {
+	if m.framing == FramingAuto {
+		framing, err := m.detectFraming()
+		if err != nil {
+			return "", err
+		}
+		m.framing = framing
+	}
+
+	if m.framing == FramingContentLength {
+		return m.readContentLengthMessage()
+	}
+	return m.readLine()
+}
+
+// detectFraming peeks past leading whitespace at the first byte of the
+// stream. JSON messages start with '{' or '[', header blocks with a letter.
+func (m *messageReader) detectFraming() (Framing, error) {
+	for {
+		b, err := m.reader.Peek(1)
+		if err != nil {
+			return FramingAuto, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = m.reader.ReadByte()
+			continue
+		case '{', '[':
+			return FramingNewline, nil
+		default:
+			return FramingContentLength, nil
+		}
+	}
+}
+
+// readLine reads a newline-delimited message.
+func (m *messageReader) readLine() (string, error) {
+	line, err := m.reader.ReadString('\n')
+	if err == io.EOF && strings.TrimSpace(line) != "" {
+		return line, nil
+	}
+	return line, err
+}
+
+// readContentLengthMessage reads a header block followed by a body of the
+// length it declares.
+func (m *messageReader) readContentLengthMessage() (string, error) {
+	length := -1
+	sawHeader := false
+	for {
+		line, err := m.reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && (strings.TrimSpace(line) != "" || length >= 0) {
+				return "", io.ErrUnexpectedEOF
+			}
+			return "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if length >= 0 {
+				break
+			}
+			if sawHeader {
+				return "", errMissingContentLength
+			}
+			// Skip blank lines between messages
+			continue
+		}
+		sawHeader = true
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return "", fmt.Errorf("malformed header line: %q", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 0 {
+				return "", fmt.Errorf("invalid Content-Length: %q", value)
+			}
+			if n > maxContentLength {
+				return "", fmt.Errorf("message length %d exceeds the maximum of %d bytes", n, maxContentLength)
+			}
+			length = n
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(m.reader, body); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+	return string(body), nil
+}
+
+// writeMessage writes a message with the given framing.
+func writeMessage(writer io.Writer, message []byte, framing Framing) error {
+	if framing == FramingContentLength {
+		if _, err := fmt.Fprintf(writer, "Content-Length: %d\r\n\r\n", len(message)); err != nil {
+			return fmt.Errorf("error writing headers: %w", err)
+		}
+		n, err := writer.Write(message)
+		if err != nil {
+			return fmt.Errorf("error writing response (%d bytes): %w", n, err)
+		}
+		return nil
+	}
+
+	n, err := writer.Write(message)
+	if err != nil {
+		return fmt.Errorf("error writing response (%d bytes): %w", n, err)
+	}
+
+	// Add a newline
+	_, err = writer.Write([]byte("\n"))
+	if err != nil {
+		return fmt.Errorf("error writing newline: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package stdio
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/server"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/interfaces/rest"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readAll reads messages until the reader fails and returns them with the
+// error that stopped it.
+func readAll(r *messageReader) ([]string, error) {
+	var messages []string
+	for {
+		message, err := r.readMessage()
+		if err != nil {
+			return messages, err
+		}
+		messages = append(messages, message)
+	}
+}
+
+func TestMessageReader(t *testing.T) {
+	ping := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+	list := `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`
+	framed := func(body string) string {
+		return "Content-Length: " + strconv.Itoa(len(body)) + "\r\n\r\n" + body
+	}
+
+	tests := []struct {
+		name        string
+		framing     Framing
+		input       string
+		want        []string
+		wantErr     error
+		wantErrText string
+		wantFraming Framing
+	}{
+		{
+			name:        "auto detects newlines",
+			input:       ping + "\n" + list + "\n",
+			want:        []string{ping + "\n", list + "\n"},
+			wantErr:     io.EOF,
+			wantFraming: FramingNewline,
+		},
+		{
+			name:        "final line without newline",
+			input:       "\n  " + ping,
+			want:        []string{ping},
+			wantErr:     io.EOF,
+			wantFraming: FramingNewline,
+		},
+		{
+			name:        "auto detects headers",
+			input:       framed(ping) + framed(list),
+			want:        []string{ping, list},
+			wantErr:     io.EOF,
+			wantFraming: FramingContentLength,
+		},
+		{
+			name:        "extra headers, any case, blank lines between messages",
+			input:       "content-length: " + strconv.Itoa(len(ping)) + "\r\nContent-Type: application/vscode-jsonrpc; charset=utf-8\r\n\r\n" + ping + "\r\n\r\n" + framed(list),
+			want:        []string{ping, list},
+			wantErr:     io.EOF,
+			wantFraming: FramingContentLength,
+		},
+		{
+			name:        "bare newlines after headers",
+			input:       "Content-Length: " + strconv.Itoa(len(ping)) + "\n\n" + ping,
+			want:        []string{ping},
+			wantErr:     io.EOF,
+			wantFraming: FramingContentLength,
+		},
+		{
+			name:        "forced header framing",
+			framing:     FramingContentLength,
+			input:       framed(`[` + ping + `]`),
+			want:        []string{`[` + ping + `]`},
+			wantErr:     io.EOF,
+			wantFraming: FramingContentLength,
+		},
+		{
+			name:        "missing Content-Length",
+			input:       "Content-Type: application/json\r\n\r\n" + ping,
+			wantErr:     errMissingContentLength,
+			wantFraming: FramingContentLength,
+		},
+		{
+			name:        "malformed header",
+			input:       "Content-Length 12\r\n\r\n",
+			wantErrText: "malformed header line",
+			wantFraming: FramingContentLength,
+		},
+		{
+			name:        "negative length",
+			input:       "Content-Length: -1\r\n\r\n",
+			wantErrText: "invalid Content-Length",
+			wantFraming: FramingContentLength,
+		},
+		{
+			name:        "oversized message",
+			input:       "Content-Length: 999999999\r\n\r\n",
+			wantErrText: "exceeds the maximum",
+			wantFraming: FramingContentLength,
+		},
+		{
+			name:        "truncated body",
+			input:       "Content-Length: 100\r\n\r\n" + ping,
+			wantErr:     io.ErrUnexpectedEOF,
+			wantFraming: FramingContentLength,
+		},
+		{
+			name:        "truncated headers",
+			input:       framed(ping) + "Content-Length: 10",
+			want:        []string{ping},
+			wantErr:     io.ErrUnexpectedEOF,
+			wantFraming: FramingContentLength,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newMessageReader(strings.NewReader(tt.input), tt.framing)
+			got, err := readAll(reader)
+			assert.Equal(t, tt.want, got)
+			if tt.wantErrText != "" {
+				assert.ErrorContains(t, err, tt.wantErrText)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantFraming, reader.framing)
+		})
+	}
+}
+
+func TestWriteMessage(t *testing.T) {
+	message := []byte(`{"jsonrpc":"2.0","id":7,"result":{}}`)
+
+	var newline bytes.Buffer
+	require.NoError(t, writeMessage(&newline, message, FramingNewline))
+	assert.Equal(t, string(message)+"\n", newline.String())
+
+	var headers bytes.Buffer
+	require.NoError(t, writeMessage(&headers, message, FramingContentLength))
+	assert.Equal(t, "Content-Length: 36\r\n\r\n"+string(message), headers.String())
+
+	// What is written with header framing reads back unchanged
+	read, err := newMessageReader(&headers, FramingAuto).readMessage()
+	require.NoError(t, err)
+	assert.Equal(t, string(message), read)
+}
+
+func TestListenAnswersInClientFraming(t *testing.T) {
+	service := usecases.NewServerService(usecases.ServerConfig{
+		Name:               "framing-test",
+		Version:            "0.1.0",
+		ResourceRepo:       server.NewInMemoryResourceRepository(),
+		ToolRepo:           server.NewInMemoryToolRepository(),
+		PromptRepo:         server.NewInMemoryPromptRepository(),
+		SessionRepo:        server.NewInMemorySessionRepository(),
+		NotificationSender: server.NewNotificationSender(JSONRPCVersion),
+	})
+	stdioServer := NewStdioServer(rest.NewMCPServer(service, ":0", rest.WithLogger(logging.Default())), WithLogger(logging.Default()))
+
+	ping := `{"jsonrpc":"2.0","id":"a","method":"ping"}`
+	input := "Content-Length: " + strconv.Itoa(len(ping)) + "\r\n\r\n" + ping
+	var output bytes.Buffer
+	require.NoError(t, stdioServer.Listen(context.Background(), strings.NewReader(input), &output))
+
+	require.True(t, strings.HasPrefix(output.String(), "Content-Length: "), "response not framed with headers: %q", output.String())
+	responses, err := readAll(newMessageReader(&output, FramingContentLength))
+	assert.ErrorIs(t, err, io.EOF)
+	require.Len(t, responses, 1)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":"a","result":{}}`, responses[0])
+}
@@ -2,50 +2,19 @@ package stdio
 
 import (
 	"context"
-	"fmt"
+	"os"
+	"time"
 
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
 )
 
 // WithToolHandler registers a custom handler function for a specific tool.
-// This allows you to override the default tool handling behavior.
+// The handler is registered with the server service, so the standard tools/call
+// dispatch invokes it alongside the handlers of other tools.
 func WithToolHandler(toolName string, handler func(ctx context.Context, params map[string]interface{}, session *domain.ClientSession) (interface{}, error)) StdioOption {
 	return func(s *StdioServer) {
-		if s.processor == nil {
-			s.processor = NewMessageProcessor(s.server, s.logger)
-		}
-
-		// Create an adapter that converts our handler function to a MethodHandlerFunc
-		adapter := MethodHandlerFunc(func(ctx context.Context, params interface{}, id interface{}) (interface{}, *domain.JSONRPCError) {
-			// Extract tool parameters
-			paramsMap, ok := params.(map[string]interface{})
-			if !ok {
-				return nil, &domain.JSONRPCError{
-					Code:    InvalidParamsCode,
-					Message: "Invalid params",
-				}
-			}
-
-			// Check if this is a call to our specific tool
-			nameParam, ok := paramsMap["name"].(string)
-			if !ok || nameParam != toolName {
-				// Let the default handler handle other tools
-				return nil, &domain.JSONRPCError{
-					Code:    MethodNotFoundCode,
-					Message: fmt.Sprintf("Tool handler mismatch: expected %s, got %s", toolName, nameParam),
-				}
-			}
-
-			// Get tool parameters - check both parameters and arguments fields
-			toolParams, ok := paramsMap["parameters"].(map[string]interface{})
-			if !ok {
-				// Try arguments field if parameters is not available
-				toolParams, ok = paramsMap["arguments"].(map[string]interface{})
-				if !ok {
-					toolParams = map[string]interface{}{}
-				}
-			}
-
+		// Create an adapter that converts our handler function to a service tool handler
+		adapter := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 			// Create a dummy session for now
 			session := &domain.ClientSession{
 				ID:        "stdio-session",
@@ -53,19 +22,43 @@ func WithToolHandler(toolName string, handler func(ctx context.Context, params m
 				Connected: true,
 			}
 
-			// Call the handler
-			result, err := handler(ctx, toolParams, session)
-			if err != nil {
-				return nil, &domain.JSONRPCError{
-					Code:    InternalErrorCode,
-					Message: err.Error(),
-				}
+			return handler(ctx, args, session)
+		}
+
+		s.server.GetService().RegisterToolHandler(toolName, adapter)
+	}
+}
+
+// WithEnvContext reads the named environment variables once, when the server
+// is created, and makes them available to handlers through EnvFromContext.
+// Variables that are not set are omitted. This suits passing secrets and
+// configuration to a server launched by a client such as a desktop app.
+func WithEnvContext(keys ...string) StdioOption {
+	return func(s *StdioServer) {
+		if s.env == nil {
+			s.env = make(map[string]string, len(keys))
+		}
+		for _, key := range keys {
+			if value, ok := os.LookupEnv(key); ok {
+				s.env[key] = value
 			}
+		}
+	}
+}
 
-			return result, nil
-		})
+// EnvFromContext returns the value of an environment variable captured with
+// WithEnvContext. It reports false if the variable was not captured or not set.
+func EnvFromContext(ctx context.Context, key string) (string, bool) {
+	return domain.EnvFromContext(ctx, key)
+}
 
-		// Override the tools/call handler with our custom one
-		s.processor.RegisterHandler("tools/call", adapter)
+// WithShutdownTimeout lets messages being processed when the server is
+// stopped, for example by SIGTERM, finish and write their responses for up to
+// timeout before Listen returns. Reading new input stops immediately, and
+// messages still running after the timeout are canceled. By default messages
+// in flight are canceled as soon as the server is stopped.
+func WithShutdownTimeout(timeout time.Duration) StdioOption {
+	return func(s *StdioServer) {
+		s.shutdownTimeout = timeout
 	}
 }
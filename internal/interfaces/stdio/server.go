@@ -2,32 +2,39 @@
 package stdio
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/signal"
+	"runtime/debug"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/interfaces/rest"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/usecases"
 )
 
 // Constants for JSON-RPC
 const (
 	JSONRPCVersion = "2.0"
 
-	// Error codes
-	ParseErrorCode     = -32700
-	InvalidParamsCode  = -32602
-	MethodNotFoundCode = -32601
-	InternalErrorCode  = -32603
+	// Error codes, see the domain package
+	ParseErrorCode       = domain.ParseErrorCode
+	InvalidRequestCode   = domain.InvalidRequestCode
+	InvalidParamsCode    = domain.InvalidParamsCode
+	MethodNotFoundCode   = domain.MethodNotFoundCode
+	InternalErrorCode    = domain.InternalErrorCode
+	RateLimitedCode      = domain.RateLimitedCode
+	ServerBusyCode       = domain.ServerErrorCode
+	ResourceNotFoundCode = domain.ResourceNotFoundCode
 )
 
 // StdioContextFunc is a function that takes an existing context and returns
@@ -44,6 +51,17 @@ type StdioServer struct {
 	logger      *logging.Logger
 	contextFunc StdioContextFunc
 	processor   *MessageProcessor
+	// env holds the environment variables captured by WithEnvContext
+	env map[string]string
+	// framing is how messages are delimited on the streams
+	framing Framing
+	// writeMu serializes writes to stdout
+	writeMu sync.Mutex
+	// shutdownTimeout bounds the wait for in-flight messages, see
+	// WithShutdownTimeout
+	shutdownTimeout time.Duration
+	// inflight tracks the messages being processed
+	inflight sync.WaitGroup
 }
 
 // StdioOption defines a function type for configuring StdioServer
@@ -117,7 +135,10 @@ func NewStdioServer(server *rest.MCPServer, opts ...StdioOption) *StdioServer {
 }
 
 // Listen starts listening for JSON-RPC messages on the provided input and writes responses to the provided output.
-// It runs until the context is cancelled or an error occurs.
+// It runs until the context is cancelled or an error occurs. A read of the input
+// that is in progress when the context is cancelled is abandoned. Messages are
+// processed in order, but input is read while a request is processed so a
+// notifications/cancelled can cancel it.
 // Returns an error if there are issues with reading input or writing output.
 func (s *StdioServer) Listen(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
 	// Add in any custom context
@@ -125,88 +146,223 @@ func (s *StdioServer) Listen(ctx context.Context, stdin io.Reader, stdout io.Wri
 		ctx = s.contextFunc(ctx)
 	}
 
-	reader := bufio.NewReader(stdin)
+	if s.env != nil {
+		ctx = domain.WithEnv(ctx, s.env)
+	}
+
+	// The stdio stream is a single session; its store lives as long as the stream
+	store := domain.NewSessionStore()
+	defer store.Clear()
+	ctx = domain.WithSessionStore(ctx, store)
+	ctx = domain.WithSessionInfo(ctx, domain.NewSessionInfoHolder(domain.StdioSessionID, ""))
+
+	reader := newMessageReader(stdin, s.framing)
+	ctx = domain.WithClientLog(ctx, s.clientLog(stdout, reader))
+
+	// Drop session state, such as resource subscriptions, when the stream ends
+	defer s.server.GetService().EndSession(domain.StdioSessionID)
+	forwarding := false
+
+	// With a shutdown timeout, messages in flight outlive the cancellation
+	// of ctx until the timeout elapses
+	processCtx := ctx
+	if s.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		processCtx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+		defer cancel()
+	}
 
-	// Process messages serially to avoid concurrent writes to stdout
+	// Read in the background so cancellation stops reading new input, and
+	// so a notifications/cancelled is read while a request is processed
+	reads := make(chan readResult)
+	stopReading := make(chan struct{})
+	defer close(stopReading)
+	go func(reads chan<- readResult) {
+		for {
+			line, err := reader.readMessage()
+			select {
+			case reads <- readResult{line: line, err: err}:
+			case <-stopReading:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}(reads)
+
+	// Process messages one at a time and in order. Messages read while one
+	// is processed wait their turn, except cancellations, which must reach
+	// the request being processed.
+	var (
+		queue    []string
+		done     chan error
+		inputErr error
+	)
 	for {
+		if done == nil && len(queue) > 0 {
+			done = s.startMessage(processCtx, queue[0], stdout, reader.framing)
+			queue = queue[1:]
+		}
+		if done == nil && reads == nil {
+			return inputErr
+		}
+
 		select {
 		case <-ctx.Done():
+			s.waitInFlight()
 			return ctx.Err()
-		default:
-			// Read a line from stdin
-			line, err := reader.ReadString('\n')
+		case err := <-done:
+			done = nil
 			if err != nil {
-				if err == io.EOF {
+				return err
+			}
+		case read := <-reads:
+			if read.err != nil {
+				// Finish the messages already read, then stop
+				if read.err == io.EOF {
 					s.logger.Info("Input stream closed")
-					return nil
+				} else {
+					s.logger.Error("Error reading input", logging.Fields{"error": read.err})
+					inputErr = read.err
 				}
-				s.logger.Error("Error reading input", logging.Fields{"error": err})
-				return err
+				reads = nil
+				continue
 			}
 
-			// Process message and get response
-			response, processErr := s.processor.Process(ctx, line)
+			// Forward server notifications once the framing is known
+			if !forwarding {
+				forwarding = true
+				stop := s.forwardNotifications(stdout, reader.framing)
+				defer stop()
+			}
 
-			// Handle processing errors
-			if processErr != nil {
-				if isTerminalError(processErr) {
-					return processErr
+			if done != nil && isCancellation(read.line) {
+				if err := s.processMessage(processCtx, read.line, stdout, reader.framing); err != nil {
+					return err
 				}
+				continue
+			}
+			queue = append(queue, read.line)
+		}
+	}
+}
 
-				s.logger.Error("Error processing message", logging.Fields{"error": processErr})
+// startMessage processes a message in the background. The returned channel
+// receives the outcome, see processMessage.
+func (s *StdioServer) startMessage(ctx context.Context, line string, stdout io.Writer, framing Framing) chan error {
+	done := make(chan error, 1)
+	s.inflight.Add(1)
+	go func() {
+		defer s.inflight.Done()
+		done <- s.processMessage(ctx, line, stdout, framing)
+	}()
+	return done
+}
 
-				// If we have a response (error response), send it
-				if response != nil {
-					if err := s.writeResponse(response, stdout); err != nil {
-						s.logger.Error("Error writing error response", logging.Fields{"error": err})
-						if isTerminalError(err) {
-							return err
-						}
-					}
-				}
+// readResult is the outcome of reading one message from the input.
+type readResult struct {
+	line string
+	err  error
+}
 
-				// Continue processing next messages for non-terminal errors
-				continue
-			}
+// processMessage processes one message and writes its response. It returns
+// only terminal errors, which stop the server.
+func (s *StdioServer) processMessage(ctx context.Context, line string, stdout io.Writer, framing Framing) error {
+	response, processErr := s.processor.Process(ctx, line)
+	if processErr != nil {
+		if isTerminalError(processErr) {
+			return processErr
+		}
+		s.logger.Error("Error processing message", logging.Fields{"error": processErr})
+	}
 
-			// Send successful response if we have one
-			if response != nil {
-				if err := s.writeResponse(response, stdout); err != nil {
-					s.logger.Error("Error writing response", logging.Fields{"error": err})
-					if isTerminalError(err) {
-						return err
-					}
-				}
-			}
+	// Error responses are sent as well; the client is waiting for them
+	if response == nil {
+		return nil
+	}
+	if err := s.writeResponse(response, stdout, framing); err != nil {
+		s.logger.Error("Error writing response", logging.Fields{"error": err})
+		if isTerminalError(err) {
+			return err
 		}
 	}
+	return nil
 }
 
-// writeResponse marshals and writes a JSON-RPC response message followed by a newline.
+// waitInFlight waits for the messages being processed to write their
+// responses, for at most the shutdown timeout if one is set.
+func (s *StdioServer) waitInFlight() {
+	if s.shutdownTimeout <= 0 {
+		s.inflight.Wait()
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(s.shutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		s.logger.Warn("Shutdown timeout elapsed with messages in flight", logging.Fields{"timeout": s.shutdownTimeout.String()})
+	}
+}
+
+// writeResponse marshals and writes a JSON-RPC response message with the
+// framing of the input stream.
 // Returns an error if marshaling or writing fails.
-func (s *StdioServer) writeResponse(response interface{}, writer io.Writer) error {
+func (s *StdioServer) writeResponse(response interface{}, writer io.Writer, framing Framing) error {
 	responseBytes, err := json.Marshal(response)
 	if err != nil {
 		return fmt.Errorf("error marshaling response: %w", err)
 	}
 
-	// Write response
-	n, err := writer.Write(responseBytes)
-	if err != nil {
-		return fmt.Errorf("error writing response (%d bytes): %w", n, err)
-	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return writeMessage(writer, responseBytes, framing)
+}
 
-	// Add a newline
-	_, err = writer.Write([]byte("\n"))
-	if err != nil {
-		return fmt.Errorf("error writing newline: %w", err)
+// clientLog returns the function that writes log messages from tool handlers
+// to the client as notifications/message. Messages below the server's log
+// level are dropped.
+func (s *StdioServer) clientLog(writer io.Writer, reader *messageReader) domain.ClientLogFunc {
+	return func(ctx context.Context, level, logger string, data interface{}) error {
+		logLevel, ok := logging.LevelFromMCP(level)
+		if !ok || !s.logger.Enabled(logLevel) {
+			return nil
+		}
+		notification := domain.NewLogMessageNotification(level, logger, data)
+		return s.writeResponse(notification.ToJSONRPC(JSONRPCVersion), writer, reader.framing)
 	}
+}
 
-	return nil
+// forwardNotifications writes the notifications the server sends to the
+// stdio session, such as notifications/resources/updated, to the client. The
+// returned function stops forwarding.
+func (s *StdioServer) forwardNotifications(writer io.Writer, framing Framing) func() {
+	notifications, unregister := s.server.RegisterNotificationSession(domain.StdioSessionID)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for notification := range notifications {
+			if err := s.writeResponse(notification, writer, framing); err != nil {
+				s.logger.Error("Error writing notification", logging.Fields{"method": notification.Method, "error": err})
+			}
+		}
+	}()
+	return func() {
+		unregister()
+		<-done
+	}
 }
 
 // ServeStdio is a convenience function that creates and starts a StdioServer with os.Stdin and os.Stdout.
-// It sets up signal handling for graceful shutdown on SIGTERM and SIGINT.
+// It sets up signal handling for graceful shutdown on SIGTERM and SIGINT; see
+// WithShutdownTimeout for letting in-flight messages finish.
 // Returns an error if the server encounters any issues during operation.
 func ServeStdio(server *rest.MCPServer, opts ...StdioOption) error {
 	s := NewStdioServer(server, opts...)
@@ -236,11 +392,20 @@ func ServeStdio(server *rest.MCPServer, opts ...StdioOption) error {
 	return nil
 }
 
+// requestTimeoutKey is the context key for the timeout applied to a message.
+type requestTimeoutKey struct{}
+
 // MessageProcessor handles JSON-RPC message processing
 type MessageProcessor struct {
 	server   *rest.MCPServer
 	logger   *logging.Logger
 	handlers map[string]MethodHandler
+	localeMu sync.RWMutex
+	locale   string // Locale the client requested at initialization
+	// inflight holds the cancel functions of the requests being processed,
+	// see notifications/cancelled
+	inflightMu sync.Mutex
+	inflight   map[domain.RequestID]context.CancelFunc
 }
 
 // MethodHandler defines the interface for JSON-RPC method handlers
@@ -262,6 +427,7 @@ func NewMessageProcessor(server *rest.MCPServer, logger *logging.Logger) *Messag
 		server:   server,
 		logger:   logger,
 		handlers: make(map[string]MethodHandler),
+		inflight: make(map[domain.RequestID]context.CancelFunc),
 	}
 
 	// Register standard handlers
@@ -269,6 +435,13 @@ func NewMessageProcessor(server *rest.MCPServer, logger *logging.Logger) *Messag
 	p.RegisterHandler("ping", MethodHandlerFunc(p.handlePing))
 	p.RegisterHandler("tools/list", MethodHandlerFunc(p.handleToolsList))
 	p.RegisterHandler("tools/call", MethodHandlerFunc(p.handleToolsCall))
+	p.RegisterHandler("prompts/get", MethodHandlerFunc(p.handlePromptsGet))
+	p.RegisterHandler("resources/read", MethodHandlerFunc(p.handleResourcesRead))
+	p.RegisterHandler("resources/templates/list", MethodHandlerFunc(p.handleResourceTemplatesList))
+	p.RegisterHandler("resources/subscribe", MethodHandlerFunc(p.handleResourcesSubscribe))
+	p.RegisterHandler("resources/unsubscribe", MethodHandlerFunc(p.handleResourcesUnsubscribe))
+	p.RegisterHandler("completion/complete", MethodHandlerFunc(p.handleCompletionComplete))
+	p.RegisterHandler("logging/setLevel", MethodHandlerFunc(p.handleLoggingSetLevel))
 
 	return p
 }
@@ -286,26 +459,50 @@ func (p *MessageProcessor) Process(ctx context.Context, message string) (interfa
 		return nil, nil // Skip empty messages
 	}
 
-	// Create a timeout context for message processing
-	msgCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	// Parse the message as a JSON-RPC request
+	var baseMessage domain.JSONRPCRequest
+	if err := json.Unmarshal([]byte(message), &baseMessage); err != nil {
+		return createErrorResponse(nil, ParseErrorCode, "Parse error"), nil
+	}
+
+	// Validate JSON-RPC version, as the HTTP transport does
+	if baseMessage.JSONRPC != JSONRPCVersion {
+		return createErrorResponse(baseMessage.ID, InvalidRequestCode, "Invalid JSON-RPC version"), nil
+	}
+
+	// Apply the server's request timeout, or the tool's own timeout for tool
+	// calls, as the HTTP transport does
+	timeout := p.server.RequestTimeout(ctx, baseMessage)
+	msgCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
+	msgCtx = context.WithValue(msgCtx, requestTimeoutKey{}, timeout)
 
-	// Parse the message as a JSON-RPC request
-	var baseMessage struct {
-		JSONRPC string      `json:"jsonrpc"`
-		ID      interface{} `json:"id"`
-		Method  string      `json:"method"`
-		Params  interface{} `json:"params"`
+	// Reject requests that are not allowed during maintenance, as the HTTP
+	// transport does
+	if rpcErr := p.server.MaintenanceRejection(baseMessage.Method); rpcErr != nil {
+		return createErrorResponseFromJSONRPCError(baseMessage.ID, rpcErr), nil
 	}
 
-	if err := json.Unmarshal([]byte(message), &baseMessage); err != nil {
-		return createErrorResponse(nil, ParseErrorCode, "Parse error"), nil
+	// Let the server's interceptors reject the message before it is dispatched
+	if rpcErr := p.intercept(msgCtx, baseMessage.Method, message); rpcErr != nil {
+		if baseMessage.ID == nil {
+			return nil, nil
+		}
+		return createErrorResponseFromJSONRPCError(baseMessage.ID, rpcErr), nil
 	}
 
 	// Check if this is a notification (no ID field)
 	// Notifications don't require responses
 	if baseMessage.ID == nil && strings.HasPrefix(baseMessage.Method, "notifications/") {
 		p.logger.Info("Received notification", logging.Fields{"method": baseMessage.Method})
+		switch baseMessage.Method {
+		case "notifications/initialized":
+			if holder, ok := domain.SessionInfoHolderFromContext(msgCtx); ok {
+				holder.MarkReady()
+			}
+		case "notifications/cancelled":
+			p.cancelRequest(baseMessage.Params)
+		}
 		// Process notification but don't return a response
 		return nil, nil
 	}
@@ -313,6 +510,11 @@ func (p *MessageProcessor) Process(ctx context.Context, message string) (interfa
 	// Find handler for the method
 	handler, exists := p.handlers[baseMessage.Method]
 
+	// Fall back to the custom method handlers registered on the server
+	if !exists {
+		handler, exists = p.customMethodHandler(baseMessage.Method)
+	}
+
 	// Handle notifications with a prefix
 	if !exists && strings.HasPrefix(baseMessage.Method, "notifications/") {
 		p.logger.Info("Processed notification", logging.Fields{"method": baseMessage.Method})
@@ -328,8 +530,34 @@ func (p *MessageProcessor) Process(ctx context.Context, message string) (interfa
 		), nil
 	}
 
+	// Track requests by ID so clients can cancel them. Reusing the ID of a
+	// request still in flight would make the two responses ambiguous.
+	if baseMessage.ID != nil {
+		var cancelRequest context.CancelCauseFunc
+		msgCtx, cancelRequest = context.WithCancelCause(msgCtx)
+		defer cancelRequest(nil)
+		untrack, ok := p.trackRequest(baseMessage.ID, func() { cancelRequest(errRequestCancelled) })
+		if !ok {
+			p.logger.Warn("Duplicate request ID", logging.Fields{"method": baseMessage.Method, "id": baseMessage.ID})
+			return createErrorResponse(baseMessage.ID, InvalidRequestCode, "Invalid Request: duplicate request id"), nil
+		}
+		defer untrack()
+	}
+
 	// Execute the method handler
-	result, jsonRpcErr := handler.Handle(msgCtx, baseMessage.Params, baseMessage.ID)
+	result, jsonRpcErr := p.handle(msgCtx, handler, baseMessage)
+
+	// Skip the response if the connection was closed while it was being built
+	if err := ctx.Err(); err != nil {
+		p.logger.Info("Connection closed, dropping response", logging.Fields{"method": baseMessage.Method, "error": err})
+		return nil, nil
+	}
+
+	// Requests the client cancelled are not answered
+	if errors.Is(context.Cause(msgCtx), errRequestCancelled) {
+		p.logger.Info("Request cancelled by the client, dropping response", logging.Fields{"method": baseMessage.Method, "id": baseMessage.ID})
+		return nil, nil
+	}
 	if jsonRpcErr != nil {
 		return createErrorResponseFromJSONRPCError(baseMessage.ID, jsonRpcErr), nil
 	}
@@ -338,28 +566,77 @@ func (p *MessageProcessor) Process(ctx context.Context, message string) (interfa
 	return createSuccessResponse(baseMessage.ID, result), nil
 }
 
+// intercept runs the server's request interceptors on a message.
+func (p *MessageProcessor) intercept(ctx context.Context, method, message string) *domain.JSONRPCError {
+	var raw struct {
+		Params json.RawMessage `json:"params"`
+	}
+	_ = json.Unmarshal([]byte(message), &raw)
+	return p.server.InterceptRequest(ctx, method, raw.Params)
+}
+
+// handle runs a method handler, turning a panic into an internal error so a
+// buggy handler cannot take down the server.
+func (p *MessageProcessor) handle(ctx context.Context, handler MethodHandler, request domain.JSONRPCRequest) (result interface{}, rpcErr *domain.JSONRPCError) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Error("Method handler panicked", logging.Fields{"method": request.Method, "panic": fmt.Sprint(r), "stack": string(debug.Stack())})
+			result, rpcErr = nil, &domain.JSONRPCError{Code: InternalErrorCode, Message: "Internal error"}
+		}
+	}()
+	return handler.Handle(ctx, request.Params, request.ID)
+}
+
+// customMethodHandler adapts the custom handler the server has registered
+// for method, see rest.MCPServer.AddMethodHandler.
+func (p *MessageProcessor) customMethodHandler(method string) (MethodHandler, bool) {
+	if !p.server.HasMethodHandler(method) {
+		return nil, false
+	}
+	return MethodHandlerFunc(func(ctx context.Context, params interface{}, id interface{}) (interface{}, *domain.JSONRPCError) {
+		var raw json.RawMessage
+		if params != nil {
+			encoded, err := json.Marshal(params)
+			if err != nil {
+				return nil, &domain.JSONRPCError{Code: InvalidParamsCode, Message: "Invalid params"}
+			}
+			raw = encoded
+		}
+		result, rpcErr, ok := p.server.CallMethodHandler(ctx, method, raw)
+		if !ok {
+			return nil, &domain.JSONRPCError{Code: MethodNotFoundCode, Message: fmt.Sprintf("Method '%s' not found", method)}
+		}
+		return result, rpcErr
+	}), true
+}
+
 // Method handlers
 
 func (p *MessageProcessor) handleInitialize(ctx context.Context, params interface{}, id interface{}) (interface{}, *domain.JSONRPCError) {
-	name, version, instructions := p.server.GetServerInfo()
+	if holder, ok := domain.SessionInfoHolderFromContext(ctx); ok {
+		holder.SetClientInfo(params)
+	}
+	if locale := domain.LocaleFromInitializeParams(params); locale != "" {
+		p.localeMu.Lock()
+		p.locale = locale
+		p.localeMu.Unlock()
+	}
+
+	requested := domain.ProtocolVersionFromInitializeParams(params)
+	protocolVersion, ok := domain.NegotiateProtocolVersion(requested)
+	if !ok {
+		p.logger.Warn("Unsupported protocol version requested", logging.Fields{"requested": requested, "protocolVersion": protocolVersion})
+	}
+
+	name, version, _ := p.server.GetServerInfo()
+	instructions := p.server.GetService().Instructions(ctx)
 	result := map[string]interface{}{
-		"protocolVersion": "2024-11-05",
+		"protocolVersion": protocolVersion,
 		"serverInfo": map[string]string{
 			"name":    name,
 			"version": version,
 		},
-		"capabilities": map[string]interface{}{
-			"resources": map[string]bool{
-				"listChanged": true,
-			},
-			"tools": map[string]bool{
-				"listChanged": true,
-			},
-			"prompts": map[string]bool{
-				"listChanged": true,
-			},
-			"logging": struct{}{},
-		},
+		"capabilities": p.server.Capabilities(ctx),
 	}
 
 	if instructions != "" {
@@ -370,7 +647,7 @@ func (p *MessageProcessor) handleInitialize(ctx context.Context, params interfac
 }
 
 func (p *MessageProcessor) handlePing(ctx context.Context, params interface{}, id interface{}) (interface{}, *domain.JSONRPCError) {
-	return struct{}{}, nil
+	return p.server.PingResult(), nil
 }
 
 func (p *MessageProcessor) handleToolsList(ctx context.Context, params interface{}, id interface{}) (interface{}, *domain.JSONRPCError) {
@@ -383,38 +660,24 @@ func (p *MessageProcessor) handleToolsList(ctx context.Context, params interface
 		}
 	}
 
+	p.localeMu.RLock()
+	locale := p.locale
+	p.localeMu.RUnlock()
+
 	// Convert domain tools to response format
 	toolList := make([]map[string]interface{}, len(tools))
 	for i, tool := range tools {
-		// Format parameters as an object with properties
-		parametersObj := make(map[string]interface{})
-		parametersObj["type"] = "object"
-
-		properties := make(map[string]interface{})
-		required := []string{}
-
-		for _, param := range tool.Parameters {
-			paramObj := map[string]interface{}{
-				"type":        param.Type,
-				"description": param.Description,
-			}
-			properties[param.Name] = paramObj
-
-			if param.Required {
-				required = append(required, param.Name)
-			}
-		}
-
-		parametersObj["properties"] = properties
-		if len(required) > 0 {
-			parametersObj["required"] = required
-		}
-
 		// Build tool object
 		toolList[i] = map[string]interface{}{
 			"name":        tool.Name,
-			"description": tool.Description,
-			"inputSchema": parametersObj,
+			"description": tool.DescriptionFor(locale),
+			"inputSchema": tool.InputSchema(),
+		}
+		if outputSchema := tool.OutputJSONSchema(); outputSchema != nil {
+			toolList[i]["outputSchema"] = outputSchema
+		}
+		if annotations := tool.AnnotationsJSON(); annotations != nil {
+			toolList[i]["annotations"] = annotations
 		}
 	}
 
@@ -475,7 +738,7 @@ func (p *MessageProcessor) handleToolsCall(ctx context.Context, params interface
 
 	if !toolFound {
 		return nil, &domain.JSONRPCError{
-			Code:    MethodNotFoundCode,
+			Code:    InvalidParamsCode,
 			Message: fmt.Sprintf("Tool not found: %s", toolName),
 		}
 	}
@@ -498,67 +761,351 @@ func (p *MessageProcessor) handleToolsCall(ctx context.Context, params interface
 		}
 	}
 
-	// Handle different tool types using a strategy pattern
-	var toolResult interface{}
-	var toolErr error
+	// Dispatch the call to the registered tool handler
+	toolResult, err := p.server.GetService().CallTool(ctx, toolName, toolParams)
+	if err != nil {
+		var toolErr *domain.ToolError
+		if errors.As(err, &toolErr) {
+			return nil, &domain.JSONRPCError{
+				Code:    toolErr.JSONRPCCode(),
+				Message: toolErr.Message,
+				Data:    toolErr.Data,
+			}
+		}
 
-	// Handle all echo-related tools
-	if strings.Contains(strings.ToLower(toolName), "echo") {
-		toolResult, toolErr = handleEchoTool(toolParams)
-	} else {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return nil, &domain.JSONRPCError{
+				Code:    InvalidParamsCode,
+				Message: fmt.Sprintf("Invalid params: %v", err),
+			}
+		}
+
+		if errors.Is(err, context.Canceled) {
+			return nil, &domain.JSONRPCError{
+				Code:    InternalErrorCode,
+				Message: "Request cancelled",
+			}
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			timeout, _ := ctx.Value(requestTimeoutKey{}).(time.Duration)
+			return nil, &domain.JSONRPCError{
+				Code:    InternalErrorCode,
+				Message: domain.NewTimeoutError(timeout).Error(),
+				Data:    map[string]interface{}{"timeoutMs": timeout.Milliseconds()},
+			}
+		}
+
+		var rateLimitErr *domain.RateLimitError
+		if errors.As(err, &rateLimitErr) && (rateLimitErr.Scope == domain.RateLimitScopeSession || rateLimitErr.Scope == domain.RateLimitScopeServer) {
+			return nil, &domain.JSONRPCError{
+				Code:    ServerBusyCode,
+				Message: "Rate limit exceeded",
+				Data:    map[string]interface{}{"scope": rateLimitErr.Scope, "retryAfterMs": rateLimitErr.RetryAfter.Milliseconds()},
+			}
+		}
+		if errors.As(err, &rateLimitErr) {
+			return nil, &domain.JSONRPCError{
+				Code:    RateLimitedCode,
+				Message: fmt.Sprintf("Rate limit exceeded for tool: %s", toolName),
+			}
+		}
+
+		var busyErr *domain.ServerBusyError
+		if errors.As(err, &busyErr) {
+			return nil, &domain.JSONRPCError{
+				Code:    ServerBusyCode,
+				Message: "Server busy",
+				Data:    map[string]interface{}{"maxConcurrentCalls": busyErr.Limit},
+			}
+		}
+
+		var handlerErr *usecases.ToolHandlerNotFoundError
+		if errors.As(err, &handlerErr) {
+			return nil, &domain.JSONRPCError{
+				Code:    InternalErrorCode,
+				Message: fmt.Sprintf("Tool '%s' is registered but has no implementation", toolName),
+			}
+		}
+
+		var panicErr *domain.PanicError
+		if errors.As(err, &panicErr) {
+			p.logger.Error("Tool handler panicked", logging.Fields{"tool": toolName, "panic": fmt.Sprint(panicErr.Value), "stack": string(panicErr.Stack)})
+			return nil, &domain.JSONRPCError{
+				Code:    InternalErrorCode,
+				Message: "Internal error",
+			}
+		}
 		return nil, &domain.JSONRPCError{
 			Code:    InternalErrorCode,
-			Message: fmt.Sprintf("Tool '%s' is registered but has no implementation", toolName),
+			Message: fmt.Sprintf("Tool execution error: %v", err),
 		}
 	}
 
-	if toolErr != nil {
+	// Catch results the handler cannot serialize before writing the response
+	if _, err := json.Marshal(toolResult); err != nil {
+		p.logger.Error("Tool returned unserializable result", logging.Fields{"tool": toolName, "type": fmt.Sprintf("%T", toolResult), "error": err})
 		return nil, &domain.JSONRPCError{
 			Code:    InternalErrorCode,
-			Message: toolErr.Error(),
+			Message: "Tool returned unserializable result",
+			Data:    map[string]interface{}{"tool": toolName},
 		}
 	}
 
 	return toolResult, nil
 }
 
-// Handle echo tool types
-func handleEchoTool(params map[string]interface{}) (interface{}, error) {
-	var message string
+func (p *MessageProcessor) handleResourceTemplatesList(ctx context.Context, params interface{}, id interface{}) (interface{}, *domain.JSONRPCError) {
+	templates, err := p.server.GetService().ListResourceTemplates(ctx)
+	if err != nil {
+		return nil, &domain.JSONRPCError{
+			Code:    InternalErrorCode,
+			Message: fmt.Sprintf("Internal error: %v", err),
+		}
+	}
+
+	return map[string]interface{}{
+		"resourceTemplates": domain.ResourceTemplatesToMCP(templates),
+	}, nil
+}
 
-	// Extract message parameter
-	messageVal, exists := params["message"]
-	if !exists || messageVal == nil {
-		return nil, fmt.Errorf("missing 'message' parameter")
+func (p *MessageProcessor) handleResourcesRead(ctx context.Context, params interface{}, id interface{}) (interface{}, *domain.JSONRPCError) {
+	// Extract parameters
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return nil, &domain.JSONRPCError{
+			Code:    InvalidParamsCode,
+			Message: "Invalid params",
+		}
 	}
 
-	// Convert to string based on type
-	switch v := messageVal.(type) {
-	case string:
-		message = v
-	case float64, int, int64, float32:
-		message = fmt.Sprintf("%v", v)
-	default:
-		// Try JSON conversion for complex types
-		jsonBytes, err := json.Marshal(v)
-		if err != nil {
-			message = fmt.Sprintf("%v", v)
-		} else {
-			message = string(jsonBytes)
+	uri, ok := paramsMap["uri"].(string)
+	if !ok || uri == "" {
+		return nil, &domain.JSONRPCError{
+			Code:    InvalidParamsCode,
+			Message: "Missing or invalid 'uri' parameter",
+		}
+	}
+
+	contents, err := p.server.GetService().ReadResource(ctx, uri)
+	if err != nil {
+		var notFoundErr *domain.ResourceNotFoundError
+		switch {
+		case errors.As(err, &notFoundErr):
+			return nil, &domain.JSONRPCError{
+				Code:    ResourceNotFoundCode,
+				Message: fmt.Sprintf("Resource not found: %s", uri),
+			}
+		case errors.Is(err, domain.ErrNoContentProvider):
+			return nil, &domain.JSONRPCError{
+				Code:    MethodNotFoundCode,
+				Message: "resources/read not supported: no content provider configured",
+			}
+		default:
+			return nil, &domain.JSONRPCError{
+				Code:    InternalErrorCode,
+				Message: fmt.Sprintf("Internal error: %v", err),
+			}
+		}
+	}
+
+	entries := make([]interface{}, len(contents))
+	for i, c := range contents {
+		entries[i] = c.ToMCP()
+	}
+
+	return map[string]interface{}{
+		"contents": entries,
+	}, nil
+}
+
+func (p *MessageProcessor) handleResourcesSubscribe(ctx context.Context, params interface{}, id interface{}) (interface{}, *domain.JSONRPCError) {
+	return p.handleSubscription(ctx, params, true)
+}
+
+func (p *MessageProcessor) handleResourcesUnsubscribe(ctx context.Context, params interface{}, id interface{}) (interface{}, *domain.JSONRPCError) {
+	return p.handleSubscription(ctx, params, false)
+}
+
+// handleSubscription subscribes the stdio session to updates of a resource or
+// removes the subscription.
+func (p *MessageProcessor) handleSubscription(ctx context.Context, params interface{}, subscribe bool) (interface{}, *domain.JSONRPCError) {
+	paramsMap, _ := params.(map[string]interface{})
+	uri, ok := paramsMap["uri"].(string)
+	if !ok || uri == "" {
+		return nil, &domain.JSONRPCError{
+			Code:    InvalidParamsCode,
+			Message: "Missing or invalid 'uri' parameter",
+		}
+	}
+
+	service := p.server.GetService()
+	var err error
+	if subscribe {
+		err = service.SubscribeResource(ctx, uri)
+	} else {
+		err = service.UnsubscribeResource(ctx, uri)
+	}
+	if err != nil {
+		var notFoundErr *domain.ResourceNotFoundError
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &notFoundErr):
+			return nil, &domain.JSONRPCError{
+				Code:    ResourceNotFoundCode,
+				Message: fmt.Sprintf("Resource not found: %s", uri),
+			}
+		case errors.As(err, &validationErr):
+			return nil, &domain.JSONRPCError{
+				Code:    InvalidParamsCode,
+				Message: fmt.Sprintf("Invalid params: %v", err),
+			}
+		default:
+			return nil, &domain.JSONRPCError{
+				Code:    InternalErrorCode,
+				Message: fmt.Sprintf("Internal error: %v", err),
+			}
+		}
+	}
+	return struct{}{}, nil
+}
+
+func (p *MessageProcessor) handlePromptsGet(ctx context.Context, params interface{}, id interface{}) (interface{}, *domain.JSONRPCError) {
+	// Extract parameters
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return nil, &domain.JSONRPCError{
+			Code:    InvalidParamsCode,
+			Message: "Invalid params",
+		}
+	}
+
+	// Get prompt name
+	promptName, ok := paramsMap["name"].(string)
+	if !ok || promptName == "" {
+		return nil, &domain.JSONRPCError{
+			Code:    InvalidParamsCode,
+			Message: "Missing or invalid 'name' parameter",
+		}
+	}
+
+	promptArgs, ok := paramsMap["arguments"].(map[string]interface{})
+	if !ok {
+		promptArgs = map[string]interface{}{}
+	}
+
+	rendered, err := p.server.GetService().RenderPrompt(ctx, &domain.PromptRequest{
+		Name:       promptName,
+		Parameters: promptArgs,
+	})
+	if err != nil {
+		var notFoundErr *domain.PromptNotFoundError
+		var validationErr *domain.ValidationError
+		switch {
+		case errors.As(err, &notFoundErr):
+			return nil, &domain.JSONRPCError{
+				Code:    InvalidParamsCode,
+				Message: fmt.Sprintf("Prompt not found: %s", promptName),
+			}
+		case errors.As(err, &validationErr):
+			return nil, &domain.JSONRPCError{
+				Code:    InvalidParamsCode,
+				Message: fmt.Sprintf("Invalid params: %v", err),
+			}
+		case errors.Is(err, domain.ErrNoPromptTemplate):
+			return nil, &domain.JSONRPCError{
+				Code:    MethodNotFoundCode,
+				Message: "prompts/get not supported: no template configured",
+			}
+		default:
+			return nil, &domain.JSONRPCError{
+				Code:    InternalErrorCode,
+				Message: fmt.Sprintf("Internal error: %v", err),
+			}
 		}
 	}
 
-	// Return formatted result using the MCP content format
 	return map[string]interface{}{
-		"content": []map[string]interface{}{
+		"description": rendered.Description,
+		"messages": []map[string]interface{}{
 			{
-				"type": "text",
-				"text": message,
+				"role": "user",
+				"content": map[string]interface{}{
+					"type": "text",
+					"text": rendered.Text,
+				},
 			},
 		},
 	}, nil
 }
 
+func (p *MessageProcessor) handleCompletionComplete(ctx context.Context, params interface{}, id interface{}) (interface{}, *domain.JSONRPCError) {
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return nil, &domain.JSONRPCError{
+			Code:    InvalidParamsCode,
+			Message: "Invalid params",
+		}
+	}
+
+	var ref domain.CompletionReference
+	if refParams, ok := paramsMap["ref"].(map[string]interface{}); ok {
+		ref.Type, _ = refParams["type"].(string)
+		ref.Name, _ = refParams["name"].(string)
+		ref.URI, _ = refParams["uri"].(string)
+	}
+	var arg domain.CompletionArgument
+	if argParams, ok := paramsMap["argument"].(map[string]interface{}); ok {
+		arg.Name, _ = argParams["name"].(string)
+		arg.Value, _ = argParams["value"].(string)
+	}
+
+	result, err := p.server.GetService().Complete(ctx, ref, arg)
+	if err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			return nil, &domain.JSONRPCError{
+				Code:    InvalidParamsCode,
+				Message: fmt.Sprintf("Invalid params: %v", err),
+			}
+		}
+		return nil, &domain.JSONRPCError{
+			Code:    InternalErrorCode,
+			Message: fmt.Sprintf("Internal error: %v", err),
+		}
+	}
+
+	return map[string]interface{}{
+		"completion": map[string]interface{}{
+			"values":  result.Values,
+			"total":   result.Total,
+			"hasMore": result.HasMore,
+		},
+	}, nil
+}
+
+func (p *MessageProcessor) handleLoggingSetLevel(ctx context.Context, params interface{}, id interface{}) (interface{}, *domain.JSONRPCError) {
+	paramsMap, _ := params.(map[string]interface{})
+	level, _ := paramsMap["level"].(string)
+
+	logLevel, ok := logging.LevelFromMCP(level)
+	if !ok {
+		return nil, &domain.JSONRPCError{
+			Code:    InvalidParamsCode,
+			Message: fmt.Sprintf("Invalid params: unknown log level %q", level),
+		}
+	}
+
+	// The stdio logger and the wrapped server's logger may differ
+	if err := p.logger.SetLevel(logLevel); err != nil {
+		return nil, &domain.JSONRPCError{Code: InternalErrorCode, Message: fmt.Sprintf("Internal error: %v", err)}
+	}
+	if err := p.server.SetLogLevel(level); err != nil {
+		return nil, &domain.JSONRPCError{Code: InternalErrorCode, Message: fmt.Sprintf("Internal error: %v", err)}
+	}
+	return struct{}{}, nil
+}
+
 // Helper functions for error handling and response creation
 
 // isTerminalError determines if an error should cause the server to shut down
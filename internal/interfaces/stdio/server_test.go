@@ -0,0 +1,193 @@
+package stdio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/server"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/interfaces/rest"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/usecases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessRejectsWrongJSONRPCVersion(t *testing.T) {
+	// Ping needs no repositories, so a bare service is enough
+	service := usecases.NewServerService(usecases.ServerConfig{Name: "version-test", Version: "0.0.1"})
+	processor := NewMessageProcessor(rest.NewMCPServer(service, ":0", rest.WithLogger(logging.Default())), logging.Default())
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "JSON-RPC 2.0",
+			message: `{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+			want:    `{"jsonrpc":"2.0","id":1,"result":{}}`,
+		},
+		{
+			name:    "JSON-RPC 1.0",
+			message: `{"jsonrpc":"1.0","id":2,"method":"ping"}`,
+			want:    `{"jsonrpc":"2.0","id":2,"error":{"code":-32600,"message":"Invalid JSON-RPC version"}}`,
+		},
+		{
+			name:    "version missing",
+			message: `{"id":"three","method":"ping"}`,
+			want:    `{"jsonrpc":"2.0","id":"three","error":{"code":-32600,"message":"Invalid JSON-RPC version"}}`,
+		},
+		{
+			name:    "version of the wrong type",
+			message: `{"jsonrpc":2,"id":4,"method":"ping"}`,
+			want:    `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}`,
+		},
+		{
+			name:    "notification with a wrong version",
+			message: `{"jsonrpc":"1.0","method":"notifications/initialized"}`,
+			want:    `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Invalid JSON-RPC version"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := processor.Process(context.Background(), tt.message)
+			require.NoError(t, err)
+			got, err := json.Marshal(response)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestProcessAppliesRequestTimeouts(t *testing.T) {
+	service := usecases.NewServerService(usecases.ServerConfig{
+		Name:               "timeout-test",
+		Version:            "0.0.1",
+		ToolRepo:           server.NewInMemoryToolRepository(),
+		NotificationSender: server.NewNotificationSender(JSONRPCVersion),
+	})
+	waitForCancel := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	ctx := context.Background()
+	require.NoError(t, service.AddToolWithHandler(ctx, &domain.Tool{Name: "crawl"}, waitForCancel))
+	require.NoError(t, service.AddToolWithHandler(ctx, &domain.Tool{Name: "index", Timeout: 10 * time.Millisecond}, waitForCancel))
+	mcpServer := rest.NewMCPServer(service, ":0", rest.WithLogger(logging.Default()), rest.WithRequestTimeout(40*time.Millisecond))
+	processor := NewMessageProcessor(mcpServer, logging.Default())
+
+	tests := []struct {
+		name string
+		tool string
+		want string
+	}{
+		{
+			name: "server request timeout",
+			tool: "crawl",
+			want: `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"request timed out after 40ms","data":{"timeoutMs":40}}}`,
+		},
+		{
+			name: "tool timeout",
+			tool: "index",
+			want: `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"request timed out after 10ms","data":{"timeoutMs":10}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := processor.Process(ctx, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"`+tt.tool+`"}}`)
+			require.NoError(t, err)
+			got, err := json.Marshal(response)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestListenRejectsRequestsDuringMaintenance(t *testing.T) {
+	service := usecases.NewServerService(usecases.ServerConfig{
+		Name:               "maintenance-test",
+		Version:            "0.0.1",
+		ToolRepo:           server.NewInMemoryToolRepository(),
+		NotificationSender: server.NewNotificationSender(JSONRPCVersion),
+	})
+	require.NoError(t, service.AddToolWithHandler(context.Background(), &domain.Tool{Name: "migrate"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return "migrated", nil
+		}))
+	mcpServer := rest.NewMCPServer(service, ":0", rest.WithLogger(logging.Default()))
+	stdioServer := NewStdioServer(mcpServer, WithLogger(logging.Default()))
+
+	listen := func(messages ...string) []string {
+		var output bytes.Buffer
+		require.NoError(t, stdioServer.Listen(context.Background(), strings.NewReader(strings.Join(messages, "\n")+"\n"), &output))
+		return strings.Split(strings.TrimSpace(output.String()), "\n")
+	}
+	call := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"migrate"}}`
+	list := `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`
+
+	mcpServer.SetMaintenanceMode(true, "database upgrade")
+	responses := listen(call, list)
+	require.Len(t, responses, 2)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"error":{"code":-32001,"message":"database upgrade","data":null}}`, responses[0])
+	assert.Contains(t, responses[1], `"result"`)
+
+	mcpServer.SetMaintenanceMode(false, "")
+	responses = listen(call)
+	require.Len(t, responses, 1)
+	assert.Contains(t, responses[0], "migrated")
+}
+
+func TestListenCancelsRequests(t *testing.T) {
+	service := usecases.NewServerService(usecases.ServerConfig{
+		Name:               "cancel-test",
+		Version:            "0.0.1",
+		ToolRepo:           server.NewInMemoryToolRepository(),
+		NotificationSender: server.NewNotificationSender(JSONRPCVersion),
+	})
+	started := make(chan struct{})
+	stopped := make(chan error, 1)
+	require.NoError(t, service.AddToolWithHandler(context.Background(), &domain.Tool{Name: "export"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			stopped <- ctx.Err()
+			return nil, ctx.Err()
+		}))
+	stdioServer := NewStdioServer(rest.NewMCPServer(service, ":0", rest.WithLogger(logging.Default())), WithLogger(logging.Default()))
+
+	input, client := io.Pipe()
+	var output bytes.Buffer
+	listened := make(chan error, 1)
+	go func() { listened <- stdioServer.Listen(context.Background(), input, &output) }()
+
+	send := func(message string) {
+		_, err := io.WriteString(client, message+"\n")
+		require.NoError(t, err)
+	}
+	send(`{"jsonrpc":"2.0","id":"export-1","method":"tools/call","params":{"name":"export"}}`)
+	<-started
+
+	// The cancellation is read while the call is still running; the ping
+	// after it waits its turn
+	send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"export-1","reason":"user aborted"}}`)
+	send(`{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	select {
+	case err := <-stopped:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("the tool call was not cancelled")
+	}
+	require.NoError(t, client.Close())
+	require.NoError(t, <-listened)
+
+	// Cancelled requests are not answered
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":2,"result":{}}`, strings.TrimSpace(output.String()))
+}
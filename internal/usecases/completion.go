@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+)
+
+// AddCompletionProvider registers a provider consulted for arguments without
+// their own completer. Providers are asked in registration order and the
+// first to return values answers the request.
+func (s *ServerService) AddCompletionProvider(provider domain.CompletionProvider) {
+	s.completionMu.Lock()
+	defer s.completionMu.Unlock()
+	s.completionProviders = append(s.completionProviders, provider)
+}
+
+// SetArgumentCompleter registers the completer for one argument of the
+// referenced prompt or resource template, replacing any previous one.
+func (s *ServerService) SetArgumentCompleter(ref domain.CompletionReference, argument string, completer domain.ArgumentCompleter) {
+	s.completionMu.Lock()
+	defer s.completionMu.Unlock()
+	if s.completers == nil {
+		s.completers = make(map[string]domain.ArgumentCompleter)
+	}
+	s.completers[completerKey(ref, argument)] = completer
+}
+
+// Complete suggests values for an argument of a prompt or resource template.
+// The argument's completer is asked first, then the completion providers;
+// completable boolean prompt arguments fall back to true and false. The
+// result holds at most domain.MaxCompletionValues values; it is empty if
+// nothing completes the argument.
+func (s *ServerService) Complete(ctx context.Context, ref domain.CompletionReference, arg domain.CompletionArgument) (domain.CompletionResult, error) {
+	switch ref.Type {
+	case domain.CompletionRefPrompt, domain.CompletionRefResource:
+	default:
+		return domain.CompletionResult{}, domain.NewValidationError("ref.type", "must be ref/prompt or ref/resource")
+	}
+	if ref.Key() == "" {
+		return domain.CompletionResult{}, domain.NewValidationError("ref", "name or uri is required")
+	}
+	if arg.Name == "" {
+		return domain.CompletionResult{}, domain.NewValidationError("argument.name", "is required")
+	}
+
+	s.completionMu.RLock()
+	completer := s.completers[completerKey(ref, arg.Name)]
+	providers := s.completionProviders
+	s.completionMu.RUnlock()
+
+	if completer != nil {
+		values, err := completer(ctx, arg.Value)
+		if err != nil {
+			return domain.CompletionResult{}, err
+		}
+		return domain.NewCompletionResult(values), nil
+	}
+
+	for _, provider := range providers {
+		values, err := provider.Complete(ctx, ref, arg)
+		if err != nil {
+			return domain.CompletionResult{}, err
+		}
+		if values != nil {
+			return domain.NewCompletionResult(values), nil
+		}
+	}
+	if ref.Type == domain.CompletionRefPrompt {
+		return domain.NewCompletionResult(s.completePromptArgument(ctx, ref.Name, arg)), nil
+	}
+	return domain.NewCompletionResult(nil), nil
+}
+
+// completePromptArgument suggests the values of a completable boolean
+// argument of a registered prompt that start with the partial value.
+func (s *ServerService) completePromptArgument(ctx context.Context, name string, arg domain.CompletionArgument) []string {
+	prompt, err := s.promptRepo.GetPrompt(ctx, name)
+	if err != nil {
+		return nil
+	}
+	for _, param := range prompt.Parameters {
+		if param.Name != arg.Name || !param.Completable || param.Type != "boolean" {
+			continue
+		}
+		var values []string
+		for _, value := range []string{"false", "true"} {
+			if strings.HasPrefix(value, strings.ToLower(arg.Value)) {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// completerKey identifies the completer of one argument of a prompt or
+// resource template.
+func completerKey(ref domain.CompletionReference, argument string) string {
+	return ref.Type + "\x00" + ref.Key() + "\x00" + argument
+}
+
+// HasCompletions reports whether any completion provider or argument
+// completer is registered.
+func (s *ServerService) HasCompletions() bool {
+	s.completionMu.RLock()
+	defer s.completionMu.RUnlock()
+	return len(s.completionProviders) > 0 || len(s.completers) > 0
+}
@@ -0,0 +1,119 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+)
+
+type prefixCompletionProvider struct {
+	uri    string
+	values []string
+}
+
+func (p *prefixCompletionProvider) Complete(ctx context.Context, ref domain.CompletionReference, arg domain.CompletionArgument) ([]string, error) {
+	if ref.URI != p.uri {
+		return nil, nil
+	}
+	var matches []string
+	for _, v := range p.values {
+		if strings.HasPrefix(v, arg.Value) {
+			matches = append(matches, v)
+		}
+	}
+	return matches, nil
+}
+
+func TestServerService_Complete(t *testing.T) {
+	ctx := context.Background()
+	service := createTestServerService(nil, nil, nil, nil, nil)
+
+	promptRef := domain.CompletionReference{Type: domain.CompletionRefPrompt, Name: "review"}
+	service.SetArgumentCompleter(promptRef, "language", func(ctx context.Context, value string) ([]string, error) {
+		return []string{value + "o", value + "ava"}, nil
+	})
+	service.AddCompletionProvider(&prefixCompletionProvider{uri: "file:///{path}", values: []string{"main.go", "main_test.go", "go.mod"}})
+
+	result, err := service.Complete(ctx, promptRef, domain.CompletionArgument{Name: "language", Value: "g"})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if len(result.Values) != 2 || result.Values[0] != "go" {
+		t.Errorf("Complete(prompt) = %v, want [go gava]", result.Values)
+	}
+
+	resourceRef := domain.CompletionReference{Type: domain.CompletionRefResource, URI: "file:///{path}"}
+	result, err = service.Complete(ctx, resourceRef, domain.CompletionArgument{Name: "path", Value: "main"})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if result.Total != 2 || result.HasMore {
+		t.Errorf("Complete(resource) = %+v, want 2 values", result)
+	}
+
+	// Arguments nothing completes get an empty result
+	result, err = service.Complete(ctx, promptRef, domain.CompletionArgument{Name: "style"})
+	if err != nil || len(result.Values) != 0 {
+		t.Errorf("Complete(unknown argument) = %+v, %v, want empty result", result, err)
+	}
+
+	// Unknown reference types are rejected
+	var validationErr *domain.ValidationError
+	_, err = service.Complete(ctx, domain.CompletionReference{Type: "ref/tool", Name: "x"}, domain.CompletionArgument{Name: "a"})
+	if !errors.As(err, &validationErr) {
+		t.Errorf("Complete(ref/tool) error = %v, want ValidationError", err)
+	}
+}
+
+func TestServerService_CompleteCompletablePromptArgument(t *testing.T) {
+	ctx := context.Background()
+	service := createTestServerService(nil, nil, nil, nil, nil)
+
+	prompt := &domain.Prompt{
+		Name:     "summarize",
+		Template: "Summarize {{text}}.",
+		Parameters: []domain.PromptParameter{
+			{Name: "text", Type: "string", Required: true},
+			{Name: "brief", Type: "boolean", Completable: true},
+			{Name: "verbose", Type: "boolean"},
+		},
+	}
+	if err := service.AddPrompt(ctx, prompt); err != nil {
+		t.Fatalf("AddPrompt() error = %v", err)
+	}
+	ref := domain.CompletionReference{Type: domain.CompletionRefPrompt, Name: "summarize"}
+
+	tests := []struct {
+		name string
+		arg  domain.CompletionArgument
+		want []string
+	}{
+		{name: "Empty value", arg: domain.CompletionArgument{Name: "brief"}, want: []string{"false", "true"}},
+		{name: "Partial value", arg: domain.CompletionArgument{Name: "brief", Value: "T"}, want: []string{"true"}},
+		{name: "Not completable", arg: domain.CompletionArgument{Name: "verbose"}, want: nil},
+		{name: "Not boolean", arg: domain.CompletionArgument{Name: "text"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.Complete(ctx, ref, tt.arg)
+			if err != nil {
+				t.Fatalf("Complete() error = %v", err)
+			}
+			if strings.Join(result.Values, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Complete() = %v, want %v", result.Values, tt.want)
+			}
+		})
+	}
+
+	// A completer takes precedence over the boolean values
+	service.SetArgumentCompleter(ref, "brief", func(ctx context.Context, value string) ([]string, error) {
+		return []string{"yes"}, nil
+	})
+	result, err := service.Complete(ctx, ref, domain.CompletionArgument{Name: "brief"})
+	if err != nil || len(result.Values) != 1 || result.Values[0] != "yes" {
+		t.Errorf("Complete() = %+v, %v, want [yes]", result, err)
+	}
+}
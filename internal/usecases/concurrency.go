@@ -0,0 +1,84 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+)
+
+// CallConcurrency reports the tool call concurrency limit and current load.
+type CallConcurrency struct {
+	// Limit is the most tool calls run at once; zero means unlimited.
+	Limit int
+	// Queue reports whether calls beyond the limit wait instead of failing.
+	Queue    bool
+	InFlight int64
+	Queued   int64
+	Rejected int64
+}
+
+// callLimiter is a semaphore bounding concurrent tool handler executions.
+type callLimiter struct {
+	slots chan struct{}
+	queue bool
+}
+
+// SetMaxConcurrentCalls limits how many tool handlers run at once across all
+// sessions. Calls beyond the limit wait for a free slot if queue is true and
+// otherwise fail with a domain.ServerBusyError. Zero or less removes the
+// limit. Calls already running keep the slot they hold.
+func (s *ServerService) SetMaxConcurrentCalls(n int, queue bool) {
+	var limiter *callLimiter
+	if n > 0 {
+		limiter = &callLimiter{slots: make(chan struct{}, n), queue: queue}
+	}
+	s.callLimiter.Store(limiter)
+}
+
+// CallConcurrency returns the concurrency limit and the number of tool calls
+// running, waiting for a slot and rejected so far.
+func (s *ServerService) CallConcurrency() CallConcurrency {
+	stats := CallConcurrency{
+		InFlight: s.callsInFlight.Load(),
+		Queued:   s.callsQueued.Load(),
+		Rejected: s.callsRejected.Load(),
+	}
+	if limiter := s.callLimiter.Load(); limiter != nil {
+		stats.Limit = cap(limiter.slots)
+		stats.Queue = limiter.queue
+	}
+	return stats
+}
+
+// acquireCallSlot reserves a slot for a tool handler and returns the function
+// that releases it.
+func (s *ServerService) acquireCallSlot(ctx context.Context) (func(), error) {
+	limiter := s.callLimiter.Load()
+	if limiter == nil {
+		s.callsInFlight.Add(1)
+		return func() { s.callsInFlight.Add(-1) }, nil
+	}
+
+	select {
+	case limiter.slots <- struct{}{}:
+	default:
+		if !limiter.queue {
+			s.callsRejected.Add(1)
+			return nil, domain.NewServerBusyError(cap(limiter.slots))
+		}
+		s.callsQueued.Add(1)
+		select {
+		case limiter.slots <- struct{}{}:
+			s.callsQueued.Add(-1)
+		case <-ctx.Done():
+			s.callsQueued.Add(-1)
+			return nil, ctx.Err()
+		}
+	}
+
+	s.callsInFlight.Add(1)
+	return func() {
+		s.callsInFlight.Add(-1)
+		<-limiter.slots
+	}, nil
+}
@@ -0,0 +1,151 @@
+package usecases
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+)
+
+// tokenBucket is a simple token bucket rate limiter.
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastFill time.Time
+	now      func() time.Time
+}
+
+// newTokenBucket creates a token bucket refilled at rps tokens per second that
+// holds at most burst tokens. The bucket starts full.
+func newTokenBucket(rps float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	b := &tokenBucket{
+		rate:  rps,
+		burst: float64(burst),
+		now:   time.Now,
+	}
+	b.tokens = b.burst
+	b.lastFill = b.now()
+	return b
+}
+
+// Allow reports whether a token is available and consumes it if so.
+func (b *tokenBucket) Allow() bool {
+	ok, _ := b.Reserve()
+	return ok
+}
+
+// Reserve consumes a token if one is available. Otherwise it reports how long
+// until the next token is available.
+func (b *tokenBucket) Reserve() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	elapsed := now.Sub(b.lastFill).Seconds()
+	b.lastFill = now
+
+	b.tokens += elapsed * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+
+	if b.tokens < 1 {
+		if b.rate <= 0 {
+			return false, 0
+		}
+		return false, time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// Refund returns a token consumed by a call that was rejected later on.
+func (b *tokenBucket) Refund() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// callRateLimit is the tool call rate limit set with SetCallRateLimit.
+type callRateLimit struct {
+	rps        float64
+	burst      int
+	perSession bool
+}
+
+// SetCallRateLimit limits how often tools can be called, refilling rps calls
+// per second up to burst. With perSession each session has its own budget,
+// keyed by the session ID of the request; requests without a session share
+// one. Otherwise the budget is shared by all sessions. Calls over the budget
+// fail with a domain.RateLimitError. rps of zero or less removes the limit.
+func (s *ServerService) SetCallRateLimit(rps float64, burst int, perSession bool) {
+	s.callLimitsMu.Lock()
+	defer s.callLimitsMu.Unlock()
+
+	s.callLimits = make(map[string]*tokenBucket)
+	if rps <= 0 {
+		s.callRate = nil
+		return
+	}
+	s.callRate = &callRateLimit{rps: rps, burst: burst, perSession: perSession}
+}
+
+// EndSession drops the state kept for a session, such as its rate limit
+// budget and resource subscriptions. Transports call it when a session
+// disconnects.
+func (s *ServerService) EndSession(sessionID string) {
+	s.endSubscriptions(sessionID)
+
+	s.callLimitsMu.Lock()
+	defer s.callLimitsMu.Unlock()
+	if s.callRate != nil && s.callRate.perSession {
+		delete(s.callLimits, sessionID)
+	}
+}
+
+// allowCall applies the call rate limit to a tool call.
+func (s *ServerService) allowCall(ctx context.Context) error {
+	s.callLimitsMu.Lock()
+	limit := s.callRate
+	if limit == nil {
+		s.callLimitsMu.Unlock()
+		return nil
+	}
+	key, scope := "", domain.RateLimitScopeServer
+	if limit.perSession {
+		key, scope = callSessionID(ctx), domain.RateLimitScopeSession
+	}
+	bucket, ok := s.callLimits[key]
+	if !ok {
+		bucket = newTokenBucket(limit.rps, limit.burst)
+		s.callLimits[key] = bucket
+	}
+	s.callLimitsMu.Unlock()
+
+	if ok, retryAfter := bucket.Reserve(); !ok {
+		err := domain.NewRateLimitError(scope)
+		err.RetryAfter = retryAfter
+		return err
+	}
+	return nil
+}
+
+// callSessionID returns the ID of the session a request came from.
+func callSessionID(ctx context.Context) string {
+	if sessionID, ok := domain.SessionIDFromContext(ctx); ok {
+		return sessionID
+	}
+	if info, ok := domain.SessionInfoFromContext(ctx); ok {
+		return info.ID
+	}
+	return ""
+}
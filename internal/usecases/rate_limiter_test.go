@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+)
+
+func TestTokenBucket_Allow(t *testing.T) {
+	current := time.Unix(0, 0)
+	bucket := newTokenBucket(2, 3)
+	bucket.now = func() time.Time { return current }
+	bucket.lastFill = current
+
+	// The bucket starts full with burst tokens
+	for i := 0; i < 3; i++ {
+		if !bucket.Allow() {
+			t.Fatalf("Allow() = false on call %d, want true", i+1)
+		}
+	}
+	if bucket.Allow() {
+		t.Errorf("Allow() = true after burst exhausted, want false")
+	}
+
+	// Half a second refills one token at 2 rps
+	current = current.Add(500 * time.Millisecond)
+	if !bucket.Allow() {
+		t.Errorf("Allow() = false after refill, want true")
+	}
+	if bucket.Allow() {
+		t.Errorf("Allow() = true with empty bucket, want false")
+	}
+
+	// Tokens never exceed the burst size
+	current = current.Add(time.Minute)
+	allowed := 0
+	for bucket.Allow() {
+		allowed++
+	}
+	if allowed != 3 {
+		t.Errorf("Allow() succeeded %d times after long idle, want 3", allowed)
+	}
+}
+
+func TestTokenBucket_ReserveRetryAfter(t *testing.T) {
+	current := time.Unix(0, 0)
+	bucket := newTokenBucket(4, 1)
+	bucket.now = func() time.Time { return current }
+	bucket.lastFill = current
+
+	if ok, _ := bucket.Reserve(); !ok {
+		t.Fatalf("Reserve() = false with a full bucket, want true")
+	}
+	ok, retryAfter := bucket.Reserve()
+	if ok || retryAfter != 250*time.Millisecond {
+		t.Errorf("Reserve() = %v, %v, want false, 250ms", ok, retryAfter)
+	}
+}
+
+func TestServerService_CallRateLimit(t *testing.T) {
+	ctx := context.Background()
+	service := createTestServerService(nil, nil, nil, nil, nil)
+	err := service.AddToolWithHandler(ctx, &domain.Tool{Name: "echo"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("AddToolWithHandler() error = %v", err)
+	}
+
+	// Each session has its own budget
+	service.SetCallRateLimit(0.001, 1, true)
+	alice := domain.WithSessionID(ctx, "alice")
+	bob := domain.WithSessionID(ctx, "bob")
+	if _, err := service.CallTool(alice, "echo", nil); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	var rateLimitErr *domain.RateLimitError
+	if _, err := service.CallTool(alice, "echo", nil); !errors.As(err, &rateLimitErr) {
+		t.Fatalf("CallTool() error = %v, want RateLimitError", err)
+	}
+	if rateLimitErr.Scope != domain.RateLimitScopeSession || rateLimitErr.RetryAfter <= 0 {
+		t.Errorf("RateLimitError = %+v, want session scope with a retry hint", rateLimitErr)
+	}
+	if _, err := service.CallTool(bob, "echo", nil); err != nil {
+		t.Errorf("CallTool() for another session error = %v", err)
+	}
+
+	// Ending the session releases its budget
+	service.EndSession("alice")
+	if len(service.callLimits) != 1 {
+		t.Errorf("callLimits has %d sessions after EndSession, want 1", len(service.callLimits))
+	}
+	if _, err := service.CallTool(alice, "echo", nil); err != nil {
+		t.Errorf("CallTool() after EndSession error = %v", err)
+	}
+
+	// A global budget is shared by all sessions
+	service.SetCallRateLimit(0.001, 1, false)
+	if _, err := service.CallTool(alice, "echo", nil); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if _, err := service.CallTool(bob, "echo", nil); !errors.As(err, &rateLimitErr) || rateLimitErr.Scope != domain.RateLimitScopeServer {
+		t.Errorf("CallTool() error = %v, want server scoped RateLimitError", err)
+	}
+}
+
+func TestServerService_RejectedCallsKeepToolBudget(t *testing.T) {
+	ctx := context.Background()
+	service := createTestServerService(nil, nil, nil, nil, nil)
+	tool := &domain.Tool{Name: "lookup", RateLimit: &domain.ToolRateLimit{Burst: 2}}
+	err := service.AddToolWithHandler(ctx, tool, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return "found", nil
+	})
+	if err != nil {
+		t.Fatalf("AddToolWithHandler() error = %v", err)
+	}
+	service.SetCallRateLimit(0.001, 1, true)
+
+	// A call over the session's budget leaves the tool's token for others
+	carol := domain.WithSessionID(ctx, "carol")
+	if _, err := service.CallTool(carol, "lookup", nil); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	var rateLimitErr *domain.RateLimitError
+	if _, err := service.CallTool(carol, "lookup", nil); !errors.As(err, &rateLimitErr) || rateLimitErr.Scope != domain.RateLimitScopeSession {
+		t.Fatalf("CallTool() error = %v, want session scoped RateLimitError", err)
+	}
+	if _, err := service.CallTool(domain.WithSessionID(ctx, "dave"), "lookup", nil); err != nil {
+		t.Errorf("CallTool() for another session error = %v, want the tool budget kept", err)
+	}
+}
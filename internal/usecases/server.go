@@ -3,75 +3,307 @@ package usecases
 
 import (
 	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
 
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
 )
 
+// ToolHandlerFunc handles a call to a registered tool with the given arguments.
+type ToolHandlerFunc func(ctx context.Context, args map[string]interface{}) (interface{}, error)
+
+// ToolHandlerNotFoundError indicates that a tool is registered but has no handler.
+type ToolHandlerNotFoundError struct {
+	Name string
+}
+
+// Error returns the error message.
+func (e *ToolHandlerNotFoundError) Error() string {
+	return fmt.Sprintf("tool handler not implemented for: %s", e.Name)
+}
+
 // ServerService handles business logic for the MCP server.
 type ServerService struct {
 	name               string
 	version            string
 	instructions       string
+	instructionsFunc   func(ctx context.Context) string
 	resourceRepo       domain.ResourceRepository
+	templateRepo       domain.ResourceTemplateRepository
 	toolRepo           domain.ToolRepository
 	promptRepo         domain.PromptRepository
 	sessionRepo        domain.SessionRepository
 	notificationSender domain.NotificationSender
+	toolHandlersMu     sync.RWMutex
+	toolHandlers       map[string]ToolHandlerFunc
+	toolLimitersMu     sync.Mutex
+	toolLimiters       map[string]*tokenBucket
+	// Tool call rate limit, see SetCallRateLimit
+	callLimitsMu sync.Mutex
+	callRate     *callRateLimit
+	callLimits   map[string]*tokenBucket
+	// Argument completion, see Complete
+	completionMu        sync.RWMutex
+	completers          map[string]domain.ArgumentCompleter
+	completionProviders []domain.CompletionProvider
+	// Tool call concurrency, see SetMaxConcurrentCalls
+	callLimiter   atomic.Pointer[callLimiter]
+	callsInFlight atomic.Int64
+	callsQueued   atomic.Int64
+	callsRejected atomic.Int64
+	// Resource subscriptions by session ID, see SubscribeResource
+	subscriptionsMu sync.Mutex
+	subscriptions   map[string]map[string]bool
 }
 
 // ServerConfig contains configuration for the ServerService.
 type ServerConfig struct {
-	Name               string
-	Version            string
-	Instructions       string
-	ResourceRepo       domain.ResourceRepository
-	ToolRepo           domain.ToolRepository
-	PromptRepo         domain.PromptRepository
-	SessionRepo        domain.SessionRepository
-	NotificationSender domain.NotificationSender
+	Name         string
+	Version      string
+	Instructions string
+	// InstructionsFunc, if set, generates the instructions for each
+	// initialize request instead of Instructions.
+	InstructionsFunc func(ctx context.Context) string
+	ResourceRepo     domain.ResourceRepository
+	// ResourceTemplateRepo is optional; without it no resource templates can be registered.
+	ResourceTemplateRepo domain.ResourceTemplateRepository
+	ToolRepo             domain.ToolRepository
+	PromptRepo           domain.PromptRepository
+	SessionRepo          domain.SessionRepository
+	NotificationSender   domain.NotificationSender
+	ToolHandlers         map[string]ToolHandlerFunc
+}
+
+// NotificationSender returns the sender used to notify clients.
+func (s *ServerService) NotificationSender() domain.NotificationSender {
+	return s.notificationSender
 }
 
 // NewServerService creates a new ServerService with the given repositories and configuration.
 func NewServerService(config ServerConfig) *ServerService {
+	toolHandlers := make(map[string]ToolHandlerFunc, len(config.ToolHandlers))
+	for name, handler := range config.ToolHandlers {
+		toolHandlers[name] = handler
+	}
+
 	return &ServerService{
 		name:               config.Name,
 		version:            config.Version,
 		instructions:       config.Instructions,
+		instructionsFunc:   config.InstructionsFunc,
 		resourceRepo:       config.ResourceRepo,
+		templateRepo:       config.ResourceTemplateRepo,
 		toolRepo:           config.ToolRepo,
 		promptRepo:         config.PromptRepo,
 		sessionRepo:        config.SessionRepo,
 		notificationSender: config.NotificationSender,
+		toolHandlers:       toolHandlers,
+		toolLimiters:       make(map[string]*tokenBucket),
 	}
 }
 
-// ServerInfo returns information about the server.
+// ServerInfo returns information about the server. The instructions are the
+// static ones; see Instructions.
 func (s *ServerService) ServerInfo() (string, string, string) {
 	return s.name, s.version, s.instructions
 }
 
+// Instructions returns the instructions sent to clients in the initialize
+// response, generated by the configured InstructionsFunc if there is one.
+func (s *ServerService) Instructions(ctx context.Context) string {
+	if s.instructionsFunc != nil {
+		return s.instructionsFunc(ctx)
+	}
+	return s.instructions
+}
+
 // ListResources returns all available resources.
 func (s *ServerService) ListResources(ctx context.Context) ([]*domain.Resource, error) {
 	return s.resourceRepo.ListResources(ctx)
 }
 
+// ListResourcesFiltered returns the resources matching filter. It returns a
+// domain.ValidationError if the filter's pattern is malformed.
+func (s *ServerService) ListResourcesFiltered(ctx context.Context, filter domain.ResourceFilter) ([]*domain.Resource, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	resources, err := s.resourceRepo.ListResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]*domain.Resource, 0, len(resources))
+	for _, resource := range resources {
+		if filter.Matches(resource.URI) {
+			matched = append(matched, resource)
+		}
+	}
+	return matched, nil
+}
+
 // GetResource returns a resource by its URI.
 func (s *ServerService) GetResource(ctx context.Context, uri string) (*domain.Resource, error) {
 	return s.resourceRepo.GetResource(ctx, uri)
 }
 
+// ReadResource returns the contents of a resource from its content provider.
+// URIs that are not registered as resources are matched against the resource
+// templates in registration order. Missing URIs and MIME types in the
+// contents default to the resource's own. It returns domain.ErrNoContentProvider
+// if the resource or template has no provider.
+func (s *ServerService) ReadResource(ctx context.Context, uri string) ([]domain.ResourceContents, error) {
+	resource, err := s.resourceRepo.GetResource(ctx, uri)
+	if err != nil {
+		template, vars, ok := s.matchResourceTemplate(ctx, uri)
+		if !ok {
+			return nil, err
+		}
+		return s.readTemplateResource(ctx, template, uri, vars)
+	}
+	if resource.ContentProvider == nil {
+		return nil, domain.ErrNoContentProvider
+	}
+
+	contents, err := resource.ContentProvider.ReadContent(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	return withContentDefaults(contents, resource.URI, resource.MIMEType), nil
+}
+
+// readTemplateResource reads a resource through the content provider of the template it matched.
+func (s *ServerService) readTemplateResource(ctx context.Context, template *domain.ResourceTemplate, uri string, vars map[string]string) ([]domain.ResourceContents, error) {
+	if template.ContentProvider == nil {
+		return nil, domain.ErrNoContentProvider
+	}
+
+	contents, err := template.ContentProvider.ReadTemplateContent(ctx, uri, vars)
+	if err != nil {
+		return nil, err
+	}
+	return withContentDefaults(contents, uri, template.MIMEType), nil
+}
+
+// matchResourceTemplate returns the first resource template matching uri and
+// the values of its variables.
+func (s *ServerService) matchResourceTemplate(ctx context.Context, uri string) (*domain.ResourceTemplate, map[string]string, bool) {
+	if s.templateRepo == nil {
+		return nil, nil, false
+	}
+	templates, err := s.templateRepo.ListResourceTemplates(ctx)
+	if err != nil {
+		return nil, nil, false
+	}
+	for _, template := range templates {
+		if vars, ok := template.Match(uri); ok {
+			return template, vars, true
+		}
+	}
+	return nil, nil, false
+}
+
+// withContentDefaults fills in missing URIs and MIME types in the contents.
+func withContentDefaults(contents []domain.ResourceContents, uri, mimeType string) []domain.ResourceContents {
+	for i := range contents {
+		if contents[i].URI == "" {
+			contents[i].URI = uri
+		}
+		if contents[i].MIMEType == "" {
+			contents[i].MIMEType = mimeType
+		}
+	}
+	return contents
+}
+
+// ListResourceTemplates returns all registered resource templates.
+func (s *ServerService) ListResourceTemplates(ctx context.Context) ([]*domain.ResourceTemplate, error) {
+	if s.templateRepo == nil {
+		return []*domain.ResourceTemplate{}, nil
+	}
+	return s.templateRepo.ListResourceTemplates(ctx)
+}
+
+// AddResourceTemplate registers a resource template. It returns an error if
+// the URI template is not a valid level 1 template or the service has no
+// resource template repository.
+func (s *ServerService) AddResourceTemplate(ctx context.Context, template *domain.ResourceTemplate) error {
+	if s.templateRepo == nil {
+		return fmt.Errorf("resource templates are not supported: no resource template repository configured")
+	}
+	if _, err := domain.ParseURITemplate(template.URITemplate); err != nil {
+		return domain.NewValidationError("uriTemplate", err.Error())
+	}
+	return s.templateRepo.AddResourceTemplate(ctx, template)
+}
+
+// DeleteResourceTemplate removes the resource template with the given URI template.
+func (s *ServerService) DeleteResourceTemplate(ctx context.Context, uriTemplate string) error {
+	if s.templateRepo == nil {
+		return domain.NewResourceNotFoundError(uriTemplate)
+	}
+	return s.templateRepo.DeleteResourceTemplate(ctx, uriTemplate)
+}
+
 // AddResource adds a new resource.
 func (s *ServerService) AddResource(ctx context.Context, resource *domain.Resource) error {
+	if err := s.resourceRepo.AddResource(ctx, resource); err != nil {
+		return err
+	}
+
 	// Notify clients about resource list change after adding
-	defer s.notifyResourceListChanged(ctx)
-	return s.resourceRepo.AddResource(ctx, resource)
+	s.notifyResourceListChanged(ctx)
+	return nil
 }
 
 // DeleteResource removes a resource.
 func (s *ServerService) DeleteResource(ctx context.Context, uri string) error {
+	if err := s.resourceRepo.DeleteResource(ctx, uri); err != nil {
+		return err
+	}
+
 	// Notify clients about resource list change after deletion
-	defer s.notifyResourceListChanged(ctx)
-	return s.resourceRepo.DeleteResource(ctx, uri)
+	s.notifyResourceListChanged(ctx)
+	return nil
+}
+
+// ReplaceResources replaces all resources with the given ones and notifies
+// clients once. The swap is atomic if the repository implements
+// domain.ResourceReplacer; otherwise resources are deleted and re-added one by one.
+func (s *ServerService) ReplaceResources(ctx context.Context, resources []*domain.Resource) error {
+	if err := s.replaceResourcesInRepo(ctx, resources); err != nil {
+		return err
+	}
+
+	// Notify clients about resource list change once, after the swap
+	s.notifyResourceListChanged(ctx)
+	return nil
+}
+
+// replaceResourcesInRepo swaps the resource repository contents.
+func (s *ServerService) replaceResourcesInRepo(ctx context.Context, resources []*domain.Resource) error {
+	if replacer, ok := s.resourceRepo.(domain.ResourceReplacer); ok {
+		return replacer.ReplaceResources(ctx, resources)
+	}
+
+	existing, err := s.resourceRepo.ListResources(ctx)
+	if err != nil {
+		return err
+	}
+	for _, resource := range existing {
+		if err := s.resourceRepo.DeleteResource(ctx, resource.URI); err != nil {
+			return err
+		}
+	}
+	for _, resource := range resources {
+		if err := s.resourceRepo.AddResource(ctx, resource); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // ListTools returns all available tools.
@@ -86,16 +318,318 @@ func (s *ServerService) GetTool(ctx context.Context, name string) (*domain.Tool,
 
 // AddTool adds a new tool.
 func (s *ServerService) AddTool(ctx context.Context, tool *domain.Tool) error {
+	if err := tool.Validate(); err != nil {
+		return err
+	}
+	if err := tool.CompilePatterns(); err != nil {
+		return err
+	}
+
+	if err := s.toolRepo.AddTool(ctx, tool); err != nil {
+		return err
+	}
+
 	// Notify clients about tool list change after adding
-	defer s.notifyToolListChanged(ctx)
+	s.notifyToolListChanged(ctx)
+	return nil
+}
+
+// AddToolWithHandler adds a new tool and registers the handler that executes
+// it. Calls never see the tool without its handler.
+func (s *ServerService) AddToolWithHandler(ctx context.Context, tool *domain.Tool, handler ToolHandlerFunc) error {
+	if err := tool.Validate(); err != nil {
+		return err
+	}
+	if err := tool.CompilePatterns(); err != nil {
+		return err
+	}
+
+	s.toolHandlersMu.Lock()
+	if err := s.toolRepo.AddTool(ctx, tool); err != nil {
+		s.toolHandlersMu.Unlock()
+		return err
+	}
+	s.toolHandlers[tool.Name] = handler
+	s.toolHandlersMu.Unlock()
+
+	s.notifyToolListChanged(ctx)
+	return nil
+}
+
+// UpdateTool replaces the definition and handler of an existing tool in one
+// step and notifies clients once. It returns a domain.ToolNotFoundError if
+// the tool does not exist; use AddToolWithHandler to create it.
+func (s *ServerService) UpdateTool(ctx context.Context, tool *domain.Tool, handler ToolHandlerFunc) error {
+	if err := tool.Validate(); err != nil {
+		return err
+	}
+	if err := tool.CompilePatterns(); err != nil {
+		return err
+	}
+
+	// Replace the definition and handler under one lock so a concurrent call
+	// never pairs the new definition with the old handler
+	s.toolHandlersMu.Lock()
+	if err := s.updateToolInRepo(ctx, tool); err != nil {
+		s.toolHandlersMu.Unlock()
+		return err
+	}
+	s.toolHandlers[tool.Name] = handler
+
+	// Drop the rate limiter so a changed limit takes effect
+	s.toolLimitersMu.Lock()
+	delete(s.toolLimiters, tool.Name)
+	s.toolLimitersMu.Unlock()
+	s.toolHandlersMu.Unlock()
+
+	// Notify clients about tool list change after the update
+	s.notifyToolListChanged(ctx)
+	return nil
+}
+
+// updateToolInRepo replaces an existing tool in the repository, atomically
+// if the repository implements domain.ToolUpdater. The caller holds
+// toolHandlersMu.
+func (s *ServerService) updateToolInRepo(ctx context.Context, tool *domain.Tool) error {
+	if updater, ok := s.toolRepo.(domain.ToolUpdater); ok {
+		return updater.UpdateTool(ctx, tool)
+	}
+	if _, err := s.toolRepo.GetTool(ctx, tool.Name); err != nil {
+		return err
+	}
 	return s.toolRepo.AddTool(ctx, tool)
 }
 
+// RegisterToolHandler registers the handler that executes the named tool.
+func (s *ServerService) RegisterToolHandler(name string, handler ToolHandlerFunc) {
+	s.toolHandlersMu.Lock()
+	defer s.toolHandlersMu.Unlock()
+	s.toolHandlers[name] = handler
+}
+
+// CallTool executes the named tool with the given arguments using its registered handler.
+// It returns a ToolNotFoundError if the tool does not exist and a
+// ToolHandlerNotFoundError if the tool has no registered handler. Calls that
+// exceed the tool's rate limit or the call rate limit fail with a
+// domain.RateLimitError, and calls
+// beyond the concurrency limit with a domain.ServerBusyError.
+func (s *ServerService) CallTool(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+	// Read the tool and its handler together so an UpdateTool in between
+	// cannot pair the old definition with the new handler
+	s.toolHandlersMu.RLock()
+	tool, err := s.toolRepo.GetTool(ctx, name)
+	handler, ok := s.toolHandlers[name]
+	s.toolHandlersMu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var limiter *tokenBucket
+	if tool.RateLimit != nil {
+		limiter = s.toolLimiter(tool)
+		if !limiter.Allow() {
+			return nil, domain.NewRateLimitError(fmt.Sprintf("tool %s", name))
+		}
+	}
+	if err := s.allowCall(ctx); err != nil {
+		// A call the session or server limit rejects does not count
+		// against the tool's limit
+		if limiter != nil {
+			limiter.Refund()
+		}
+		return nil, err
+	}
+
+	if !ok {
+		return nil, &ToolHandlerNotFoundError{Name: name}
+	}
+
+	if err := tool.ValidateArguments(args); err != nil {
+		return nil, err
+	}
+
+	release, err := s.acquireCallSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Hold the slot until the handler returns, even if the call is canceled
+	run := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		defer release()
+		return handler(ctx, args)
+	}
+
+	result, err := runToolHandler(ctx, run, withParameterDefaults(tool, args))
+	if err != nil {
+		return result, err
+	}
+	if structured, ok := result.(domain.StructuredResult); ok {
+		if result, err = domain.WrapStructuredResult(structured); err != nil {
+			return nil, err
+		}
+	} else if tool.AutoWrapResults {
+		if result, err = domain.WrapToolResult(result); err != nil {
+			return nil, err
+		}
+	}
+	if tool.ValidateOutput {
+		if err := tool.ValidateStructuredContent(result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// withParameterDefaults returns the call arguments with declared defaults
+// injected for omitted parameters. Precedence is: client value, then declared
+// default, otherwise the argument stays absent. An explicit null counts as a
+// client value and is passed through. The caller's map is not modified.
+func withParameterDefaults(tool *domain.Tool, args map[string]interface{}) map[string]interface{} {
+	var merged map[string]interface{}
+	for _, param := range tool.Parameters {
+		if param.Default == nil {
+			continue
+		}
+		if _, ok := args[param.Name]; ok {
+			continue
+		}
+		if merged == nil {
+			merged = make(map[string]interface{}, len(args)+1)
+			for k, v := range args {
+				merged[k] = v
+			}
+		}
+		merged[param.Name] = param.Default
+	}
+
+	if merged == nil {
+		return args
+	}
+	return merged
+}
+
+// runToolHandler runs the handler and returns early with the context error if
+// the context is done before the handler returns. A panic in the handler is
+// returned as a domain.PanicError.
+func runToolHandler(ctx context.Context, handler ToolHandlerFunc, args map[string]interface{}) (interface{}, error) {
+	type toolResult struct {
+		result interface{}
+		err    error
+	}
+
+	done := make(chan toolResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- toolResult{err: domain.NewPanicError(r, debug.Stack())}
+			}
+		}()
+		result, err := handler(ctx, args)
+		done <- toolResult{result: result, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.result, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// toolLimiter returns the shared rate limiter for the tool, creating it on first use.
+func (s *ServerService) toolLimiter(tool *domain.Tool) *tokenBucket {
+	s.toolLimitersMu.Lock()
+	defer s.toolLimitersMu.Unlock()
+
+	limiter, ok := s.toolLimiters[tool.Name]
+	if !ok {
+		limiter = newTokenBucket(float64(tool.RateLimit.RequestsPerSecond), tool.RateLimit.Burst)
+		s.toolLimiters[tool.Name] = limiter
+	}
+	return limiter
+}
+
 // DeleteTool removes a tool.
 func (s *ServerService) DeleteTool(ctx context.Context, name string) error {
+	s.toolHandlersMu.Lock()
+	if err := s.toolRepo.DeleteTool(ctx, name); err != nil {
+		s.toolHandlersMu.Unlock()
+		return err
+	}
+	delete(s.toolHandlers, name)
+	s.toolHandlersMu.Unlock()
+
+	s.toolLimitersMu.Lock()
+	delete(s.toolLimiters, name)
+	s.toolLimitersMu.Unlock()
+
 	// Notify clients about tool list change after deletion
-	defer s.notifyToolListChanged(ctx)
-	return s.toolRepo.DeleteTool(ctx, name)
+	s.notifyToolListChanged(ctx)
+	return nil
+}
+
+// ReplaceTools replaces all tools with the given ones and notifies clients
+// once. Handlers of tools that remain are kept; those of removed tools are
+// dropped. The swap is atomic if the repository implements domain.ToolReplacer;
+// otherwise tools are deleted and re-added one by one. Nothing is changed if a
+// tool is invalid, as AddTool would reject it.
+func (s *ServerService) ReplaceTools(ctx context.Context, tools []*domain.Tool) error {
+	keep := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		if err := tool.Validate(); err != nil {
+			return fmt.Errorf("tool %s: %w", tool.Name, err)
+		}
+		if err := tool.CompilePatterns(); err != nil {
+			return fmt.Errorf("tool %s: %w", tool.Name, err)
+		}
+		keep[tool.Name] = true
+	}
+
+	// Swap the tools and prune the handlers under one lock so a concurrent
+	// call never pairs a new definition with a stale handler
+	s.toolHandlersMu.Lock()
+	if err := s.replaceToolsInRepo(ctx, tools); err != nil {
+		s.toolHandlersMu.Unlock()
+		return err
+	}
+	for name := range s.toolHandlers {
+		if !keep[name] {
+			delete(s.toolHandlers, name)
+		}
+	}
+
+	// Drop limiters so changed rate limits take effect
+	s.toolLimitersMu.Lock()
+	s.toolLimiters = make(map[string]*tokenBucket)
+	s.toolLimitersMu.Unlock()
+	s.toolHandlersMu.Unlock()
+
+	// Notify clients about tool list change once, after the swap
+	s.notifyToolListChanged(ctx)
+	return nil
+}
+
+// replaceToolsInRepo swaps the tool repository contents. The caller holds
+// toolHandlersMu.
+func (s *ServerService) replaceToolsInRepo(ctx context.Context, tools []*domain.Tool) error {
+	if replacer, ok := s.toolRepo.(domain.ToolReplacer); ok {
+		return replacer.ReplaceTools(ctx, tools)
+	}
+
+	existing, err := s.toolRepo.ListTools(ctx)
+	if err != nil {
+		return err
+	}
+	for _, tool := range existing {
+		if err := s.toolRepo.DeleteTool(ctx, tool.Name); err != nil {
+			return err
+		}
+	}
+	for _, tool := range tools {
+		if err := s.toolRepo.AddTool(ctx, tool); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // ListPrompts returns all available prompts.
@@ -108,18 +642,94 @@ func (s *ServerService) GetPrompt(ctx context.Context, name string) (*domain.Pro
 	return s.promptRepo.GetPrompt(ctx, name)
 }
 
+// RenderPrompt renders the requested prompt template with the request parameters.
+// It returns domain.ErrNoPromptTemplate if the prompt has no template.
+func (s *ServerService) RenderPrompt(ctx context.Context, request *domain.PromptRequest) (*domain.PromptResult, error) {
+	prompt, err := s.promptRepo.GetPrompt(ctx, request.Name)
+	if err != nil {
+		return nil, err
+	}
+	if prompt.Template == "" {
+		return nil, domain.ErrNoPromptTemplate
+	}
+
+	text, err := prompt.Render(request.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PromptResult{
+		Description: prompt.Description,
+		Text:        text,
+	}, nil
+}
+
 // AddPrompt adds a new prompt.
 func (s *ServerService) AddPrompt(ctx context.Context, prompt *domain.Prompt) error {
+	if err := prompt.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.promptRepo.AddPrompt(ctx, prompt); err != nil {
+		return err
+	}
+
 	// Notify clients about prompt list change after adding
-	defer s.notifyPromptListChanged(ctx)
-	return s.promptRepo.AddPrompt(ctx, prompt)
+	s.notifyPromptListChanged(ctx)
+	return nil
 }
 
 // DeletePrompt removes a prompt.
 func (s *ServerService) DeletePrompt(ctx context.Context, name string) error {
+	if err := s.promptRepo.DeletePrompt(ctx, name); err != nil {
+		return err
+	}
+
 	// Notify clients about prompt list change after deletion
-	defer s.notifyPromptListChanged(ctx)
-	return s.promptRepo.DeletePrompt(ctx, name)
+	s.notifyPromptListChanged(ctx)
+	return nil
+}
+
+// ReplacePrompts replaces all prompts with the given ones and notifies clients
+// once. The swap is atomic if the repository implements domain.PromptReplacer;
+// otherwise prompts are deleted and re-added one by one.
+func (s *ServerService) ReplacePrompts(ctx context.Context, prompts []*domain.Prompt) error {
+	for _, prompt := range prompts {
+		if err := prompt.Validate(); err != nil {
+			return fmt.Errorf("prompt %s: %w", prompt.Name, err)
+		}
+	}
+
+	if err := s.replacePromptsInRepo(ctx, prompts); err != nil {
+		return err
+	}
+
+	// Notify clients about prompt list change once, after the swap
+	s.notifyPromptListChanged(ctx)
+	return nil
+}
+
+// replacePromptsInRepo swaps the prompt repository contents.
+func (s *ServerService) replacePromptsInRepo(ctx context.Context, prompts []*domain.Prompt) error {
+	if replacer, ok := s.promptRepo.(domain.PromptReplacer); ok {
+		return replacer.ReplacePrompts(ctx, prompts)
+	}
+
+	existing, err := s.promptRepo.ListPrompts(ctx)
+	if err != nil {
+		return err
+	}
+	for _, prompt := range existing {
+		if err := s.promptRepo.DeletePrompt(ctx, prompt.Name); err != nil {
+			return err
+		}
+	}
+	for _, prompt := range prompts {
+		if err := s.promptRepo.AddPrompt(ctx, prompt); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // RegisterSession adds a new client session.
@@ -146,7 +756,7 @@ func (s *ServerService) BroadcastNotification(ctx context.Context, notification
 
 func (s *ServerService) notifyResourceListChanged(ctx context.Context) {
 	notification := &domain.Notification{
-		Method: "resources/list/changed",
+		Method: domain.ResourceListChangedMethod,
 		Params: map[string]interface{}{},
 	}
 	_ = s.BroadcastNotification(ctx, notification)
@@ -154,7 +764,7 @@ func (s *ServerService) notifyResourceListChanged(ctx context.Context) {
 
 func (s *ServerService) notifyToolListChanged(ctx context.Context) {
 	notification := &domain.Notification{
-		Method: "tools/list/changed",
+		Method: domain.ToolListChangedMethod,
 		Params: map[string]interface{}{},
 	}
 	_ = s.BroadcastNotification(ctx, notification)
@@ -162,7 +772,7 @@ func (s *ServerService) notifyToolListChanged(ctx context.Context) {
 
 func (s *ServerService) notifyPromptListChanged(ctx context.Context) {
 	notification := &domain.Notification{
-		Method: "prompts/list/changed",
+		Method: domain.PromptListChangedMethod,
 		Params: map[string]interface{}{},
 	}
 	_ = s.BroadcastNotification(ctx, notification)
@@ -2,8 +2,12 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
 )
@@ -76,6 +80,45 @@ func (m *MockResourceRepository) DeleteResource(ctx context.Context, uri string)
 	return nil
 }
 
+// MockResourceTemplateRepository is a mock implementation of domain.ResourceTemplateRepository
+type MockResourceTemplateRepository struct {
+	mu        sync.RWMutex
+	templates []*domain.ResourceTemplate
+}
+
+// NewMockResourceTemplateRepository creates a new mock resource template repository
+func NewMockResourceTemplateRepository() *MockResourceTemplateRepository {
+	return &MockResourceTemplateRepository{}
+}
+
+// ListResourceTemplates returns all resource templates in registration order
+func (m *MockResourceTemplateRepository) ListResourceTemplates(ctx context.Context) ([]*domain.ResourceTemplate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]*domain.ResourceTemplate(nil), m.templates...), nil
+}
+
+// AddResourceTemplate adds a new resource template to the repository
+func (m *MockResourceTemplateRepository) AddResourceTemplate(ctx context.Context, template *domain.ResourceTemplate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.templates = append(m.templates, template)
+	return nil
+}
+
+// DeleteResourceTemplate removes a resource template from the repository
+func (m *MockResourceTemplateRepository) DeleteResourceTemplate(ctx context.Context, uriTemplate string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, template := range m.templates {
+		if template.URITemplate == uriTemplate {
+			m.templates = append(m.templates[:i], m.templates[i+1:]...)
+			return nil
+		}
+	}
+	return domain.NewResourceNotFoundError(uriTemplate)
+}
+
 // MockToolRepository is a mock implementation of domain.ToolRepository
 type MockToolRepository struct {
 	tools map[string]*domain.Tool
@@ -378,6 +421,40 @@ func TestNewServerService(t *testing.T) {
 	}
 }
 
+func TestServerService_Instructions(t *testing.T) {
+	ctx := context.Background()
+
+	// Static instructions are returned as configured
+	service := NewServerService(ServerConfig{Instructions: "Use the tools wisely"})
+	if got := service.Instructions(ctx); got != "Use the tools wisely" {
+		t.Errorf("Instructions() = %q, want %q", got, "Use the tools wisely")
+	}
+
+	// A function generates them for each call, reflecting the current tools
+	toolRepo := NewMockToolRepository()
+	service = NewServerService(ServerConfig{
+		Instructions: "ignored",
+		ToolRepo:     toolRepo,
+		InstructionsFunc: func(ctx context.Context) string {
+			tools, _ := toolRepo.ListTools(ctx)
+			names := make([]string, len(tools))
+			for i, tool := range tools {
+				names[i] = tool.Name
+			}
+			return "Tools: " + strings.Join(names, ", ")
+		},
+	})
+	if got := service.Instructions(ctx); got != "Tools: " {
+		t.Errorf("Instructions() = %q, want %q", got, "Tools: ")
+	}
+	if err := toolRepo.AddTool(ctx, &domain.Tool{Name: "echo"}); err != nil {
+		t.Fatalf("AddTool() error = %v", err)
+	}
+	if got := service.Instructions(ctx); got != "Tools: echo" {
+		t.Errorf("Instructions() = %q, want %q", got, "Tools: echo")
+	}
+}
+
 func TestServerService_Resource(t *testing.T) {
 	// Setup
 	ctx := context.Background()
@@ -439,6 +516,38 @@ func TestServerService_Resource(t *testing.T) {
 	}
 }
 
+func TestServerService_ListResourcesFiltered(t *testing.T) {
+	ctx := context.Background()
+	mockResourceRepo := NewMockResourceRepository()
+	service := createTestServerService(mockResourceRepo, nil, nil, nil, nil)
+	for _, uri := range []string{"file:///docs/a.md", "file:///docs/b.txt", "db://users"} {
+		if err := mockResourceRepo.AddResource(ctx, &domain.Resource{URI: uri}); err != nil {
+			t.Fatalf("AddResource() error = %v", err)
+		}
+	}
+
+	resources, err := service.ListResourcesFiltered(ctx, domain.ResourceFilter{URIPrefix: "file://", Pattern: "*.md"})
+	if err != nil {
+		t.Fatalf("ListResourcesFiltered() error = %v", err)
+	}
+	if len(resources) != 0 {
+		t.Errorf("ListResourcesFiltered() returned %v resources, want 0", len(resources))
+	}
+
+	resources, err = service.ListResourcesFiltered(ctx, domain.ResourceFilter{URIPrefix: "file://", Pattern: "file:///docs/*.md"})
+	if err != nil {
+		t.Fatalf("ListResourcesFiltered() error = %v", err)
+	}
+	if len(resources) != 1 || resources[0].URI != "file:///docs/a.md" {
+		t.Errorf("ListResourcesFiltered() = %v, want only file:///docs/a.md", resources)
+	}
+
+	var validationErr *domain.ValidationError
+	if _, err := service.ListResourcesFiltered(ctx, domain.ResourceFilter{Pattern: "["}); !errors.As(err, &validationErr) {
+		t.Errorf("ListResourcesFiltered() error = %v, want validation error", err)
+	}
+}
+
 func TestServerService_Tool(t *testing.T) {
 	// Setup
 	ctx := context.Background()
@@ -506,6 +615,367 @@ func TestServerService_Tool(t *testing.T) {
 	}
 }
 
+func TestServerService_CallTool(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	service := createTestServerService(nil, nil, nil, nil, nil)
+
+	// Test AddToolWithHandler
+	err := service.AddToolWithHandler(ctx, &domain.Tool{Name: "greet"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return "hello " + args["name"].(string), nil
+	})
+	if err != nil {
+		t.Fatalf("AddToolWithHandler() error = %v", err)
+	}
+
+	// Test successful dispatch
+	result, err := service.CallTool(ctx, "greet", map[string]interface{}{"name": "Ada"})
+	if err != nil {
+		t.Errorf("CallTool() error = %v", err)
+	}
+	if result != "hello Ada" {
+		t.Errorf("CallTool() = %v, want %v", result, "hello Ada")
+	}
+
+	// Test unknown tool
+	_, err = service.CallTool(ctx, "missing", nil)
+	if _, ok := err.(*domain.ToolNotFoundError); !ok {
+		t.Errorf("CallTool() error = %v, want *domain.ToolNotFoundError", err)
+	}
+
+	// Test tool registered without a handler
+	if err := service.AddTool(ctx, &domain.Tool{Name: "no-handler"}); err != nil {
+		t.Fatalf("AddTool() error = %v", err)
+	}
+	_, err = service.CallTool(ctx, "no-handler", nil)
+	if _, ok := err.(*ToolHandlerNotFoundError); !ok {
+		t.Errorf("CallTool() error = %v, want *ToolHandlerNotFoundError", err)
+	}
+
+	// Test handler is removed with the tool
+	if err := service.DeleteTool(ctx, "greet"); err != nil {
+		t.Fatalf("DeleteTool() error = %v", err)
+	}
+	if err := service.AddTool(ctx, &domain.Tool{Name: "greet"}); err != nil {
+		t.Fatalf("AddTool() error = %v", err)
+	}
+	_, err = service.CallTool(ctx, "greet", nil)
+	if _, ok := err.(*ToolHandlerNotFoundError); !ok {
+		t.Errorf("CallTool() error = %v, want *ToolHandlerNotFoundError after deletion", err)
+	}
+}
+
+func TestServerService_UpdateTool(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	sender := NewMockNotificationSender()
+	service := createTestServerService(nil, nil, nil, nil, sender)
+	handler := func(reply string) ToolHandlerFunc {
+		return func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			return reply, nil
+		}
+	}
+
+	// Updating a missing tool does not create it
+	err := service.UpdateTool(ctx, &domain.Tool{Name: "greet"}, handler("new"))
+	if _, ok := err.(*domain.ToolNotFoundError); !ok {
+		t.Fatalf("UpdateTool() error = %v, want *domain.ToolNotFoundError", err)
+	}
+	if len(sender.notifications) != 0 {
+		t.Errorf("UpdateTool() sent %d notifications for a missing tool, want 0", len(sender.notifications))
+	}
+
+	if err := service.AddToolWithHandler(ctx, &domain.Tool{Name: "greet"}, handler("old")); err != nil {
+		t.Fatalf("AddToolWithHandler() error = %v", err)
+	}
+	sender.notifications = nil
+
+	// The definition and handler are replaced with a single notification
+	updated := &domain.Tool{Name: "greet", Description: "updated"}
+	if err := service.UpdateTool(ctx, updated, handler("new")); err != nil {
+		t.Fatalf("UpdateTool() error = %v", err)
+	}
+	if len(sender.notifications) != 1 {
+		t.Errorf("UpdateTool() sent %d notifications, want 1", len(sender.notifications))
+	}
+	tool, err := service.GetTool(ctx, "greet")
+	if err != nil || tool.Description != "updated" {
+		t.Errorf("GetTool() = %v, %v, want the updated tool", tool, err)
+	}
+	result, err := service.CallTool(ctx, "greet", nil)
+	if err != nil || result != "new" {
+		t.Errorf("CallTool() = %v, %v, want %v", result, err, "new")
+	}
+}
+
+func TestServerService_UpdateToolIsAtomic(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	service := createTestServerService(nil, nil, nil, nil, NewMockNotificationSender())
+	version := func(v string) (*domain.Tool, ToolHandlerFunc) {
+		tool := &domain.Tool{
+			Name:       "versioned",
+			Parameters: []domain.ToolParameter{{Name: "version", Type: "string", Default: v}},
+		}
+		handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			if args["version"] != v {
+				return nil, fmt.Errorf("handler %s called with the definition of %v", v, args["version"])
+			}
+			return v, nil
+		}
+		return tool, handler
+	}
+	tool, handler := version("v0")
+	if err := service.AddToolWithHandler(ctx, tool, handler); err != nil {
+		t.Fatalf("AddToolWithHandler() error = %v", err)
+	}
+
+	// Calls racing with updates always see a matching definition and handler
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			tool, handler := version(fmt.Sprintf("v%d", i%2))
+			if err := service.UpdateTool(ctx, tool, handler); err != nil {
+				t.Errorf("UpdateTool() error = %v", err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		if _, err := service.CallTool(ctx, "versioned", nil); err != nil {
+			t.Fatalf("CallTool() error = %v", err)
+		}
+	}
+	wg.Wait()
+}
+
+func TestServerService_ResourceSubscriptionsEndWithSession(t *testing.T) {
+	// Setup
+	ctx := domain.WithSessionID(context.Background(), "session-1")
+	sender := NewMockNotificationSender()
+	service := createTestServerService(nil, nil, nil, nil, sender)
+	if err := service.AddResource(ctx, &domain.Resource{URI: "logs://app"}); err != nil {
+		t.Fatalf("AddResource() error = %v", err)
+	}
+
+	if err := service.SubscribeResource(ctx, "logs://app"); err != nil {
+		t.Fatalf("SubscribeResource() error = %v", err)
+	}
+	if err := service.NotifyResourceUpdated(ctx, "logs://app"); err != nil {
+		t.Fatalf("NotifyResourceUpdated() error = %v", err)
+	}
+	if got := len(sender.GetSentNotifications("session-1")); got != 1 {
+		t.Fatalf("sent %d notifications, want 1", got)
+	}
+
+	// Subscriptions are dropped when the session ends
+	service.EndSession("session-1")
+	if err := service.NotifyResourceUpdated(ctx, "logs://app"); err != nil {
+		t.Fatalf("NotifyResourceUpdated() error = %v", err)
+	}
+	if got := len(sender.GetSentNotifications("session-1")); got != 1 {
+		t.Errorf("sent %d notifications after the session ended, want 1", got)
+	}
+}
+
+func TestServerService_CallToolPanic(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	service := createTestServerService(nil, nil, nil, nil, nil)
+
+	err := service.AddToolWithHandler(ctx, &domain.Tool{Name: "buggy"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		var counts map[string]int
+		counts["calls"]++
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("AddToolWithHandler() error = %v", err)
+	}
+
+	// Test that the panic is returned as an error
+	_, err = service.CallTool(ctx, "buggy", nil)
+	var panicErr *domain.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("CallTool() error = %v, want *domain.PanicError", err)
+	}
+	if len(panicErr.Stack) == 0 {
+		t.Errorf("PanicError.Stack is empty")
+	}
+}
+
+func TestServerService_CallToolRateLimit(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	service := createTestServerService(nil, nil, nil, nil, nil)
+
+	tool := &domain.Tool{
+		Name:      "expensive",
+		RateLimit: &domain.ToolRateLimit{RequestsPerSecond: 1, Burst: 2},
+	}
+	err := service.AddToolWithHandler(ctx, tool, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("AddToolWithHandler() error = %v", err)
+	}
+
+	// Calls within the burst succeed
+	for i := 0; i < 2; i++ {
+		if _, err := service.CallTool(ctx, "expensive", nil); err != nil {
+			t.Fatalf("CallTool() call %d error = %v", i+1, err)
+		}
+	}
+
+	// The next call exceeds the shared limit
+	_, err = service.CallTool(ctx, "expensive", nil)
+	if _, ok := err.(*domain.RateLimitError); !ok {
+		t.Errorf("CallTool() error = %v, want *domain.RateLimitError", err)
+	}
+}
+
+func TestServerService_CallToolContextDeadline(t *testing.T) {
+	// Setup
+	service := createTestServerService(nil, nil, nil, nil, nil)
+
+	release := make(chan struct{})
+	defer close(release)
+	err := service.AddToolWithHandler(context.Background(), &domain.Tool{Name: "slow"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		// Ignores the context to simulate a handler that hangs
+		<-release
+		return "done", nil
+	})
+	if err != nil {
+		t.Fatalf("AddToolWithHandler() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = service.CallTool(ctx, "slow", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CallTool() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestServerService_CallToolDefaults(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	service := createTestServerService(nil, nil, nil, nil, nil)
+
+	tool := &domain.Tool{
+		Name: "export",
+		Parameters: []domain.ToolParameter{
+			{Name: "format", Type: "string", Default: "json"},
+			{Name: "limit", Type: "number"},
+		},
+	}
+	var received map[string]interface{}
+	err := service.AddToolWithHandler(ctx, tool, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		received = args
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("AddToolWithHandler() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		args       map[string]interface{}
+		wantFormat interface{}
+	}{
+		{"Default injected", map[string]interface{}{}, "json"},
+		{"Nil arguments", nil, "json"},
+		{"Client value wins", map[string]interface{}{"format": "csv"}, "csv"},
+		{"Explicit null kept", map[string]interface{}{"format": nil}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.CallTool(ctx, "export", tt.args); err != nil {
+				t.Fatalf("CallTool() error = %v", err)
+			}
+			format, ok := received["format"]
+			if !ok || format != tt.wantFormat {
+				t.Errorf("format = %v (present %v), want %v", format, ok, tt.wantFormat)
+			}
+			if _, ok := received["limit"]; ok {
+				t.Errorf("limit should stay absent without a default")
+			}
+		})
+	}
+}
+
+func TestServerService_CallToolValidateOutput(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	service := createTestServerService(nil, nil, nil, nil, nil)
+
+	temperature := interface{}(12.0)
+	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{"temperature": temperature}, nil
+	}
+	outputSchema := []domain.ToolParameter{{Name: "temperature", Type: "number", Required: true}}
+
+	strict := &domain.Tool{Name: "strict", AutoWrapResults: true, OutputSchema: outputSchema, ValidateOutput: true}
+	lenient := &domain.Tool{Name: "lenient", AutoWrapResults: true, OutputSchema: outputSchema}
+	for _, tool := range []*domain.Tool{strict, lenient} {
+		if err := service.AddToolWithHandler(ctx, tool, handler); err != nil {
+			t.Fatalf("AddToolWithHandler() error = %v", err)
+		}
+	}
+
+	if _, err := service.CallTool(ctx, "strict", nil); err != nil {
+		t.Errorf("CallTool() error = %v, want nil for a conforming result", err)
+	}
+
+	temperature = "warm"
+	if _, err := service.CallTool(ctx, "strict", nil); !errors.Is(err, domain.ErrInvalidToolOutput) {
+		t.Errorf("CallTool() error = %v, want %v", err, domain.ErrInvalidToolOutput)
+	}
+	if _, err := service.CallTool(ctx, "lenient", nil); err != nil {
+		t.Errorf("CallTool() error = %v, want nil when output validation is not enabled", err)
+	}
+}
+
+type temperatureResult struct {
+	Temperature float64 `json:"temperature"`
+}
+
+func (r temperatureResult) StructuredContent() interface{} { return r }
+
+func TestServerService_CallToolStructuredResult(t *testing.T) {
+	ctx := context.Background()
+	service := createTestServerService(nil, nil, nil, nil, nil)
+
+	// Structured results are wrapped without AutoWrapResults and validated
+	tool := &domain.Tool{
+		Name:           "weather",
+		OutputSchema:   []domain.ToolParameter{{Name: "temperature", Type: "number", Required: true}},
+		ValidateOutput: true,
+	}
+	err := service.AddToolWithHandler(ctx, tool, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		return temperatureResult{Temperature: 12.5}, nil
+	})
+	if err != nil {
+		t.Fatalf("AddToolWithHandler() error = %v", err)
+	}
+
+	result, err := service.CallTool(ctx, "weather", nil)
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	wrapped := result.(map[string]interface{})
+	if wrapped["structuredContent"] != (temperatureResult{Temperature: 12.5}) {
+		t.Errorf("structuredContent = %v, want the handler result", wrapped["structuredContent"])
+	}
+	text := wrapped["content"].([]interface{})[0].(map[string]interface{})["text"]
+	if text != `{"temperature":12.5}` {
+		t.Errorf("content text = %v, want the JSON fallback", text)
+	}
+}
+
 func TestServerService_Prompt(t *testing.T) {
 	// Setup
 	ctx := context.Background()
@@ -574,6 +1044,65 @@ func TestServerService_Prompt(t *testing.T) {
 	}
 }
 
+func TestServerService_RenderPrompt(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	mockPromptRepo := NewMockPromptRepository()
+	service := createTestServerService(nil, nil, mockPromptRepo, nil, nil)
+
+	prompt := &domain.Prompt{
+		Name:        "welcome",
+		Description: "A welcome prompt",
+		Template:    "Hello, {{name}}! Welcome to {{place}}.",
+		Parameters: []domain.PromptParameter{
+			{Name: "name", Type: "string", Required: true},
+			{Name: "place", Type: "string", Required: true},
+		},
+	}
+	if err := service.AddPrompt(ctx, prompt); err != nil {
+		t.Fatalf("AddPrompt() error = %v", err)
+	}
+
+	// Test successful rendering
+	result, err := service.RenderPrompt(ctx, &domain.PromptRequest{
+		Name:       "welcome",
+		Parameters: map[string]interface{}{"name": "Ada", "place": "Paris"},
+	})
+	if err != nil {
+		t.Fatalf("RenderPrompt() error = %v", err)
+	}
+	if result.Text != "Hello, Ada! Welcome to Paris." {
+		t.Errorf("RenderPrompt().Text = %v, want %v", result.Text, "Hello, Ada! Welcome to Paris.")
+	}
+	if result.Description != prompt.Description {
+		t.Errorf("RenderPrompt().Description = %v, want %v", result.Description, prompt.Description)
+	}
+
+	// Test missing required parameter
+	_, err = service.RenderPrompt(ctx, &domain.PromptRequest{
+		Name:       "welcome",
+		Parameters: map[string]interface{}{"name": "Ada"},
+	})
+	if _, ok := err.(*domain.ValidationError); !ok {
+		t.Errorf("RenderPrompt() error = %v, want *domain.ValidationError", err)
+	}
+
+	// Test unknown prompt
+	_, err = service.RenderPrompt(ctx, &domain.PromptRequest{Name: "missing"})
+	if _, ok := err.(*domain.PromptNotFoundError); !ok {
+		t.Errorf("RenderPrompt() error = %v, want *domain.PromptNotFoundError", err)
+	}
+
+	// Test prompt without a template
+	if err := service.AddPrompt(ctx, &domain.Prompt{Name: "empty"}); err != nil {
+		t.Fatalf("AddPrompt() error = %v", err)
+	}
+	_, err = service.RenderPrompt(ctx, &domain.PromptRequest{Name: "empty"})
+	if !errors.Is(err, domain.ErrNoPromptTemplate) {
+		t.Errorf("RenderPrompt() error = %v, want %v", err, domain.ErrNoPromptTemplate)
+	}
+}
+
 func TestServerService_Session(t *testing.T) {
 	// Setup
 	ctx := context.Background()
@@ -667,8 +1196,8 @@ func TestServerService_ResourceNotifications(t *testing.T) {
 	if len(broadcastNotifications) != 1 {
 		t.Errorf("Expected 1 broadcast notification after AddResource, got %d", len(broadcastNotifications))
 	}
-	if broadcastNotifications[0].Method != "resources/list/changed" {
-		t.Errorf("Expected notification method to be 'resources/list/changed', got %s", broadcastNotifications[0].Method)
+	if broadcastNotifications[0].Method != domain.ResourceListChangedMethod {
+		t.Errorf("Expected notification method to be 'notifications/resources/list_changed', got %s", broadcastNotifications[0].Method)
 	}
 
 	// Test DeleteResource (should trigger notification)
@@ -682,12 +1211,162 @@ func TestServerService_ResourceNotifications(t *testing.T) {
 	if len(broadcastNotifications) != 2 {
 		t.Errorf("Expected 2 broadcast notifications after DeleteResource, got %d", len(broadcastNotifications))
 	}
-	if broadcastNotifications[1].Method != "resources/list/changed" {
-		t.Errorf("Expected notification method to be 'resources/list/changed', got %s", broadcastNotifications[1].Method)
+	if broadcastNotifications[1].Method != domain.ResourceListChangedMethod {
+		t.Errorf("Expected notification method to be 'notifications/resources/list_changed', got %s", broadcastNotifications[1].Method)
+	}
+}
+
+func TestServerService_ToolNotifications(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	mockToolRepo := NewMockToolRepository()
+	mockNotificationSender := NewMockNotificationSender()
+	service := createTestServerService(nil, mockToolRepo, nil, nil, mockNotificationSender)
+
+	// Test AddTool (should trigger notification)
+	tool := &domain.Tool{
+		Name:        "test-tool",
+		Description: "A test tool",
+	}
+	err := service.AddTool(ctx, tool)
+	if err != nil {
+		t.Errorf("AddTool() error = %v", err)
+	}
+
+	// Verify notification was sent
+	broadcastNotifications := mockNotificationSender.GetBroadcastNotifications()
+	if len(broadcastNotifications) != 1 {
+		t.Fatalf("Expected 1 broadcast notification after AddTool, got %d", len(broadcastNotifications))
+	}
+	if broadcastNotifications[0].Method != domain.ToolListChangedMethod {
+		t.Errorf("Expected notification method to be 'notifications/tools/list_changed', got %s", broadcastNotifications[0].Method)
+	}
+
+	// Test DeleteTool (should trigger notification)
+	err = service.DeleteTool(ctx, tool.Name)
+	if err != nil {
+		t.Errorf("DeleteTool() error = %v", err)
+	}
+
+	// Verify second notification was sent
+	broadcastNotifications = mockNotificationSender.GetBroadcastNotifications()
+	if len(broadcastNotifications) != 2 {
+		t.Fatalf("Expected 2 broadcast notifications after DeleteTool, got %d", len(broadcastNotifications))
+	}
+	if broadcastNotifications[1].Method != domain.ToolListChangedMethod {
+		t.Errorf("Expected notification method to be 'notifications/tools/list_changed', got %s", broadcastNotifications[1].Method)
+	}
+}
+
+func TestServerService_PromptNotifications(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	mockPromptRepo := NewMockPromptRepository()
+	mockNotificationSender := NewMockNotificationSender()
+	service := createTestServerService(nil, nil, mockPromptRepo, nil, mockNotificationSender)
+
+	// Test AddPrompt (should trigger notification)
+	prompt := &domain.Prompt{
+		Name:        "test-prompt",
+		Description: "A test prompt",
+	}
+	err := service.AddPrompt(ctx, prompt)
+	if err != nil {
+		t.Errorf("AddPrompt() error = %v", err)
+	}
+
+	// Verify notification was sent
+	broadcastNotifications := mockNotificationSender.GetBroadcastNotifications()
+	if len(broadcastNotifications) != 1 {
+		t.Fatalf("Expected 1 broadcast notification after AddPrompt, got %d", len(broadcastNotifications))
+	}
+	if broadcastNotifications[0].Method != domain.PromptListChangedMethod {
+		t.Errorf("Expected notification method to be 'notifications/prompts/list_changed', got %s", broadcastNotifications[0].Method)
+	}
+
+	// Test DeletePrompt (should trigger notification)
+	err = service.DeletePrompt(ctx, prompt.Name)
+	if err != nil {
+		t.Errorf("DeletePrompt() error = %v", err)
+	}
+
+	// Verify second notification was sent
+	broadcastNotifications = mockNotificationSender.GetBroadcastNotifications()
+	if len(broadcastNotifications) != 2 {
+		t.Fatalf("Expected 2 broadcast notifications after DeletePrompt, got %d", len(broadcastNotifications))
+	}
+	if broadcastNotifications[1].Method != domain.PromptListChangedMethod {
+		t.Errorf("Expected notification method to be 'notifications/prompts/list_changed', got %s", broadcastNotifications[1].Method)
 	}
 }
 
 // Helper function to create a test server service
+// errStoreUnavailable is returned by the unavailable repositories below.
+var errStoreUnavailable = errors.New("store unavailable")
+
+type unavailableResourceRepository struct{ *MockResourceRepository }
+
+func (unavailableResourceRepository) AddResource(ctx context.Context, resource *domain.Resource) error {
+	return errStoreUnavailable
+}
+
+type unavailableToolRepository struct{ *MockToolRepository }
+
+func (unavailableToolRepository) AddTool(ctx context.Context, tool *domain.Tool) error {
+	return errStoreUnavailable
+}
+
+type unavailablePromptRepository struct{ *MockPromptRepository }
+
+func (unavailablePromptRepository) AddPrompt(ctx context.Context, prompt *domain.Prompt) error {
+	return errStoreUnavailable
+}
+
+func TestServerService_FailedChangesDoNotNotify(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		change func(s *ServerService) error
+	}{
+		{"AddResource", func(s *ServerService) error {
+			return s.AddResource(ctx, &domain.Resource{URI: "file:///a"})
+		}},
+		{"ReplaceResources", func(s *ServerService) error {
+			return s.ReplaceResources(ctx, []*domain.Resource{{URI: "file:///a"}})
+		}},
+		{"AddTool", func(s *ServerService) error {
+			return s.AddTool(ctx, &domain.Tool{Name: "a"})
+		}},
+		{"ReplaceTools", func(s *ServerService) error {
+			return s.ReplaceTools(ctx, []*domain.Tool{{Name: "a"}})
+		}},
+		{"AddPrompt", func(s *ServerService) error {
+			return s.AddPrompt(ctx, &domain.Prompt{Name: "a"})
+		}},
+		{"ReplacePrompts", func(s *ServerService) error {
+			return s.ReplacePrompts(ctx, []*domain.Prompt{{Name: "a"}})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := NewMockNotificationSender()
+			service := createTestServerService(
+				unavailableResourceRepository{NewMockResourceRepository()},
+				unavailableToolRepository{NewMockToolRepository()},
+				unavailablePromptRepository{NewMockPromptRepository()},
+				nil, sender)
+
+			if err := tt.change(service); !errors.Is(err, errStoreUnavailable) {
+				t.Fatalf("%s() error = %v, want %v", tt.name, err, errStoreUnavailable)
+			}
+			if len(sender.notifications) != 0 {
+				t.Errorf("%s() sent %d notifications after failing, want 0", tt.name, len(sender.notifications))
+			}
+		})
+	}
+}
+
 func createTestServerService(
 	resourceRepo domain.ResourceRepository,
 	toolRepo domain.ToolRepository,
@@ -724,3 +1403,266 @@ func createTestServerService(
 
 	return NewServerService(config)
 }
+
+func TestServerService_ReadResource(t *testing.T) {
+	ctx := context.Background()
+	service := createTestServerService(nil, nil, nil, nil, nil)
+
+	provider := domain.ResourceContentProviderFunc(func(ctx context.Context, uri string) ([]domain.ResourceContents, error) {
+		return []domain.ResourceContents{{Text: "hello"}}, nil
+	})
+	if err := service.AddResource(ctx, &domain.Resource{URI: "file:///a.txt", MIMEType: "text/plain", ContentProvider: provider}); err != nil {
+		t.Fatalf("AddResource() error = %v", err)
+	}
+	if err := service.AddResource(ctx, &domain.Resource{URI: "file:///b.txt"}); err != nil {
+		t.Fatalf("AddResource() error = %v", err)
+	}
+
+	contents, err := service.ReadResource(ctx, "file:///a.txt")
+	if err != nil {
+		t.Fatalf("ReadResource() error = %v", err)
+	}
+	if len(contents) != 1 || contents[0].Text != "hello" {
+		t.Fatalf("ReadResource() = %+v, want one text entry", contents)
+	}
+	if contents[0].URI != "file:///a.txt" || contents[0].MIMEType != "text/plain" {
+		t.Errorf("ReadResource() should default URI and MIME type, got %+v", contents[0])
+	}
+
+	if _, err := service.ReadResource(ctx, "file:///b.txt"); !errors.Is(err, domain.ErrNoContentProvider) {
+		t.Errorf("ReadResource() error = %v, want %v", err, domain.ErrNoContentProvider)
+	}
+}
+
+func TestServerService_ReadResourceTemplate(t *testing.T) {
+	ctx := context.Background()
+	service := createTestServerService(nil, nil, nil, nil, nil)
+
+	// Without a template repository templates cannot be registered
+	if err := service.AddResourceTemplate(ctx, &domain.ResourceTemplate{URITemplate: "file:///{path}"}); err == nil {
+		t.Error("AddResourceTemplate() should fail without a resource template repository")
+	}
+	service.templateRepo = NewMockResourceTemplateRepository()
+
+	var gotVars map[string]string
+	provider := domain.ResourceTemplateContentProviderFunc(func(ctx context.Context, uri string, vars map[string]string) ([]domain.ResourceContents, error) {
+		gotVars = vars
+		return []domain.ResourceContents{{Text: "issue " + vars["id"]}}, nil
+	})
+	if err := service.AddResourceTemplate(ctx, &domain.ResourceTemplate{URITemplate: "repo://{owner}/issues/{id}", MIMEType: "text/plain", ContentProvider: provider}); err != nil {
+		t.Fatalf("AddResourceTemplate() error = %v", err)
+	}
+	if err := service.AddResourceTemplate(ctx, &domain.ResourceTemplate{URITemplate: "file:///{path}"}); err != nil {
+		t.Fatalf("AddResourceTemplate() error = %v", err)
+	}
+
+	var validationErr *domain.ValidationError
+	if err := service.AddResourceTemplate(ctx, &domain.ResourceTemplate{URITemplate: "file:///{path"}); !errors.As(err, &validationErr) {
+		t.Errorf("AddResourceTemplate() error = %v, want *domain.ValidationError", err)
+	}
+
+	templates, err := service.ListResourceTemplates(ctx)
+	if err != nil || len(templates) != 2 {
+		t.Fatalf("ListResourceTemplates() = %v, %v, want 2 templates", templates, err)
+	}
+
+	contents, err := service.ReadResource(ctx, "repo://golang/issues/42")
+	if err != nil {
+		t.Fatalf("ReadResource() error = %v", err)
+	}
+	if len(contents) != 1 || contents[0].Text != "issue 42" {
+		t.Fatalf("ReadResource() = %+v, want one text entry", contents)
+	}
+	if contents[0].URI != "repo://golang/issues/42" || contents[0].MIMEType != "text/plain" {
+		t.Errorf("ReadResource() should default URI and MIME type, got %+v", contents[0])
+	}
+	if gotVars["owner"] != "golang" || gotVars["id"] != "42" {
+		t.Errorf("provider vars = %v, want owner and id", gotVars)
+	}
+
+	if _, err := service.ReadResource(ctx, "file:///notes.txt"); !errors.Is(err, domain.ErrNoContentProvider) {
+		t.Errorf("ReadResource() error = %v, want %v", err, domain.ErrNoContentProvider)
+	}
+
+	var notFoundErr *domain.ResourceNotFoundError
+	if _, err := service.ReadResource(ctx, "http://example.com"); !errors.As(err, &notFoundErr) {
+		t.Errorf("ReadResource() error = %v, want *domain.ResourceNotFoundError", err)
+	}
+}
+
+func TestServerService_ReplaceTools(t *testing.T) {
+	ctx := context.Background()
+	mockNotificationSender := NewMockNotificationSender()
+	service := createTestServerService(nil, nil, nil, nil, mockNotificationSender)
+
+	handler := func(ctx context.Context, args map[string]interface{}) (interface{}, error) { return "ok", nil }
+	service.toolRepo.AddTool(ctx, &domain.Tool{Name: "old"})
+	service.toolRepo.AddTool(ctx, &domain.Tool{Name: "kept"})
+	service.RegisterToolHandler("old", handler)
+	service.RegisterToolHandler("kept", handler)
+
+	if err := service.ReplaceTools(ctx, []*domain.Tool{{Name: "kept"}, {Name: "new"}}); err != nil {
+		t.Fatalf("ReplaceTools() error = %v", err)
+	}
+
+	tools, _ := service.ListTools(ctx)
+	names := make(map[string]bool)
+	for _, tool := range tools {
+		names[tool.Name] = true
+	}
+	if len(tools) != 2 || !names["kept"] || !names["new"] {
+		t.Errorf("ListTools() after ReplaceTools = %v, want kept and new", names)
+	}
+
+	// Exactly one notification for the whole swap
+	broadcastNotifications := mockNotificationSender.GetBroadcastNotifications()
+	if len(broadcastNotifications) != 1 || broadcastNotifications[0].Method != domain.ToolListChangedMethod {
+		t.Errorf("Expected 1 notifications/tools/list_changed notification, got %v", broadcastNotifications)
+	}
+
+	// Handlers of removed tools are dropped, others are kept
+	if _, err := service.CallTool(ctx, "kept", nil); err != nil {
+		t.Errorf("CallTool(kept) error = %v", err)
+	}
+	var handlerErr *ToolHandlerNotFoundError
+	if _, err := service.CallTool(ctx, "new", nil); !errors.As(err, &handlerErr) {
+		t.Errorf("CallTool(new) error = %v, want *ToolHandlerNotFoundError", err)
+	}
+	service.toolHandlersMu.RLock()
+	_, oldHandler := service.toolHandlers["old"]
+	service.toolHandlersMu.RUnlock()
+	if oldHandler {
+		t.Error("handler of removed tool should be dropped")
+	}
+
+	// A tool AddTool would reject leaves the tool set unchanged, even next
+	// to valid ones
+	invalid := map[string]*domain.Tool{
+		"invalid pattern":   {Name: "bad", Parameters: []domain.ToolParameter{{Name: "p", Type: "string", Pattern: "("}}},
+		"empty name":        {Name: ""},
+		"unnamed parameter": {Name: "bad", Parameters: []domain.ToolParameter{{Type: "string"}}},
+		"unknown type":      {Name: "bad", Parameters: []domain.ToolParameter{{Name: "p", Type: "decimal"}}},
+	}
+	for name, tool := range invalid {
+		if err := service.AddTool(ctx, tool); err == nil {
+			t.Fatalf("%s: AddTool() should reject the tool", name)
+		}
+		if err := service.ReplaceTools(ctx, []*domain.Tool{{Name: "kept"}, tool}); err == nil {
+			t.Errorf("%s: ReplaceTools() should reject the tool", name)
+		}
+		if tools, _ := service.ListTools(ctx); len(tools) != 2 {
+			t.Errorf("%s: ListTools() after rejected ReplaceTools = %d tools, want 2", name, len(tools))
+		}
+	}
+}
+
+func TestServerService_ReplaceResourcesAndPrompts(t *testing.T) {
+	ctx := context.Background()
+	mockNotificationSender := NewMockNotificationSender()
+	service := createTestServerService(nil, nil, nil, nil, mockNotificationSender)
+
+	service.resourceRepo.AddResource(ctx, &domain.Resource{URI: "old://a"})
+	service.promptRepo.AddPrompt(ctx, &domain.Prompt{Name: "old"})
+
+	if err := service.ReplaceResources(ctx, []*domain.Resource{{URI: "new://a"}, {URI: "new://b"}}); err != nil {
+		t.Fatalf("ReplaceResources() error = %v", err)
+	}
+	if err := service.ReplacePrompts(ctx, []*domain.Prompt{{Name: "new"}}); err != nil {
+		t.Fatalf("ReplacePrompts() error = %v", err)
+	}
+
+	if resources, _ := service.ListResources(ctx); len(resources) != 2 {
+		t.Errorf("ListResources() = %d resources, want 2", len(resources))
+	}
+	if _, err := service.GetResource(ctx, "old://a"); err == nil {
+		t.Error("old resource should be removed")
+	}
+	if _, err := service.GetPrompt(ctx, "new"); err != nil {
+		t.Errorf("GetPrompt(new) error = %v", err)
+	}
+
+	broadcastNotifications := mockNotificationSender.GetBroadcastNotifications()
+	if len(broadcastNotifications) != 2 {
+		t.Fatalf("Expected 2 broadcast notifications, got %d", len(broadcastNotifications))
+	}
+	if broadcastNotifications[0].Method != domain.ResourceListChangedMethod || broadcastNotifications[1].Method != domain.PromptListChangedMethod {
+		t.Errorf("unexpected notifications %s, %s", broadcastNotifications[0].Method, broadcastNotifications[1].Method)
+	}
+}
+
+func TestServerService_MaxConcurrentCalls(t *testing.T) {
+	ctx := context.Background()
+	service := createTestServerService(nil, nil, nil, nil, nil)
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	err := service.AddToolWithHandler(ctx, &domain.Tool{Name: "slow"}, func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		started <- struct{}{}
+		<-unblock
+		return "done", nil
+	})
+	if err != nil {
+		t.Fatalf("AddToolWithHandler() error = %v", err)
+	}
+
+	// Calls beyond the limit are rejected
+	service.SetMaxConcurrentCalls(1, false)
+	errs := make(chan error, 2)
+	go func() {
+		_, err := service.CallTool(ctx, "slow", nil)
+		errs <- err
+	}()
+	<-started
+
+	var busyErr *domain.ServerBusyError
+	if _, err := service.CallTool(ctx, "slow", nil); !errors.As(err, &busyErr) || busyErr.Limit != 1 {
+		t.Fatalf("CallTool() error = %v, want ServerBusyError with limit 1", err)
+	}
+	if stats := service.CallConcurrency(); stats.Limit != 1 || stats.InFlight != 1 || stats.Rejected != 1 {
+		t.Errorf("CallConcurrency() = %+v, want limit 1, 1 in flight, 1 rejected", stats)
+	}
+
+	unblock <- struct{}{}
+	if err := <-errs; err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+
+	// With queueing, calls wait for a free slot
+	service.SetMaxConcurrentCalls(1, true)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := service.CallTool(ctx, "slow", nil)
+			errs <- err
+		}()
+	}
+	<-started
+	for service.CallConcurrency().Queued != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	unblock <- struct{}{}
+	<-started
+	unblock <- struct{}{}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("CallTool() error = %v", err)
+		}
+	}
+
+	// Queued calls give up when their context is done
+	go func() {
+		_, err := service.CallTool(ctx, "slow", nil)
+		errs <- err
+	}()
+	<-started
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := service.CallTool(waitCtx, "slow", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CallTool() error = %v, want context.DeadlineExceeded", err)
+	}
+	unblock <- struct{}{}
+	<-errs
+
+	if stats := service.CallConcurrency(); stats.InFlight != 0 || stats.Queued != 0 {
+		t.Errorf("CallConcurrency() = %+v, want no calls in flight or queued", stats)
+	}
+}
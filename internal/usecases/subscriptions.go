@@ -0,0 +1,99 @@
+package usecases
+
+import (
+	"context"
+	"sort"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+)
+
+// SubscribeResource subscribes the session a request came from to updates of
+// the resource at uri, see NotifyResourceUpdated. The URI must be a
+// registered resource or match a resource template. It returns a
+// domain.ValidationError for requests without a session and a
+// domain.ResourceNotFoundError for unknown URIs.
+func (s *ServerService) SubscribeResource(ctx context.Context, uri string) error {
+	sessionID := callSessionID(ctx)
+	if sessionID == "" {
+		return domain.NewValidationError("session", "resource subscriptions require a session")
+	}
+	if _, err := s.resourceRepo.GetResource(ctx, uri); err != nil {
+		if _, _, ok := s.matchResourceTemplate(ctx, uri); !ok {
+			return domain.NewResourceNotFoundError(uri)
+		}
+	}
+
+	s.subscriptionsMu.Lock()
+	defer s.subscriptionsMu.Unlock()
+	if s.subscriptions == nil {
+		s.subscriptions = make(map[string]map[string]bool)
+	}
+	uris, ok := s.subscriptions[sessionID]
+	if !ok {
+		uris = make(map[string]bool)
+		s.subscriptions[sessionID] = uris
+	}
+	uris[uri] = true
+	return nil
+}
+
+// UnsubscribeResource removes the subscription of the session a request came
+// from to the resource at uri. Unsubscribing from a resource the session is
+// not subscribed to is not an error.
+func (s *ServerService) UnsubscribeResource(ctx context.Context, uri string) error {
+	sessionID := callSessionID(ctx)
+	if sessionID == "" {
+		return domain.NewValidationError("session", "resource subscriptions require a session")
+	}
+
+	s.subscriptionsMu.Lock()
+	defer s.subscriptionsMu.Unlock()
+	if uris, ok := s.subscriptions[sessionID]; ok {
+		delete(uris, uri)
+		if len(uris) == 0 {
+			delete(s.subscriptions, sessionID)
+		}
+	}
+	return nil
+}
+
+// NotifyResourceUpdated sends notifications/resources/updated for the
+// resource at uri to every session subscribed to it. Sessions that can no
+// longer be reached are skipped; the first delivery error is returned.
+func (s *ServerService) NotifyResourceUpdated(ctx context.Context, uri string) error {
+	notification := &domain.Notification{
+		Method: "notifications/resources/updated",
+		Params: map[string]interface{}{"uri": uri},
+	}
+
+	var firstErr error
+	for _, sessionID := range s.resourceSubscribers(uri) {
+		if err := s.notificationSender.SendNotification(ctx, sessionID, notification); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// resourceSubscribers returns the IDs of the sessions subscribed to uri in
+// sorted order.
+func (s *ServerService) resourceSubscribers(uri string) []string {
+	s.subscriptionsMu.Lock()
+	defer s.subscriptionsMu.Unlock()
+
+	var sessionIDs []string
+	for sessionID, uris := range s.subscriptions {
+		if uris[uri] {
+			sessionIDs = append(sessionIDs, sessionID)
+		}
+	}
+	sort.Strings(sessionIDs)
+	return sessionIDs
+}
+
+// endSubscriptions drops the resource subscriptions of a session.
+func (s *ServerService) endSubscriptions(sessionID string) {
+	s.subscriptionsMu.Lock()
+	defer s.subscriptionsMu.Unlock()
+	delete(s.subscriptions, sessionID)
+}
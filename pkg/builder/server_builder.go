@@ -40,6 +40,14 @@ func (b *ServerBuilder) WithInstructions(instructions string) *ServerBuilder {
 	return b
 }
 
+// WithInstructionsFunc sets a function that generates the server instructions
+// when a client initializes, for example to list the tools currently
+// registered. It takes precedence over WithInstructions.
+func (b *ServerBuilder) WithInstructionsFunc(fn func(ctx context.Context) string) *ServerBuilder {
+	b.internal.WithInstructionsFunc(fn)
+	return b
+}
+
 // WithAddress sets the server address.
 func (b *ServerBuilder) WithAddress(address string) *ServerBuilder {
 	b.internal.WithAddress(address)
@@ -53,6 +61,12 @@ func (b *ServerBuilder) WithResourceRepository(repo types.ResourceRepository) *S
 	return b
 }
 
+// WithResourceTemplateRepository sets the resource template repository.
+func (b *ServerBuilder) WithResourceTemplateRepository(repo types.ResourceTemplateRepository) *ServerBuilder {
+	b.internal.WithResourceTemplateRepository(&resourceTemplateRepositoryAdapter{repo})
+	return b
+}
+
 // WithToolRepository sets the tool repository.
 func (b *ServerBuilder) WithToolRepository(repo types.ToolRepository) *ServerBuilder {
 	// Type adaptation from pkg to internal
@@ -81,24 +95,12 @@ func (b *ServerBuilder) WithNotificationSender(sender types.NotificationSender)
 	return b
 }
 
-// AddTool adds a tool to the server's tool repository.
+// AddTool adds a tool to the server's tool repository. Invalid tools and
+// tools whose name is already registered are skipped; the first such error
+// is returned by Err and ServeStdio.
 func (b *ServerBuilder) AddTool(ctx context.Context, tool *types.Tool) *ServerBuilder {
 	// Convert pkg type to internal type
-	internalTool := &internalDomain.Tool{
-		Name:        tool.Name,
-		Description: tool.Description,
-		Parameters:  make([]internalDomain.ToolParameter, len(tool.Parameters)),
-	}
-
-	// Convert parameters
-	for i, param := range tool.Parameters {
-		internalTool.Parameters[i] = internalDomain.ToolParameter{
-			Name:        param.Name,
-			Description: param.Description,
-			Type:        param.Type,
-			Required:    param.Required,
-		}
-	}
+	internalTool := toInternalTool(tool)
 
 	b.internal.AddTool(ctx, internalTool)
 	return b
@@ -107,17 +109,20 @@ func (b *ServerBuilder) AddTool(ctx context.Context, tool *types.Tool) *ServerBu
 // AddResource adds a resource to the server's resource repository.
 func (b *ServerBuilder) AddResource(ctx context.Context, resource *types.Resource) *ServerBuilder {
 	// Convert pkg type to internal type
-	internalResource := &internalDomain.Resource{
-		URI:         resource.URI,
-		Name:        resource.Name,
-		Description: resource.Description,
-		MIMEType:    resource.MIMEType,
-	}
+	internalResource := toInternalResource(resource)
 
 	b.internal.AddResource(ctx, internalResource)
 	return b
 }
 
+// AddResourceTemplate adds a resource template to the server's resource
+// template repository. Templates that are not valid RFC 6570 level 1 URI
+// templates are ignored.
+func (b *ServerBuilder) AddResourceTemplate(ctx context.Context, template *types.ResourceTemplate) *ServerBuilder {
+	b.internal.AddResourceTemplate(ctx, toInternalResourceTemplate(template))
+	return b
+}
+
 // AddPrompt adds a prompt to the server's prompt repository.
 func (b *ServerBuilder) AddPrompt(ctx context.Context, prompt *types.Prompt) *ServerBuilder {
 	// Convert pkg type to internal type
@@ -135,6 +140,7 @@ func (b *ServerBuilder) AddPrompt(ctx context.Context, prompt *types.Prompt) *Se
 			Description: param.Description,
 			Type:        param.Type,
 			Required:    param.Required,
+			Completable: param.Completable,
 		}
 	}
 
@@ -142,6 +148,43 @@ func (b *ServerBuilder) AddPrompt(ctx context.Context, prompt *types.Prompt) *Se
 	return b
 }
 
+// RemoveTool removes a tool and its handler. Clients connected to a server
+// built from this builder are notified that the tool list changed.
+func (b *ServerBuilder) RemoveTool(ctx context.Context, name string) error {
+	return b.internal.RemoveTool(ctx, name)
+}
+
+// RemoveResource removes a resource. Clients connected to a server built from
+// this builder are notified that the resource list changed.
+func (b *ServerBuilder) RemoveResource(ctx context.Context, uri string) error {
+	return b.internal.RemoveResource(ctx, uri)
+}
+
+// RemovePrompt removes a prompt. Clients connected to a server built from this
+// builder are notified that the prompt list changed.
+func (b *ServerBuilder) RemovePrompt(ctx context.Context, name string) error {
+	return b.internal.RemovePrompt(ctx, name)
+}
+
+// WithCompletionProvider adds a provider that completes prompt and resource
+// template arguments without their own completer.
+func (b *ServerBuilder) WithCompletionProvider(provider types.CompletionProvider) *ServerBuilder {
+	b.internal.WithCompletionProvider(&completionProviderAdapter{provider})
+	return b
+}
+
+// WithPromptArgumentCompleter sets the completer for one argument of a
+// prompt. It receives the partially typed value and returns candidates.
+func (b *ServerBuilder) WithPromptArgumentCompleter(prompt, argument string, completer func(ctx context.Context, value string) ([]string, error)) *ServerBuilder {
+	b.internal.WithPromptArgumentCompleter(prompt, argument, completer)
+	return b
+}
+
+// Err returns the first error registering a tool or prompt, or nil.
+func (b *ServerBuilder) Err() error {
+	return b.internal.Err()
+}
+
 // ServeStdio builds and starts serving a stdio server.
 func (b *ServerBuilder) ServeStdio(opts ...stdio.StdioOption) error {
 	return b.internal.ServeStdio(opts...)
@@ -160,12 +203,7 @@ func (a *resourceRepositoryAdapter) GetResource(ctx context.Context, uri string)
 		return nil, err
 	}
 
-	return &internalDomain.Resource{
-		URI:         resource.URI,
-		Name:        resource.Name,
-		Description: resource.Description,
-		MIMEType:    resource.MIMEType,
-	}, nil
+	return toInternalResource(resource), nil
 }
 
 func (a *resourceRepositoryAdapter) ListResources(ctx context.Context) ([]*internalDomain.Resource, error) {
@@ -176,30 +214,207 @@ func (a *resourceRepositoryAdapter) ListResources(ctx context.Context) ([]*inter
 
 	internalResources := make([]*internalDomain.Resource, len(resources))
 	for i, resource := range resources {
-		internalResources[i] = &internalDomain.Resource{
-			URI:         resource.URI,
-			Name:        resource.Name,
-			Description: resource.Description,
-			MIMEType:    resource.MIMEType,
-		}
+		internalResources[i] = toInternalResource(resource)
 	}
 
 	return internalResources, nil
 }
 
 func (a *resourceRepositoryAdapter) AddResource(ctx context.Context, resource *internalDomain.Resource) error {
-	return a.repo.AddResource(ctx, &types.Resource{
+	return a.repo.AddResource(ctx, toPkgResource(resource))
+}
+
+// toInternalResource converts a pkg resource to an internal resource.
+func toInternalResource(resource *types.Resource) *internalDomain.Resource {
+	internalResource := &internalDomain.Resource{
 		URI:         resource.URI,
 		Name:        resource.Name,
 		Description: resource.Description,
 		MIMEType:    resource.MIMEType,
-	})
+	}
+
+	switch provider := resource.ContentProvider.(type) {
+	case nil:
+	case *pkgContentProvider:
+		internalResource.ContentProvider = provider.provider
+	default:
+		internalResource.ContentProvider = &internalContentProvider{provider: provider}
+	}
+
+	return internalResource
+}
+
+// toPkgResource converts an internal resource to a pkg resource.
+func toPkgResource(resource *internalDomain.Resource) *types.Resource {
+	pkgResource := &types.Resource{
+		URI:         resource.URI,
+		Name:        resource.Name,
+		Description: resource.Description,
+		MIMEType:    resource.MIMEType,
+	}
+
+	switch provider := resource.ContentProvider.(type) {
+	case nil:
+	case *internalContentProvider:
+		pkgResource.ContentProvider = provider.provider
+	default:
+		pkgResource.ContentProvider = &pkgContentProvider{provider: provider}
+	}
+
+	return pkgResource
+}
+
+// internalContentProvider adapts a pkg ResourceContentProvider to an internal one.
+type internalContentProvider struct {
+	provider types.ResourceContentProvider
+}
+
+func (p *internalContentProvider) ReadContent(ctx context.Context, uri string) ([]internalDomain.ResourceContents, error) {
+	contents, err := p.provider.ReadContent(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	return toInternalContents(contents), nil
+}
+
+// pkgContentProvider adapts an internal ResourceContentProvider to a pkg one.
+type pkgContentProvider struct {
+	provider internalDomain.ResourceContentProvider
+}
+
+func (p *pkgContentProvider) ReadContent(ctx context.Context, uri string) ([]types.ResourceContents, error) {
+	contents, err := p.provider.ReadContent(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	return toPkgContents(contents), nil
+}
+
+// toInternalContents converts pkg resource contents to internal resource contents.
+func toInternalContents(contents []types.ResourceContents) []internalDomain.ResourceContents {
+	internalContents := make([]internalDomain.ResourceContents, len(contents))
+	for i, c := range contents {
+		internalContents[i] = internalDomain.ResourceContents{
+			URI:      c.URI,
+			MIMEType: c.MIMEType,
+			Content:  c.Content,
+			Text:     c.Text,
+		}
+	}
+	return internalContents
+}
+
+// toPkgContents converts internal resource contents to pkg resource contents.
+func toPkgContents(contents []internalDomain.ResourceContents) []types.ResourceContents {
+	pkgContents := make([]types.ResourceContents, len(contents))
+	for i, c := range contents {
+		pkgContents[i] = types.ResourceContents{
+			URI:      c.URI,
+			MIMEType: c.MIMEType,
+			Content:  c.Content,
+			Text:     c.Text,
+		}
+	}
+	return pkgContents
 }
 
 func (a *resourceRepositoryAdapter) DeleteResource(ctx context.Context, uri string) error {
 	return a.repo.DeleteResource(ctx, uri)
 }
 
+// resourceTemplateRepositoryAdapter adapts a pkg ResourceTemplateRepository to an internal one.
+type resourceTemplateRepositoryAdapter struct {
+	repo types.ResourceTemplateRepository
+}
+
+func (a *resourceTemplateRepositoryAdapter) ListResourceTemplates(ctx context.Context) ([]*internalDomain.ResourceTemplate, error) {
+	templates, err := a.repo.ListResourceTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	internalTemplates := make([]*internalDomain.ResourceTemplate, len(templates))
+	for i, template := range templates {
+		internalTemplates[i] = toInternalResourceTemplate(template)
+	}
+
+	return internalTemplates, nil
+}
+
+func (a *resourceTemplateRepositoryAdapter) AddResourceTemplate(ctx context.Context, template *internalDomain.ResourceTemplate) error {
+	return a.repo.AddResourceTemplate(ctx, toPkgResourceTemplate(template))
+}
+
+func (a *resourceTemplateRepositoryAdapter) DeleteResourceTemplate(ctx context.Context, uriTemplate string) error {
+	return a.repo.DeleteResourceTemplate(ctx, uriTemplate)
+}
+
+// toInternalResourceTemplate converts a pkg resource template to an internal resource template.
+func toInternalResourceTemplate(template *types.ResourceTemplate) *internalDomain.ResourceTemplate {
+	internalTemplate := &internalDomain.ResourceTemplate{
+		URITemplate: template.URITemplate,
+		Name:        template.Name,
+		Description: template.Description,
+		MIMEType:    template.MIMEType,
+	}
+
+	switch provider := template.ContentProvider.(type) {
+	case nil:
+	case *pkgTemplateContentProvider:
+		internalTemplate.ContentProvider = provider.provider
+	default:
+		internalTemplate.ContentProvider = &internalTemplateContentProvider{provider: provider}
+	}
+
+	return internalTemplate
+}
+
+// toPkgResourceTemplate converts an internal resource template to a pkg resource template.
+func toPkgResourceTemplate(template *internalDomain.ResourceTemplate) *types.ResourceTemplate {
+	pkgTemplate := &types.ResourceTemplate{
+		URITemplate: template.URITemplate,
+		Name:        template.Name,
+		Description: template.Description,
+		MIMEType:    template.MIMEType,
+	}
+
+	switch provider := template.ContentProvider.(type) {
+	case nil:
+	case *internalTemplateContentProvider:
+		pkgTemplate.ContentProvider = provider.provider
+	default:
+		pkgTemplate.ContentProvider = &pkgTemplateContentProvider{provider: provider}
+	}
+
+	return pkgTemplate
+}
+
+// internalTemplateContentProvider adapts a pkg ResourceTemplateContentProvider to an internal one.
+type internalTemplateContentProvider struct {
+	provider types.ResourceTemplateContentProvider
+}
+
+func (p *internalTemplateContentProvider) ReadTemplateContent(ctx context.Context, uri string, vars map[string]string) ([]internalDomain.ResourceContents, error) {
+	contents, err := p.provider.ReadTemplateContent(ctx, uri, vars)
+	if err != nil {
+		return nil, err
+	}
+	return toInternalContents(contents), nil
+}
+
+// pkgTemplateContentProvider adapts an internal ResourceTemplateContentProvider to a pkg one.
+type pkgTemplateContentProvider struct {
+	provider internalDomain.ResourceTemplateContentProvider
+}
+
+func (p *pkgTemplateContentProvider) ReadTemplateContent(ctx context.Context, uri string, vars map[string]string) ([]types.ResourceContents, error) {
+	contents, err := p.provider.ReadTemplateContent(ctx, uri, vars)
+	if err != nil {
+		return nil, err
+	}
+	return toPkgContents(contents), nil
+}
+
 // toolRepositoryAdapter adapts a pkg ToolRepository to an internal ToolRepository.
 type toolRepositoryAdapter struct {
 	repo types.ToolRepository
@@ -211,20 +426,7 @@ func (a *toolRepositoryAdapter) GetTool(ctx context.Context, name string) (*inte
 		return nil, err
 	}
 
-	internalTool := &internalDomain.Tool{
-		Name:        tool.Name,
-		Description: tool.Description,
-		Parameters:  make([]internalDomain.ToolParameter, len(tool.Parameters)),
-	}
-
-	for i, param := range tool.Parameters {
-		internalTool.Parameters[i] = internalDomain.ToolParameter{
-			Name:        param.Name,
-			Description: param.Description,
-			Type:        param.Type,
-			Required:    param.Required,
-		}
-	}
+	internalTool := toInternalTool(tool)
 
 	return internalTool, nil
 }
@@ -237,46 +439,152 @@ func (a *toolRepositoryAdapter) ListTools(ctx context.Context) ([]*internalDomai
 
 	internalTools := make([]*internalDomain.Tool, len(tools))
 	for i, tool := range tools {
-		internalTools[i] = &internalDomain.Tool{
-			Name:        tool.Name,
-			Description: tool.Description,
-			Parameters:  make([]internalDomain.ToolParameter, len(tool.Parameters)),
-		}
-
-		for j, param := range tool.Parameters {
-			internalTools[i].Parameters[j] = internalDomain.ToolParameter{
-				Name:        param.Name,
-				Description: param.Description,
-				Type:        param.Type,
-				Required:    param.Required,
-			}
-		}
+		internalTools[i] = toInternalTool(tool)
 	}
 
 	return internalTools, nil
 }
 
 func (a *toolRepositoryAdapter) AddTool(ctx context.Context, tool *internalDomain.Tool) error {
-	pkgTool := &types.Tool{
+	pkgTool := toPkgTool(tool)
+
+	return a.repo.AddTool(ctx, pkgTool)
+}
+
+func (a *toolRepositoryAdapter) DeleteTool(ctx context.Context, name string) error {
+	return a.repo.DeleteTool(ctx, name)
+}
+
+// toInternalTool converts a pkg tool to an internal tool.
+func toInternalTool(tool *types.Tool) *internalDomain.Tool {
+	internalTool := &internalDomain.Tool{
 		Name:        tool.Name,
 		Description: tool.Description,
-		Parameters:  make([]types.ToolParameter, len(tool.Parameters)),
+		Parameters:  make([]internalDomain.ToolParameter, len(tool.Parameters)),
 	}
 
 	for i, param := range tool.Parameters {
-		pkgTool.Parameters[i] = types.ToolParameter{
-			Name:        param.Name,
-			Description: param.Description,
-			Type:        param.Type,
-			Required:    param.Required,
+		internalTool.Parameters[i] = toInternalParameter(param)
+	}
+
+	if tool.RateLimit != nil {
+		internalTool.RateLimit = &internalDomain.ToolRateLimit{
+			RequestsPerSecond: tool.RateLimit.RequestsPerSecond,
+			Burst:             tool.RateLimit.Burst,
+		}
+	}
+	internalTool.Timeout = tool.Timeout
+	internalTool.LocalizedDescriptions = tool.LocalizedDescriptions
+	internalTool.AutoWrapResults = tool.AutoWrapResults
+	for _, field := range tool.OutputSchema {
+		internalTool.OutputSchema = append(internalTool.OutputSchema, toInternalParameter(field))
+	}
+	internalTool.ValidateOutput = tool.ValidateOutput
+	if tool.Annotations != nil {
+		internalTool.Annotations = &internalDomain.ToolAnnotations{
+			ReadOnlyHint:    tool.Annotations.ReadOnlyHint,
+			DestructiveHint: tool.Annotations.DestructiveHint,
+			IdempotentHint:  tool.Annotations.IdempotentHint,
+			OpenWorldHint:   tool.Annotations.OpenWorldHint,
 		}
 	}
 
-	return a.repo.AddTool(ctx, pkgTool)
+	return internalTool
+}
+
+// toInternalParameter converts a pkg tool parameter, including nested items
+// and properties, to an internal tool parameter.
+func toInternalParameter(param types.ToolParameter) internalDomain.ToolParameter {
+	internalParam := internalDomain.ToolParameter{
+		Name:             param.Name,
+		Description:      param.Description,
+		Type:             param.Type,
+		Required:         param.Required,
+		Default:          param.Default,
+		Minimum:          param.Minimum,
+		Maximum:          param.Maximum,
+		ExclusiveMinimum: param.ExclusiveMinimum,
+		ExclusiveMaximum: param.ExclusiveMaximum,
+		MinLength:        param.MinLength,
+		MaxLength:        param.MaxLength,
+		Pattern:          param.Pattern,
+	}
+
+	if param.Items != nil {
+		items := toInternalParameter(*param.Items)
+		internalParam.Items = &items
+	}
+	for _, property := range param.Properties {
+		internalParam.Properties = append(internalParam.Properties, toInternalParameter(property))
+	}
+
+	return internalParam
+}
+
+// toPkgParameter converts an internal tool parameter, including nested items
+// and properties, to a pkg tool parameter.
+func toPkgParameter(param internalDomain.ToolParameter) types.ToolParameter {
+	pkgParam := types.ToolParameter{
+		Name:             param.Name,
+		Description:      param.Description,
+		Type:             param.Type,
+		Required:         param.Required,
+		Default:          param.Default,
+		Minimum:          param.Minimum,
+		Maximum:          param.Maximum,
+		ExclusiveMinimum: param.ExclusiveMinimum,
+		ExclusiveMaximum: param.ExclusiveMaximum,
+		MinLength:        param.MinLength,
+		MaxLength:        param.MaxLength,
+		Pattern:          param.Pattern,
+	}
+
+	if param.Items != nil {
+		items := toPkgParameter(*param.Items)
+		pkgParam.Items = &items
+	}
+	for _, property := range param.Properties {
+		pkgParam.Properties = append(pkgParam.Properties, toPkgParameter(property))
+	}
+
+	return pkgParam
 }
 
-func (a *toolRepositoryAdapter) DeleteTool(ctx context.Context, name string) error {
-	return a.repo.DeleteTool(ctx, name)
+// toPkgTool converts an internal tool to a pkg tool.
+func toPkgTool(tool *internalDomain.Tool) *types.Tool {
+	pkgTool := &types.Tool{
+		Name:        tool.Name,
+		Description: tool.Description,
+		Parameters:  make([]types.ToolParameter, len(tool.Parameters)),
+	}
+
+	for i, param := range tool.Parameters {
+		pkgTool.Parameters[i] = toPkgParameter(param)
+	}
+
+	if tool.RateLimit != nil {
+		pkgTool.RateLimit = &types.ToolRateLimit{
+			RequestsPerSecond: tool.RateLimit.RequestsPerSecond,
+			Burst:             tool.RateLimit.Burst,
+		}
+	}
+	pkgTool.Timeout = tool.Timeout
+	pkgTool.LocalizedDescriptions = tool.LocalizedDescriptions
+	pkgTool.AutoWrapResults = tool.AutoWrapResults
+	for _, field := range tool.OutputSchema {
+		pkgTool.OutputSchema = append(pkgTool.OutputSchema, toPkgParameter(field))
+	}
+	pkgTool.ValidateOutput = tool.ValidateOutput
+	if tool.Annotations != nil {
+		pkgTool.Annotations = &types.ToolAnnotations{
+			ReadOnlyHint:    tool.Annotations.ReadOnlyHint,
+			DestructiveHint: tool.Annotations.DestructiveHint,
+			IdempotentHint:  tool.Annotations.IdempotentHint,
+			OpenWorldHint:   tool.Annotations.OpenWorldHint,
+		}
+	}
+
+	return pkgTool
 }
 
 // promptRepositoryAdapter adapts a pkg PromptRepository to an internal PromptRepository.
@@ -303,6 +611,7 @@ func (a *promptRepositoryAdapter) GetPrompt(ctx context.Context, name string) (*
 			Description: param.Description,
 			Type:        param.Type,
 			Required:    param.Required,
+			Completable: param.Completable,
 		}
 	}
 
@@ -330,6 +639,7 @@ func (a *promptRepositoryAdapter) ListPrompts(ctx context.Context) ([]*internalD
 				Description: param.Description,
 				Type:        param.Type,
 				Required:    param.Required,
+				Completable: param.Completable,
 			}
 		}
 	}
@@ -351,6 +661,7 @@ func (a *promptRepositoryAdapter) AddPrompt(ctx context.Context, prompt *interna
 			Description: param.Description,
 			Type:        param.Type,
 			Required:    param.Required,
+			Completable: param.Completable,
 		}
 	}
 
@@ -427,3 +738,13 @@ func (a *notificationSenderAdapter) BroadcastNotification(ctx context.Context, n
 		Params: notification.Params,
 	})
 }
+
+type completionProviderAdapter struct {
+	provider types.CompletionProvider
+}
+
+func (a *completionProviderAdapter) Complete(ctx context.Context, ref internalDomain.CompletionReference, arg internalDomain.CompletionArgument) ([]string, error) {
+	return a.provider.Complete(ctx,
+		types.CompletionReference{Type: ref.Type, Name: ref.Name, URI: ref.URI},
+		types.CompletionArgument{Name: arg.Name, Value: arg.Value})
+}
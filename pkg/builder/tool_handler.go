@@ -2,6 +2,7 @@ package builder
 
 import (
 	"context"
+	"time"
 
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/interfaces/stdio"
@@ -16,3 +17,10 @@ type ToolHandlerFunc func(ctx context.Context, params map[string]interface{}, se
 func WithToolHandler(toolName string, handler ToolHandlerFunc) stdio.StdioOption {
 	return stdio.WithToolHandler(toolName, handler)
 }
+
+// WithShutdownTimeout returns a stdio option that lets in-flight requests
+// finish and write their responses for up to timeout when ServeStdio receives
+// SIGINT or SIGTERM.
+func WithShutdownTimeout(timeout time.Duration) stdio.StdioOption {
+	return stdio.WithShutdownTimeout(timeout)
+}
@@ -0,0 +1,254 @@
+// Package client provides a minimal MCP client for servers served over HTTP.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Default retry settings, used once retries are enabled with WithRetry.
+const (
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay  = 5 * time.Second
+)
+
+// idempotentMethods are the methods that are always safe to send again.
+// tools/call is retried only with WithToolCallRetries.
+var idempotentMethods = map[string]bool{
+	"initialize":               true,
+	"ping":                     true,
+	"tools/list":               true,
+	"resources/list":           true,
+	"resources/read":           true,
+	"resources/templates/list": true,
+	"prompts/list":             true,
+	"prompts/get":              true,
+}
+
+// Error is a JSON-RPC error returned by the server.
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	return fmt.Sprintf("json-rpc error %d: %s", e.Code, e.Message)
+}
+
+// statusError is returned for HTTP responses with a non-2xx status.
+type statusError struct {
+	status int
+}
+
+// Error returns the error message.
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status %d %s", e.status, http.StatusText(e.status))
+}
+
+// Client sends JSON-RPC requests to an MCP server's HTTP endpoint.
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+	nextID     atomic.Int64
+
+	// Retry policy, see WithRetry
+	maxAttempts    int
+	baseDelay      time.Duration
+	maxDelay       time.Duration
+	retryDeadline  time.Duration
+	retryToolCalls bool
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used to send requests. The default is
+// http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetry retries requests that fail with a connection error or a 5xx
+// response, up to maxAttempts attempts in total. The delay before each retry
+// starts at baseDelay and doubles, up to WithMaxRetryDelay. Only idempotent
+// methods such as initialize and tools/list are retried; see
+// WithToolCallRetries. By default requests are sent once.
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(c *Client) {
+		c.maxAttempts = maxAttempts
+		c.baseDelay = baseDelay
+	}
+}
+
+// WithMaxRetryDelay caps the delay between retries. The default is 5 seconds.
+func WithMaxRetryDelay(delay time.Duration) Option {
+	return func(c *Client) {
+		c.maxDelay = delay
+	}
+}
+
+// WithRetryDeadline bounds the total time spent on a request, including all
+// of its retries. The request's context deadline applies as well.
+func WithRetryDeadline(deadline time.Duration) Option {
+	return func(c *Client) {
+		c.retryDeadline = deadline
+	}
+}
+
+// WithToolCallRetries retries tools/call requests as well. Enable it only if
+// the server's tools are safe to run more than once.
+func WithToolCallRetries() Option {
+	return func(c *Client) {
+		c.retryToolCalls = true
+	}
+}
+
+// New creates a client for the JSON-RPC endpoint of an MCP server, such as
+// "http://localhost:8080/jsonrpc".
+func New(endpoint string, opts ...Option) *Client {
+	c := &Client{
+		endpoint:    endpoint,
+		httpClient:  http.DefaultClient,
+		maxAttempts: 1,
+		baseDelay:   defaultRetryBaseDelay,
+		maxDelay:    defaultRetryMaxDelay,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Initialize sends the initialize request and returns the server's result.
+func (c *Client) Initialize(ctx context.Context, clientName, clientVersion string) (json.RawMessage, error) {
+	return c.Call(ctx, "initialize", map[string]interface{}{
+		"clientInfo": map[string]interface{}{"name": clientName, "version": clientVersion},
+	})
+}
+
+// ListTools returns the result of tools/list.
+func (c *Client) ListTools(ctx context.Context) (json.RawMessage, error) {
+	return c.Call(ctx, "tools/list", map[string]interface{}{})
+}
+
+// CallTool calls a tool and returns its result. It is retried only with
+// WithToolCallRetries.
+func (c *Client) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (json.RawMessage, error) {
+	return c.Call(ctx, "tools/call", map[string]interface{}{
+		"name":      name,
+		"arguments": arguments,
+	})
+}
+
+// Call sends a JSON-RPC request and returns its result. A JSON-RPC error
+// response is returned as an *Error and is never retried.
+func (c *Client) Call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      c.nextID.Add(1),
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	if c.retryDeadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.retryDeadline)
+		defer cancel()
+	}
+
+	attempts := 1
+	if c.canRetry(method) && c.maxAttempts > 1 {
+		attempts = c.maxAttempts
+	}
+
+	delay := c.baseDelay
+	for attempt := 1; ; attempt++ {
+		result, err := c.send(ctx, body)
+		if err == nil || attempt >= attempts || !retryable(err) {
+			return result, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		}
+		delay = min(delay*2, c.maxDelay)
+	}
+}
+
+// canRetry reports whether requests for method may be sent more than once.
+func (c *Client) canRetry(method string) bool {
+	return idempotentMethods[method] || (method == "tools/call" && c.retryToolCalls)
+}
+
+// send makes a single attempt at a request.
+func (c *Client) send(ctx context.Context, body []byte) (json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &statusError{status: resp.StatusCode}
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *Error          `json:"error"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.Error != nil {
+		return nil, response.Error
+	}
+	return response.Result, nil
+}
+
+// retryable reports whether a failed attempt may succeed if sent again:
+// connection errors and 5xx responses are, JSON-RPC errors, other HTTP
+// statuses and canceled requests are not.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return false
+	}
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.status >= 500
+	}
+	// Errors decoding a complete response will not change on retry
+	var syntaxErr *json.SyntaxError
+	return !errors.As(err, &syntaxErr)
+}
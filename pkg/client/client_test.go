@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedServer answers the i-th request with the i-th status, then with
+// 200 and a result once the script runs out. It records every request.
+type scriptedServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	requests []map[string]interface{}
+	times    []time.Time
+}
+
+func newScriptedServer(t *testing.T, statuses ...int) *scriptedServer {
+	t.Helper()
+
+	s := &scriptedServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]interface{}
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &request)
+
+		s.mu.Lock()
+		n := len(s.requests)
+		s.requests = append(s.requests, request)
+		s.times = append(s.times, time.Now())
+		s.mu.Unlock()
+
+		if n < len(s.statuses) {
+			w.WriteHeader(s.statuses[n])
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      request["id"],
+			"result":  map[string]interface{}{"attempt": n + 1},
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *scriptedServer) attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// received returns the requests and the times they arrived.
+func (s *scriptedServer) received() ([]map[string]interface{}, []time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]map[string]interface{}{}, s.requests...), append([]time.Time{}, s.times...)
+}
+
+func TestCallRetries(t *testing.T) {
+	unavailable, badGateway := http.StatusServiceUnavailable, http.StatusBadGateway
+
+	tests := []struct {
+		name         string
+		statuses     []int
+		opts         []Option
+		call         func(c *Client) (json.RawMessage, error)
+		wantAttempts int
+		wantStatus   int
+	}{
+		{
+			name:         "recovers within the attempts",
+			statuses:     []int{unavailable, badGateway},
+			opts:         []Option{WithRetry(3, time.Millisecond)},
+			call:         func(c *Client) (json.RawMessage, error) { return c.ListTools(context.Background()) },
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up after the attempts",
+			statuses:     []int{unavailable, unavailable, unavailable, unavailable},
+			opts:         []Option{WithRetry(3, time.Millisecond)},
+			call:         func(c *Client) (json.RawMessage, error) { return c.ListTools(context.Background()) },
+			wantAttempts: 3,
+			wantStatus:   unavailable,
+		},
+		{
+			name:         "sends once by default",
+			statuses:     []int{unavailable},
+			call:         func(c *Client) (json.RawMessage, error) { return c.ListTools(context.Background()) },
+			wantAttempts: 1,
+			wantStatus:   unavailable,
+		},
+		{
+			name:         "client errors are final",
+			statuses:     []int{http.StatusUnauthorized},
+			opts:         []Option{WithRetry(3, time.Millisecond)},
+			call:         func(c *Client) (json.RawMessage, error) { return c.ListTools(context.Background()) },
+			wantAttempts: 1,
+			wantStatus:   http.StatusUnauthorized,
+		},
+		{
+			name:     "tool calls are not retried",
+			statuses: []int{unavailable},
+			opts:     []Option{WithRetry(3, time.Millisecond)},
+			call: func(c *Client) (json.RawMessage, error) {
+				return c.CallTool(context.Background(), "charge_card", map[string]interface{}{"cents": 500})
+			},
+			wantAttempts: 1,
+			wantStatus:   unavailable,
+		},
+		{
+			name:     "tool calls are retried when allowed",
+			statuses: []int{unavailable},
+			opts:     []Option{WithRetry(3, time.Millisecond), WithToolCallRetries()},
+			call: func(c *Client) (json.RawMessage, error) {
+				return c.CallTool(context.Background(), "get_weather", map[string]interface{}{"city": "Oslo"})
+			},
+			wantAttempts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newScriptedServer(t, tt.statuses...)
+			result, err := tt.call(New(server.URL, tt.opts...))
+
+			assert.Equal(t, tt.wantAttempts, server.attempts())
+			if tt.wantStatus != 0 {
+				var statusErr *statusError
+				require.ErrorAs(t, err, &statusErr)
+				assert.Equal(t, tt.wantStatus, statusErr.status)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, fmt.Sprintf(`{"attempt":%d}`, tt.wantAttempts), string(result))
+
+			// Every attempt resends the same request
+			requests, _ := server.received()
+			for _, request := range requests[1:] {
+				assert.Equal(t, requests[0], request)
+			}
+		})
+	}
+}
+
+func TestCallDoesNotRetryJSONRPCErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		_, _ = io.WriteString(w, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"Method not found"}}`)
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL, WithRetry(5, time.Millisecond)).Call(context.Background(), "ping", nil)
+	var rpcErr *Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, -32601, rpcErr.Code)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestCallBackoff(t *testing.T) {
+	unavailable := http.StatusServiceUnavailable
+	server := newScriptedServer(t, unavailable, unavailable, unavailable)
+	c := New(server.URL, WithRetry(4, 20*time.Millisecond), WithMaxRetryDelay(30*time.Millisecond))
+
+	_, err := c.Initialize(context.Background(), "backoff-test", "1.0")
+	require.NoError(t, err)
+
+	// The delay doubles from the base delay up to the maximum
+	_, times := server.received()
+	require.Len(t, times, 4)
+	for i, want := range []time.Duration{20 * time.Millisecond, 30 * time.Millisecond, 30 * time.Millisecond} {
+		assert.GreaterOrEqual(t, times[i+1].Sub(times[i]), want, "delay before attempt %d", i+2)
+	}
+}
+
+func TestCallRetryDeadline(t *testing.T) {
+	statuses := make([]int, 10)
+	for i := range statuses {
+		statuses[i] = http.StatusInternalServerError
+	}
+	server := newScriptedServer(t, statuses...)
+	c := New(server.URL, WithRetry(10, 40*time.Millisecond), WithRetryDeadline(100*time.Millisecond))
+
+	start := time.Now()
+	_, err := c.ListTools(context.Background())
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "error = %v, want the deadline exceeded", err)
+	assert.Contains(t, err.Error(), "last error: unexpected HTTP status 500")
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Less(t, server.attempts(), 10)
+}
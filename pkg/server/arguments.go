@@ -0,0 +1,132 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+)
+
+// GetString returns the named parameter as a string. Numbers and booleans
+// are formatted. It returns a ToolError with code -32602 (invalid params) if
+// the parameter is missing or of another type, so handlers can return it
+// as is.
+func (r ToolCallRequest) GetString(name string) (string, error) {
+	value, err := r.parameter(name)
+	if err != nil {
+		return "", err
+	}
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
+	case float64, float32, int, int64, int32:
+		return fmt.Sprint(v), nil
+	}
+	return "", invalidParameter(name, "a string", value)
+}
+
+// GetFloat returns the named parameter as a float64, parsing strings. It
+// returns a ToolError with code -32602 (invalid params) if the parameter is
+// missing or not a number.
+func (r ToolCallRequest) GetFloat(name string) (float64, error) {
+	value, err := r.parameter(name)
+	if err != nil {
+		return 0, err
+	}
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f, nil
+		}
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f, nil
+		}
+	}
+	return 0, invalidParameter(name, "a number", value)
+}
+
+// GetInt returns the named parameter as an int, parsing strings. Numbers
+// with a fractional part are rejected. It returns a ToolError with code
+// -32602 (invalid params) if the parameter is missing or not an integer.
+func (r ToolCallRequest) GetInt(name string) (int, error) {
+	value, err := r.parameter(name)
+	if err != nil {
+		return 0, err
+	}
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case int32:
+		return int(v), nil
+	case string:
+		if i, err := strconv.Atoi(v); err == nil {
+			return i, nil
+		}
+	}
+
+	// Integral floats, as JSON numbers are decoded
+	f, err := r.GetFloat(name)
+	if err == nil && f == math.Trunc(f) && f >= math.MinInt && f < math.MaxInt {
+		return int(f), nil
+	}
+	return 0, invalidParameter(name, "an integer", value)
+}
+
+// GetBool returns the named parameter as a bool, parsing strings such as
+// "true" or "0". It returns a ToolError with code -32602 (invalid params) if
+// the parameter is missing or not a boolean.
+func (r ToolCallRequest) GetBool(name string) (bool, error) {
+	value, err := r.parameter(name)
+	if err != nil {
+		return false, err
+	}
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b, nil
+		}
+	}
+	return false, invalidParameter(name, "a boolean", value)
+}
+
+// parameter returns the named parameter, or an error if it is missing.
+func (r ToolCallRequest) parameter(name string) (interface{}, error) {
+	value, ok := r.Parameters[name]
+	if !ok || value == nil {
+		return nil, ToolError{Code: domain.InvalidParamsCode, Message: fmt.Sprintf("missing parameter %q", name)}
+	}
+	return value, nil
+}
+
+// invalidParameter returns the error for a parameter of the wrong type.
+func invalidParameter(name, want string, value interface{}) error {
+	got := fmt.Sprintf("%T %v", value, value)
+	if s, ok := value.(string); ok {
+		got = fmt.Sprintf("string %q", s)
+	}
+	return ToolError{
+		Code:    domain.InvalidParamsCode,
+		Message: fmt.Sprintf("parameter %q must be %s, got %s", name, want, got),
+	}
+}
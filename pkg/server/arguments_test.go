@@ -0,0 +1,130 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// searchRequest holds parameters as clients send them: JSON numbers decoded
+// as float64, numbers and booleans sent as strings, and nulls.
+var searchRequest = ToolCallRequest{
+	Name: "search",
+	Parameters: map[string]interface{}{
+		"query":     "golang mcp",
+		"limit":     float64(20),
+		"offset":    "40",
+		"threshold": 0.75,
+		"minScore":  "0.5",
+		"exact":     true,
+		"fuzzy":     "0",
+		"page":      json.Number("3"),
+		"retries":   int64(2),
+		"cursor":    nil,
+		"tags":      []interface{}{"go"},
+		"huge":      1e300,
+	},
+}
+
+// wantInvalidParams checks err is a -32602 ToolError with the given message.
+func wantInvalidParams(t *testing.T, err error, message string) {
+	t.Helper()
+	var toolErr ToolError
+	require.True(t, errors.As(err, &toolErr), "error = %v, want a ToolError", err)
+	assert.Equal(t, domain.InvalidParamsCode, toolErr.Code)
+	assert.Equal(t, message, toolErr.Message)
+}
+
+func TestGetString(t *testing.T) {
+	tests := map[string]string{
+		"query":     "golang mcp",
+		"limit":     "20",
+		"threshold": "0.75",
+		"exact":     "true",
+		"page":      "3",
+		"retries":   "2",
+	}
+	for name, want := range tests {
+		got, err := searchRequest.GetString(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+
+	_, err := searchRequest.GetString("tags")
+	wantInvalidParams(t, err, `parameter "tags" must be a string, got []interface {} [go]`)
+}
+
+func TestGetFloat(t *testing.T) {
+	tests := map[string]float64{
+		"limit":     20,
+		"offset":    40,
+		"threshold": 0.75,
+		"minScore":  0.5,
+		"page":      3,
+		"retries":   2,
+	}
+	for name, want := range tests {
+		got, err := searchRequest.GetFloat(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+
+	_, err := searchRequest.GetFloat("query")
+	wantInvalidParams(t, err, `parameter "query" must be a number, got string "golang mcp"`)
+	_, err = searchRequest.GetFloat("exact")
+	wantInvalidParams(t, err, `parameter "exact" must be a number, got bool true`)
+}
+
+func TestGetInt(t *testing.T) {
+	tests := map[string]int{
+		"limit":   20,
+		"offset":  40,
+		"page":    3,
+		"retries": 2,
+	}
+	for name, want := range tests {
+		got, err := searchRequest.GetInt(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+
+	// Fractions and numbers beyond int are not integers
+	_, err := searchRequest.GetInt("threshold")
+	wantInvalidParams(t, err, `parameter "threshold" must be an integer, got float64 0.75`)
+	_, err = searchRequest.GetInt("minScore")
+	wantInvalidParams(t, err, `parameter "minScore" must be an integer, got string "0.5"`)
+	_, err = searchRequest.GetInt("huge")
+	wantInvalidParams(t, err, `parameter "huge" must be an integer, got float64 1e+300`)
+}
+
+func TestGetBool(t *testing.T) {
+	exact, err := searchRequest.GetBool("exact")
+	require.NoError(t, err)
+	assert.True(t, exact)
+	fuzzy, err := searchRequest.GetBool("fuzzy")
+	require.NoError(t, err)
+	assert.False(t, fuzzy)
+
+	_, err = searchRequest.GetBool("query")
+	wantInvalidParams(t, err, `parameter "query" must be a boolean, got string "golang mcp"`)
+	_, err = searchRequest.GetBool("limit")
+	wantInvalidParams(t, err, `parameter "limit" must be a boolean, got float64 20`)
+}
+
+func TestAccessorsReportMissingParameters(t *testing.T) {
+	// Absent and null parameters are both missing
+	for _, name := range []string{"sort", "cursor"} {
+		_, err := searchRequest.GetString(name)
+		wantInvalidParams(t, err, `missing parameter "`+name+`"`)
+		_, err = searchRequest.GetFloat(name)
+		wantInvalidParams(t, err, `missing parameter "`+name+`"`)
+		_, err = searchRequest.GetInt(name)
+		wantInvalidParams(t, err, `missing parameter "`+name+`"`)
+		_, err = searchRequest.GetBool(name)
+		wantInvalidParams(t, err, `missing parameter "`+name+`"`)
+	}
+}
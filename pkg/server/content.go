@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// Content is a content block of a tool result. Build it with TextContent,
+// ImageContent, AudioContent or ResourceContent.
+type Content map[string]interface{}
+
+// TextContent returns a text content block.
+func TextContent(text string) Content {
+	return Content{"type": "text", "text": text}
+}
+
+// ImageContent returns an image content block with the image data base64
+// encoded, e.g. ImageContent(png, "image/png").
+func ImageContent(data []byte, mimeType string) Content {
+	return Content{
+		"type":     "image",
+		"data":     base64.StdEncoding.EncodeToString(data),
+		"mimeType": mimeType,
+	}
+}
+
+// AudioContent returns an audio content block with the audio data base64
+// encoded, e.g. AudioContent(wav, "audio/wav").
+func AudioContent(data []byte, mimeType string) Content {
+	return Content{
+		"type":     "audio",
+		"data":     base64.StdEncoding.EncodeToString(data),
+		"mimeType": mimeType,
+	}
+}
+
+// ResourceContent returns a content block referring to the resource with the
+// given URI. Clients read its contents with resources/read.
+func ResourceContent(uri string) Content {
+	return Content{
+		"type":     "resource",
+		"resource": map[string]interface{}{"uri": uri},
+	}
+}
+
+// EmbeddedResourceContent returns a resource content block that carries the
+// resource contents. Text MIME types are sent as text, anything else as a
+// base64 blob.
+func EmbeddedResourceContent(uri, mimeType string, data []byte) Content {
+	resource := map[string]interface{}{"uri": uri, "mimeType": mimeType}
+	if isTextMIMEType(mimeType) {
+		resource["text"] = string(data)
+	} else {
+		resource["blob"] = base64.StdEncoding.EncodeToString(data)
+	}
+	return Content{"type": "resource", "resource": resource}
+}
+
+// ToolResult assembles content blocks into a tool result a handler can return.
+func ToolResult(content ...Content) map[string]interface{} {
+	if content == nil {
+		content = []Content{}
+	}
+	return map[string]interface{}{"content": content}
+}
+
+// ToolErrorResult returns a tool result flagged with isError that describes a
+// tool failure. Unlike returning an error, which becomes a JSON-RPC error, it
+// is sent as a normal result so the client can show the failure to the model
+// and let it retry or adjust.
+func ToolErrorResult(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"content": []Content{TextContent(text)},
+		"isError": true,
+	}
+}
+
+// StructuredResult is implemented by tool handler results that carry
+// machine-readable data for clients, typically matching the tool's output
+// schema. The server sends StructuredContent as structuredContent and adds a
+// text fallback to content: the result's String method if it has one,
+// otherwise the JSON encoding of the structured content.
+type StructuredResult interface {
+	StructuredContent() interface{}
+}
+
+// Structured wraps a value, such as a struct or map, so a handler returns it
+// as structuredContent with its JSON text as the content fallback.
+func Structured(value interface{}) StructuredResult {
+	return structuredResult{value: value}
+}
+
+// structuredResult is the StructuredResult returned by Structured.
+type structuredResult struct {
+	value interface{}
+}
+
+// StructuredContent returns the wrapped value.
+func (r structuredResult) StructuredContent() interface{} {
+	return r.value
+}
+
+// isTextMIMEType reports whether content of the MIME type is text.
+func isTextMIMEType(mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0]))
+	switch {
+	case strings.HasPrefix(mimeType, "text/"):
+		return true
+	case mimeType == "application/json", mimeType == "application/xml",
+		mimeType == "application/javascript", mimeType == "application/yaml":
+		return true
+	case strings.HasSuffix(mimeType, "+json"), strings.HasSuffix(mimeType, "+xml"):
+		return true
+	}
+	return false
+}
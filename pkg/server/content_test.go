@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentBlocks(t *testing.T) {
+	// The first bytes of a PNG and a WAV file
+	png := []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+	wav := []byte("RIFF\x24\x00\x00\x00WAVE")
+
+	tests := []struct {
+		name    string
+		content Content
+		want    string
+	}{
+		{"text", TextContent("Build passed"), `{"type":"text","text":"Build passed"}`},
+		{"image", ImageContent(png, "image/png"), `{"type":"image","data":"iVBORw0KGgo=","mimeType":"image/png"}`},
+		{"audio", AudioContent(wav, "audio/wav"), `{"type":"audio","data":"UklGRiQAAABXQVZF","mimeType":"audio/wav"}`},
+		{"resource link", ResourceContent("file:///var/log/build.log"), `{"type":"resource","resource":{"uri":"file:///var/log/build.log"}}`},
+		{
+			"embedded text resource",
+			EmbeddedResourceContent("file:///ci.yaml", "application/yaml", []byte("steps: []")),
+			`{"type":"resource","resource":{"uri":"file:///ci.yaml","mimeType":"application/yaml","text":"steps: []"}}`,
+		},
+		{
+			"embedded binary resource",
+			EmbeddedResourceContent("file:///logo.png", "image/png", png),
+			`{"type":"resource","resource":{"uri":"file:///logo.png","mimeType":"image/png","blob":"iVBORw0KGgo="}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.content)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestToolResult(t *testing.T) {
+	got, err := json.Marshal(ToolResult(TextContent("2 files changed"), ResourceContent("git://diff")))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"content":[
+		{"type":"text","text":"2 files changed"},
+		{"type":"resource","resource":{"uri":"git://diff"}}
+	]}`, string(got))
+
+	// No blocks still encodes an empty content array, not null
+	got, err = json.Marshal(ToolResult())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"content":[]}`, string(got))
+
+	got, err = json.Marshal(ToolErrorResult("repository not found"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"content":[{"type":"text","text":"repository not found"}],"isError":true}`, string(got))
+}
+
+func TestToolResultPassesThroughCallTool(t *testing.T) {
+	ctx := context.Background()
+	s := NewMCPServer("test-server", "1.0.0")
+	require.NoError(t, s.AddTool(ctx, tools.NewTool("screenshot"), func(ctx context.Context, request ToolCallRequest) (interface{}, error) {
+		return ToolResult(TextContent("Home page"), ImageContent([]byte{1, 2, 3}, "image/png")), nil
+	}))
+
+	result, err := s.builder.BuildService().CallTool(ctx, "screenshot", map[string]interface{}{})
+	require.NoError(t, err)
+	got, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"content":[
+		{"type":"text","text":"Home page"},
+		{"type":"image","data":"AQID","mimeType":"image/png"}
+	]}`, string(got))
+}
+
+func TestIsTextMIMEType(t *testing.T) {
+	tests := map[string]bool{
+		"text/plain":                true,
+		"text/markdown":             true,
+		"Text/HTML; charset=utf-8":  true,
+		"application/json":          true,
+		"application/vnd.api+json":  true,
+		"application/atom+xml":      true,
+		"application/yaml":          true,
+		"image/png":                 false,
+		"application/octet-stream":  false,
+		"application/pdf":           false,
+		"":                          false,
+		"application/jsonl-invalid": false,
+	}
+
+	for mimeType, want := range tests {
+		assert.Equal(t, want, isTextMIMEType(mimeType), "isTextMIMEType(%q)", mimeType)
+	}
+}
@@ -0,0 +1,295 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/pkg/types"
+)
+
+// FileSystemResources exposes the files under a directory as resources with
+// file:// URIs. File contents are read when a client reads the resource.
+type FileSystemResources struct {
+	root    string
+	include []string
+	exclude []string
+	maxSize int64
+
+	// mu guards server and uris, the resources registered by the last Refresh
+	mu     sync.Mutex
+	server *MCPServer
+	uris   map[string]bool
+}
+
+// FileSystemOption configures FileSystemResources.
+type FileSystemOption func(*FileSystemResources)
+
+// WithInclude only exposes files matching one of the glob patterns. Patterns
+// use path.Match syntax and are matched against both the slash-separated path
+// relative to the root and the file name, so "*.md" matches "docs/guide.md".
+func WithInclude(patterns ...string) FileSystemOption {
+	return func(f *FileSystemResources) {
+		f.include = append(f.include, patterns...)
+	}
+}
+
+// WithExclude hides files and directories matching one of the glob patterns,
+// e.g. ".git" or "*.key". Patterns are matched like WithInclude's. Exclusion
+// takes precedence over inclusion.
+func WithExclude(patterns ...string) FileSystemOption {
+	return func(f *FileSystemResources) {
+		f.exclude = append(f.exclude, patterns...)
+	}
+}
+
+// WithMaxFileSize hides files larger than the given number of bytes.
+func WithMaxFileSize(bytes int64) FileSystemOption {
+	return func(f *FileSystemResources) {
+		f.maxSize = bytes
+	}
+}
+
+// NewFileSystemResources exposes the files under root. Register them with
+// AddFileSystemResources.
+func NewFileSystemResources(root string, opts ...FileSystemOption) (*FileSystemResources, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root %s: %w", root, err)
+	}
+	// Resolve symlinks so containment checks compare real paths
+	abs, err = filepath.EvalSymlinks(abs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root %s: %w", root, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("invalid root %s: not a directory", root)
+	}
+
+	f := &FileSystemResources{root: abs}
+	for _, opt := range opts {
+		opt(f)
+	}
+	for _, pattern := range append(append([]string{}, f.include...), f.exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+	}
+	return f, nil
+}
+
+// AddFileSystemResources registers the files exposed by f as resources. Call
+// f.Refresh to pick up files added or removed later.
+func (s *MCPServer) AddFileSystemResources(ctx context.Context, f *FileSystemResources) error {
+	f.mu.Lock()
+	f.server = s
+	f.mu.Unlock()
+	return f.Refresh(ctx)
+}
+
+// Refresh walks the root again, registering new files and removing the
+// resources of files that are gone or no longer match the filters.
+func (f *FileSystemResources) Refresh(ctx context.Context) error {
+	resources, err := f.Resources()
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.server == nil {
+		return fmt.Errorf("file system resources are not registered with a server")
+	}
+
+	uris := make(map[string]bool, len(resources))
+	for _, resource := range resources {
+		if err := f.server.AddResource(ctx, resource); err != nil {
+			return err
+		}
+		uris[resource.URI] = true
+	}
+	for uri := range f.uris {
+		if !uris[uri] {
+			f.server.builder.DeleteResource(ctx, uri)
+		}
+	}
+	f.uris = uris
+	return nil
+}
+
+// Resources walks the root and returns a resource for every exposed file, in
+// path order.
+func (f *FileSystemResources) Resources() ([]*types.Resource, error) {
+	var resources []*types.Resource
+	err := filepath.WalkDir(f.root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == f.root {
+			return nil
+		}
+		rel := f.relative(name)
+		if entry.IsDir() {
+			if matchAny(f.exclude, rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || !f.allowed(rel) {
+			return nil
+		}
+		if f.maxSize > 0 {
+			info, err := entry.Info()
+			if err != nil || info.Size() > f.maxSize {
+				return nil
+			}
+		}
+
+		resources = append(resources, &types.Resource{
+			URI:             fileURI(name),
+			Name:            rel,
+			MIMEType:        mime.TypeByExtension(filepath.Ext(name)),
+			ContentProvider: f,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", f.root, err)
+	}
+
+	sort.Slice(resources, func(i, j int) bool { return resources[i].Name < resources[j].Name })
+	return resources, nil
+}
+
+// ReadContent reads the file a file:// URI points to. URIs outside the root,
+// including through symlinks, and files hidden by the filters are reported as
+// not found.
+func (f *FileSystemResources) ReadContent(ctx context.Context, uri string) ([]types.ResourceContents, error) {
+	name, err := f.resolve(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotFound, uri)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || !info.Mode().IsRegular() || (f.maxSize > 0 && info.Size() > f.maxSize) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrNotFound, uri)
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", uri, err)
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(name))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	contents := types.ResourceContents{URI: uri, MIMEType: mimeType}
+	if isTextMIMEType(mimeType) {
+		contents.Text = string(data)
+	} else {
+		contents.Content = data
+	}
+	return []types.ResourceContents{contents}, nil
+}
+
+// resolve maps a file:// URI to a path inside the root, rejecting anything
+// that escapes it.
+func (f *FileSystemResources) resolve(uri string) (string, error) {
+	notFound := fmt.Errorf("%w: %s", domain.ErrNotFound, uri)
+
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" || (u.Host != "" && u.Host != "localhost") {
+		return "", notFound
+	}
+	name := filepath.Clean(filepath.FromSlash(u.Path))
+	if !f.contains(name) {
+		return "", notFound
+	}
+
+	// Follow symlinks and check the real path is still inside the root
+	real, err := filepath.EvalSymlinks(name)
+	if err != nil || !f.contains(real) {
+		return "", notFound
+	}
+	if !f.allowed(f.relative(real)) || !f.allowed(f.relative(name)) {
+		return "", notFound
+	}
+	return real, nil
+}
+
+// contains reports whether name is inside the root.
+func (f *FileSystemResources) contains(name string) bool {
+	rel, err := filepath.Rel(f.root, name)
+	if err != nil || rel == "." {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// relative returns the slash-separated path of name relative to the root.
+func (f *FileSystemResources) relative(name string) string {
+	rel, err := filepath.Rel(f.root, name)
+	if err != nil {
+		return name
+	}
+	return filepath.ToSlash(rel)
+}
+
+// allowed reports whether a file passes the include and exclude filters.
+// Files in excluded directories are not allowed either.
+func (f *FileSystemResources) allowed(rel string) bool {
+	for dir := path.Dir(rel); dir != "."; dir = path.Dir(dir) {
+		if matchAny(f.exclude, dir) {
+			return false
+		}
+	}
+	if matchAny(f.exclude, rel) {
+		return false
+	}
+	return len(f.include) == 0 || matchAny(f.include, rel)
+}
+
+// matchAny reports whether the path or its base name matches one of the patterns.
+func matchAny(patterns []string, rel string) bool {
+	base := path.Base(rel)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fileURI returns the file:// URI of an absolute path.
+func fileURI(name string) string {
+	slashed := filepath.ToSlash(name)
+	if !strings.HasPrefix(slashed, "/") {
+		// Windows paths such as C:/dir become file:///C:/dir
+		slashed = "/" + slashed
+	}
+	return (&url.URL{Scheme: "file", Path: slashed}).String()
+}
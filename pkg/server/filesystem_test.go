@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTree creates the files under dir, keyed by slash-separated path.
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0o644))
+	}
+}
+
+// projectDocs lays out a project directory next to a private one and returns
+// the real path of the project root and of the private directory.
+func projectDocs(t *testing.T) (root, private string) {
+	t.Helper()
+	base, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	root = filepath.Join(base, "project")
+	private = filepath.Join(base, "private")
+	writeTree(t, root, map[string]string{
+		"README.md":         "# Project",
+		"docs/guide.md":     "Guide",
+		".git/config":       "[core]",
+		"build/out/app.txt": "binary",
+	})
+	writeTree(t, private, map[string]string{"passwords.txt": "hunter2"})
+
+	if err := os.Symlink(filepath.Join(private, "passwords.txt"), filepath.Join(root, "passwords.txt")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	require.NoError(t, os.Symlink(filepath.Join(root, "README.md"), filepath.Join(root, "docs", "readme-link.md")))
+	return root, private
+}
+
+func TestFileSystemResourcesResolve(t *testing.T) {
+	root, private := projectDocs(t)
+	f, err := NewFileSystemResources(root, WithExclude(".git", "build*"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		uri  string
+		want string
+	}{
+		{"file in root", fileURI(filepath.Join(root, "README.md")), filepath.Join(root, "README.md")},
+		{"nested file", fileURI(filepath.Join(root, "docs", "guide.md")), filepath.Join(root, "docs", "guide.md")},
+		{"localhost host", "file://localhost" + filepath.ToSlash(filepath.Join(root, "README.md")), filepath.Join(root, "README.md")},
+		{"symlink within root", fileURI(filepath.Join(root, "docs", "readme-link.md")), filepath.Join(root, "README.md")},
+		{"dot-dot escape", "file://" + filepath.ToSlash(root) + "/../private/passwords.txt", ""},
+		{"dot-dot back into root", "file://" + filepath.ToSlash(root) + "/docs/../README.md", filepath.Join(root, "README.md")},
+		{"symlink escape", fileURI(filepath.Join(root, "passwords.txt")), ""},
+		{"file outside root", fileURI(filepath.Join(private, "passwords.txt")), ""},
+		{"root itself", fileURI(root), ""},
+		{"excluded directory", fileURI(filepath.Join(root, ".git", "config")), ""},
+		{"excluded directory glob", fileURI(filepath.Join(root, "build", "out", "app.txt")), ""},
+		{"missing file", fileURI(filepath.Join(root, "missing.md")), ""},
+		{"other scheme", "https://example.com/README.md", ""},
+		{"remote host", "file://fileserver" + filepath.ToSlash(filepath.Join(root, "README.md")), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.resolve(tt.uri)
+			if tt.want == "" {
+				assert.True(t, errors.Is(err, domain.ErrNotFound), "resolve(%q) error = %v, want not found", tt.uri, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFileSystemResourcesContains(t *testing.T) {
+	root := filepath.FromSlash("/srv/docs")
+	f := &FileSystemResources{root: root}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"child", filepath.Join(root, "a.md"), true},
+		{"grandchild", filepath.Join(root, "sub", "a.md"), true},
+		{"name starting with dots", filepath.Join(root, "..notes"), true},
+		{"root", root, false},
+		{"parent", filepath.Dir(root), false},
+		{"sibling with common prefix", root + "-old", false},
+		{"escape through parent", filepath.Join(root, "..", "etc", "passwd"), false},
+		{"unrelated", filepath.FromSlash("/etc/passwd"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, f.contains(tt.path))
+		})
+	}
+}
+
+func TestFileSystemResourcesExcludedDirectories(t *testing.T) {
+	root, _ := projectDocs(t)
+
+	tests := []struct {
+		name    string
+		exclude []string
+		want    []string
+	}{
+		{"no filters", nil, []string{".git/config", "README.md", "build/out/app.txt", "docs/guide.md"}},
+		{"directory name", []string{".git"}, []string{"README.md", "build/out/app.txt", "docs/guide.md"}},
+		{"directory glob", []string{"bui*"}, []string{".git/config", "README.md", "docs/guide.md"}},
+		{"nested directory path", []string{"build/out"}, []string{".git/config", "README.md", "docs/guide.md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFileSystemResources(root, WithExclude(tt.exclude...))
+			require.NoError(t, err)
+			resources, err := f.Resources()
+			require.NoError(t, err)
+
+			// Symlinks are not listed, whether or not they escape the root
+			var names []string
+			for _, resource := range resources {
+				names = append(names, resource.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestFileSystemResourcesRefresh(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"a.txt": "a", "b.txt": "b"})
+
+	s := NewMCPServer("test-server", "1.0.0")
+	f, err := NewFileSystemResources(root)
+	require.NoError(t, err)
+	require.NoError(t, s.AddFileSystemResources(ctx, f))
+
+	listed := func() []string {
+		resources, err := s.builder.BuildService().ListResources(ctx)
+		require.NoError(t, err)
+		var names []string
+		for _, resource := range resources {
+			names = append(names, resource.Name)
+		}
+		return names
+	}
+	assert.ElementsMatch(t, []string{"a.txt", "b.txt"}, listed())
+
+	// Deleted files are dropped and new ones picked up
+	require.NoError(t, os.Remove(filepath.Join(root, "a.txt")))
+	writeTree(t, root, map[string]string{"c.txt": "c"})
+	require.NoError(t, f.Refresh(ctx))
+	assert.ElementsMatch(t, []string{"b.txt", "c.txt"}, listed())
+
+	// Refreshing without a server is an error
+	unregistered, err := NewFileSystemResources(root)
+	require.NoError(t, err)
+	assert.Error(t, unregistered.Refresh(ctx))
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+)
+
+// ClientLogger sends log messages from a tool handler to the client that
+// called the tool as notifications/message. Messages below the level set with
+// logging/setLevel are dropped. Over stdio they are written to the client;
+// over HTTP they are sent to the calling session, or to all sessions for
+// requests made without one.
+type ClientLogger struct {
+	ctx context.Context
+	log domain.ClientLogFunc
+}
+
+// LoggerFromContext returns the logger for the tool call in ctx. Outside a
+// tool handler it returns a logger that discards messages.
+func LoggerFromContext(ctx context.Context) *ClientLogger {
+	log, _ := domain.ClientLogFromContext(ctx)
+	return &ClientLogger{ctx: ctx, log: log}
+}
+
+// Debug sends a debug message.
+func (l *ClientLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.send("debug", msg, fields)
+}
+
+// Info sends an informational message.
+func (l *ClientLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.send("info", msg, fields)
+}
+
+// Warning sends a warning.
+func (l *ClientLogger) Warning(msg string, fields ...map[string]interface{}) {
+	l.send("warning", msg, fields)
+}
+
+// Error sends an error message.
+func (l *ClientLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.send("error", msg, fields)
+}
+
+// send forwards the message to the client. A message without fields is sent
+// as a string; with fields, as an object holding the message and the fields.
+func (l *ClientLogger) send(level, msg string, fields []map[string]interface{}) {
+	if l.log == nil {
+		return
+	}
+
+	var data interface{} = msg
+	if len(fields) > 0 {
+		object := map[string]interface{}{}
+		for _, f := range fields {
+			for k, v := range f {
+				object[k] = v
+			}
+		}
+		object["message"] = msg
+		data = object
+	}
+	_ = l.log(l.ctx, level, "", data)
+}
+
+// withToolLogger names the tool as the source of log messages sent from its
+// handler.
+func withToolLogger(ctx context.Context, toolName string) context.Context {
+	log, ok := domain.ClientLogFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return domain.WithClientLog(ctx, func(ctx context.Context, level, logger string, data interface{}) error {
+		if logger == "" {
+			logger = toolName
+		}
+		return log(ctx, level, logger, data)
+	})
+}
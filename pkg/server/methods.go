@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/interfaces/rest"
+)
+
+// MethodHandler handles a custom JSON-RPC method. Return a ToolError to
+// control the error code sent to the client; other errors are sent as -32603
+// (internal error).
+type MethodHandler func(ctx context.Context, params json.RawMessage) (interface{}, error)
+
+// ErrReservedMethod is returned by AddMethodHandler for methods defined by
+// the MCP protocol, such as "initialize" or anything under "tools/".
+var ErrReservedMethod = rest.ErrReservedMethod
+
+// AddMethodHandler registers a handler for a custom JSON-RPC method, such as
+// an experimental or vendor-specific extension, over both HTTP and stdio.
+// Methods reserved by the MCP protocol are rejected so they cannot be
+// shadowed.
+func (s *MCPServer) AddMethodHandler(method string, handler MethodHandler) error {
+	if method == "" {
+		return fmt.Errorf("method name is required")
+	}
+	if handler == nil {
+		return fmt.Errorf("handler for method %s is nil", method)
+	}
+	if rest.IsReservedMethod(method) {
+		return fmt.Errorf("%w: %s", ErrReservedMethod, method)
+	}
+
+	adapted := func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		result, err := handler(ctx, params)
+		if err != nil {
+			return nil, toInternalError(err)
+		}
+		return result, nil
+	}
+	s.builder.WithMethodHandler(method, adapted)
+
+	// Make the method available on a server that is already running
+	s.httpMu.Lock()
+	mcpServer := s.httpServer
+	s.httpMu.Unlock()
+	if mcpServer != nil {
+		return mcpServer.AddMethodHandler(method, adapted)
+	}
+	return nil
+}
+
+// RequestInterceptor inspects every incoming JSON-RPC message, including
+// notifications, before it is dispatched. Return a ToolError to control the
+// error code sent to the client; other errors are sent as -32600 (invalid
+// request). Rejected notifications are dropped.
+type RequestInterceptor func(ctx context.Context, method string, params json.RawMessage) error
+
+// WithRequestInterceptor adds an interceptor that runs on every message
+// before method dispatch over both HTTP and stdio, covering protocol methods
+// such as initialize and resources/list as well as tool calls. Use it for
+// global authorization, audit logging or protocol version gating.
+// Interceptors run in the order they are added and the first error rejects
+// the message.
+func WithRequestInterceptor(interceptor RequestInterceptor) Option {
+	return func(s *MCPServer) {
+		if interceptor == nil {
+			return
+		}
+		s.builder.WithRequestInterceptor(func(ctx context.Context, method string, params json.RawMessage) error {
+			if err := interceptor(ctx, method, params); err != nil {
+				return toInternalError(err)
+			}
+			return nil
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ToolMiddleware wraps a tool handler to add behavior such as logging, metrics
+// or authorization around every tool call. A middleware can short-circuit the
+// call by returning without calling next.
+type ToolMiddleware func(next ToolHandler) ToolHandler
+
+// UseToolMiddleware wraps every tool handler, including those registered
+// later, with the given middleware. Middleware runs in registration order:
+// the first one registered is the outermost and sees the call first.
+func (s *MCPServer) UseToolMiddleware(mw ...ToolMiddleware) {
+	s.middlewareMu.Lock()
+	defer s.middlewareMu.Unlock()
+	s.middleware = append(s.middleware, mw...)
+}
+
+// wrapToolHandler applies the registered middleware to a handler.
+func (s *MCPServer) wrapToolHandler(handler ToolHandler) ToolHandler {
+	s.middlewareMu.RLock()
+	defer s.middlewareMu.RUnlock()
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		handler = s.middleware[i](handler)
+	}
+	return handler
+}
+
+// LoggingMiddleware logs the name, duration and error of every tool call. A
+// nil logger uses the standard logger.
+func LoggingMiddleware(logger *log.Logger) ToolMiddleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, request ToolCallRequest) (interface{}, error) {
+			start := time.Now()
+			result, err := next(ctx, request)
+			if err != nil {
+				logger.Printf("tool=%s duration=%s error=%v", request.Name, time.Since(start), err)
+			} else {
+				logger.Printf("tool=%s duration=%s", request.Name, time.Since(start))
+			}
+			return result, err
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolMiddlewareOrder(t *testing.T) {
+	ctx := context.Background()
+	s := NewMCPServer("test-server", "1.0.0")
+
+	var trace []string
+	traced := func(name string) ToolMiddleware {
+		return func(next ToolHandler) ToolHandler {
+			return func(ctx context.Context, request ToolCallRequest) (interface{}, error) {
+				trace = append(trace, name+" before "+request.Name)
+				result, err := next(ctx, request)
+				trace = append(trace, name+" after "+request.Name)
+				return result, err
+			}
+		}
+	}
+	handler := func(ctx context.Context, request ToolCallRequest) (interface{}, error) {
+		trace = append(trace, "handler "+request.Name)
+		return "done", nil
+	}
+
+	// Middleware also wraps tools registered before it
+	require.NoError(t, s.AddTool(ctx, tools.NewTool("early"), handler))
+	s.UseToolMiddleware(traced("auth"), traced("audit"))
+	require.NoError(t, s.AddTool(ctx, tools.NewTool("late"), handler))
+
+	service := s.builder.BuildService()
+	for _, name := range []string{"early", "late"} {
+		trace = nil
+		_, err := service.CallTool(ctx, name, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"auth before " + name,
+			"audit before " + name,
+			"handler " + name,
+			"audit after " + name,
+			"auth after " + name,
+		}, trace)
+	}
+}
+
+func TestToolMiddlewareShortCircuit(t *testing.T) {
+	ctx := context.Background()
+	s := NewMCPServer("test-server", "1.0.0")
+
+	errForbidden := errors.New("forbidden")
+	s.UseToolMiddleware(func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, request ToolCallRequest) (interface{}, error) {
+			if request.Parameters["token"] != "letmein" {
+				return nil, errForbidden
+			}
+			return next(ctx, request)
+		}
+	})
+	calls := 0
+	require.NoError(t, s.AddTool(ctx, tools.NewTool("deploy", tools.WithString("token")), func(ctx context.Context, request ToolCallRequest) (interface{}, error) {
+		calls++
+		return "deployed", nil
+	}))
+	service := s.builder.BuildService()
+
+	_, err := service.CallTool(ctx, "deploy", map[string]interface{}{"token": "guess"})
+	assert.ErrorIs(t, err, errForbidden)
+	assert.Zero(t, calls, "a rejected call should not reach the handler")
+
+	_, err = service.CallTool(ctx, "deploy", map[string]interface{}{"token": "letmein"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	ctx := context.Background()
+	s := NewMCPServer("test-server", "1.0.0")
+	var logged bytes.Buffer
+	s.UseToolMiddleware(LoggingMiddleware(log.New(&logged, "", 0)))
+
+	errDisk := errors.New("disk full")
+	require.NoError(t, s.AddTool(ctx, tools.NewTool("save", tools.WithBoolean("fail")), func(ctx context.Context, request ToolCallRequest) (interface{}, error) {
+		if request.Parameters["fail"] == true {
+			return nil, errDisk
+		}
+		return "saved", nil
+	}))
+	service := s.builder.BuildService()
+
+	_, err := service.CallTool(ctx, "save", map[string]interface{}{"fail": false})
+	require.NoError(t, err)
+	assert.Regexp(t, `^tool=save duration=\S+\n$`, logged.String())
+
+	logged.Reset()
+	_, err = service.CallTool(ctx, "save", map[string]interface{}{"fail": true})
+	assert.ErrorIs(t, err, errDisk)
+	assert.Regexp(t, `^tool=save duration=\S+ error=disk full\n$`, logged.String())
+}
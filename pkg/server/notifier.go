@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+)
+
+// Notifier sends server-initiated notifications to connected clients, for
+// example from a background goroutine that watches for changes. Get it with
+// MCPServer.Notifier.
+type Notifier struct {
+	sender domain.NotificationSender
+}
+
+// Notifier returns the notifier for the server's clients. It reaches clients
+// connected over SSE and stdio, including servers started later with
+// ServeHTTP, ServeStdio or Serve.
+func (s *MCPServer) Notifier() *Notifier {
+	return &Notifier{sender: s.builder.NotificationSender()}
+}
+
+// Broadcast sends a notification to every connected client. params is sent
+// as the notification's params and must marshal to a JSON object, such as a
+// map or a struct; nil sends none. Clients whose notification queue is full
+// are skipped and the first such error is returned.
+func (n *Notifier) Broadcast(ctx context.Context, method string, params interface{}) error {
+	notification, err := newNotification(method, params)
+	if err != nil {
+		return err
+	}
+	return n.sender.BroadcastNotification(ctx, notification)
+}
+
+// Send sends a notification to one session, see SessionIDs and
+// SessionFromContext. params is encoded as for Broadcast. It returns an error
+// if the session is not connected.
+func (n *Notifier) Send(ctx context.Context, sessionID, method string, params interface{}) error {
+	notification, err := newNotification(method, params)
+	if err != nil {
+		return err
+	}
+	return n.sender.SendNotification(ctx, sessionID, notification)
+}
+
+// newNotification builds a notification, marshaling params to a JSON object.
+func newNotification(method string, params interface{}) (*domain.Notification, error) {
+	if method == "" {
+		return nil, fmt.Errorf("notification method cannot be empty")
+	}
+
+	notification := &domain.Notification{Method: method}
+	switch p := params.(type) {
+	case nil:
+	case map[string]interface{}:
+		notification.Params = p
+	default:
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal params for %s: %w", method, err)
+		}
+		if err := json.Unmarshal(data, &notification.Params); err != nil {
+			return nil, fmt.Errorf("params for %s must be a JSON object: %w", method, err)
+		}
+	}
+	return notification, nil
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nextNotification waits briefly for a notification on ch.
+func nextNotification(t *testing.T, ch <-chan server.JSONRPCNotification) server.JSONRPCNotification {
+	t.Helper()
+	select {
+	case notification := <-ch:
+		return notification
+	case <-time.After(time.Second):
+		t.Fatal("no notification received")
+		return server.JSONRPCNotification{}
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	ctx := context.Background()
+	s := NewMCPServer("test-server", "1.0.0")
+
+	// The notifier is taken before the server that delivers its notifications is built
+	notifier := s.Notifier()
+	mcpServer := s.builder.BuildMCPServer()
+	editor, stopEditor := mcpServer.RegisterNotificationSession("editor")
+	defer stopEditor()
+	terminal, stopTerminal := mcpServer.RegisterNotificationSession("terminal")
+	defer stopTerminal()
+
+	type buildStatus struct {
+		Branch string `json:"branch"`
+		Passed bool   `json:"passed"`
+	}
+	require.NoError(t, notifier.Broadcast(ctx, "notifications/build", buildStatus{Branch: "main", Passed: true}))
+	for _, ch := range []<-chan server.JSONRPCNotification{editor, terminal} {
+		notification := nextNotification(t, ch)
+		assert.Equal(t, "notifications/build", notification.Method)
+		assert.Equal(t, map[string]interface{}{"branch": "main", "passed": true}, notification.Params)
+	}
+
+	// Send reaches only the named session
+	require.NoError(t, notifier.Send(ctx, "terminal", "notifications/progress", map[string]interface{}{"percent": 50}))
+	notification := nextNotification(t, terminal)
+	assert.Equal(t, "notifications/progress", notification.Method)
+	assert.Equal(t, map[string]interface{}{"percent": 50}, notification.Params)
+	select {
+	case notification := <-editor:
+		t.Errorf("editor received %s sent to terminal", notification.Method)
+	default:
+	}
+
+	require.NoError(t, notifier.Broadcast(ctx, "notifications/ping", nil))
+	assert.Nil(t, nextNotification(t, editor).Params)
+
+	assert.Error(t, notifier.Send(ctx, "closed-session", "notifications/progress", nil))
+}
+
+func TestNotifierRejectsInvalidNotifications(t *testing.T) {
+	notifier := NewMCPServer("test-server", "1.0.0").Notifier()
+	ctx := context.Background()
+
+	assert.ErrorContains(t, notifier.Broadcast(ctx, "", nil), "method cannot be empty")
+	assert.ErrorContains(t, notifier.Broadcast(ctx, "notifications/count", 42), "must be a JSON object")
+	assert.ErrorContains(t, notifier.Broadcast(ctx, "notifications/count", []string{"a"}), "must be a JSON object")
+	assert.ErrorContains(t, notifier.Send(ctx, "any", "notifications/count", func() {}), "failed to marshal params")
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// PluginAPIVersion is the version of the plugin contract implemented by this
+// package. It changes whenever the contract below changes incompatibly.
+const PluginAPIVersion = 1
+
+const (
+	// registerToolsSymbol is the function every plugin must export.
+	registerToolsSymbol = "RegisterTools"
+	// apiVersionSymbol is the optional variable a plugin exports to declare
+	// the PluginAPIVersion it was written against.
+	apiVersionSymbol = "PluginAPIVersion"
+)
+
+// LoadPlugin opens a Go plugin (.so file) and lets it register its tools on
+// the server. Call it before ServeStdio or ServeHTTP.
+//
+// The plugin contract:
+//
+//	package main
+//
+//	import "github.com/FreePeak/golang-mcp-server-sdk/pkg/server"
+//
+//	// Optional; checked against server.PluginAPIVersion when present.
+//	var PluginAPIVersion = server.PluginAPIVersion
+//
+//	func RegisterTools(s *server.MCPServer) error {
+//		return s.AddTool(context.Background(), myTool, myHandler)
+//	}
+//
+// Built with: go build -buildmode=plugin -o tools.so ./path/to/plugin
+//
+// Limitations of Go's plugin package apply: plugins are only supported on
+// Linux, FreeBSD and macOS with cgo enabled; the plugin and the server must be
+// built with the same Go toolchain, build flags and versions of every shared
+// package (including this SDK); and a plugin cannot be unloaded once opened.
+func (s *MCPServer) LoadPlugin(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open plugin %s (it must be built with the same Go version and dependency versions as the server): %w", path, err)
+	}
+	return s.registerPlugin(path, p.Lookup)
+}
+
+// registerPlugin checks the symbols an opened plugin exports and runs its
+// RegisterTools function.
+func (s *MCPServer) registerPlugin(path string, lookup func(name string) (plugin.Symbol, error)) error {
+	if sym, err := lookup(apiVersionSymbol); err == nil {
+		version, ok := sym.(*int)
+		if !ok {
+			return fmt.Errorf("plugin %s: %s has type %T, want int", path, apiVersionSymbol, sym)
+		}
+		if *version != PluginAPIVersion {
+			return fmt.Errorf("plugin %s: built for plugin API version %d, server supports version %d", path, *version, PluginAPIVersion)
+		}
+	}
+
+	sym, err := lookup(registerToolsSymbol)
+	if err != nil {
+		return fmt.Errorf("plugin %s does not export %s: %w", path, registerToolsSymbol, err)
+	}
+
+	register, ok := sym.(func(*MCPServer) error)
+	if !ok {
+		return fmt.Errorf("plugin %s: %s has type %T, want func(*server.MCPServer) error", path, registerToolsSymbol, sym)
+	}
+
+	if err := register(s); err != nil {
+		return fmt.Errorf("plugin %s failed to register tools: %w", path, err)
+	}
+	return nil
+}
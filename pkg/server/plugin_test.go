@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"plugin"
+	"testing"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePlugin stands in for an opened .so file, exporting the given symbols.
+type fakePlugin map[string]plugin.Symbol
+
+func (p fakePlugin) Lookup(name string) (plugin.Symbol, error) {
+	if sym, ok := p[name]; ok {
+		return sym, nil
+	}
+	return nil, fmt.Errorf("plugin: symbol %s not found", name)
+}
+
+func TestRegisterPlugin(t *testing.T) {
+	current, future := PluginAPIVersion, PluginAPIVersion+1
+	errRegister := errors.New("weather API key missing")
+	addWeather := func(s *MCPServer) error {
+		return s.AddTool(context.Background(), tools.NewTool("weather"), func(ctx context.Context, req ToolCallRequest) (interface{}, error) {
+			return "sunny", nil
+		})
+	}
+
+	tests := []struct {
+		name      string
+		symbols   fakePlugin
+		wantErr   string
+		wantIs    error
+		wantTools []string
+	}{
+		{
+			name:      "current API version",
+			symbols:   fakePlugin{"PluginAPIVersion": &current, "RegisterTools": addWeather},
+			wantTools: []string{"weather"},
+		},
+		{
+			name:      "no declared API version",
+			symbols:   fakePlugin{"RegisterTools": addWeather},
+			wantTools: []string{"weather"},
+		},
+		{
+			name:    "newer API version",
+			symbols: fakePlugin{"PluginAPIVersion": &future, "RegisterTools": addWeather},
+			wantErr: fmt.Sprintf("built for plugin API version %d, server supports version %d", future, current),
+		},
+		{
+			name:    "API version of the wrong type",
+			symbols: fakePlugin{"PluginAPIVersion": new(string), "RegisterTools": addWeather},
+			wantErr: "PluginAPIVersion has type *string, want int",
+		},
+		{
+			name:    "missing RegisterTools",
+			symbols: fakePlugin{"PluginAPIVersion": &current},
+			wantErr: "does not export RegisterTools",
+		},
+		{
+			name:    "RegisterTools with the wrong signature",
+			symbols: fakePlugin{"RegisterTools": func() error { return nil }},
+			wantErr: "want func(*server.MCPServer) error",
+		},
+		{
+			name:    "RegisterTools fails",
+			symbols: fakePlugin{"RegisterTools": func(*MCPServer) error { return errRegister }},
+			wantErr: "failed to register tools",
+			wantIs:  errRegister,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewMCPServer("test-server", "1.0.0")
+			err := s.registerPlugin("weather.so", tt.symbols.Lookup)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "weather.so")
+				assert.Contains(t, err.Error(), tt.wantErr)
+				if tt.wantIs != nil {
+					assert.ErrorIs(t, err, tt.wantIs)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+
+			var names []string
+			s.toolsMu.RLock()
+			for name := range s.tools {
+				names = append(names, name)
+			}
+			s.toolsMu.RUnlock()
+			assert.ElementsMatch(t, tt.wantTools, names)
+		})
+	}
+}
+
+func TestLoadPluginMissingFile(t *testing.T) {
+	s := NewMCPServer("test-server", "1.0.0")
+	err := s.LoadPlugin(filepath.Join(t.TempDir(), "missing.so"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to open plugin")
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/pkg/types"
+)
+
+// StaticResource returns a resource that serves data from memory, for
+// exposing documents or configuration without writing a content provider.
+// The MIME type is detected from data with http.DetectContentType; set the
+// returned resource's MIMEType to override it. Text MIME types are served as
+// text contents and anything else as a base64 blob. Register it with
+// AddResource.
+func StaticResource(uri, name string, data []byte) *types.Resource {
+	resource := &types.Resource{
+		URI:      uri,
+		Name:     name,
+		MIMEType: http.DetectContentType(data),
+	}
+	resource.ContentProvider = types.ResourceContentProviderFunc(func(ctx context.Context, uri string) ([]types.ResourceContents, error) {
+		contents := types.ResourceContents{URI: resource.URI, MIMEType: resource.MIMEType}
+		if isTextMIMEType(resource.MIMEType) {
+			contents.Text = string(data)
+		} else {
+			contents.Content = data
+		}
+		return []types.ResourceContents{contents}, nil
+	})
+	return resource
+}
+
+// AddResource adds a resource to the MCP server. Resources with a content
+// provider can be read by clients with resources/read.
+func (s *MCPServer) AddResource(ctx context.Context, resource *types.Resource) error {
+	if resource == nil {
+		return fmt.Errorf("resource cannot be nil")
+	}
+	if resource.URI == "" {
+		return fmt.Errorf("resource URI cannot be empty")
+	}
+
+	internalResource := &domain.Resource{
+		URI:         resource.URI,
+		Name:        resource.Name,
+		Description: resource.Description,
+		MIMEType:    resource.MIMEType,
+	}
+	if resource.ContentProvider != nil {
+		internalResource.ContentProvider = &contentProviderAdapter{provider: resource.ContentProvider}
+	}
+
+	s.builder.AddResource(ctx, internalResource)
+	return nil
+}
+
+// AddDynamicResource adds a resource whose contents are computed by read on
+// each resources/read, for resources that are expensive to materialize. The
+// metadata is listed immediately. Contents without a URI or MIME type default
+// to the resource's own, and an error from read is returned to the client as
+// an internal error.
+func (s *MCPServer) AddDynamicResource(ctx context.Context, meta types.Resource, read func(ctx context.Context) ([]types.ResourceContents, error)) error {
+	if read == nil {
+		return fmt.Errorf("resource read function cannot be nil")
+	}
+	meta.ContentProvider = types.ResourceContentProviderFunc(func(ctx context.Context, uri string) ([]types.ResourceContents, error) {
+		return read(ctx)
+	})
+	return s.AddResource(ctx, &meta)
+}
+
+// RemoveResource removes a resource. Connected clients are notified that the
+// resource list changed.
+func (s *MCPServer) RemoveResource(ctx context.Context, uri string) error {
+	return s.builder.RemoveResource(ctx, uri)
+}
+
+// NotifyResourceUpdated sends notifications/resources/updated to the clients
+// that subscribed to the resource at uri with resources/subscribe. Call it
+// when the contents of a dynamic resource, such as a log or metrics feed,
+// change.
+func (s *MCPServer) NotifyResourceUpdated(ctx context.Context, uri string) error {
+	return s.builder.NotifyResourceUpdated(ctx, uri)
+}
+
+// contentProviderAdapter adapts a public content provider to the internal one.
+type contentProviderAdapter struct {
+	provider types.ResourceContentProvider
+}
+
+// ReadContent reads the contents from the public provider.
+func (p *contentProviderAdapter) ReadContent(ctx context.Context, uri string) ([]domain.ResourceContents, error) {
+	contents, err := p.provider.ReadContent(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	internalContents := make([]domain.ResourceContents, len(contents))
+	for i, c := range contents {
+		internalContents[i] = domain.ResourceContents{
+			URI:      c.URI,
+			MIMEType: c.MIMEType,
+			Content:  c.Content,
+			Text:     c.Text,
+		}
+	}
+	return internalContents, nil
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticResource(t *testing.T) {
+	ctx := context.Background()
+	gif := []byte("GIF89a\x01\x00\x01\x00")
+
+	tests := []struct {
+		name         string
+		resource     *types.Resource
+		wantMIMEType string
+		wantText     string
+		wantBlob     []byte
+	}{
+		{
+			name:         "text",
+			resource:     StaticResource("docs://style-guide", "Style guide", []byte("Use tabs.")),
+			wantMIMEType: "text/plain; charset=utf-8",
+			wantText:     "Use tabs.",
+		},
+		{
+			name:         "binary",
+			resource:     StaticResource("assets://pixel.gif", "Pixel", gif),
+			wantMIMEType: "image/gif",
+			wantBlob:     gif,
+		},
+		{
+			name: "overridden MIME type",
+			resource: func() *types.Resource {
+				r := StaticResource("config://app", "App config", []byte(`{"debug":false}`))
+				r.MIMEType = "application/json"
+				return r
+			}(),
+			wantMIMEType: "application/json",
+			wantText:     `{"debug":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantMIMEType, tt.resource.MIMEType)
+			contents, err := tt.resource.ContentProvider.ReadContent(ctx, tt.resource.URI)
+			require.NoError(t, err)
+			require.Len(t, contents, 1)
+			assert.Equal(t, types.ResourceContents{
+				URI:      tt.resource.URI,
+				MIMEType: tt.wantMIMEType,
+				Text:     tt.wantText,
+				Content:  tt.wantBlob,
+			}, contents[0])
+		})
+	}
+}
+
+func TestAddResource(t *testing.T) {
+	ctx := context.Background()
+	s := NewMCPServer("test-server", "1.0.0")
+
+	assert.Error(t, s.AddResource(ctx, nil))
+	assert.Error(t, s.AddResource(ctx, &types.Resource{Name: "No URI"}))
+
+	changelog := StaticResource("docs://changelog", "Changelog", []byte("v1.2.0: faster startup"))
+	changelog.Description = "Release notes"
+	require.NoError(t, s.AddResource(ctx, changelog))
+	require.NoError(t, s.AddResource(ctx, &types.Resource{URI: "docs://roadmap", Name: "Roadmap"}))
+	service := s.builder.BuildService()
+
+	// The metadata is listed and the contents read through the provider
+	listed, err := service.ListResources(ctx)
+	require.NoError(t, err)
+	assert.Len(t, listed, 2)
+	resource, err := service.GetResource(ctx, "docs://changelog")
+	require.NoError(t, err)
+	assert.Equal(t, "Changelog", resource.Name)
+	assert.Equal(t, "Release notes", resource.Description)
+	contents, err := service.ReadResource(ctx, "docs://changelog")
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+	assert.Equal(t, "v1.2.0: faster startup", contents[0].Text)
+
+	// A resource without a provider is listed but cannot be read
+	_, err = service.ReadResource(ctx, "docs://roadmap")
+	assert.ErrorIs(t, err, domain.ErrNoContentProvider)
+}
+
+func TestAddDynamicResource(t *testing.T) {
+	ctx := context.Background()
+	s := NewMCPServer("test-server", "1.0.0")
+
+	assert.Error(t, s.AddDynamicResource(ctx, types.Resource{URI: "metrics://queue"}, nil))
+
+	reads := 0
+	errBackend := errors.New("metrics backend unreachable")
+	var failNext bool
+	err := s.AddDynamicResource(ctx, types.Resource{URI: "metrics://queue", Name: "Queue depth", MIMEType: "application/json"},
+		func(ctx context.Context) ([]types.ResourceContents, error) {
+			reads++
+			if failNext {
+				return nil, errBackend
+			}
+			return []types.ResourceContents{{Text: fmt.Sprintf(`{"depth":%d}`, reads*10)}}, nil
+		})
+	require.NoError(t, err)
+	service := s.builder.BuildService()
+
+	// Registering lists the resource without reading it
+	resource, err := service.GetResource(ctx, "metrics://queue")
+	require.NoError(t, err)
+	assert.Equal(t, "Queue depth", resource.Name)
+	assert.Zero(t, reads)
+
+	// Every read computes fresh contents, defaulting to the resource's URI and MIME type
+	for _, want := range []string{`{"depth":10}`, `{"depth":20}`} {
+		contents, err := service.ReadResource(ctx, "metrics://queue")
+		require.NoError(t, err)
+		require.Len(t, contents, 1)
+		assert.Equal(t, domain.ResourceContents{URI: "metrics://queue", MIMEType: "application/json", Text: want}, contents[0])
+	}
+
+	failNext = true
+	_, err = service.ReadResource(ctx, "metrics://queue")
+	assert.ErrorIs(t, err, errBackend)
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/interfaces/rest"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/interfaces/stdio"
+)
+
+// serveShutdownTimeout bounds how long Serve waits for the HTTP transport to
+// shut down once its context is canceled or another transport stopped.
+const serveShutdownTimeout = 10 * time.Second
+
+// Transport is a way of serving the MCP server, see Serve.
+type Transport interface {
+	// run serves mcpServer until ctx is canceled or the transport stops.
+	run(ctx context.Context, s *MCPServer, mcpServer *rest.MCPServer) error
+}
+
+// httpTransport serves the HTTP endpoints, see HTTPTransport.
+type httpTransport struct{}
+
+// stdioTransport serves standard I/O, see StdioTransport.
+type stdioTransport struct{}
+
+// HTTPTransport serves the HTTP and SSE endpoints at the server's address, or
+// HTTPS if TLS is configured, like ServeHTTP.
+func HTTPTransport() Transport {
+	return httpTransport{}
+}
+
+// StdioTransport serves standard input and output, like ServeStdio.
+func StdioTransport() Transport {
+	return stdioTransport{}
+}
+
+// Serve serves the MCP server over several transports at once:
+//
+//	err := mcpServer.Serve(ctx, server.HTTPTransport(), server.StdioTransport())
+//
+// All transports share one service and notification sender, so a
+// notification broadcast reaches both stdio and SSE clients. Serve returns
+// when ctx is canceled, Shutdown or Drain is called, or any transport stops,
+// for example because standard input was closed; the other transports are
+// stopped with it. It returns the first transport error.
+func (s *MCPServer) Serve(ctx context.Context, transports ...Transport) error {
+	if len(transports) == 0 {
+		return fmt.Errorf("no transports to serve")
+	}
+
+	// Keep standard output free for the stdio stream
+	var opts []rest.MCPServerOption
+	for _, t := range transports {
+		if _, ok := t.(stdioTransport); ok {
+			opts = append(opts, rest.WithLogger(s.logger))
+			break
+		}
+	}
+	mcpServer := s.builder.BuildMCPServer(opts...)
+
+	// Keep the running server so Shutdown, Drain and the session accessors see it
+	s.httpMu.Lock()
+	s.httpServer = mcpServer
+	s.httpMu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	log.Printf("Starting MCP server: %s v%s", s.name, s.version)
+	errs := make(chan error, len(transports))
+	for _, t := range transports {
+		go func(t Transport) {
+			errs <- t.run(ctx, s, mcpServer)
+		}(t)
+	}
+
+	// The first transport to stop stops the others
+	err := <-errs
+	cancel()
+	for range transports[1:] {
+		if transportErr := <-errs; err == nil {
+			err = transportErr
+		}
+	}
+	return err
+}
+
+// run serves HTTP until the server is shut down or ctx is canceled, in which
+// case it shuts the server down.
+func (httpTransport) run(ctx context.Context, s *MCPServer, mcpServer *rest.MCPServer) error {
+	done := make(chan error, 1)
+	go func() {
+		if s.tlsEnabled() {
+			done <- mcpServer.StartTLS(s.certFile, s.keyFile, s.tlsConfig)
+		} else {
+			done <- mcpServer.Start()
+		}
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), serveShutdownTimeout)
+		defer cancel()
+		stopErr := mcpServer.Stop(stopCtx)
+		if err = <-done; errors.Is(err, http.ErrServerClosed) {
+			err = stopErr
+		}
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// run serves standard I/O until the input is closed or ctx is canceled.
+func (stdioTransport) run(ctx context.Context, s *MCPServer, mcpServer *rest.MCPServer) error {
+	stdioServer := stdio.NewStdioServer(mcpServer, s.stdioOptions()...)
+	if err := stdioServer.Listen(ctx, os.Stdin, os.Stdout); !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// stderrLogger returns a logger that writes to standard error, falling back
+// to the default logger.
+func stderrLogger() *logging.Logger {
+	logger, err := logging.New(logging.Config{
+		Level:       logging.InfoLevel,
+		OutputPaths: []string{"stderr"},
+	})
+	if err != nil {
+		return logging.Default()
+	}
+	return logger
+}
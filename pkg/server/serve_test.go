@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/interfaces/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// probeTransport reports the server it was given and runs until ctx is
+// canceled, or fails at once with err.
+type probeTransport struct {
+	served chan *rest.MCPServer
+	err    error
+}
+
+func newProbeTransport(err error) *probeTransport {
+	return &probeTransport{served: make(chan *rest.MCPServer, 1), err: err}
+}
+
+func (p *probeTransport) run(ctx context.Context, s *MCPServer, mcpServer *rest.MCPServer) error {
+	p.served <- mcpServer
+	if p.err != nil {
+		return p.err
+	}
+	<-ctx.Done()
+	return nil
+}
+
+// freeAddress returns a local address nothing listens on.
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return addr
+}
+
+func TestServeWithoutTransports(t *testing.T) {
+	s := NewMCPServer("test-server", "1.0.0")
+	assert.Error(t, s.Serve(context.Background()))
+}
+
+func TestServeStopsAllTransportsWhenOneFails(t *testing.T) {
+	s := NewMCPServer("test-server", "1.0.0")
+	errPipe := errors.New("broken pipe")
+	failing, healthy := newProbeTransport(errPipe), newProbeTransport(nil)
+
+	err := s.Serve(context.Background(), healthy, failing)
+	assert.ErrorIs(t, err, errPipe)
+
+	// Both transports served the same server, the one Shutdown stops
+	served := <-healthy.served
+	assert.Same(t, served, <-failing.served)
+	s.httpMu.Lock()
+	defer s.httpMu.Unlock()
+	assert.Same(t, served, s.httpServer)
+}
+
+func TestServeHTTPAlongsideOtherTransports(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(s *MCPServer, cancel context.CancelFunc)
+	}{
+		{"context canceled", func(s *MCPServer, cancel context.CancelFunc) { cancel() }},
+		{"Shutdown", func(s *MCPServer, cancel context.CancelFunc) {
+			assert.NoError(t, s.Shutdown(context.Background()))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := freeAddress(t)
+			s := NewMCPServer("test-server", "1.0.0")
+			s.SetAddress(addr)
+			probe := newProbeTransport(nil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			served := make(chan error, 1)
+			go func() { served <- s.Serve(ctx, HTTPTransport(), probe) }()
+
+			<-probe.served
+			require.Eventually(t, func() bool {
+				resp, err := http.Get("http://" + addr + "/healthz")
+				if err != nil {
+					return false
+				}
+				resp.Body.Close()
+				return resp.StatusCode == http.StatusOK
+			}, 2*time.Second, 10*time.Millisecond)
+
+			// Stopping ends every transport without an error
+			tt.stop(s, cancel)
+			select {
+			case err := <-served:
+				assert.NoError(t, err)
+			case <-time.After(2 * time.Second):
+				t.Fatal("Serve did not return")
+			}
+			_, err := http.Get("http://" + addr + "/healthz")
+			assert.Error(t, err, "the HTTP transport should no longer listen")
+		})
+	}
+}
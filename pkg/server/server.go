@@ -3,13 +3,22 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
+	"time"
 
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/builder"
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/server"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/interfaces/rest"
 	"github.com/FreePeak/golang-mcp-server-sdk/internal/interfaces/stdio"
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/usecases"
 	"github.com/FreePeak/golang-mcp-server-sdk/pkg/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ToolHandler is a function that handles tool calls.
@@ -19,99 +28,539 @@ type ToolHandler func(ctx context.Context, request ToolCallRequest) (interface{}
 type ToolCallRequest struct {
 	Name       string
 	Parameters map[string]interface{}
-	Session    *types.ClientSession
+	// Session is the client session the call came from, or nil if the call
+	// did not come through a server transport. Its ID is empty for plain
+	// HTTP requests.
+	Session *types.ClientSession
+	// ProgressToken is the token the client supplied in _meta to receive
+	// progress notifications, or nil if it did not ask for progress.
+	ProgressToken interface{}
+
+	progress domain.ProgressReporter
+}
+
+// ReportProgress sends a notifications/progress update to the client that made
+// the call. A total of zero means the total is unknown. It does nothing if the
+// client did not supply a progress token.
+func (r ToolCallRequest) ReportProgress(ctx context.Context, current, total float64) error {
+	if r.ProgressToken == nil || r.progress == nil {
+		return nil
+	}
+	return r.progress(ctx, current, total)
+}
+
+// ToolError can be returned by a tool handler to send a JSON-RPC error with a
+// specific code and structured data instead of the default -32603 internal
+// error. A zero Code is sent as -32603.
+type ToolError struct {
+	Code    int
+	Message string
+	Data    interface{}
+}
+
+// Error returns the error message.
+func (e ToolError) Error() string {
+	return e.Message
 }
 
 // MCPServer represents an MCP server that can be used to handle MCP protocol messages.
 type MCPServer struct {
-	name     string
-	version  string
+	name    string
+	version string
+	builder *builder.ServerBuilder
+
+	// toolsMu guards tools and handlers, which change while the server runs
+	toolsMu  sync.RWMutex
 	tools    map[string]*types.Tool
 	handlers map[string]ToolHandler
-	builder  *builder.ServerBuilder
+
+	// TLS settings used by ServeHTTP when configured
+	certFile  string
+	keyFile   string
+	tlsConfig *tls.Config
+
+	// httpMu guards httpServer, the server started by ServeHTTP, ServeStdio
+	// or Serve
+	httpMu     sync.Mutex
+	httpServer *rest.MCPServer
+
+	// envKeys are the environment variables passed to handlers over stdio
+	envKeys []string
+	// stdioShutdownTimeout, see WithStdioShutdownTimeout
+	stdioShutdownTimeout time.Duration
+	// Tool call concurrency, see WithMaxConcurrentCalls
+	maxConcurrentCalls int
+	queueCalls         bool
+
+	// middlewareMu guards middleware, see UseToolMiddleware
+	middlewareMu sync.RWMutex
+	middleware   []ToolMiddleware
+
+	// shutdownMu guards shutdownHooks, see OnShutdown
+	shutdownMu    sync.Mutex
+	shutdownHooks []ShutdownHook
+
+	// logger records server events, written to standard error
+	logger *logging.Logger
+}
+
+// Option configures an MCPServer.
+type Option func(*MCPServer)
+
+// WithInstructions sets the instructions sent to clients in the initialize
+// response, describing how to use the server.
+func WithInstructions(instructions string) Option {
+	return func(s *MCPServer) {
+		s.builder.WithInstructions(instructions)
+	}
+}
+
+// WithInstructionsFunc sets a function that generates the instructions when a
+// client initializes, so they can describe the server's current state, such
+// as the tools registered. It takes precedence over WithInstructions, and
+// empty instructions are omitted from the response.
+func WithInstructionsFunc(fn func(ctx context.Context) string) Option {
+	return func(s *MCPServer) {
+		s.builder.WithInstructionsFunc(fn)
+	}
+}
+
+// WithRequestTimeout overrides the default 30 second timeout for processing a
+// single request. Tools can set their own timeout with tools.WithTimeout.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(s *MCPServer) {
+		s.builder.WithRequestTimeout(timeout)
+	}
+}
+
+// WithShutdownGrace sets how long Shutdown and Drain wait after sending
+// notifications/server/shutdown to connected SSE clients before closing their
+// connections, so clients can react instead of seeing the stream drop. By
+// default connections close as soon as the notification is written.
+func WithShutdownGrace(grace time.Duration) Option {
+	return func(s *MCPServer) {
+		s.builder.WithShutdownGrace(grace)
+	}
+}
+
+// WithStdioShutdownTimeout lets requests in flight over stdio finish and write
+// their responses for up to timeout when the server receives SIGINT or SIGTERM
+// or Serve's context is canceled. By default they are canceled immediately.
+func WithStdioShutdownTimeout(timeout time.Duration) Option {
+	return func(s *MCPServer) {
+		s.stdioShutdownTimeout = timeout
+	}
+}
+
+// WithSessionIdleTimeout closes SSE sessions that neither received a message
+// nor sent an event for the given duration, so clients that connect and go
+// silent do not hold resources indefinitely. Heartbeats do not count as
+// activity. By default sessions stay open until the client disconnects.
+func WithSessionIdleTimeout(timeout time.Duration) Option {
+	return func(s *MCPServer) {
+		s.builder.WithSessionIdleTimeout(timeout)
+	}
+}
+
+// SSEDelivery selects how responses to messages posted by SSE clients are
+// delivered, see WithSSEDelivery.
+type SSEDelivery = server.SSEDelivery
+
+// Delivery modes for WithSSEDelivery.
+const (
+	// SSEDeliveryBoth sends responses on the SSE stream and in the HTTP
+	// response body. It is the default.
+	SSEDeliveryBoth = server.SSEDeliveryBoth
+	// SSEDeliveryHTTP sends responses only in the HTTP response body.
+	SSEDeliveryHTTP = server.SSEDeliveryHTTP
+	// SSEDeliverySSE sends responses only on the SSE stream and answers
+	// the HTTP request with 202 Accepted.
+	SSEDeliverySSE = server.SSEDeliverySSE
+)
+
+// WithSSEDelivery sets the channel on which SSE clients receive responses to
+// the messages they post. By default each response is sent both on the SSE
+// stream and in the HTTP response body, which makes clients that read both
+// see it twice. Use SSEDeliverySSE for spec-compliant clients that only read
+// the stream, or SSEDeliveryHTTP for clients that only read response bodies.
+func WithSSEDelivery(delivery SSEDelivery) Option {
+	return func(s *MCPServer) {
+		s.builder.WithSSEDelivery(delivery)
+	}
+}
+
+// WithEventBatching makes the HTTP server send SSE events that queue up within
+// window as a single "batch" event whose data is a JSON array of up to
+// maxBatch messages. The connected event advertises the settings. Clients
+// must understand the batched format, so batching is off by default.
+func WithEventBatching(maxBatch int, window time.Duration) Option {
+	return func(s *MCPServer) {
+		s.builder.WithEventBatching(maxBatch, window)
+	}
+}
+
+// WithPrettyJSON indents the JSON-RPC responses the HTTP server writes to
+// response bodies, which is easier to read when debugging by hand. Responses
+// are compact by default, and stdio and the SSE stream are always compact.
+func WithPrettyJSON(pretty bool) Option {
+	return func(s *MCPServer) {
+		s.builder.WithPrettyJSON(pretty)
+	}
+}
+
+// WithPingDiagnostics adds the server's uptime in seconds, active SSE session
+// count and number of in-flight requests to ping results, so monitoring tools
+// can use ping as a lightweight health probe over any transport. By default
+// ping returns an empty result.
+func WithPingDiagnostics() Option {
+	return func(s *MCPServer) {
+		s.builder.WithPingDiagnostics()
+	}
+}
+
+// WithTracer traces every JSON-RPC message served over HTTP with an
+// OpenTelemetry span named after its method. Tool handlers run within the
+// span's context, so spans they start nest under it, and a traceparent header
+// on the request joins the caller's trace. Tracing is disabled by default.
+func WithTracer(tracer trace.Tracer) Option {
+	return func(s *MCPServer) {
+		s.builder.WithTracer(tracer)
+	}
+}
+
+// WithMetrics records per-method request counts, error counts and latency
+// histograms and serves them at the /metrics endpoint in the Prometheus text
+// format.
+func WithMetrics() Option {
+	return func(s *MCPServer) {
+		s.builder.WithMetrics(server.NewRequestMetrics())
+	}
+}
+
+// WithMetricsCollector records request metrics with a custom collector, for
+// example one backed by a Prometheus client registry. The collector is served
+// at /metrics if it implements http.Handler.
+func WithMetricsCollector(collector types.MetricsCollector) Option {
+	return func(s *MCPServer) {
+		s.builder.WithMetrics(collector)
+	}
+}
+
+// WithRequestLogging logs the method, ID, duration and status of every HTTP
+// JSON-RPC request, and the request and response bodies at debug level. The
+// values of the named params, e.g. "password", are logged as ***.
+func WithRequestLogging(redactParams ...string) Option {
+	return func(s *MCPServer) {
+		s.builder.WithRequestLogging(redactParams...)
+	}
+}
+
+// WithBasePath mounts all HTTP endpoints under the given path prefix, for
+// servers behind a reverse proxy on a subpath: with "/api/mcp" the SSE
+// endpoint is "/api/mcp/sse" and the message endpoint advertised to SSE
+// clients includes the prefix. /status reports the effective endpoints.
+func WithBasePath(path string) Option {
+	return func(s *MCPServer) {
+		s.builder.WithBasePath(path)
+	}
+}
+
+// WithHealthPath changes the path of the HTTP health check endpoint from
+// /healthz. The endpoint answers 200 while the server accepts requests and 503
+// before it is listening and while it drains.
+func WithHealthPath(path string) Option {
+	return func(s *MCPServer) {
+		s.builder.WithHealthPath(path)
+	}
+}
+
+// WithMaxRequestBytes limits the size of HTTP request bodies. Larger requests
+// are rejected with HTTP 413. The default is 4 MiB; raise it for servers that
+// accept large payloads, or pass zero to remove the limit.
+func WithMaxRequestBytes(n int64) Option {
+	return func(s *MCPServer) {
+		s.builder.WithMaxRequestBytes(n)
+	}
+}
+
+// WithMaxConcurrentCalls limits how many tool calls run at once over HTTP and
+// stdio. Calls beyond the limit are rejected with -32000 "Server busy" unless
+// WithQueuedCalls is also given. The limit and current load are reported by
+// the /status endpoint. By default calls are unlimited.
+func WithMaxConcurrentCalls(n int) Option {
+	return func(s *MCPServer) {
+		s.maxConcurrentCalls = n
+		s.builder.WithMaxConcurrentCalls(n, s.queueCalls)
+	}
+}
+
+// WithQueuedCalls makes calls beyond the WithMaxConcurrentCalls limit wait
+// for a running call to finish instead of being rejected.
+func WithQueuedCalls() Option {
+	return func(s *MCPServer) {
+		s.queueCalls = true
+		s.builder.WithMaxConcurrentCalls(s.maxConcurrentCalls, true)
+	}
+}
+
+// WithRateLimit limits how often each session can call tools: rps calls per
+// second with bursts of up to burst calls. Calls over the budget are rejected
+// with -32000 "Rate limit exceeded" and a retryAfterMs hint in the error data.
+// A session's budget is released when it disconnects.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(s *MCPServer) {
+		s.builder.WithCallRateLimit(rps, burst, true)
+	}
+}
+
+// WithGlobalRateLimit is like WithRateLimit but the budget is shared by all
+// sessions.
+func WithGlobalRateLimit(rps float64, burst int) Option {
+	return func(s *MCPServer) {
+		s.builder.WithCallRateLimit(rps, burst, false)
+	}
+}
+
+// Capabilities that can be advertised with WithCapabilities.
+const (
+	CapabilityResources   = rest.CapabilityResources
+	CapabilityTools       = rest.CapabilityTools
+	CapabilityPrompts     = rest.CapabilityPrompts
+	CapabilityLogging     = rest.CapabilityLogging
+	CapabilityCompletions = rest.CapabilityCompletions
+)
+
+// WithCapabilities sets the capabilities advertised to clients in the
+// initialize response. By default tools, resources and prompts are advertised
+// only if any are registered when the client connects; use this for servers
+// that register them later.
+func WithCapabilities(names ...string) Option {
+	return func(s *MCPServer) {
+		s.builder.WithCapabilities(names...)
+	}
+}
+
+// WithCORS restricts which browser origins may call the HTTP server. Only the
+// listed origins, such as "https://app.example.com", are allowed; "*" allows
+// any. With allowCredentials, browsers may send cookies and authorization
+// headers, and the request's Origin is echoed back instead of *. The policy
+// applies to /jsonrpc, /sse and /message alike, and preflight requests are
+// answered. By default any origin is allowed without credentials.
+func WithCORS(allowedOrigins []string, allowCredentials bool) Option {
+	return func(s *MCPServer) {
+		s.builder.WithCORS(allowedOrigins, allowCredentials)
+	}
+}
+
+// WithEnvContext makes the named environment variables available to tool
+// handlers served over stdio through EnvFromContext. They are read once when
+// ServeStdio starts; variables that are not set are omitted.
+func WithEnvContext(keys ...string) Option {
+	return func(s *MCPServer) {
+		s.envKeys = append(s.envKeys, keys...)
+	}
+}
+
+// EnvFromContext returns the value of an environment variable configured
+// with WithEnvContext. It reports false if the variable is not set.
+func EnvFromContext(ctx context.Context, key string) (string, bool) {
+	return domain.EnvFromContext(ctx, key)
+}
+
+// WithTLS makes ServeHTTP serve HTTPS using the given certificate and key files.
+func WithTLS(certFile, keyFile string) Option {
+	return func(s *MCPServer) {
+		s.certFile = certFile
+		s.keyFile = keyFile
+	}
+}
+
+// WithTLSConfig makes ServeHTTP serve HTTPS using the given TLS configuration.
+// Use it to provide certificates loaded from memory.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(s *MCPServer) {
+		s.tlsConfig = config
+	}
 }
 
 // NewMCPServer creates a new MCP server with the specified name and version.
-func NewMCPServer(name, version string) *MCPServer {
-	return &MCPServer{
+func NewMCPServer(name, version string, opts ...Option) *MCPServer {
+	s := &MCPServer{
 		name:     name,
 		version:  version,
 		tools:    make(map[string]*types.Tool),
 		handlers: make(map[string]ToolHandler),
 		builder:  builder.NewServerBuilder().WithName(name).WithVersion(version),
+		logger:   stderrLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
+}
+
+// ToolWithHandler bundles a tool with the handler that executes it, for
+// registering many tools at once with AddTools.
+type ToolWithHandler struct {
+	Tool    *types.Tool
+	Handler ToolHandler
 }
 
 // AddTool adds a tool to the MCP server.
 func (s *MCPServer) AddTool(ctx context.Context, tool *types.Tool, handler ToolHandler) error {
+	return s.AddTools(ctx, ToolWithHandler{Tool: tool, Handler: handler})
+}
+
+// AddTools adds several tools to the MCP server in one call. All tools are
+// validated first: if any is invalid, the error for the first one names the
+// tool and none of the tools are added. A tool is invalid if its name is
+// already registered (ErrDuplicateTool), if a parameter has no name or if a
+// parameter type is not one of string, number, integer, boolean, object or
+// array.
+func (s *MCPServer) AddTools(ctx context.Context, tools ...ToolWithHandler) error {
+	s.toolsMu.Lock()
+	defer s.toolsMu.Unlock()
+
+	internalTools := make([]*domain.Tool, len(tools))
+	handlers := make([]usecases.ToolHandlerFunc, len(tools))
+	names := make(map[string]bool, len(tools))
+	for i, t := range tools {
+		if t.Tool == nil {
+			return fmt.Errorf("tool cannot be nil")
+		}
+		if t.Handler == nil {
+			return fmt.Errorf("tool %s: handler cannot be nil", t.Tool.Name)
+		}
+		if _, exists := s.tools[t.Tool.Name]; exists || names[t.Tool.Name] {
+			return fmt.Errorf("tool %s: %w", t.Tool.Name, ErrDuplicateTool)
+		}
+		names[t.Tool.Name] = true
+
+		// Compile parameter patterns once, rejecting invalid ones up front
+		internalTool := convertToInternalTool(t.Tool)
+		if err := internalTool.Validate(); err != nil {
+			return fmt.Errorf("tool %s: %w", t.Tool.Name, err)
+		}
+		if err := internalTool.CompilePatterns(); err != nil {
+			return fmt.Errorf("tool %s: %w", t.Tool.Name, err)
+		}
+		internalTools[i] = internalTool
+		handlers[i] = s.adaptToolHandler(t.Tool.Name, t.Handler)
+	}
+
+	// Add to the internal builder along with their handlers
+	if err := s.builder.AddToolsWithHandlers(ctx, internalTools, handlers); err != nil {
+		return err
+	}
+
+	// Store the tools and their handlers once the builder accepted them
+	for _, t := range tools {
+		s.tools[t.Tool.Name] = t.Tool
+		s.handlers[t.Tool.Name] = t.Handler
+	}
+
+	return nil
+}
+
+// UpdateTool replaces the definition and handler of an existing tool in one
+// step, for servers that reload tool schemas at runtime. Connected clients
+// are notified once that the tool list changed. It returns an error matching
+// ErrNotFound if the tool does not exist; use AddTool to create it.
+func (s *MCPServer) UpdateTool(ctx context.Context, tool *types.Tool, handler ToolHandler) error {
 	if tool == nil {
 		return fmt.Errorf("tool cannot be nil")
 	}
 	if handler == nil {
-		return fmt.Errorf("handler cannot be nil")
+		return fmt.Errorf("tool %s: handler cannot be nil", tool.Name)
+	}
+
+	internalTool := convertToInternalTool(tool)
+	if err := internalTool.Validate(); err != nil {
+		return fmt.Errorf("tool %s: %w", tool.Name, err)
+	}
+	s.toolsMu.Lock()
+	defer s.toolsMu.Unlock()
+	if err := s.builder.UpdateTool(ctx, internalTool, s.adaptToolHandler(tool.Name, handler)); err != nil {
+		return fmt.Errorf("tool %s: %w", tool.Name, err)
 	}
 
-	// Store the tool and its handler
 	s.tools[tool.Name] = tool
 	s.handlers[tool.Name] = handler
+	return nil
+}
+
+// ErrNotFound is returned by UpdateTool, RemoveTool, RemoveResource and
+// RemovePrompt when the item does not exist. Match it with errors.Is.
+var ErrNotFound = domain.ErrNotFound
 
-	// Add to the internal builder
-	s.builder.AddTool(ctx, convertToInternalTool(tool))
+// ErrDuplicateTool is returned by AddTool and AddTools when a tool with the
+// same name is already registered. Use UpdateTool to replace a tool.
+var ErrDuplicateTool = domain.ErrDuplicateTool
 
+// RemoveTool removes a tool and its handler. Connected clients are notified
+// that the tool list changed.
+func (s *MCPServer) RemoveTool(ctx context.Context, name string) error {
+	s.toolsMu.Lock()
+	defer s.toolsMu.Unlock()
+	if err := s.builder.RemoveTool(ctx, name); err != nil {
+		return err
+	}
+	delete(s.tools, name)
+	delete(s.handlers, name)
 	return nil
 }
 
+// RemovePrompt removes a prompt. Connected clients are notified that the
+// prompt list changed.
+func (s *MCPServer) RemovePrompt(ctx context.Context, name string) error {
+	return s.builder.RemovePrompt(ctx, name)
+}
+
 // RegisterToolHandler registers a handler for the specified tool.
 func (s *MCPServer) RegisterToolHandler(name string, handler ToolHandler) error {
+	s.toolsMu.Lock()
+	defer s.toolsMu.Unlock()
 	if _, exists := s.tools[name]; !exists {
 		return fmt.Errorf("tool %s not found", name)
 	}
 
 	s.handlers[name] = handler
+	s.builder.WithToolHandler(name, s.adaptToolHandler(name, handler))
 	return nil
 }
 
 // ServeStdio serves the MCP server over standard I/O.
 func (s *MCPServer) ServeStdio() error {
+	if err := s.builder.Err(); err != nil {
+		return err
+	}
 	log.Printf("Starting MCP server over stdio: %s v%s", s.name, s.version)
 
-	// Create stdio options with tool handlers
-	var stdioOpts []stdio.StdioOption
-
-	// Add the default error logger
-	stdioOpts = append(stdioOpts, stdio.WithErrorLogger(log.Default()))
-
-	// Add all tool handlers as stdio options
-	for name, handler := range s.handlers {
-		// Capture variables in the closure correctly
-		toolName := name
-		toolHandler := handler
-
-		// Create an adapter function that converts between our API and the internal API
-		adapter := func(ctx context.Context, params map[string]interface{}, session *domain.ClientSession) (interface{}, error) {
-			// Convert domain session to public session
-			pubSession := &types.ClientSession{
-				ID:        session.ID,
-				UserAgent: session.UserAgent,
-				Connected: session.Connected,
-			}
+	// Keep the running server so SetMaintenanceMode reaches it
+	mcpServer := s.builder.BuildMCPServer()
+	s.httpMu.Lock()
+	s.httpServer = mcpServer
+	s.httpMu.Unlock()
 
-			// Create request and call the handler
-			request := ToolCallRequest{
-				Name:       toolName,
-				Parameters: params,
-				Session:    pubSession,
-			}
+	return stdio.ServeStdio(mcpServer, s.stdioOptions()...)
+}
 
-			return toolHandler(ctx, request)
-		}
+// stdioOptions returns the options for serving over stdio; tool handlers are
+// dispatched by the server service.
+func (s *MCPServer) stdioOptions() []stdio.StdioOption {
+	// Add the default error logger
+	stdioOpts := []stdio.StdioOption{stdio.WithErrorLogger(log.Default())}
 
-		// Add the tool handler as an option
-		stdioOpts = append(stdioOpts, stdio.WithToolHandler(toolName, adapter))
+	// Surface the configured environment variables to handlers
+	if len(s.envKeys) > 0 {
+		stdioOpts = append(stdioOpts, stdio.WithEnvContext(s.envKeys...))
 	}
-
-	// Start the stdio server with our custom handler
-	return s.builder.ServeStdio(stdioOpts...)
+	if s.stdioShutdownTimeout > 0 {
+		stdioOpts = append(stdioOpts, stdio.WithShutdownTimeout(s.stdioShutdownTimeout))
+	}
+	return stdioOpts
 }
 
 // SetAddress sets the HTTP address for the server.
@@ -126,23 +575,183 @@ func (s *MCPServer) GetAddress() string {
 	return restServer.GetAddress()
 }
 
-// ServeHTTP starts the HTTP server.
+// ServeHTTP starts the HTTP server. It serves HTTPS if TLS is configured with
+// WithTLS or WithTLSConfig.
 func (s *MCPServer) ServeHTTP() error {
 	// Create an HTTP server with all our tools already registered through the builder
 	mcpServer := s.builder.BuildMCPServer()
 
-	// The tools are already registered with the server through the builder pattern
-	// when we called AddTool on our MCPServer, which called AddTool on the builder
+	// Keep the running server so Shutdown stops it
+	s.httpMu.Lock()
+	s.httpServer = mcpServer
+	s.httpMu.Unlock()
+
+	if s.tlsEnabled() {
+		return mcpServer.StartTLS(s.certFile, s.keyFile, s.tlsConfig)
+	}
 
 	// Start the HTTP server
 	return mcpServer.Start()
 }
 
-// Shutdown gracefully shuts down the HTTP server.
+// tlsEnabled reports whether TLS has been configured.
+func (s *MCPServer) tlsEnabled() bool {
+	return s.tlsConfig != nil || (s.certFile != "" && s.keyFile != "")
+}
+
+// SetMaintenanceMode puts the running server into or out of maintenance
+// mode, over HTTP and stdio alike. In maintenance, tool calls are rejected
+// with the given message while sessions stay connected and initialize, ping
+// and list requests still work. It has no effect before the server is served.
+func (s *MCPServer) SetMaintenanceMode(on bool, message string) {
+	s.httpMu.Lock()
+	mcpServer := s.httpServer
+	s.httpMu.Unlock()
+
+	if mcpServer != nil {
+		mcpServer.SetMaintenanceMode(on, message)
+	}
+}
+
+// ActiveSessions returns the number of clients connected over SSE. It is
+// zero until ServeHTTP has been called.
+func (s *MCPServer) ActiveSessions() int {
+	s.httpMu.Lock()
+	mcpServer := s.httpServer
+	s.httpMu.Unlock()
+
+	if mcpServer == nil {
+		return 0
+	}
+	return mcpServer.ActiveSessions()
+}
+
+// SessionIDs returns the IDs of the clients connected over SSE in sorted order.
+func (s *MCPServer) SessionIDs() []string {
+	s.httpMu.Lock()
+	mcpServer := s.httpServer
+	s.httpMu.Unlock()
+
+	if mcpServer == nil {
+		return []string{}
+	}
+	return mcpServer.SessionIDs()
+}
+
+// Shutdown gracefully shuts down the HTTP server, then runs the shutdown
+// hooks registered with OnShutdown.
 func (s *MCPServer) Shutdown(ctx context.Context) error {
-	// Build the MCP server to get access to the Stop method
-	mcpServer := s.builder.BuildMCPServer()
-	return mcpServer.Stop(ctx)
+	s.httpMu.Lock()
+	mcpServer := s.httpServer
+	s.httpMu.Unlock()
+
+	// Nothing to stop if ServeHTTP was never called
+	var err error
+	if mcpServer != nil {
+		err = mcpServer.Stop(ctx)
+	}
+	return errors.Join(err, s.runShutdownHooks(ctx))
+}
+
+// Drain gracefully shuts down the HTTP server: new requests are rejected with
+// -32000 "server draining" while in-flight requests finish, up to ctx's
+// deadline, before connections are closed. Use it instead of Shutdown for
+// rolling deploys behind a load balancer. The shutdown hooks registered with
+// OnShutdown run once the server has stopped.
+func (s *MCPServer) Drain(ctx context.Context) error {
+	s.httpMu.Lock()
+	mcpServer := s.httpServer
+	s.httpMu.Unlock()
+
+	// Nothing to drain if ServeHTTP was never called
+	var err error
+	if mcpServer != nil {
+		err = mcpServer.Drain(ctx)
+	}
+	return errors.Join(err, s.runShutdownHooks(ctx))
+}
+
+// SessionStore returns the key-value store of the session a tool call came
+// from, or nil if the call was not made over a session-based transport.
+// The store is safe for concurrent use and cleared when the session disconnects.
+func SessionStore(ctx context.Context) types.SessionStore {
+	store, ok := domain.SessionStoreFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return store
+}
+
+// SessionInfo describes the client session a tool call came from.
+type SessionInfo struct {
+	// ID is the session ID, "stdio" for the single stdio session and empty
+	// for plain HTTP requests.
+	ID string
+	// ClientName and ClientVersion come from the clientInfo the client sent
+	// in initialize; they are empty before the session initialized.
+	ClientName    string
+	ClientVersion string
+	// UserAgent is the HTTP user agent of the client, empty over stdio.
+	UserAgent string
+	// Ready reports whether the client sent notifications/initialized.
+	Ready bool
+}
+
+// SessionFromContext returns the info of the session a tool call came from.
+// It reports false if the call did not come through a server transport.
+func SessionFromContext(ctx context.Context) (SessionInfo, bool) {
+	info, ok := domain.SessionInfoFromContext(ctx)
+	if !ok {
+		return SessionInfo{}, false
+	}
+	return SessionInfo{
+		ID:            info.ID,
+		ClientName:    info.ClientName,
+		ClientVersion: info.ClientVersion,
+		UserAgent:     info.UserAgent,
+		Ready:         info.Ready,
+	}, true
+}
+
+// adaptToolHandler converts a public tool handler to the internal handler
+// signature. The tool middleware is applied on each call so middleware added
+// after the tool still wraps it.
+func (s *MCPServer) adaptToolHandler(toolName string, handler ToolHandler) usecases.ToolHandlerFunc {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		request := ToolCallRequest{
+			Name:       toolName,
+			Parameters: params,
+		}
+		if info, ok := domain.SessionInfoFromContext(ctx); ok {
+			request.Session = &types.ClientSession{
+				ID:        info.ID,
+				UserAgent: info.UserAgent,
+				Connected: true,
+			}
+		}
+		if token, reporter, ok := domain.ProgressFromContext(ctx); ok {
+			request.ProgressToken = token
+			request.progress = reporter
+		}
+		ctx = withToolLogger(ctx, toolName)
+		result, err := s.wrapToolHandler(handler)(ctx, request)
+		return result, toInternalError(err)
+	}
+}
+
+// toInternalError converts a ToolError, returned by value or by pointer, to
+// the internal error the dispatcher translates. Other errors are unchanged.
+func toInternalError(err error) error {
+	var toolErr ToolError
+	var toolErrPtr *ToolError
+	switch {
+	case errors.As(err, &toolErr):
+	case errors.As(err, &toolErrPtr) && toolErrPtr != nil:
+		toolErr = *toolErrPtr
+	default:
+		return err
+	}
+	return &domain.ToolError{Code: toolErr.Code, Message: toolErr.Message, Data: toolErr.Data}
 }
 
 // Helper function to convert a public tool to an internal tool
@@ -154,13 +763,59 @@ func convertToInternalTool(tool *types.Tool) *domain.Tool {
 	}
 
 	for i, param := range tool.Parameters {
-		internalTool.Parameters[i] = domain.ToolParameter{
-			Name:        param.Name,
-			Description: param.Description,
-			Type:        param.Type,
-			Required:    param.Required,
+		internalTool.Parameters[i] = convertToInternalParameter(param)
+	}
+
+	if tool.RateLimit != nil {
+		internalTool.RateLimit = &domain.ToolRateLimit{
+			RequestsPerSecond: tool.RateLimit.RequestsPerSecond,
+			Burst:             tool.RateLimit.Burst,
+		}
+	}
+	internalTool.Timeout = tool.Timeout
+	internalTool.LocalizedDescriptions = tool.LocalizedDescriptions
+	internalTool.AutoWrapResults = tool.AutoWrapResults
+	for _, field := range tool.OutputSchema {
+		internalTool.OutputSchema = append(internalTool.OutputSchema, convertToInternalParameter(field))
+	}
+	internalTool.ValidateOutput = tool.ValidateOutput
+	if tool.Annotations != nil {
+		internalTool.Annotations = &domain.ToolAnnotations{
+			ReadOnlyHint:    tool.Annotations.ReadOnlyHint,
+			DestructiveHint: tool.Annotations.DestructiveHint,
+			IdempotentHint:  tool.Annotations.IdempotentHint,
+			OpenWorldHint:   tool.Annotations.OpenWorldHint,
 		}
 	}
 
 	return internalTool
 }
+
+// Helper function to convert a public tool parameter, including nested items
+// and properties, to an internal tool parameter
+func convertToInternalParameter(param types.ToolParameter) domain.ToolParameter {
+	internalParam := domain.ToolParameter{
+		Name:             param.Name,
+		Description:      param.Description,
+		Type:             param.Type,
+		Required:         param.Required,
+		Default:          param.Default,
+		Minimum:          param.Minimum,
+		Maximum:          param.Maximum,
+		ExclusiveMinimum: param.ExclusiveMinimum,
+		ExclusiveMaximum: param.ExclusiveMaximum,
+		MinLength:        param.MinLength,
+		MaxLength:        param.MaxLength,
+		Pattern:          param.Pattern,
+	}
+
+	if param.Items != nil {
+		items := convertToInternalParameter(*param.Items)
+		internalParam.Items = &items
+	}
+	for _, property := range param.Properties {
+		internalParam.Properties = append(internalParam.Properties, convertToInternalParameter(property))
+	}
+
+	return internalParam
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/domain"
+	"github.com/FreePeak/golang-mcp-server-sdk/pkg/tools"
+	"github.com/FreePeak/golang-mcp-server-sdk/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolCallRequestSession(t *testing.T) {
+	s := NewMCPServer("test-server", "1.0.0")
+	var got *types.ClientSession
+	require.NoError(t, s.AddTool(context.Background(), tools.NewTool("whoami"), func(ctx context.Context, req ToolCallRequest) (interface{}, error) {
+		got = req.Session
+		return "ok", nil
+	}))
+	service := s.builder.BuildService()
+
+	ctx := domain.WithSessionInfo(context.Background(), domain.NewSessionInfoHolder("session-1", "inspector/1.0"))
+	_, err := service.CallTool(ctx, "whoami", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, &types.ClientSession{ID: "session-1", UserAgent: "inspector/1.0", Connected: true}, got)
+
+	// Calls that did not come through a transport have no session
+	_, err = service.CallTool(context.Background(), "whoami", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestConcurrentToolChanges(t *testing.T) {
+	s := NewMCPServer("test-server", "1.0.0")
+	handler := func(ctx context.Context, req ToolCallRequest) (interface{}, error) {
+		return "ok", nil
+	}
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("tool-%d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, s.AddTool(ctx, tools.NewTool(name), handler))
+			assert.NoError(t, s.UpdateTool(ctx, tools.NewTool(name, tools.WithDescription("updated")), handler))
+			assert.NoError(t, s.RegisterToolHandler(name, handler))
+			assert.NoError(t, s.RemoveTool(ctx, name))
+		}()
+	}
+	wg.Wait()
+
+	s.toolsMu.RLock()
+	defer s.toolsMu.RUnlock()
+	assert.Empty(t, s.tools)
+	assert.Empty(t, s.handlers)
+}
+
+// localhostCertificate returns a self-signed certificate for 127.0.0.1 as a
+// key pair and as PEM encoded certificate and key.
+func localhostCertificate(t *testing.T) (cert tls.Certificate, certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mcp test server"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	cert, err = tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	return cert, certPEM, keyPEM
+}
+
+func TestServeHTTPWithTLS(t *testing.T) {
+	cert, certPEM, keyPEM := localhostCertificate(t)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+
+	tests := []struct {
+		name   string
+		option Option
+	}{
+		{"certificate files", WithTLS(certFile, keyFile)},
+		{"tls config", WithTLSConfig(&tls.Config{Certificates: []tls.Certificate{cert}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			addr := listener.Addr().String()
+			require.NoError(t, listener.Close())
+
+			s := NewMCPServer("test-server", "1.0.0", tt.option)
+			s.SetAddress(addr)
+			served := make(chan error, 1)
+			go func() { served <- s.ServeHTTP() }()
+
+			// Clients that trust the certificate reach the server over HTTPS
+			roots := x509.NewCertPool()
+			require.True(t, roots.AppendCertsFromPEM(certPEM))
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+			require.Eventually(t, func() bool {
+				resp, err := client.Get("https://" + addr + "/healthz")
+				if err != nil {
+					return false
+				}
+				resp.Body.Close()
+				return resp.StatusCode == http.StatusOK
+			}, 2*time.Second, 10*time.Millisecond)
+
+			// Plain HTTP is refused
+			resp, err := http.Get("http://" + addr + "/healthz")
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			require.NoError(t, s.Shutdown(context.Background()))
+			assert.True(t, errors.Is(<-served, http.ErrServerClosed))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+)
+
+// ShutdownHook is a cleanup callback run when the server shuts down, such as
+// flushing buffers or closing database connections.
+type ShutdownHook func(ctx context.Context) error
+
+// OnShutdown registers a hook that Shutdown and Drain run once the HTTP
+// server has stopped, so no request is still using what the hook cleans up.
+// Hooks run in reverse registration order. A failing hook is logged and does
+// not stop the others; the errors are returned together from Shutdown or
+// Drain. Each hook runs at most once.
+func (s *MCPServer) OnShutdown(hook ShutdownHook) {
+	if hook == nil {
+		return
+	}
+
+	s.shutdownMu.Lock()
+	defer s.shutdownMu.Unlock()
+	s.shutdownHooks = append(s.shutdownHooks, hook)
+}
+
+// runShutdownHooks runs the registered shutdown hooks in LIFO order and
+// returns their errors joined.
+func (s *MCPServer) runShutdownHooks(ctx context.Context) error {
+	// Take the hooks so a second Shutdown does not run them again
+	s.shutdownMu.Lock()
+	hooks := s.shutdownHooks
+	s.shutdownHooks = nil
+	s.shutdownMu.Unlock()
+
+	// Give the hooks a live context even if a drain used up ctx's deadline
+	if ctx.Err() != nil {
+		ctx = context.WithoutCancel(ctx)
+	}
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i](ctx); err != nil {
+			s.logger.Error("Shutdown hook failed", logging.Fields{"error": err})
+			errs = append(errs, fmt.Errorf("shutdown hook: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
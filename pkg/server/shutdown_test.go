@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FreePeak/golang-mcp-server-sdk/internal/infrastructure/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownHooks(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(s *MCPServer, ctx context.Context) error
+	}{
+		{"Shutdown", (*MCPServer).Shutdown},
+		{"Drain", (*MCPServer).Drain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewMCPServer("test-server", "1.0.0")
+			logPath := filepath.Join(t.TempDir(), "server.log")
+			logger, err := logging.New(logging.Config{Level: logging.InfoLevel, OutputPaths: []string{logPath}})
+			require.NoError(t, err)
+			s.logger = logger
+
+			errFlush := errors.New("flush failed")
+			errClose := errors.New("close failed")
+			var ran []string
+			hook := func(name string, err error) ShutdownHook {
+				return func(ctx context.Context) error {
+					ran = append(ran, name)
+					return err
+				}
+			}
+			s.OnShutdown(hook("open database", errClose))
+			s.OnShutdown(hook("start metrics", nil))
+			s.OnShutdown(hook("open buffer", errFlush))
+
+			// Hooks run last registered first, and every failure is reported
+			err = tt.stop(s, context.Background())
+			assert.Equal(t, []string{"open buffer", "start metrics", "open database"}, ran)
+			assert.ErrorIs(t, err, errFlush)
+			assert.ErrorIs(t, err, errClose)
+
+			logged, readErr := os.ReadFile(logPath)
+			require.NoError(t, readErr)
+			assert.Contains(t, string(logged), "Shutdown hook failed")
+			assert.Contains(t, string(logged), "close failed")
+
+			// A second stop does not run the hooks again
+			assert.NoError(t, tt.stop(s, context.Background()))
+			assert.Len(t, ran, 3)
+		})
+	}
+}
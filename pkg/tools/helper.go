@@ -2,6 +2,8 @@
 package tools
 
 import (
+	"time"
+
 	"github.com/FreePeak/golang-mcp-server-sdk/pkg/types"
 )
 
@@ -30,6 +32,141 @@ func WithDescription(description string) ToolOption {
 	}
 }
 
+// WithRateLimit limits invocations of the tool across all sessions to rps
+// requests per second with the given burst size. Calls that exceed the limit
+// are rejected with a rate limit error.
+func WithRateLimit(rps int, burst int) ToolOption {
+	return func(t *types.Tool) {
+		t.RateLimit = &types.ToolRateLimit{
+			RequestsPerSecond: rps,
+			Burst:             burst,
+		}
+	}
+}
+
+// WithLocalizedDescription adds a description of the tool for the given
+// locale. Clients that request the locale at initialization see it in
+// tools/list; others see the default description.
+func WithLocalizedDescription(locale, text string) ToolOption {
+	return func(t *types.Tool) {
+		if t.LocalizedDescriptions == nil {
+			t.LocalizedDescriptions = make(map[string]string)
+		}
+		t.LocalizedDescriptions[locale] = text
+	}
+}
+
+// AutoWrapResults wraps the tool handler's plain return value as MCP content:
+// a string becomes text content, image bytes become image content and any
+// other value is returned as structuredContent with a JSON text copy.
+// Results that already contain a "content" field are passed through.
+func AutoWrapResults() ToolOption {
+	return func(t *types.Tool) {
+		t.AutoWrapResults = true
+	}
+}
+
+// WithOutputSchema declares the fields of the tool's structuredContent result
+// using the same builders as tool parameters, e.g.
+// WithOutputSchema(WithNumber("temperature", Required())). The schema is
+// advertised to clients in tools/list.
+func WithOutputSchema(fields ...ToolOption) ToolOption {
+	return func(t *types.Tool) {
+		// Collect the fields by applying the builders to a scratch tool
+		scratch := &types.Tool{}
+		for _, field := range fields {
+			field(scratch)
+		}
+
+		t.OutputSchema = append(t.OutputSchema, scratch.Parameters...)
+	}
+}
+
+// ValidateOutput makes the server check the handler's structuredContent
+// against the output schema before sending it. Non-conforming results fail
+// with an internal error.
+func ValidateOutput() ToolOption {
+	return func(t *types.Tool) {
+		t.ValidateOutput = true
+	}
+}
+
+// WithTimeout sets a timeout for calls to the tool that overrides the
+// server's request timeout.
+func WithTimeout(timeout time.Duration) ToolOption {
+	return func(t *types.Tool) {
+		t.Timeout = timeout
+	}
+}
+
+// AnnotationOption is a function that sets a tool annotation.
+type AnnotationOption func(*types.ToolAnnotations)
+
+// WithAnnotations sets behavior hints that clients use to decide whether to
+// ask the user before calling the tool, e.g.
+// WithAnnotations(ReadOnly(), Idempotent()). The hints are advertised in
+// tools/list; they do not change how the server runs the tool.
+func WithAnnotations(options ...AnnotationOption) ToolOption {
+	return func(t *types.Tool) {
+		if t.Annotations == nil {
+			t.Annotations = &types.ToolAnnotations{}
+		}
+		for _, option := range options {
+			option(t.Annotations)
+		}
+	}
+}
+
+// ReadOnly marks the tool as not modifying its environment.
+func ReadOnly() AnnotationOption {
+	return func(a *types.ToolAnnotations) {
+		a.ReadOnlyHint = boolPtr(true)
+	}
+}
+
+// Destructive marks the tool as possibly performing destructive updates.
+func Destructive() AnnotationOption {
+	return func(a *types.ToolAnnotations) {
+		a.DestructiveHint = boolPtr(true)
+	}
+}
+
+// NonDestructive marks the tool as only performing additive updates.
+// Clients assume updates are destructive unless told otherwise.
+func NonDestructive() AnnotationOption {
+	return func(a *types.ToolAnnotations) {
+		a.DestructiveHint = boolPtr(false)
+	}
+}
+
+// Idempotent marks repeated calls with the same arguments as having no
+// additional effect.
+func Idempotent() AnnotationOption {
+	return func(a *types.ToolAnnotations) {
+		a.IdempotentHint = boolPtr(true)
+	}
+}
+
+// OpenWorld marks the tool as interacting with external entities, such as
+// the web.
+func OpenWorld() AnnotationOption {
+	return func(a *types.ToolAnnotations) {
+		a.OpenWorldHint = boolPtr(true)
+	}
+}
+
+// ClosedWorld marks the tool as only acting within a closed domain, such as
+// a local database. Clients assume an open world unless told otherwise.
+func ClosedWorld() AnnotationOption {
+	return func(a *types.ToolAnnotations) {
+		a.OpenWorldHint = boolPtr(false)
+	}
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
 // Parameter types
 
 // ParameterOption is a function that configures a parameter.
@@ -49,6 +186,95 @@ func Required() ParameterOption {
 	}
 }
 
+// Default sets the value injected for an optional parameter when the client
+// omits it. A value supplied by the client always takes precedence.
+func Default(value interface{}) ParameterOption {
+	return func(p *types.ToolParameter) {
+		p.Default = value
+	}
+}
+
+// Minimum sets the inclusive lower bound of a number or integer parameter.
+func Minimum(min float64) ParameterOption {
+	return func(p *types.ToolParameter) {
+		p.Minimum = &min
+	}
+}
+
+// Maximum sets the inclusive upper bound of a number or integer parameter.
+func Maximum(max float64) ParameterOption {
+	return func(p *types.ToolParameter) {
+		p.Maximum = &max
+	}
+}
+
+// ExclusiveMinimum sets a lower bound the value must be strictly greater than.
+func ExclusiveMinimum(min float64) ParameterOption {
+	return func(p *types.ToolParameter) {
+		p.ExclusiveMinimum = &min
+	}
+}
+
+// ExclusiveMaximum sets an upper bound the value must be strictly less than.
+func ExclusiveMaximum(max float64) ParameterOption {
+	return func(p *types.ToolParameter) {
+		p.ExclusiveMaximum = &max
+	}
+}
+
+// MinLength sets the minimum length, in characters, of a string parameter.
+func MinLength(n int) ParameterOption {
+	return func(p *types.ToolParameter) {
+		p.MinLength = &n
+	}
+}
+
+// MaxLength sets the maximum length, in characters, of a string parameter.
+func MaxLength(n int) ParameterOption {
+	return func(p *types.ToolParameter) {
+		p.MaxLength = &n
+	}
+}
+
+// Pattern sets a regular expression that a string parameter must match.
+// The expression is compiled when the tool is registered.
+func Pattern(regex string) ParameterOption {
+	return func(p *types.ToolParameter) {
+		p.Pattern = regex
+	}
+}
+
+// Items describes the elements of an array parameter as a parameter of the
+// given type, e.g. Items("string") or Items("object", Properties(...)).
+func Items(itemType string, options ...ParameterOption) ParameterOption {
+	return func(p *types.ToolParameter) {
+		items := types.ToolParameter{
+			Type: itemType,
+		}
+
+		// Apply options
+		for _, option := range options {
+			option(&items)
+		}
+
+		p.Items = &items
+	}
+}
+
+// Properties describes the fields of an object parameter using the same
+// builders as tool parameters, e.g. Properties(WithString("name", Required())).
+func Properties(properties ...ToolOption) ParameterOption {
+	return func(p *types.ToolParameter) {
+		// Collect the fields by applying the builders to a scratch tool
+		scratch := &types.Tool{}
+		for _, property := range properties {
+			property(scratch)
+		}
+
+		p.Properties = append(p.Properties, scratch.Parameters...)
+	}
+}
+
 // Type functions for creating parameters
 
 // WithString adds a string parameter to a tool.
@@ -3,6 +3,7 @@ package types
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -29,6 +30,19 @@ type Resource struct {
 	Name        string
 	Description string
 	MIMEType    string
+	// ContentProvider supplies the resource contents for resources/read.
+	ContentProvider ResourceContentProvider
+}
+
+// ResourceTemplate describes a family of resources addressed by an RFC 6570
+// level 1 URI template such as "file:///{path}".
+type ResourceTemplate struct {
+	URITemplate string
+	Name        string
+	Description string
+	MIMEType    string
+	// ContentProvider supplies the contents of resources matching the template.
+	ContentProvider ResourceTemplateContentProvider
 }
 
 // ResourceContents represents the contents of a resource.
@@ -44,6 +58,35 @@ type Tool struct {
 	Name        string
 	Description string
 	Parameters  []ToolParameter
+	RateLimit   *ToolRateLimit
+	// Timeout overrides the server's request timeout for calls to this tool.
+	Timeout time.Duration
+	// LocalizedDescriptions maps a locale such as "fr" or "pt-BR" to a translated description.
+	LocalizedDescriptions map[string]string
+	// AutoWrapResults wraps plain handler return values as MCP content.
+	AutoWrapResults bool
+	// OutputSchema describes the fields of the tool's structuredContent result.
+	OutputSchema []ToolParameter
+	// ValidateOutput rejects results whose structuredContent does not match OutputSchema.
+	ValidateOutput bool
+	// Annotations are behavior hints advertised to clients in tools/list.
+	Annotations *ToolAnnotations
+}
+
+// ToolAnnotations are hints about a tool's behavior that help clients decide
+// whether to ask the user before calling it. A nil hint is left out and takes
+// its default from the MCP specification.
+type ToolAnnotations struct {
+	ReadOnlyHint    *bool
+	DestructiveHint *bool
+	IdempotentHint  *bool
+	OpenWorldHint   *bool
+}
+
+// ToolRateLimit limits how often a tool can be invoked across all sessions.
+type ToolRateLimit struct {
+	RequestsPerSecond int
+	Burst             int
 }
 
 // ToolParameter defines a parameter for a tool.
@@ -52,6 +95,23 @@ type ToolParameter struct {
 	Description string
 	Type        string
 	Required    bool
+	// Default is injected into the call arguments when an optional parameter is omitted.
+	Default interface{}
+	// Items describes the elements of an array parameter.
+	Items *ToolParameter
+	// Properties describes the fields of an object parameter.
+	Properties []ToolParameter
+	// Minimum and Maximum bound number and integer values inclusively;
+	// ExclusiveMinimum and ExclusiveMaximum bound them exclusively. Nil means unbounded.
+	Minimum          *float64
+	Maximum          *float64
+	ExclusiveMinimum *float64
+	ExclusiveMaximum *float64
+	// MinLength and MaxLength bound the length of a string value in characters.
+	MinLength *int
+	MaxLength *int
+	// Pattern is a regular expression a string value must match.
+	Pattern string
 }
 
 // ToolCall represents a request to execute a tool.
@@ -75,12 +135,17 @@ type Prompt struct {
 	Parameters  []PromptParameter
 }
 
-// PromptParameter defines a parameter for a prompt template.
+// PromptParameter defines a parameter for a prompt template. Type is one of
+// the tool parameter types; an empty Type accepts any value.
 type PromptParameter struct {
 	Name        string
 	Description string
 	Type        string
 	Required    bool
+	// Completable marks the argument as answered by completion/complete.
+	// Boolean arguments complete to true and false unless a completer
+	// suggests other values.
+	Completable bool
 }
 
 // PromptRequest represents a request to render a prompt.
@@ -102,6 +167,56 @@ type Notification struct {
 	Params map[string]interface{}
 }
 
+// MetricsCollector records request metrics. Implement it to send metrics to a
+// custom backend. A collector that also implements http.Handler is served at
+// the /metrics endpoint.
+type MetricsCollector interface {
+	// ObserveRequest records one processed JSON-RPC message with its method,
+	// processing time and whether it produced an error response.
+	ObserveRequest(method string, duration time.Duration, failed bool)
+}
+
+// ResourceContentProvider supplies the contents of a resource.
+type ResourceContentProvider interface {
+	// ReadContent returns the contents of the resource with the given URI.
+	ReadContent(ctx context.Context, uri string) ([]ResourceContents, error)
+}
+
+// ResourceContentProviderFunc adapts a function to a ResourceContentProvider.
+type ResourceContentProviderFunc func(ctx context.Context, uri string) ([]ResourceContents, error)
+
+// ReadContent calls f(ctx, uri).
+func (f ResourceContentProviderFunc) ReadContent(ctx context.Context, uri string) ([]ResourceContents, error) {
+	return f(ctx, uri)
+}
+
+// ResourceTemplateContentProvider supplies the contents of resources matching a resource template.
+type ResourceTemplateContentProvider interface {
+	// ReadTemplateContent returns the contents of the resource with the given
+	// URI. vars holds the values of the template variables extracted from it.
+	ReadTemplateContent(ctx context.Context, uri string, vars map[string]string) ([]ResourceContents, error)
+}
+
+// ResourceTemplateContentProviderFunc adapts a function to a ResourceTemplateContentProvider.
+type ResourceTemplateContentProviderFunc func(ctx context.Context, uri string, vars map[string]string) ([]ResourceContents, error)
+
+// ReadTemplateContent calls f(ctx, uri, vars).
+func (f ResourceTemplateContentProviderFunc) ReadTemplateContent(ctx context.Context, uri string, vars map[string]string) ([]ResourceContents, error) {
+	return f(ctx, uri, vars)
+}
+
+// ResourceTemplateRepository defines the interface for managing resource templates.
+type ResourceTemplateRepository interface {
+	// ListResourceTemplates returns all registered resource templates.
+	ListResourceTemplates(ctx context.Context) ([]*ResourceTemplate, error)
+
+	// AddResourceTemplate adds a resource template to the repository.
+	AddResourceTemplate(ctx context.Context, template *ResourceTemplate) error
+
+	// DeleteResourceTemplate removes the resource template with the given URI template.
+	DeleteResourceTemplate(ctx context.Context, uriTemplate string) error
+}
+
 // ResourceRepository defines the interface for managing resources.
 type ResourceRepository interface {
 	// GetResource retrieves a resource by its URI.
@@ -170,3 +285,46 @@ type NotificationSender interface {
 	// BroadcastNotification sends a notification to all connected clients.
 	BroadcastNotification(ctx context.Context, notification *Notification) error
 }
+
+// SessionStore is a key-value store scoped to a single client session.
+// Values are cleared when the session disconnects.
+type SessionStore interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{})
+	Delete(key string)
+}
+
+// Completion reference types.
+const (
+	CompletionRefPrompt   = "ref/prompt"
+	CompletionRefResource = "ref/resource"
+)
+
+// CompletionReference identifies what an argument is being completed for:
+// a prompt by Name or a resource template by URI.
+type CompletionReference struct {
+	Type string
+	Name string
+	URI  string
+}
+
+// CompletionArgument is the argument being completed and its partial value.
+type CompletionArgument struct {
+	Name  string
+	Value string
+}
+
+// CompletionProvider suggests values for an argument of a prompt or resource
+// template. It returns nil values for references it does not handle; at most
+// 100 values are sent to the client.
+type CompletionProvider interface {
+	Complete(ctx context.Context, ref CompletionReference, arg CompletionArgument) ([]string, error)
+}
+
+// CompletionProviderFunc adapts a function to a CompletionProvider.
+type CompletionProviderFunc func(ctx context.Context, ref CompletionReference, arg CompletionArgument) ([]string, error)
+
+// Complete calls f(ctx, ref, arg).
+func (f CompletionProviderFunc) Complete(ctx context.Context, ref CompletionReference, arg CompletionArgument) ([]string, error) {
+	return f(ctx, ref, arg)
+}